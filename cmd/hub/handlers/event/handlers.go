@@ -0,0 +1,212 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// keepAliveInterval represents how often a comment is sent to the stream
+	// to prevent idle connections from being closed by proxies.
+	keepAliveInterval = 10 * time.Second
+
+	// maxStreamDuration represents the maximum duration of a stream. It must
+	// be lower than the server write timeout. Clients are expected to
+	// reconnect providing the Last-Event-ID header to resume the stream.
+	maxStreamDuration = 25 * time.Second
+
+	// reconnectionTime represents the time (in milliseconds) clients should
+	// wait before reconnecting once the stream is closed.
+	reconnectionTime = 1000
+
+	// catchUpBatchSize represents the number of events requested at once
+	// when sending the events missed since the last event id provided.
+	catchUpBatchSize = 100
+)
+
+// Handlers represents a group of http handlers in charge of handling events
+// operations.
+type Handlers struct {
+	eventManager hub.EventManager
+	eventsBroker hub.EventsBroker
+	logger       zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(eventManager hub.EventManager, eventsBroker hub.EventsBroker) *Handlers {
+	return &Handlers{
+		eventManager: eventManager,
+		eventsBroker: eventsBroker,
+		logger:       log.With().Str("handlers", "event").Logger(),
+	}
+}
+
+// Stream is an http handler that streams the events processed using
+// server-sent events. By default only the events the user is subscribed to
+// are sent, but all public events can be requested using the scope query
+// parameter. When the Last-Event-ID header is provided, the events processed
+// since that event are sent before the ones processed from now on. Otherwise,
+// the stream starts with the id of the last event processed.
+func (h *Handlers) Stream(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetEventsToStreamInput{}
+	switch r.FormValue("scope") {
+	case "", "subscriptions":
+	case "all":
+		input.All = true
+	default:
+		err := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid scope")
+		h.logger.Error().Err(err).Str("method", "Stream").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err := errors.New("streaming not supported")
+		h.logger.Error().Err(err).Str("method", "Stream").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Subscribe to the events processed before catching up, so that no events
+	// are missed in between
+	events, unsubscribe := h.eventsBroker.Subscribe()
+	defer unsubscribe()
+
+	// Get the first batch of events missed since the last event id provided.
+	// When it is not provided, the id of the last event processed is sent to
+	// the client instead, so that it can resume the stream from that point if
+	// it is closed before any event is received.
+	var missedEventsJSON []byte
+	var lastProcessedEventID string
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		input.LastEventID = lastEventID
+		input.Limit = catchUpBatchSize
+		var err error
+		missedEventsJSON, err = h.eventManager.GetToStreamJSON(r.Context(), input)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Send()
+			helpers.RenderErrorJSON(w, err)
+			return
+		}
+	} else {
+		var err error
+		lastProcessedEventID, err = h.eventManager.GetLastProcessedID(r.Context())
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Send()
+			helpers.RenderErrorJSON(w, err)
+			return
+		}
+	}
+
+	// Start stream
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectionTime)
+	if lastProcessedEventID != "" {
+		// Messages with no data only update the client's last event id
+		fmt.Fprintf(w, "id: %s\n\n", lastProcessedEventID)
+	}
+	flusher.Flush()
+
+	// Send the events missed
+	sent := make(map[string]struct{})
+	for missedEventsJSON != nil {
+		n, lastEventID, err := writeEvents(w, missedEventsJSON, sent)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Send()
+			return
+		}
+		flusher.Flush()
+		if n < catchUpBatchSize {
+			break
+		}
+		input.LastEventID = lastEventID
+		missedEventsJSON, err = h.eventManager.GetToStreamJSON(r.Context(), input)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Send()
+			return
+		}
+	}
+
+	// Send the events processed from now on
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	timeout := time.NewTimer(maxStreamDuration)
+	defer timeout.Stop()
+	for {
+		select {
+		case eventID, ok := <-events:
+			if !ok {
+				return
+			}
+			if _, ok := sent[eventID]; ok {
+				continue
+			}
+			eventsJSON, err := h.eventManager.GetToStreamJSON(r.Context(), &hub.GetEventsToStreamInput{
+				All:     input.All,
+				EventID: eventID,
+			})
+			if err != nil {
+				h.logger.Error().Err(err).Str("method", "Stream").Send()
+				return
+			}
+			if _, _, err := writeEvents(w, eventsJSON, nil); err != nil {
+				h.logger.Error().Err(err).Str("method", "Stream").Send()
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case <-timeout.C:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEvents writes the events in the json array provided to the stream,
+// returning the number of events written and the id of the last one. When a
+// set of sent events ids is provided, the ids written are added to it.
+func writeEvents(
+	w http.ResponseWriter,
+	eventsJSON []byte,
+	sent map[string]struct{},
+) (int, string, error) {
+	var events []json.RawMessage
+	if err := json.Unmarshal(eventsJSON, &events); err != nil {
+		return 0, "", err
+	}
+	var lastEventID string
+	for _, eventJSON := range events {
+		var e struct {
+			EventID string `json:"event_id"`
+		}
+		if err := json.Unmarshal(eventJSON, &e); err != nil {
+			return 0, "", err
+		}
+		var data bytes.Buffer
+		if err := json.Compact(&data, eventJSON); err != nil {
+			return 0, "", err
+		}
+		fmt.Fprintf(w, "id: %s\ndata: %s\n\n", e.EventID, data.Bytes())
+		if sent != nil {
+			sent[e.EventID] = struct{}{}
+		}
+		lastEventID = e.EventID
+	}
+	return len(events), lastEventID, nil
+}
@@ -0,0 +1,239 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/event"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	event1ID = "00000000-0000-0000-0000-000000000001"
+	event2ID = "00000000-0000-0000-0000-000000000002"
+	event3ID = "00000000-0000-0000-0000-000000000003"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestStream(t *testing.T) {
+	t.Run("invalid scope", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?scope=invalid", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("error getting missed events", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r.Header.Set("Last-Event-ID", event1ID)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+				hw := newHandlersWrapper()
+				hw.eb.On("Subscribe").Return(make(chan string), func() {})
+				hw.em.On("GetToStreamJSON", r.Context(), &hub.GetEventsToStreamInput{
+					LastEventID: event1ID,
+					Limit:       catchUpBatchSize,
+				}).Return(nil, tc.err)
+				hw.h.Stream(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.em.AssertExpectations(t)
+				hw.eb.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error getting last processed event id", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.eb.On("Subscribe").Return(make(chan string), func() {})
+		hw.em.On("GetLastProcessedID", r.Context()).Return("", tests.ErrFakeDB)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.em.AssertExpectations(t)
+		hw.eb.AssertExpectations(t)
+	})
+
+	t.Run("last processed event id is sent when the stream starts", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		events := make(chan string)
+		close(events)
+		hw := newHandlersWrapper()
+		hw.eb.On("Subscribe").Return(events, func() {})
+		hw.em.On("GetLastProcessedID", r.Context()).Return(event1ID, nil)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "retry: 1000\n\nid: "+event1ID+"\n\n", string(data))
+		hw.em.AssertExpectations(t)
+		hw.eb.AssertExpectations(t)
+	})
+
+	t.Run("missed and new events are streamed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?scope=all", nil)
+		r.Header.Set("Last-Event-ID", event1ID)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		events := make(chan string, 2)
+		events <- event2ID
+		events <- event3ID
+		close(events)
+		unsubscribed := false
+		hw := newHandlersWrapper()
+		hw.eb.On("Subscribe").Return(events, func() { unsubscribed = true })
+		hw.em.On("GetToStreamJSON", r.Context(), &hub.GetEventsToStreamInput{
+			All:         true,
+			LastEventID: event1ID,
+			Limit:       catchUpBatchSize,
+		}).Return([]byte(fmt.Sprintf(`[{"event_id": "%s", "event_kind": 0}]`, event2ID)), nil)
+		hw.em.On("GetToStreamJSON", r.Context(), &hub.GetEventsToStreamInput{
+			All:     true,
+			EventID: event3ID,
+		}).Return([]byte(fmt.Sprintf(`[
+			{
+				"event_id": "%s",
+				"event_kind": 6
+			}
+		]`, event3ID)), nil)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", h.Get("Content-Type"))
+		assert.Equal(t, "no-cache", h.Get("Cache-Control"))
+		expectedData := strings.Join([]string{
+			"retry: 1000\n\n",
+			fmt.Sprintf("id: %s\ndata: {\"event_id\":\"%s\",\"event_kind\":0}\n\n", event2ID, event2ID),
+			fmt.Sprintf("id: %s\ndata: {\"event_id\":\"%s\",\"event_kind\":6}\n\n", event3ID, event3ID),
+		}, "")
+		assert.Equal(t, expectedData, string(data))
+		assert.True(t, unsubscribed)
+		hw.em.AssertExpectations(t)
+		hw.eb.AssertExpectations(t)
+	})
+
+	t.Run("events not visible to the user are not streamed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		events := make(chan string, 1)
+		events <- event1ID
+		close(events)
+		hw := newHandlersWrapper()
+		hw.eb.On("Subscribe").Return(events, func() {})
+		hw.em.On("GetLastProcessedID", r.Context()).Return("", nil)
+		hw.em.On("GetToStreamJSON", r.Context(), &hub.GetEventsToStreamInput{
+			EventID: event1ID,
+		}).Return([]byte("[]"), nil)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "retry: 1000\n\n", string(data))
+		hw.em.AssertExpectations(t)
+		hw.eb.AssertExpectations(t)
+	})
+
+	t.Run("error getting new event", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		events := make(chan string, 1)
+		events <- event1ID
+		hw := newHandlersWrapper()
+		hw.eb.On("Subscribe").Return(events, func() {})
+		hw.em.On("GetLastProcessedID", r.Context()).Return(event1ID, nil)
+		hw.em.On("GetToStreamJSON", r.Context(), &hub.GetEventsToStreamInput{
+			EventID: event1ID,
+		}).Return(nil, tests.ErrFakeDB)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.em.AssertExpectations(t)
+		hw.eb.AssertExpectations(t)
+	})
+}
+
+type handlersWrapper struct {
+	em *event.ManagerMock
+	eb *event.BrokerMock
+	h  *Handlers
+}
+
+func newHandlersWrapper() *handlersWrapper {
+	em := &event.ManagerMock{}
+	eb := &event.BrokerMock{}
+
+	return &handlersWrapper{
+		em: em,
+		eb: eb,
+		h:  NewHandlers(em, eb),
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/apikey"
+	"github.com/artifacthub/hub/cmd/hub/handlers/event"
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/cmd/hub/handlers/org"
 	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
@@ -36,9 +37,12 @@ type Services struct {
 	UserManager         hub.UserManager
 	RepositoryManager   hub.RepositoryManager
 	PackageManager      hub.PackageManager
+	TemplateRenderer    hub.ChartTemplateRenderer
 	SubscriptionManager hub.SubscriptionManager
 	WebhookManager      hub.WebhookManager
 	APIKeyManager       hub.APIKeyManager
+	EventManager        hub.EventManager
+	EventsBroker        hub.EventsBroker
 	ImageStore          img.Store
 	Authorizer          hub.Authorizer
 }
@@ -64,6 +68,7 @@ type Handlers struct {
 	Subscriptions *subscription.Handlers
 	Webhooks      *webhook.Handlers
 	APIKeys       *apikey.Handlers
+	Events        *event.Handlers
 	Static        *static.Handlers
 }
 
@@ -82,10 +87,11 @@ func Setup(ctx context.Context, cfg *viper.Viper, svc *Services) (*Handlers, err
 		Organizations: org.NewHandlers(svc.OrganizationManager, svc.Authorizer, cfg),
 		Users:         userHandlers,
 		Repositories:  repo.NewHandlers(svc.RepositoryManager),
-		Packages:      pkg.NewHandlers(svc.PackageManager, cfg),
-		Subscriptions: subscription.NewHandlers(svc.SubscriptionManager),
-		Webhooks:      webhook.NewHandlers(svc.WebhookManager),
+		Packages:      pkg.NewHandlers(svc.PackageManager, svc.TemplateRenderer, cfg),
+		Subscriptions: subscription.NewHandlers(svc.SubscriptionManager, cfg),
+		Webhooks:      webhook.NewHandlers(svc.WebhookManager, svc.PackageManager, cfg),
 		APIKeys:       apikey.NewHandlers(svc.APIKeyManager),
+		Events:        event.NewHandlers(svc.EventManager, svc.EventsBroker),
 		Static:        static.NewHandlers(cfg, svc.ImageStore),
 	}
 	h.setupRouter()
@@ -178,6 +184,8 @@ func (h *Handlers) setupRouter() {
 				r.Route("/{repoName}", func(r chi.Router) {
 					r.Put("/claimOwnership", h.Repositories.ClaimOwnership)
 					r.Put("/transfer", h.Repositories.Transfer)
+					r.Put("/track", h.Repositories.RequestTracking)
+					r.Get("/trackingRuns", h.Repositories.GetTrackingRuns)
 					r.Put("/", h.Repositories.Update)
 					r.Delete("/", h.Repositories.Delete)
 				})
@@ -188,6 +196,8 @@ func (h *Handlers) setupRouter() {
 				r.Route("/{repoName}", func(r chi.Router) {
 					r.Put("/claimOwnership", h.Repositories.ClaimOwnership)
 					r.Put("/transfer", h.Repositories.Transfer)
+					r.Put("/track", h.Repositories.RequestTracking)
+					r.Get("/trackingRuns", h.Repositories.GetTrackingRuns)
 					r.Put("/", h.Repositories.Update)
 					r.Delete("/", h.Repositories.Delete)
 				})
@@ -209,23 +219,33 @@ func (h *Handlers) setupRouter() {
 				r.With(h.Users.InjectUserID).Get("/", h.Packages.GetStars)
 				r.With(h.Users.RequireLogin).Put("/", h.Packages.ToggleStar)
 			})
+			r.Get("/{packageID}/{version}/dependencies", h.Packages.GetDependencies)
+			r.Get("/{packageID}/{version}/sbom", h.Packages.GetSnapshotSBOM)
 			r.Get("/{packageID}/{version}/securityReport", h.Packages.GetSnapshotSecurityReport)
+			r.With(h.Users.RequireLogin).Post("/{packageID}/{version}/template", h.Packages.RenderChartTemplates)
 			r.Get("/{packageID}/{version}/valuesSchema", h.Packages.GetValuesSchema)
 			r.Get("/{packageID}/changelog", h.Packages.GetChangeLog)
+			r.Get("/{packageID}/dependents", h.Packages.GetDependents)
 		})
 
 		// Subscriptions
 		r.Route("/subscriptions", func(r chi.Router) {
-			r.Use(h.Users.RequireLogin)
-			r.Route("/opt-out", func(r chi.Router) {
-				r.Get("/", h.Subscriptions.GetOptOutList)
-				r.Post("/", h.Subscriptions.AddOptOut)
-				r.Delete("/{optOutID}", h.Subscriptions.DeleteOptOut)
+			r.Get("/unsubscribe", h.Subscriptions.ConfirmUnsubscribe)
+			r.Post("/unsubscribe", h.Subscriptions.Unsubscribe)
+			r.Group(func(r chi.Router) {
+				r.Use(h.Users.RequireLogin)
+				r.Route("/opt-out", func(r chi.Router) {
+					r.Get("/", h.Subscriptions.GetOptOutList)
+					r.Post("/", h.Subscriptions.AddOptOut)
+					r.Delete("/{optOutID}", h.Subscriptions.DeleteOptOut)
+				})
+				r.Get("/organizations", h.Subscriptions.GetOrganizationsByUser)
+				r.Get("/repositories", h.Subscriptions.GetRepositoriesByUser)
+				r.Get("/{packageID}", h.Subscriptions.GetByPackage)
+				r.Get("/", h.Subscriptions.GetByUser)
+				r.Post("/", h.Subscriptions.Add)
+				r.Delete("/", h.Subscriptions.Delete)
 			})
-			r.Get("/{packageID}", h.Subscriptions.GetByPackage)
-			r.Get("/", h.Subscriptions.GetByUser)
-			r.Post("/", h.Subscriptions.Add)
-			r.Delete("/", h.Subscriptions.Delete)
 		})
 
 		// Webhooks
@@ -238,6 +258,8 @@ func (h *Handlers) setupRouter() {
 					r.Get("/", h.Webhooks.Get)
 					r.Put("/", h.Webhooks.Update)
 					r.Delete("/", h.Webhooks.Delete)
+					r.Get("/deliveries", h.Webhooks.GetDeliveries)
+					r.Post("/deliveries/{webhookDeliveryID}/redeliver", h.Webhooks.Redeliver)
 				})
 			})
 			r.Route("/org/{orgName}", func(r chi.Router) {
@@ -247,9 +269,12 @@ func (h *Handlers) setupRouter() {
 					r.Get("/", h.Webhooks.Get)
 					r.Put("/", h.Webhooks.Update)
 					r.Delete("/", h.Webhooks.Delete)
+					r.Get("/deliveries", h.Webhooks.GetDeliveries)
+					r.Post("/deliveries/{webhookDeliveryID}/redeliver", h.Webhooks.Redeliver)
 				})
 			})
 			r.Post("/test", h.Webhooks.TriggerTest)
+			r.Post("/preview", h.Webhooks.Preview)
 		})
 
 		// API keys
@@ -264,6 +289,9 @@ func (h *Handlers) setupRouter() {
 			})
 		})
 
+		// Events
+		r.With(h.Users.RequireLogin).Get("/events/stream", h.Events.Stream)
+
 		// Availability checks
 		r.Route("/check-availability", func(r chi.Router) {
 			r.Head("/{resourceKind:^repositoryName$|^repositoryURL$}", h.Repositories.CheckAvailability)
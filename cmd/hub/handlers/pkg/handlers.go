@@ -3,6 +3,7 @@ package pkg
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sort"
@@ -12,6 +13,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/sbom"
 	"github.com/go-chi/chi"
 	"github.com/gorilla/feeds"
 	"github.com/rs/zerolog"
@@ -19,20 +21,32 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	// maxValuesSize represents the maximum size of the values document that
+	// can be provided to render the templates of a chart.
+	maxValuesSize = 1024 * 1024
+)
+
 // Handlers represents a group of http handlers in charge of handling packages
 // operations.
 type Handlers struct {
-	pkgManager hub.PackageManager
-	cfg        *viper.Viper
-	logger     zerolog.Logger
+	pkgManager       hub.PackageManager
+	templateRenderer hub.ChartTemplateRenderer
+	cfg              *viper.Viper
+	logger           zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
-func NewHandlers(pkgManager hub.PackageManager, cfg *viper.Viper) *Handlers {
+func NewHandlers(
+	pkgManager hub.PackageManager,
+	templateRenderer hub.ChartTemplateRenderer,
+	cfg *viper.Viper,
+) *Handlers {
 	return &Handlers{
-		pkgManager: pkgManager,
-		cfg:        cfg,
-		logger:     log.With().Str("handlers", "pkg").Logger(),
+		pkgManager:       pkgManager,
+		templateRenderer: templateRenderer,
+		cfg:              cfg,
+		logger:           log.With().Str("handlers", "pkg").Logger(),
 	}
 }
 
@@ -67,6 +81,33 @@ func (h *Handlers) GetChangeLog(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetDependencies is an http handler used to get the dependencies of a
+// package's version.
+func (h *Handlers) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	version := chi.URLParam(r, "version")
+	dataJSON, err := h.pkgManager.GetDependenciesJSON(r.Context(), packageID, version)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetDependenciesJSON").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
+// GetDependents is an http handler used to get the packages that depend on
+// the package provided.
+func (h *Handlers) GetDependents(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	dataJSON, err := h.pkgManager.GetDependentsJSON(r.Context(), packageID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetDependentsJSON").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
 // GetHarborReplicationDump is an http handler used to get a summary of all
 // available packages versions of kind Helm in the hub database so that they
 // can be synchronized in Harbor.
@@ -92,6 +133,24 @@ func (h *Handlers) GetRandom(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetSnapshotSBOM is an http handler used to get the software bill of
+// materials of a package's snapshot. CycloneDX is the default format.
+func (h *Handlers) GetSnapshotSBOM(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	version := chi.URLParam(r, "version")
+	format := r.FormValue("format")
+	if format == "" {
+		format = sbom.FormatCycloneDX
+	}
+	dataJSON, err := h.pkgManager.GetSnapshotSBOMJSON(r.Context(), packageID, version, format)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetSnapshotSBOMJSON").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 30*time.Minute, http.StatusOK)
+}
+
 // GetSnapshotSecurityReport is an http handler used to get the security report
 // of a package's snapshot.
 func (h *Handlers) GetSnapshotSecurityReport(w http.ResponseWriter, r *http.Request) {
@@ -191,6 +250,29 @@ func (h *Handlers) InjectIndexMeta(next http.Handler) http.Handler {
 	})
 }
 
+// RenderChartTemplates is an http handler used to render the templates of a
+// Helm chart version using the values document provided in the request body.
+func (h *Handlers) RenderChartTemplates(w http.ResponseWriter, r *http.Request) {
+	values, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxValuesSize))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "RenderChartTemplates").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	input := &hub.RenderChartTemplatesInput{
+		PackageID: chi.URLParam(r, "packageID"),
+		Version:   chi.URLParam(r, "version"),
+		Values:    values,
+	}
+	dataJSON, err := h.templateRenderer.RenderJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "RenderChartTemplates").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // RssFeed is an http handler used to get the RSS feed of a given package.
 func (h *Handlers) RssFeed(w http.ResponseWriter, r *http.Request) {
 	// Get package details
@@ -366,6 +448,16 @@ func buildSearchInput(qs url.Values) (*hub.SearchPackageInput, error) {
 		}
 	}
 
+	// Only display packages with a verified signature
+	var verifiedSignature bool
+	if qs.Get("verified_signature") != "" {
+		var err error
+		verifiedSignature, err = strconv.ParseBool(qs.Get("verified_signature"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid verified signature: %s", qs.Get("verified_signature"))
+		}
+	}
+
 	// Include deprecated packages
 	var deprecated bool
 	if qs.Get("deprecated") != "" {
@@ -390,6 +482,7 @@ func buildSearchInput(qs url.Values) (*hub.SearchPackageInput, error) {
 		Official:          official,
 		Operators:         operators,
 		Deprecated:        deprecated,
+		VerifiedSignature: verifiedSignature,
 		Licenses:          qs["license"],
 		Capabilities:      qs["capabilities"],
 	}, nil
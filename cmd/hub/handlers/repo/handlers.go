@@ -169,6 +169,32 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetTrackingRuns is an http handler that returns the most recent tracking
+// runs of the provided repository. The user doing the request must be the
+// owner of the repository or belong to the organization which owns it.
+func (h *Handlers) GetTrackingRuns(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	dataJSON, err := h.repoManager.GetTrackingRunsJSON(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetTrackingRuns").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// RequestTracking is an http handler that queues a tracking request for the
+// provided repository, so that it's tracked as soon as possible.
+func (h *Handlers) RequestTracking(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	if err := h.repoManager.RequestTracking(r.Context(), repoName); err != nil {
+		h.logger.Error().Err(err).Str("method", "RequestTracking").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Transfer is an http handler that transfers the provided repository to a
 // different owner.
 func (h *Handlers) Transfer(w http.ResponseWriter, r *http.Request) {
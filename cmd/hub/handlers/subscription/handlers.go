@@ -3,27 +3,67 @@ package subscription
 import (
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"net/http"
 	"strconv"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+var (
+	// unsubscribeConfirmationTmpl is the template of the page displayed to
+	// confirm an unsubscribe request. Unsubscribing requires submitting the
+	// form, so that links prefetched by email clients or scanners do not
+	// unsubscribe users unexpectedly.
+	unsubscribeConfirmationTmpl = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <title>Artifact Hub - Unsubscribe</title>
+  </head>
+  <body>
+    <p>Please confirm that you want to stop receiving these Artifact Hub notifications.</p>
+    <form method="post" action="?token={{ . }}">
+      <button type="submit">Unsubscribe</button>
+    </form>
+  </body>
+</html>
+`))
+
+	// unsubscribeSucceededTmpl is the template of the page displayed once an
+	// unsubscribe request has been processed.
+	unsubscribeSucceededTmpl = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <title>Artifact Hub - Unsubscribe</title>
+  </head>
+  <body>
+    <p>You have been unsubscribed successfully.</p>
+  </body>
+</html>
+`))
 )
 
 // Handlers represents a group of http handlers in charge of handling
 // subscriptions operations.
 type Handlers struct {
 	subscriptionManager hub.SubscriptionManager
+	cfg                 *viper.Viper
 	logger              zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
-func NewHandlers(subscriptionManager hub.SubscriptionManager) *Handlers {
+func NewHandlers(subscriptionManager hub.SubscriptionManager, cfg *viper.Viper) *Handlers {
 	return &Handlers{
 		subscriptionManager: subscriptionManager,
+		cfg:                 cfg,
 		logger:              log.With().Str("handlers", "subscription").Logger(),
 	}
 }
@@ -71,8 +111,10 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := &hub.Subscription{
-		PackageID: r.FormValue("package_id"),
-		EventKind: hub.EventKind(eventKind),
+		PackageID:        r.FormValue("package_id"),
+		RepositoryID:     r.FormValue("repository_id"),
+		OrganizationName: r.FormValue("organization_name"),
+		EventKind:        hub.EventKind(eventKind),
 	}
 	if err := h.subscriptionManager.Delete(r.Context(), s); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
@@ -130,3 +172,74 @@ func (h *Handlers) GetOptOutList(w http.ResponseWriter, r *http.Request) {
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
+
+// GetOrganizationsByUser is an http handler that returns the organizations
+// subscriptions of the user doing the request.
+func (h *Handlers) GetOrganizationsByUser(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.subscriptionManager.GetOrganizationsByUserJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetOrganizationsByUser").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetRepositoriesByUser is an http handler that returns the repositories
+// subscriptions of the user doing the request.
+func (h *Handlers) GetRepositoriesByUser(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.subscriptionManager.GetRepositoriesByUserJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetRepositoriesByUser").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// ConfirmUnsubscribe is an http handler that renders a page asking the user to
+// confirm the unsubscribe request encoded in the token provided. Following the
+// links included in the emails body does not unsubscribe users on its own.
+func (h *Handlers) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	key := []byte(h.cfg.GetString("email.unsubscribeKey"))
+	token := r.FormValue("token")
+	if _, err := subscription.ParseUnsubscribeToken(key, token); err != nil {
+		h.logger.Error().Err(err).Str("method", "ConfirmUnsubscribe").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := unsubscribeConfirmationTmpl.Execute(w, token); err != nil {
+		h.logger.Error().Err(err).Str("method", "ConfirmUnsubscribe").Send()
+	}
+}
+
+// Unsubscribe is an http handler that stops the notifications described in the
+// unsubscribe token provided from being delivered to the user. Users do not
+// need to be logged in, as the token is signed. It handles both the
+// confirmation form submissions and the one-click unsubscribe requests sent by
+// email clients (RFC 8058).
+func (h *Handlers) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	key := []byte(h.cfg.GetString("email.unsubscribeKey"))
+	urs, err := subscription.ParseUnsubscribeToken(key, r.FormValue("token"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Unsubscribe").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	for _, ur := range urs {
+		if err := h.subscriptionManager.Unsubscribe(r.Context(), ur); err != nil {
+			h.logger.Error().Err(err).Str("method", "Unsubscribe").Send()
+			helpers.RenderErrorJSON(w, err)
+			return
+		}
+	}
+	if r.PostFormValue("List-Unsubscribe") == "One-Click" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := unsubscribeSucceededTmpl.Execute(w, nil); err != nil {
+		h.logger.Error().Err(err).Str("method", "Unsubscribe").Send()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
@@ -16,10 +17,13 @@ import (
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+const unsubscribeKey = "key"
+
 func TestMain(m *testing.M) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	os.Exit(m.Run())
@@ -498,16 +502,250 @@ func TestGetOptOutList(t *testing.T) {
 	})
 }
 
+func TestGetOrganizationsByUser(t *testing.T) {
+	t.Run("error getting user organizations subscriptions", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetOrganizationsByUserJSON", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.GetOrganizationsByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("get user organizations subscriptions succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetOrganizationsByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetOrganizationsByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+func TestGetRepositoriesByUser(t *testing.T) {
+	t.Run("error getting user repositories subscriptions", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetRepositoriesByUserJSON", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.GetRepositoriesByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("get user repositories subscriptions succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetRepositoriesByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetRepositoriesByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+func TestConfirmUnsubscribe(t *testing.T) {
+	urs := []*hub.UnsubscribeRequest{
+		{
+			UserID:    "00000000-0000-0000-0000-000000000001",
+			EventKind: hub.NewRelease,
+			PackageID: "00000000-0000-0000-0000-000000000001",
+		},
+	}
+	token := subscription.NewUnsubscribeToken([]byte(unsubscribeKey), urs, time.Now().Add(time.Hour))
+
+	t.Run("invalid token provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?token=invalid", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.ConfirmUnsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("expired token provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		expiredToken := subscription.NewUnsubscribeToken([]byte(unsubscribeKey), urs, time.Now().Add(-time.Hour))
+		r, _ := http.NewRequest("GET", "/?token="+expiredToken, nil)
+
+		hw := newHandlersWrapper()
+		hw.h.ConfirmUnsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("confirmation page rendered without unsubscribing", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.h.ConfirmUnsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", h.Get("Content-Type"))
+		assert.Contains(t, string(data), `<form method="post" action="?token=`+token+`">`)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	urs := []*hub.UnsubscribeRequest{
+		{
+			UserID:    "00000000-0000-0000-0000-000000000001",
+			EventKind: hub.NewRelease,
+			PackageID: "00000000-0000-0000-0000-000000000001",
+		},
+		{
+			UserID:       "00000000-0000-0000-0000-000000000001",
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+		},
+	}
+	token := subscription.NewUnsubscribeToken([]byte(unsubscribeKey), urs, time.Now().Add(time.Hour))
+
+	t.Run("invalid token provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token=invalid", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("token signed with a different key", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		otherToken := subscription.NewUnsubscribeToken([]byte("other"), urs, time.Now().Add(time.Hour))
+		r, _ := http.NewRequest("POST", "/?token="+otherToken, nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("error unsubscribing", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Unsubscribe", r.Context(), urs[0]).Return(tests.ErrFakeDB)
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("confirmation form submitted successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Unsubscribe", r.Context(), urs[0]).Return(nil)
+		hw.sm.On("Unsubscribe", r.Context(), urs[1]).Return(nil)
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(data), "unsubscribed successfully")
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("one-click unsubscribe succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader("List-Unsubscribe=One-Click")
+		r, _ := http.NewRequest("POST", "/?token="+token, body)
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Unsubscribe", r.Context(), urs[0]).Return(nil)
+		hw.sm.On("Unsubscribe", r.Context(), urs[1]).Return(nil)
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
 type handlersWrapper struct {
 	sm *subscription.ManagerMock
 	h  *Handlers
 }
 
 func newHandlersWrapper() *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("email.unsubscribeKey", unsubscribeKey)
 	sm := &subscription.ManagerMock{}
 
 	return &handlersWrapper{
 		sm: sm,
-		h:  NewHandlers(sm),
+		h:  NewHandlers(sm, cfg),
 	}
 }
@@ -1,11 +1,10 @@
 package webhook
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"text/template"
+	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
@@ -13,19 +12,32 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/satori/uuid"
+	"github.com/spf13/viper"
 )
 
+// previewEventID represents the event id used in the payloads previews.
+const previewEventID = "00000000-0000-0000-0000-000000000000"
+
 // Handlers represents a group of http handlers in charge of handling webhooks
 // operations.
 type Handlers struct {
 	webhookManager hub.WebhookManager
+	pkgManager     hub.PackageManager
+	cfg            *viper.Viper
 	logger         zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
-func NewHandlers(webhookManager hub.WebhookManager) *Handlers {
+func NewHandlers(
+	webhookManager hub.WebhookManager,
+	pkgManager hub.PackageManager,
+	cfg *viper.Viper,
+) *Handlers {
 	return &Handlers{
 		webhookManager: webhookManager,
+		pkgManager:     pkgManager,
+		cfg:            cfg,
 		logger:         log.With().Str("handlers", "webhook").Logger(),
 	}
 }
@@ -70,6 +82,19 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetDeliveries is an http handler that returns the most recent delivery
+// attempts of the provided webhook.
+func (h *Handlers) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID := chi.URLParam(r, "webhookID")
+	dataJSON, err := h.webhookManager.GetDeliveriesJSON(r.Context(), webhookID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetDeliveries").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // GetOwnedByOrg is an http handler that returns the webhooks owned by the
 // organization provided. The user doing the request must belong to the
 // organization.
@@ -96,6 +121,78 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// Preview is an http handler that renders the payload the provided webhook
+// would receive when an event of the given kind happens in the package
+// selected, without sending it.
+func (h *Handlers) Preview(w http.ResponseWriter, r *http.Request) {
+	// Read input from request body
+	input := &hub.WebhookPreviewInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "Preview").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	if input.Webhook == nil {
+		err := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "webhook not provided")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	if _, err := uuid.FromString(input.PackageID); err != nil {
+		err := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	if _, ok := notification.WebhookSampleTemplateData[input.EventKind]; !ok {
+		err := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "unsupported event kind")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Get package selected
+	p, err := h.pkgManager.Get(r.Context(), &hub.GetPackageInput{
+		PackageID: input.PackageID,
+		Version:   input.Version,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Preview").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Prepare payload using the package selected
+	payload, err := notification.BuildWebhookPayload(
+		input.Webhook,
+		input.EventKind,
+		newPreviewTemplateData(h.cfg.GetString("server.baseURL"), input.EventKind, p),
+	)
+	if err != nil {
+		helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	contentType := input.Webhook.ContentType
+	if contentType == "" {
+		contentType = notification.DefaultPayloadContentType
+	}
+	dataJSON, _ := json.Marshal(map[string]string{
+		"content_type": contentType,
+		"payload":      string(payload),
+	})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Redeliver is an http handler that schedules the notification of the provided
+// webhook delivery to be delivered again.
+func (h *Handlers) Redeliver(w http.ResponseWriter, r *http.Request) {
+	webhookID := chi.URLParam(r, "webhookID")
+	webhookDeliveryID := chi.URLParam(r, "webhookDeliveryID")
+	if err := h.webhookManager.Redeliver(r.Context(), webhookID, webhookDeliveryID); err != nil {
+		h.logger.Error().Err(err).Str("method", "Redeliver").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // TriggerTest is an http handler used to test a webhook before adding or
 // updating it.
 func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
@@ -106,34 +203,30 @@ func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Prepare payload
-	var tmpl *template.Template
-	if wh.Template != "" {
-		var err error
-		tmpl, err = template.New("").Parse(wh.Template)
-		if err != nil {
-			err = fmt.Errorf("error parsing template: %w", err)
-			helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
-			return
-		}
-	} else {
-		tmpl = notification.DefaultWebhookPayloadTmpl
+	// Prepare payload using the test data of the first event kind selected
+	eventKind := hub.NewRelease
+	if len(wh.EventKinds) > 0 {
+		eventKind = wh.EventKinds[0]
 	}
-	var payload bytes.Buffer
-	if err := tmpl.Execute(&payload, webhookTestTemplateData); err != nil {
-		err = fmt.Errorf("error executing template: %w", err)
+	tmplData, ok := notification.WebhookSampleTemplateData[eventKind]
+	if !ok {
+		err := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "unsupported event kind")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	payload, err := notification.BuildWebhookPayload(wh, eventKind, tmplData)
+	if err != nil {
 		helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
 		return
 	}
 
 	// Call webhook endpoint
-	req, _ := http.NewRequest("POST", wh.URL, &payload)
-	contentType := wh.ContentType
-	if contentType == "" {
-		contentType = notification.DefaultPayloadContentType
+	req, err := notification.NewWebhookRequest(wh, payload, time.Now())
+	if err != nil {
+		err = fmt.Errorf("error preparing request: %w", err)
+		helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
+		return
 	}
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("X-ArtifactHub-Secret", wh.Secret)
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		err = fmt.Errorf("error doing request: %s", err.Error())
@@ -167,28 +260,39 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// webhookTestTemplateData represents the notification template data used by
-// TriggerTest handler.
-var webhookTestTemplateData = &hub.PackageNotificationTemplateData{
-	BaseURL: "https://artifacthub.io",
-	Event: map[string]interface{}{
-		"id":   "00000000-0000-0000-0000-000000000001",
-		"kind": "package.new-release",
-	},
-	Package: map[string]interface{}{
-		"name":    "sample-package",
-		"version": "1.0.0",
-		"url":     "https://artifacthub.io/packages/helm/artifacthub/sample-package/1.0.0",
-		"changes": []string{
-			"Cool feature",
-			"Bug fixed",
-		},
-		"containsSecurityUpdates": true,
-		"prerelease":              true,
-		"repository": map[string]interface{}{
-			"kind":      "helm",
-			"name":      "repo1",
-			"publisher": "org1",
-		},
-	},
+// newPreviewTemplateData returns the notification template data used to
+// preview the payload of an event of the given kind in the package provided.
+// The event specific data not available in the package (like the previous
+// owner of a transferred repository) is taken from the sample template data.
+func newPreviewTemplateData(baseURL string, eventKind hub.EventKind, p *hub.Package) interface{} {
+	e := &hub.Event{
+		EventID:        previewEventID,
+		EventKind:      eventKind,
+		RepositoryID:   p.Repository.RepositoryID,
+		PackageID:      p.PackageID,
+		PackageVersion: p.Version,
+	}
+	switch eventKind {
+	case hub.SecurityAlert:
+		sample := notification.WebhookSampleTemplateData[eventKind].(*hub.PackageNotificationTemplateData)
+		e.Data = map[string]interface{}{
+			"vulnerabilities": sample.Package["vulnerabilities"],
+		}
+	case hub.RepositoryTransferred:
+		e.Data = map[string]interface{}{
+			"previous_owner": map[string]interface{}{
+				"user_alias": "user1",
+			},
+		}
+	case hub.RepositoryVerifiedPublisherChanged:
+		e.Data = map[string]interface{}{
+			"verified_publisher": p.Repository.VerifiedPublisher,
+		}
+	}
+	switch eventKind {
+	case hub.RepositoryTrackingErrors, hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+		return notification.NewRepoNotificationTemplateData(baseURL, e, p.Repository)
+	default:
+		return notification.NewPkgNotificationTemplateData(baseURL, e, p)
+	}
 }
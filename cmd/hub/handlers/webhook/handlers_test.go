@@ -3,6 +3,9 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -14,10 +17,12 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/webhook"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -273,6 +278,76 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetDeliveries(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"webhookID"},
+			Values: []string{"000000001"},
+		},
+	}
+
+	t.Run("error getting webhook deliveries", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.wm.On("GetDeliveriesJSON", r.Context(), "000000001").Return(nil, tc.err)
+				hw.h.GetDeliveries(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.wm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get webhook deliveries succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetDeliveriesJSON", r.Context(), "000000001").Return([]byte("dataJSON"), nil)
+		hw.h.GetDeliveries(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.wm.AssertExpectations(t)
+	})
+}
+
 func TestGetOwnedByOrg(t *testing.T) {
 	rctx := &chi.Context{
 		URLParams: chi.RouteParams{
@@ -378,6 +453,263 @@ func TestGetOwnedByUser(t *testing.T) {
 	})
 }
 
+func TestPreview(t *testing.T) {
+	p := &hub.Package{
+		PackageID:      "00000000-0000-0000-0000-000000000001",
+		Name:           "pkg1",
+		NormalizedName: "pkg1",
+		Version:        "1.0.0",
+		Repository: &hub.Repository{
+			RepositoryID:      "00000000-0000-0000-0000-000000000001",
+			Kind:              hub.Helm,
+			Name:              "repo1",
+			OrganizationName:  "org1",
+			VerifiedPublisher: true,
+		},
+	}
+	input := &hub.GetPackageInput{PackageID: p.PackageID}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			inputJSON   string
+		}{
+			{
+				"no input provided",
+				"",
+			},
+			{
+				"invalid json",
+				"-",
+			},
+			{
+				"webhook not provided",
+				`{"package_id": "00000000-0000-0000-0000-000000000001"}`,
+			},
+			{
+				"invalid package id",
+				`{"webhook": {}, "package_id": "invalid"}`,
+			},
+			{
+				"unsupported event kind",
+				`{"webhook": {}, "package_id": "00000000-0000-0000-0000-000000000001", "event_kind": 3}`,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.inputJSON))
+
+				hw := newHandlersWrapper()
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("error getting package", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(`{
+					"webhook": {},
+					"package_id": "00000000-0000-0000-0000-000000000001"
+				}`))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), input).Return(nil, tc.err)
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`{
+			"webhook": {"template": "{{ .Package.name }}"},
+			"package_id": "00000000-0000-0000-0000-000000000001",
+			"event_kind": 2
+		}`))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), input).Return(p, nil)
+		hw.h.Preview(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, getErrorMessage(t, data), "error executing template")
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("preview rendered successfully", func(t *testing.T) {
+		testCases := []struct {
+			inputJSON       string
+			expectedPreview map[string]string
+		}{
+			{
+				`{
+					"webhook": {
+						"template": "{{ .Package.name }} {{ .Package.version }} {{ .Package.url }}",
+						"content_type": "text/plain"
+					},
+					"package_id": "00000000-0000-0000-0000-000000000001",
+					"event_kind": 0
+				}`,
+				map[string]string{
+					"content_type": "text/plain",
+					"payload":      "pkg1 1.0.0 baseURL/packages/helm/repo1/pkg1/1.0.0",
+				},
+			},
+			{
+				`{
+					"webhook": {
+						"template": "{{ .Repository.name }} {{ .Repository.verifiedPublisher }}"
+					},
+					"package_id": "00000000-0000-0000-0000-000000000001",
+					"event_kind": 7
+				}`,
+				map[string]string{
+					"content_type": notification.DefaultPayloadContentType,
+					"payload":      "repo1 true",
+				},
+			},
+			{
+				`{
+					"webhook": {
+						"template": "{{ json .Repository.previousOwner }}"
+					},
+					"package_id": "00000000-0000-0000-0000-000000000001",
+					"event_kind": 6
+				}`,
+				map[string]string{
+					"content_type": notification.DefaultPayloadContentType,
+					"payload":      `{"organizationName":null,"userAlias":"user1"}`,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedPreview["payload"], func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.inputJSON))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), input).Return(p, nil)
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				h := resp.Header
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/json", h.Get("Content-Type"))
+				var preview map[string]string
+				require.NoError(t, json.Unmarshal(data, &preview))
+				assert.Equal(t, tc.expectedPreview, preview)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"webhookID", "webhookDeliveryID"},
+			Values: []string{"000000001", "000000002"},
+		},
+	}
+
+	t.Run("error redelivering webhook notification", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.wm.On("Redeliver", r.Context(), "000000001", "000000002").Return(tc.err)
+				hw.h.Redeliver(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.wm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("redeliver webhook notification succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("Redeliver", r.Context(), "000000001", "000000002").Return(nil)
+		hw.h.Redeliver(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
+}
+
 func TestTriggerTest(t *testing.T) {
 	t.Run("invalid input", func(t *testing.T) {
 		testCases := []struct {
@@ -555,7 +887,7 @@ func TestTriggerTest(t *testing.T) {
 					}
 					assert.Equal(t, "POST", r.Method)
 					assert.Equal(t, contentType, r.Header.Get("Content-Type"))
-					assert.Equal(t, tc.secret, r.Header.Get("X-ArtifactHub-Secret"))
+					assert.Equal(t, tc.secret, r.Header.Get(notification.WebhookSecretHeader))
 					payload, _ := ioutil.ReadAll(r.Body)
 					assert.Equal(t, tc.expectedPayload, payload)
 				}))
@@ -581,6 +913,85 @@ func TestTriggerTest(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("unsupported event kind", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{
+			URL:        "http://webhook1.url",
+			EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+		}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("webhook endpoint call using channel payload builder succeeded", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			err := json.NewDecoder(r.Body).Decode(&payload)
+			require.NoError(t, err)
+			assert.Equal(t, "sample-package 1.0.0 security alert", payload["text"])
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{
+			URL:        ts.URL,
+			Channel:    hub.SlackWebhookChannel,
+			EventKinds: []hub.EventKind{hub.SecurityAlert},
+		}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("webhook endpoint call with signed payload succeeded", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := ioutil.ReadAll(r.Body)
+			assert.Empty(t, r.Header.Get(notification.WebhookSecretHeader))
+			parts := strings.Split(r.Header.Get(notification.WebhookSignatureHeader), ",")
+			require.Len(t, parts, 2)
+			require.True(t, strings.HasPrefix(parts[0], "t="))
+			mac := hmac.New(sha256.New, []byte("very"))
+			_, _ = mac.Write([]byte(strings.TrimPrefix(parts[0], "t=") + "." + string(payload)))
+			assert.Equal(t, "v1="+hex.EncodeToString(mac.Sum(nil)), parts[1])
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{
+			Secret:       "very",
+			SignPayloads: true,
+			URL:          ts.URL,
+		}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -694,15 +1105,20 @@ func TestUpdate(t *testing.T) {
 
 type handlersWrapper struct {
 	wm *webhook.ManagerMock
+	pm *pkg.ManagerMock
 	h  *Handlers
 }
 
 func newHandlersWrapper() *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("server.baseURL", "baseURL")
 	wm := &webhook.ManagerMock{}
+	pm := &pkg.ManagerMock{}
 
 	return &handlersWrapper{
 		wm: wm,
-		h:  NewHandlers(wm),
+		pm: pm,
+		h:  NewHandlers(wm, pm, cfg),
 	}
 }
 
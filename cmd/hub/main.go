@@ -15,6 +15,7 @@ import (
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/event"
+	"github.com/artifacthub/hub/internal/helmchart"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img/pg"
 	"github.com/artifacthub/hub/internal/notification"
@@ -30,6 +31,9 @@ import (
 )
 
 func main() {
+	// Run as a chart templates renderer when requested
+	helmchart.RunRendererIfRequested()
+
 	// Setup configuration and logger
 	cfg, err := util.SetupConfig("hub")
 	if err != nil {
@@ -48,22 +52,36 @@ func main() {
 	var es hub.EmailSender
 	if s := email.NewSender(cfg); s != nil {
 		es = s
+		if cfg.GetString("email.unsubscribeKey") == "" {
+			log.Warn().Msg("email.unsubscribeKey not set: notifications emails will not include unsubscribe links")
+		}
 	}
 	az, err := authz.NewAuthorizer(db)
 	if err != nil {
 		log.Fatal().Err(err).Msg("authorizer setup failed")
 	}
 
-	// Setup and launch http server
+	// Setup and launch events broker
+	var wg sync.WaitGroup
 	ctx, stop := context.WithCancel(context.Background())
+	eventsBroker := event.NewBroker(db)
+	wg.Add(1)
+	go eventsBroker.Run(ctx, &wg)
+
+	// Setup and launch http server
+	rm := repo.NewManager(cfg, db, az)
+	pm := pkg.NewManager(db)
 	hSvc := &handlers.Services{
 		OrganizationManager: org.NewManager(db, es, az),
 		UserManager:         user.NewManager(db, es),
-		RepositoryManager:   repo.NewManager(cfg, db, az),
-		PackageManager:      pkg.NewManager(db),
+		RepositoryManager:   rm,
+		PackageManager:      pm,
+		TemplateRenderer:    helmchart.NewTemplateRenderer(pm, rm),
 		SubscriptionManager: subscription.NewManager(db),
 		WebhookManager:      webhook.NewManager(db),
 		APIKeyManager:       apikey.NewManager(db),
+		EventManager:        event.NewManager(db),
+		EventsBroker:        eventsBroker,
 		ImageStore:          pg.NewImageStore(db),
 		Authorizer:          az,
 	}
@@ -96,10 +114,9 @@ func main() {
 	}()
 
 	// Setup and launch events dispatcher
-	var wg sync.WaitGroup
 	eSvc := &event.Services{
 		DB:                  db,
-		EventManager:        event.NewManager(),
+		EventManager:        event.NewManager(db),
 		SubscriptionManager: subscription.NewManager(db),
 		WebhookManager:      webhook.NewManager(db),
 		NotificationManager: notification.NewManager(),
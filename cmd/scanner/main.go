@@ -2,17 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/sbom"
 	"github.com/artifacthub/hub/internal/scanner"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	trivyBackend       = "trivy"
+	trivyServerBackend = "trivy-server"
+	grypeBackend       = "grype"
+
+	// defaultReportMaxAge represents the default maximum age of snapshots'
+	// security reports. Snapshots with older reports will be scanned again.
+	defaultReportMaxAge = 7 * 24 * time.Hour
 )
 
 func main() {
@@ -37,9 +53,10 @@ func main() {
 		log.Info().Msg("scanner shutting down..")
 	}()
 
-	// Check required external tools are available
-	if _, err := exec.LookPath("trivy"); err != nil {
-		log.Fatal().Err(err).Msg("trivy not found")
+	// Setup scanner backend
+	sc, err := setupScanner(ctx, cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("scanner setup failed")
 	}
 
 	// Setup services
@@ -50,16 +67,8 @@ func main() {
 	pm := pkg.NewManager(db)
 
 	// Scan pending snapshots
-	trivyURL := cfg.GetString("scanner.trivyURL")
-	if trivyURL == "" {
-		log.Fatal().Err(err).Msg("trivy url not set")
-	}
-	trivyScanner := &scanner.TrivyScanner{
-		Ctx: ctx,
-		Cfg: cfg,
-		URL: trivyURL,
-	}
-	snapshots, err := pm.GetSnapshotsToScan(ctx)
+	cfg.SetDefault("scanner.reportMaxAge", defaultReportMaxAge)
+	snapshots, err := pm.GetSnapshotsToScan(ctx, cfg.GetDuration("scanner.reportMaxAge"))
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting snapshots to scan")
 	}
@@ -81,10 +90,16 @@ L:
 
 			logger := log.With().Str("pkg", snapshot.PackageID).Str("version", snapshot.Version).Logger()
 			logger.Info().Msg("scanning snapshot")
-			report, err := scanner.ScanSnapshot(ctx, trivyScanner, snapshot)
+			report, err := scanner.ScanSnapshot(ctx, sc, snapshot)
 			if err != nil {
 				logger.Error().Err(err).Send()
 			}
+			if report != nil {
+				report.SBOM, err = sbom.Generate(snapshot, time.Now())
+				if err != nil {
+					logger.Error().Err(err).Msg("error generating sbom")
+				}
+			}
 			if err := pm.UpdateSnapshotSecurityReport(ctx, report); err != nil {
 				logger.Error().Err(err).Send()
 			}
@@ -95,3 +110,43 @@ L:
 	wg.Wait()
 	log.Info().Msg("scanner finished")
 }
+
+// setupScanner creates the scanner instance for the backend configured. The
+// Trivy CLI is used by default.
+func setupScanner(ctx context.Context, cfg *viper.Viper) (scanner.Scanner, error) {
+	cfg.SetDefault("scanner.backend", trivyBackend)
+	backend := cfg.GetString("scanner.backend")
+	switch backend {
+	case trivyBackend, trivyServerBackend:
+		trivyURL := cfg.GetString("scanner.trivyURL")
+		if trivyURL == "" {
+			return nil, errors.New("trivy url not set")
+		}
+		if backend == trivyServerBackend {
+			return &scanner.TrivyServerScanner{
+				Ctx: ctx,
+				Cfg: cfg,
+				URL: trivyURL,
+				Hc:  &http.Client{Timeout: 5 * time.Minute},
+			}, nil
+		}
+		if _, err := exec.LookPath("trivy"); err != nil {
+			return nil, fmt.Errorf("trivy not found: %w", err)
+		}
+		return &scanner.TrivyScanner{
+			Ctx: ctx,
+			Cfg: cfg,
+			URL: trivyURL,
+		}, nil
+	case grypeBackend:
+		if _, err := exec.LookPath("grype"); err != nil {
+			return nil, fmt.Errorf("grype not found: %w", err)
+		}
+		return &scanner.GrypeScanner{
+			Ctx: ctx,
+			Cfg: cfg,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid scanner backend: %s", backend)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/authz"
+	"github.com/artifacthub/hub/internal/helmchart"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
@@ -32,6 +33,9 @@ const (
 )
 
 func main() {
+	// Run as a chart templates renderer when requested
+	helmchart.RunRendererIfRequested()
+
 	// Setup configuration and logger
 	cfg, err := util.SetupConfig("tracker")
 	if err != nil {
@@ -73,11 +77,6 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("image store setup failed")
 	}
-	repos, err := tracker.GetRepositories(ctx, cfg, rm)
-	if err != nil {
-		log.Fatal().Err(err).Msg("error getting repositories")
-	}
-	ec := tracker.NewDBErrorsCollector(rm, repos)
 	githubRL := rate.NewLimiter(rate.Every(1*time.Hour), githubMaxRequestsPerHour)
 	go func() {
 		<-time.After(1 * time.Hour)
@@ -89,12 +88,24 @@ func main() {
 		Rm:       rm,
 		Pm:       pm,
 		Is:       is,
-		Ec:       ec,
 		Hc:       &http.Client{Timeout: 10 * time.Second},
 		GithubRL: githubRL,
 	}
 
+	// Track repositories continuously when running in daemon mode
+	if cfg.GetBool("tracker.daemon") {
+		tracker.NewScheduler(svc, newTracker).Run(ctx)
+		log.Info().Msg("tracker stopped")
+		return
+	}
+
 	// Track registered repositories
+	repos, err := tracker.GetRepositories(ctx, cfg, rm)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error getting repositories")
+	}
+	ec := tracker.NewDBErrorsCollector(rm, repos)
+	svc.Ec = ec
 	cfg.SetDefault("tracker.concurrency", 1)
 	limiter := make(chan struct{}, cfg.GetInt("tracker.concurrency"))
 	var wg sync.WaitGroup
@@ -113,29 +124,11 @@ L:
 				<-limiter
 				wg.Done()
 			}()
-			var t tracker.Tracker
-			switch r.Kind {
-			case hub.Falco:
-				// Temporary solution to maintain backwards compatibility with
-				// the only Falco rules repository registered at the moment in
-				// artifacthub.io using the structure and metadata format used
-				// by the cloud native security hub.
-				if r.URL == cloudNativeSecurityHub {
-					t = falco.NewTracker(svc, r)
-				} else {
-					t = generic.NewTracker(svc, r)
-				}
-			case hub.Helm:
-				t = helm.NewTracker(svc, r)
-			case hub.HelmPlugin:
-				t = helmplugin.NewTracker(svc, r)
-			case hub.Krew:
-				t = krew.NewTracker(svc, r)
-			case hub.OLM:
-				t = olm.NewTracker(svc, r)
-			case hub.OPA, hub.TBAction:
-				t = generic.NewTracker(svc, r)
+			if err := rm.StartTracking(ctx, r.RepositoryID); err != nil {
+				log.Error().Err(err).Str("repo", r.Name).Msg("error starting repository tracking")
+				return
 			}
+			t := newTracker(svc, r)
 			if err := tracker.TrackRepository(ctx, cfg, rm, t, r); err != nil {
 				svc.Ec.Append(r.RepositoryID, err)
 				log.Error().Err(err).Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Send()
@@ -146,3 +139,32 @@ L:
 	ec.Flush()
 	log.Info().Msg("tracker finished")
 }
+
+// newTracker creates a new tracker instance for the repository provided,
+// based on the kind of the repository.
+func newTracker(svc *tracker.Services, r *hub.Repository) tracker.Tracker {
+	var t tracker.Tracker
+	switch r.Kind {
+	case hub.Falco:
+		// Temporary solution to maintain backwards compatibility with
+		// the only Falco rules repository registered at the moment in
+		// artifacthub.io using the structure and metadata format used
+		// by the cloud native security hub.
+		if r.URL == cloudNativeSecurityHub {
+			t = falco.NewTracker(svc, r)
+		} else {
+			t = generic.NewTracker(svc, r)
+		}
+	case hub.Helm:
+		t = helm.NewTracker(svc, r)
+	case hub.HelmPlugin:
+		t = helmplugin.NewTracker(svc, r)
+	case hub.Krew:
+		t = krew.NewTracker(svc, r)
+	case hub.OLM:
+		t = olm.NewTracker(svc, r)
+	case hub.OPA, hub.TBAction:
+		t = generic.NewTracker(svc, r)
+	}
+	return t
+}
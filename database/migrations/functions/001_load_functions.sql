@@ -1,5 +1,7 @@
 {{ template "repositories/get_repository_by_id.sql" }}
 {{ template "repositories/get_repository_summary.sql" }}
+{{ template "packages/get_package_summary.sql" }}
+{{ template "packages/resolve_package_dependency.sql" }}
 
 {{ template "api_keys/add_api_key.sql" }}
 {{ template "api_keys/delete_api_key.sql" }}
@@ -7,13 +9,20 @@
 {{ template "api_keys/get_user_api_keys.sql" }}
 {{ template "api_keys/update_api_key.sql" }}
 
+{{ template "events/get_events_to_stream.sql" }}
+{{ template "events/get_last_processed_event_id.sql" }}
 {{ template "events/get_pending_event.sql" }}
+{{ template "events/notify_event_processed.sql" }}
 
 {{ template "images/get_image.sql" }}
 {{ template "images/register_image.sql" }}
 
 {{ template "notifications/add_notification.sql" }}
+{{ template "notifications/get_pending_digest.sql" }}
 {{ template "notifications/get_pending_notification.sql" }}
+{{ template "notifications/mark_notification_as_dead.sql" }}
+{{ template "notifications/schedule_notification_retry.sql" }}
+{{ template "notifications/update_last_digest_sent_at.sql" }}
 {{ template "notifications/update_notification_status.sql" }}
 
 {{ template "organizations/add_organization_member.sql" }}
@@ -34,7 +43,8 @@
 {{ template "packages/get_harbor_replication_dump.sql" }}
 {{ template "packages/get_package.sql" }}
 {{ template "packages/get_package_changelog.sql" }}
-{{ template "packages/get_package_summary.sql" }}
+{{ template "packages/get_package_dependencies.sql" }}
+{{ template "packages/get_package_dependents.sql" }}
 {{ template "packages/get_packages_starred_by_user.sql" }}
 {{ template "packages/get_package_stars.sql" }}
 {{ template "packages/get_packages_stats.sql" }}
@@ -53,12 +63,17 @@
 {{ template "repositories/delete_repository.sql" }}
 {{ template "repositories/get_all_repositories.sql" }}
 {{ template "repositories/get_repositories_by_kind.sql" }}
+{{ template "repositories/get_repositories_to_track.sql" }}
 {{ template "repositories/get_repository_by_name.sql" }}
 {{ template "repositories/get_repository_packages_digest.sql" }}
+{{ template "repositories/get_repository_tracking_runs.sql" }}
 {{ template "repositories/get_org_repositories.sql" }}
 {{ template "repositories/get_user_repositories.sql" }}
+{{ template "repositories/request_repository_tracking.sql" }}
 {{ template "repositories/set_last_tracking_results.sql" }}
 {{ template "repositories/set_verified_publisher.sql" }}
+{{ template "repositories/set_repository_vulnerability_allowlist.sql" }}
+{{ template "repositories/start_repository_tracking.sql" }}
 {{ template "repositories/transfer_repository.sql" }}
 {{ template "repositories/update_repository.sql" }}
 
@@ -67,10 +82,14 @@
 {{ template "subscriptions/delete_opt_out.sql" }}
 {{ template "subscriptions/delete_subscription.sql" }}
 {{ template "subscriptions/get_package_subscriptors.sql" }}
+{{ template "subscriptions/get_repository_event_subscriptors.sql" }}
 {{ template "subscriptions/get_repository_subscriptors.sql" }}
 {{ template "subscriptions/get_user_opt_out_entries.sql" }}
+{{ template "subscriptions/get_user_organizations_subscriptions.sql" }}
 {{ template "subscriptions/get_user_package_subscriptions.sql" }}
+{{ template "subscriptions/get_user_repositories_subscriptions.sql" }}
 {{ template "subscriptions/get_user_subscriptions.sql" }}
+{{ template "subscriptions/unsubscribe.sql" }}
 
 {{ template "users/check_user_alias_availability.sql" }}
 {{ template "users/get_user_profile.sql" }}
@@ -81,11 +100,17 @@
 {{ template "users/verify_email.sql" }}
 
 {{ template "webhooks/add_webhook.sql" }}
+{{ template "webhooks/add_webhook_delivery.sql" }}
+{{ template "webhooks/delete_old_webhook_deliveries.sql" }}
 {{ template "webhooks/delete_webhook.sql" }}
 {{ template "webhooks/get_webhook.sql" }}
+{{ template "webhooks/get_webhooks_by_ids.sql" }}
+{{ template "webhooks/get_webhook_deliveries.sql" }}
 {{ template "webhooks/get_org_webhooks.sql" }}
 {{ template "webhooks/get_user_webhooks.sql" }}
 {{ template "webhooks/get_webhooks_subscribed_to_package.sql" }}
+{{ template "webhooks/get_webhooks_subscribed_to_repository.sql" }}
+{{ template "webhooks/redeliver_webhook_notification.sql" }}
 {{ template "webhooks/update_webhook.sql" }}
 {{ template "webhooks/user_has_access_to_webhook.sql" }}
 
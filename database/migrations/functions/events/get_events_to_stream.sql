@@ -0,0 +1,59 @@
+-- get_events_to_stream returns the processed events visible to the user
+-- provided, in the order their processing was committed. When an event id is provided in
+-- the input, only that event is returned (if visible). Otherwise, the events
+-- processed after the last event id provided are returned. Events are visible
+-- to the user when they have been notified about them (i.e. they are
+-- subscribed to them) or, when all events are requested, if they are public.
+-- The subscriptors stored in some events data are never returned.
+create or replace function get_events_to_stream(p_user_id uuid, p_input jsonb)
+returns setof json as $$
+declare
+    v_all boolean := coalesce((p_input->>'all')::boolean, false);
+    v_event_id uuid := nullif(p_input->>'event_id', '')::uuid;
+    v_last_event_id uuid := nullif(p_input->>'last_event_id', '')::uuid;
+    v_limit int := coalesce((p_input->>'limit')::int, 100);
+    v_last_processed_seq bigint;
+begin
+    if v_event_id is null then
+        select processed_seq into v_last_processed_seq
+        from event
+        where event_id = v_last_event_id
+        and processed_seq is not null;
+        if not found then
+            return query select '[]'::json;
+            return;
+        end if;
+    end if;
+
+    return query
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'event_id', event_id,
+        'event_kind', event_kind_id,
+        'repository_id', repository_id,
+        'package_id', package_id,
+        'package_version', package_version,
+        'data', data - 'subscriptors'
+    ))), '[]')
+    from (
+        select e.*
+        from event e
+        where e.processed_seq is not null
+        and (
+            (v_event_id is not null and e.event_id = v_event_id)
+            or
+            (v_event_id is null and e.processed_seq > v_last_processed_seq)
+        )
+        and (
+            exists (
+                select 1
+                from notification n
+                where n.event_id = e.event_id
+                and n.user_id = p_user_id
+            )
+            or (v_all and e.event_kind_id in (0, 1, 4, 5, 6, 7))
+        )
+        order by e.processed_seq asc
+        limit v_limit
+    ) events;
+end
+$$ language plpgsql;
@@ -0,0 +1,9 @@
+-- get_last_processed_event_id returns the id of the last event processed.
+create or replace function get_last_processed_event_id()
+returns uuid as $$
+    select event_id
+    from event
+    where processed_seq is not null
+    order by processed_seq desc
+    limit 1;
+$$ language sql;
@@ -0,0 +1,15 @@
+-- notify_event_processed assigns the event provided the next position in the
+-- processed events sequence and notifies the listeners of the event_processed
+-- channel. It must be called right before committing the transaction in which
+-- the event was processed. A transaction level lock is held until then, so that
+-- positions are assigned in the same order the transactions are committed and
+-- streams resuming from a given event never miss events committed later.
+create or replace function notify_event_processed(p_event_id uuid)
+returns void as $$
+begin
+    perform pg_advisory_xact_lock(hashtext('event_processed_seq'));
+    update event set processed_seq = nextval('event_processed_seq')
+    where event_id = p_event_id;
+    perform pg_notify('event_processed', p_event_id::text);
+end
+$$ language plpgsql;
@@ -0,0 +1,72 @@
+-- get_pending_digest returns the notifications pending to be delivered to a
+-- user who prefers to receive them grouped in a digest, if the user's digest
+-- is due. The user's row is locked until the transaction is finished. The
+-- user's last digest timestamp is not updated here, as it must only advance
+-- once the digest has been sent successfully (update_last_digest_sent_at).
+create or replace function get_pending_digest()
+returns setof json as $$
+declare
+    v_user_id uuid;
+    v_digest json;
+begin
+    -- Get user whose digest is due, if any
+    select u.user_id into v_user_id
+    from "user" u
+    where (
+        (
+            u.email_delivery_preference_id = 1
+            and (u.last_digest_sent_at is null or u.last_digest_sent_at <= current_timestamp - '1 day'::interval)
+        )
+        or (
+            u.email_delivery_preference_id = 2
+            and (u.last_digest_sent_at is null or u.last_digest_sent_at <= current_timestamp - '1 week'::interval)
+        )
+    )
+    and exists (
+        select 1
+        from notification n
+        where n.user_id = u.user_id
+        and n.processed = false
+        and (n.next_attempt_at is null or n.next_attempt_at <= current_timestamp)
+    )
+    for update of u skip locked
+    limit 1;
+    if not found then
+        return;
+    end if;
+
+    -- Prepare digest
+    select json_build_object(
+        'user', (
+            select json_build_object(
+                'user_id', u.user_id,
+                'email', u.email,
+                'email_delivery_preference', u.email_delivery_preference_id
+            )
+            from "user" u
+            where u.user_id = v_user_id
+        ),
+        'notifications', (
+            select json_agg(json_build_object(
+                'notification_id', n.notification_id,
+                'attempts', n.attempts,
+                'event', json_strip_nulls(json_build_object(
+                    'event_id', e.event_id,
+                    'event_kind', e.event_kind_id,
+                    'repository_id', e.repository_id,
+                    'package_id', e.package_id,
+                    'package_version', e.package_version,
+                    'data', e.data
+                ))
+            ) order by n.created_at asc)
+            from notification n
+            join event e using (event_id)
+            where n.user_id = v_user_id
+            and n.processed = false
+            and (n.next_attempt_at is null or n.next_attempt_at <= current_timestamp)
+        )
+    ) into v_digest;
+
+    return query select v_digest;
+end
+$$ language plpgsql;
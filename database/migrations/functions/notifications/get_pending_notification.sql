@@ -1,30 +1,40 @@
 -- get_pending_notification returns a pending notification if available.
+-- Notifications whose delivery has failed are not returned until the next
+-- attempt is due.
+-- Notifications of users who prefer to receive them grouped in a digest are
+-- not returned either, as they are delivered by get_pending_digest.
 create or replace function get_pending_notification()
 returns setof json as $$
     select json_strip_nulls(json_build_object(
         'notification_id', n.notification_id,
+        'attempts', n.attempts,
         'event', json_build_object(
             'event_id', e.event_id,
             'event_kind', e.event_kind_id,
             'repository_id', e.repository_id,
             'package_id', e.package_id,
-            'package_version', e.package_version
+            'package_version', e.package_version,
+            'data', e.data
         ),
         'user', (select nullif(
             jsonb_build_object(
+                'user_id', u.user_id,
                 'email', u.email
             ),
-            '{"email": null}'::jsonb
+            '{"user_id": null, "email": null}'::jsonb
         )),
         'webhook', (select nullif(
             jsonb_build_object(
                 'name', wh.name,
                 'url', wh.url,
                 'secret', wh.secret,
+                'secondary_secret', wh.secondary_secret,
+                'sign_payloads', wh.sign_payloads,
+                'channel', wh.webhook_channel_id,
                 'content_type', wh.content_type,
                 'template', wh.template
             ),
-            '{"name": null, "url": null, "secret": null, "content_type": null, "template": null}'::jsonb
+            '{"name": null, "url": null, "secret": null, "secondary_secret": null, "sign_payloads": null, "channel": null, "content_type": null, "template": null}'::jsonb
         ))
     ))
     from notification n
@@ -32,6 +42,8 @@ returns setof json as $$
     left join "user" u using (user_id)
     left join webhook wh using (webhook_id)
     where n.processed = false
+    and (n.next_attempt_at is null or n.next_attempt_at <= current_timestamp)
+    and (n.user_id is null or u.email_delivery_preference_id = 0)
     for update of n skip locked
     limit 1;
 $$ language sql;
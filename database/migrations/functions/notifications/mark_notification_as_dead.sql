@@ -0,0 +1,13 @@
+-- mark_notification_as_dead registers the last failed delivery attempt of the
+-- provided notification, marking it as dead so that it's not retried again.
+create or replace function mark_notification_as_dead(p_notification_id uuid, p_error text)
+returns void as $$
+    update notification set
+        processed = true,
+        processed_at = current_timestamp,
+        attempts = attempts + 1,
+        next_attempt_at = null,
+        dead = true,
+        error = nullif(p_error, '')
+    where notification_id = p_notification_id;
+$$ language sql;
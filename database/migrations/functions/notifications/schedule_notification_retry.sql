@@ -0,0 +1,14 @@
+-- schedule_notification_retry registers a failed delivery attempt of the
+-- provided notification, scheduling the next attempt after the delay provided
+-- (in seconds).
+create or replace function schedule_notification_retry(
+    p_notification_id uuid,
+    p_delay integer,
+    p_error text
+) returns void as $$
+    update notification set
+        attempts = attempts + 1,
+        next_attempt_at = current_timestamp + make_interval(secs => p_delay),
+        error = nullif(p_error, '')
+    where notification_id = p_notification_id;
+$$ language sql;
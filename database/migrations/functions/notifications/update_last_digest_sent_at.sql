@@ -0,0 +1,8 @@
+-- update_last_digest_sent_at records that a notifications digest has just been
+-- sent successfully to the provided user.
+create or replace function update_last_digest_sent_at(p_user_id uuid)
+returns void as $$
+    update "user" set
+        last_digest_sent_at = current_timestamp
+    where user_id = p_user_id;
+$$ language sql;
@@ -7,6 +7,8 @@ create or replace function update_notification_status(
     update notification set
         processed = p_processed,
         processed_at = current_timestamp,
+        attempts = attempts + 1,
+        next_attempt_at = null,
         error = nullif(p_error, '')
     where notification_id = p_notification_id;
 $$ language sql;
@@ -57,6 +57,8 @@ begin
         'prerelease', s.prerelease,
         'license', s.license,
         'signed', s.signed,
+        'provenance_verification', s.provenance_verification,
+        'cosign_verification', s.cosign_verification,
         'content_url', s.content_url,
         'containers_images', s.containers_images,
         'provider', s.provider,
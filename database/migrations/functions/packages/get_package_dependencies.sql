@@ -0,0 +1,17 @@
+-- get_package_dependencies returns the dependencies of the package's snapshot
+-- identified by the package id and version provided as a json array. When a
+-- dependency refers to a package registered in the hub, some details of that
+-- package are included as well.
+create or replace function get_package_dependencies(p_package_id uuid, p_version text)
+returns setof json as $$
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'name', d.name,
+        'version', d.version_constraint,
+        'repository_url', d.repository_url,
+        'alias', d.alias,
+        'package', (select get_package_summary(resolve_package_dependency(d.name, d.repository_url)))
+    )) order by d.name asc, d.alias asc), '[]')
+    from snapshot_dependency d
+    where d.package_id = p_package_id
+    and d.version = p_version;
+$$ language sql;
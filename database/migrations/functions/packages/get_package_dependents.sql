@@ -0,0 +1,20 @@
+-- get_package_dependents returns the packages whose latest version depends on
+-- the package provided as a json array. The details of the dependency are
+-- included for each of them.
+create or replace function get_package_dependents(p_package_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'package', (select get_package_summary(dp.package_id)),
+        'dependency', json_strip_nulls(json_build_object(
+            'name', d.name,
+            'version', d.version_constraint,
+            'repository_url', d.repository_url,
+            'alias', d.alias
+        ))
+    ) order by dp.name asc, dp.package_id asc), '[]')
+    from package p
+    join snapshot_dependency d on d.name = p.name
+    join package dp on dp.package_id = d.package_id and dp.latest_version = d.version
+    where p.package_id = p_package_id
+    and resolve_package_dependency(d.name, d.repository_url) = p.package_id;
+$$ language sql;
@@ -1,22 +1,59 @@
 -- get_snapshots_to_scan returns the snapshots to scan for security
--- vulnerabilities as a json array.
-create or replace function get_snapshots_to_scan()
+-- vulnerabilities as a json array. Snapshots that have never been scanned are
+-- returned, as well as the ones whose security report is older than the
+-- maximum age provided (in minutes). Packages' latest versions are returned
+-- first. The vulnerability allowlist returned for each snapshot includes the
+-- entries defined at the repository level as well as the package's ones.
+-- Snapshots are also scanned again when their vulnerability allowlist has
+-- changed or any of its entries has expired since the last scan, so that the
+-- vulnerabilities suppressed in the security report are kept up to date.
+create or replace function get_snapshots_to_scan(p_report_max_age integer)
 returns setof json as $$
     select coalesce(json_agg(json_build_object(
         'package_id', package_id,
+        'package_name', name,
         'version', version,
+        'license', license,
         'containers_images', jsonb_path_query_array(
             containers_images,
             '$[*] ? (!exists(@.whitelisted) || @.whitelisted <> true)'
-        )
+        ),
+        'dependencies', data->'dependencies',
+        'vulnerability_allowlist', vulnerability_allowlist
     )), '[]')
     from (
-        select s.package_id, s.version, s.containers_images
+        select
+            s.package_id,
+            p.name,
+            s.version,
+            s.license,
+            s.containers_images,
+            s.data,
+            va.vulnerability_allowlist
         from snapshot s
         join package p using (package_id)
         join repository r using (repository_id)
-        where (security_report is null or security_report_created_at < (current_timestamp - '1 week'::interval))
+        cross join lateral (
+            select nullif(
+                coalesce(r.vulnerability_allowlist, '[]') || coalesce(s.vulnerability_allowlist, '[]'),
+                '[]'
+            ) as vulnerability_allowlist
+        ) va
+        where (
+            security_report is null
+            or security_report_created_at < (current_timestamp - make_interval(mins => p_report_max_age))
+            or s.security_report_allowlist is distinct from va.vulnerability_allowlist
+            or exists (
+                select 1
+                from jsonb_array_elements(s.security_report_allowlist) e
+                where ((e->>'expires_at')::date + 1)::timestamp at time zone 'UTC'
+                    between s.security_report_created_at and current_timestamp
+            )
+        )
         and r.scanner_disabled = false
-        order by s.created_at desc
+        order by
+            (s.version = p.latest_version) desc,
+            security_report_created_at asc nulls first,
+            s.created_at desc
     ) s;
 $$ language sql;
@@ -1,12 +1,13 @@
 -- register_package registers the provided package in the database. This
 -- involves registering or updating the package entity when needed, registering
--- a snapshot for the package version and creating/updating/deleting the
--- package maintainers as needed depending on the ones present in the latest
--- package version.
+-- a snapshot for the package version (including its dependencies) and
+-- creating/updating/deleting the package maintainers as needed depending on
+-- the ones present in the latest package version.
 create or replace function register_package(p_pkg jsonb)
 returns void as $$
 declare
     v_previous_latest_version text;
+    v_previously_deprecated boolean;
     v_package_id uuid;
     v_name text := p_pkg->>'name';
     v_display_name text := nullif(p_pkg->>'display_name', '');
@@ -38,11 +39,14 @@ begin
         raise 'repository is disabled';
     end if;
 
-    -- Get package's latest version before registration, if available
-    select latest_version into v_previous_latest_version
-    from package
-    where name = v_name
-    and repository_id = v_repository_id;
+    -- Get package's latest version before registration, if available, and
+    -- whether it was deprecated or not
+    select p.latest_version, s.deprecated
+    into v_previous_latest_version, v_previously_deprecated
+    from package p
+    left join snapshot s on s.package_id = p.package_id and s.version = p.latest_version
+    where p.name = v_name
+    and p.repository_id = v_repository_id;
 
     -- Package
     insert into package (
@@ -151,6 +155,8 @@ begin
         deprecated,
         license,
         signed,
+        provenance_verification,
+        cosign_verification,
         content_url,
         containers_images,
         provider,
@@ -158,6 +164,7 @@ begin
         changes,
         contains_security_updates,
         prerelease,
+        vulnerability_allowlist,
         created_at
     ) values (
         v_package_id,
@@ -178,6 +185,8 @@ begin
         (p_pkg->>'deprecated')::boolean,
         nullif(p_pkg->>'license', ''),
         (p_pkg->>'signed')::boolean,
+        nullif(p_pkg->'provenance_verification', 'null'),
+        nullif(p_pkg->'cosign_verification', 'null'),
         nullif(p_pkg->>'content_url', ''),
         nullif(p_pkg->'containers_images', 'null'),
         v_provider,
@@ -185,6 +194,7 @@ begin
         v_changes,
         (p_pkg->>'contains_security_updates')::boolean,
         (p_pkg->>'prerelease')::boolean,
+        nullif(nullif(p_pkg->'vulnerability_allowlist', 'null'), '[]'),
         v_created_at
     )
     on conflict (package_id, version) do update
@@ -205,6 +215,8 @@ begin
         deprecated = excluded.deprecated,
         license = excluded.license,
         signed = excluded.signed,
+        provenance_verification = excluded.provenance_verification,
+        cosign_verification = excluded.cosign_verification,
         content_url = excluded.content_url,
         containers_images = excluded.containers_images,
         provider = excluded.provider,
@@ -212,12 +224,50 @@ begin
         changes = excluded.changes,
         contains_security_updates = excluded.contains_security_updates,
         prerelease = excluded.prerelease,
+        vulnerability_allowlist = excluded.vulnerability_allowlist,
         created_at = v_created_at;
 
+    -- Package snapshot dependencies
+    delete from snapshot_dependency
+    where package_id = v_package_id
+    and version = v_version;
+    insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, alias)
+    select
+        v_package_id,
+        v_version,
+        d->>'name',
+        nullif(d->>'version', ''),
+        nullif(d->>'repository', ''),
+        nullif(d->>'alias', '')
+    from jsonb_array_elements(nullif(p_pkg->'data'->'dependencies', 'null')) as d
+    where nullif(d->>'name', '') is not null;
+
     -- Register new release event if package's latest version has been updated
+    -- (some release details are included so that subscriptions filters can be
+    -- evaluated when the event is processed)
     if semver_gt(v_version, v_previous_latest_version) then
+        insert into event (package_id, package_version, event_kind_id, data)
+        values (v_package_id, v_version, 0, jsonb_build_object(
+            'previous_version', v_previous_latest_version,
+            'prerelease', coalesce((p_pkg->>'prerelease')::boolean, false),
+            'contains_security_updates', coalesce((p_pkg->>'contains_security_updates')::boolean, false)
+        ));
+    end if;
+
+    -- Register package deprecated event if the package's latest version is
+    -- now deprecated and it wasn't before this registration
+    if v_previous_latest_version is not null
+    and semver_gte(v_version, v_previous_latest_version)
+    and coalesce((p_pkg->>'deprecated')::boolean, false) = true
+    and coalesce(v_previously_deprecated, false) = false then
         insert into event (package_id, package_version, event_kind_id)
-        values (v_package_id, v_version, 0);
+        values (v_package_id, v_version, 5);
     end if;
+
+    -- Update repository tracking run in progress stats
+    update repository_tracking_run set
+        packages_registered = packages_registered + 1
+    where repository_id = v_repository_id
+    and finished_at is null;
 end
 $$ language plpgsql;
@@ -0,0 +1,19 @@
+-- resolve_package_dependency returns the id of the Helm package registered in
+-- the hub that the chart dependency provided refers to, if any. Dependencies
+-- are matched by name and repository url. OCI dependencies reference the
+-- registry path where the chart is located, so the chart name is appended to
+-- it before comparing it with the repository url.
+create or replace function resolve_package_dependency(p_name text, p_repository_url text)
+returns uuid as $$
+    select p.package_id
+    from package p
+    join repository r using (repository_id)
+    where p.name = p_name
+    and r.repository_kind_id = 0
+    and trim(trailing '/' from r.url) in (
+        trim(trailing '/' from p_repository_url),
+        trim(trailing '/' from p_repository_url) || '/' || p_name
+    )
+    order by r.verified_publisher desc, r.official desc, r.created_at asc
+    limit 1;
+$$ language sql;
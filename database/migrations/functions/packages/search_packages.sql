@@ -90,6 +90,15 @@ begin
             else
                 true
             end
+        and
+            case when p_input ? 'verified_signature' and (p_input->>'verified_signature')::boolean = true then
+                (
+                    coalesce((s.provenance_verification->>'verified')::boolean, false) = true
+                    or coalesce((s.cosign_verification->>'verified')::boolean, false) = true
+                )
+            else
+                true
+            end
         and
             case when p_input ? 'deprecated' and (p_input->>'deprecated')::boolean = true then
                 true
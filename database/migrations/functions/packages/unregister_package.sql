@@ -1,10 +1,12 @@
--- unregister_package unregisters the provided package version from the database.
+-- unregister_package unregisters the provided package version from the
+-- database, registering a package version removed event.
 create or replace function unregister_package(p_pkg jsonb)
 returns void as $$
 declare
     v_package_id uuid;
     v_latest_version text;
     v_snapshots_count int;
+    v_event_data jsonb;
     v_semver_regexp text := '(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?';
 begin
     -- Get package id and latest version and lock it
@@ -23,6 +25,20 @@ begin
         return;
     end if;
 
+    -- Prepare package version removed event data. We need to store the package
+    -- subscriptors and the ids of the webhooks subscribed to it before the
+    -- version is removed, as the package, its subscriptions and the webhooks
+    -- links to it will be deleted if this is the only version available.
+    v_event_data := jsonb_build_object(
+        'package_name', p_pkg->>'name',
+        'package_normalized_name', (select normalized_name from package where package_id = v_package_id),
+        'subscriptors', (select get_package_subscriptors(v_package_id, 4)),
+        'webhooks', (
+            select jsonb_agg(wh->'webhook_id')
+            from jsonb_array_elements(get_webhooks_subscribed_to_package(4, v_package_id)::jsonb) as wh
+        )
+    );
+
     -- If the version to delete is the only one available we delete the package
     -- (some other elements will be deleted on cascade)
     if v_snapshots_count = 1 then
@@ -32,6 +48,16 @@ begin
         delete from maintainer where maintainer_id not in (
             select maintainer_id from package__maintainer
         );
+
+        -- Register package version removed event (the event is not linked to
+        -- the package as it has just been deleted)
+        insert into event (repository_id, package_version, event_kind_id, data)
+        values (
+            ((p_pkg->'repository')->>'repository_id')::uuid,
+            p_pkg->>'version',
+            4,
+            v_event_data
+        );
     else
         -- If the version to delete is the last version, we need to update the
         -- package's latest version
@@ -51,6 +77,22 @@ begin
 
         -- Delete version snapshot
         delete from snapshot where package_id = v_package_id and version = p_pkg->>'version';
+
+        -- Register package version removed event
+        insert into event (repository_id, package_id, package_version, event_kind_id, data)
+        values (
+            ((p_pkg->'repository')->>'repository_id')::uuid,
+            v_package_id,
+            p_pkg->>'version',
+            4,
+            v_event_data
+        );
     end if;
+
+    -- Update repository tracking run in progress stats
+    update repository_tracking_run set
+        packages_unregistered = packages_unregistered + 1
+    where repository_id = ((p_pkg->'repository')->>'repository_id')::uuid
+    and finished_at is null;
 end
 $$ language plpgsql;
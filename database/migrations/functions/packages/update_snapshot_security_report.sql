@@ -1,11 +1,51 @@
--- update_snapshot_security_report updates the security report of the package's
--- snapshot provides.
+-- update_snapshot_security_report updates the security report and the software
+-- bill of materials of the package's snapshot provided, as well as the
+-- vulnerability allowlist applied to the report. When a snapshot that
+-- had already been scanned is scanned again and new critical or high
+-- vulnerabilities are found, a security alert event is registered.
+-- Vulnerabilities suppressed by the vulnerability allowlist are not considered
+-- when looking for new ones. When there is no previous security report (i.e.
+-- the previous scan failed), the new report is used as the baseline and no
+-- alert is registered.
 create or replace function update_snapshot_security_report(p_report jsonb)
 returns void as $$
+declare
+    v_package_id uuid := (p_report->>'package_id')::uuid;
+    v_version text := p_report->>'version';
+    v_vulnerabilities_path jsonpath := '$.*[*].Vulnerabilities[*] ? ((@.Severity == "CRITICAL" || @.Severity == "HIGH") && !exists(@.Suppression)).VulnerabilityID';
+    v_prev_security_report jsonb;
+    v_new_vulnerabilities jsonb;
+begin
+    -- Get previous security report
+    select security_report into v_prev_security_report
+    from snapshot
+    where package_id = v_package_id
+    and version = v_version
+    for update;
+
+    -- Update security report and sbom
     update snapshot set
         security_report = p_report->'full',
         security_report_summary = p_report->'summary',
-        security_report_created_at = current_timestamp
-    where package_id = (p_report->>'package_id')::uuid
-    and version = p_report->>'version';
-$$ language sql;
+        security_report_created_at = current_timestamp,
+        security_report_allowlist = nullif(nullif(p_report->'vulnerability_allowlist', 'null'), '[]'),
+        sbom = p_report->'sbom'
+    where package_id = v_package_id
+    and version = v_version;
+
+    -- Register security alert event if new critical or high vulnerabilities
+    -- have been found since the previous scan
+    if jsonb_typeof(v_prev_security_report) = 'object' then
+        select jsonb_agg(vulnerability_id order by vulnerability_id) into v_new_vulnerabilities
+        from (
+            select jsonb_path_query(p_report->'full', v_vulnerabilities_path) #>> '{}' as vulnerability_id
+            except
+            select jsonb_path_query(v_prev_security_report, v_vulnerabilities_path) #>> '{}'
+        ) nv;
+        if v_new_vulnerabilities is not null then
+            insert into event (package_id, package_version, event_kind_id, data)
+            values (v_package_id, v_version, 1, jsonb_build_object('vulnerabilities', v_new_vulnerabilities));
+        end if;
+    end if;
+end
+$$ language plpgsql;
@@ -0,0 +1,23 @@
+-- get_repositories_to_track returns the repositories that should be tracked
+-- now as a json array. A repository should be tracked when it has never been
+-- tracked, when its tracking interval (in minutes) has elapsed since it was
+-- tracked last time or when a tracking request has been queued for it. The
+-- default tracking interval provided is used for repositories without one.
+create or replace function get_repositories_to_track(p_default_tracking_interval integer)
+returns setof json as $$
+    select coalesce(json_agg(rJSON), '[]')
+    from (
+        select rJSON
+        from repository r
+        cross join get_repository_by_id(r.repository_id, true) as rJSON
+        where r.disabled = false
+        and (
+            r.tracking_requested_at is not null
+            or r.last_tracking_ts is null
+            or r.last_tracking_ts + make_interval(
+                mins => coalesce(r.tracking_interval, p_default_tracking_interval)
+            ) <= current_timestamp
+        )
+        order by r.tracking_requested_at asc nulls last, r.last_tracking_ts asc nulls first
+    ) rs;
+$$ language sql;
@@ -21,6 +21,9 @@ begin
             'digest', r.digest,
             'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
             'last_tracking_errors', r.last_tracking_errors,
+            'tracking_interval', r.tracking_interval,
+            'vulnerability_allowlist', r.vulnerability_allowlist,
+            'signing_keys_digest', r.signing_keys_digest,
             'user_alias', u.alias,
             'organization_name', o.name,
             'organization_display_name', o.display_name
@@ -45,6 +48,8 @@ begin
             'digest', r.digest,
             'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
             'last_tracking_errors', r.last_tracking_errors,
+            'tracking_interval', r.tracking_interval,
+            'vulnerability_allowlist', r.vulnerability_allowlist,
             'user_alias', u.alias,
             'organization_name', o.name,
             'organization_display_name', o.display_name
@@ -0,0 +1,50 @@
+-- get_repository_tracking_runs returns the most recent tracking runs of the
+-- provided repository as a json array. The user provided must be the owner of
+-- the repository or belong to the organization which owns it. An error is
+-- raised when the repository does not exist.
+create or replace function get_repository_tracking_runs(p_user_id uuid, p_repository_name text)
+returns setof json as $$
+declare
+    v_repository_id uuid;
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+begin
+    -- Get user or organization owning the repository
+    select r.repository_id, r.user_id, o.name
+    into v_repository_id, v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+    if not found then
+        raise no_data_found;
+    end if;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select coalesce(json_agg(json_build_object(
+        'tracking_run_id', repository_tracking_run_id,
+        'started_at', floor(extract(epoch from started_at)),
+        'finished_at', floor(extract(epoch from finished_at)),
+        'packages_registered', packages_registered,
+        'packages_unregistered', packages_unregistered,
+        'digest_before', digest_before,
+        'digest_after', digest_after,
+        'warnings', warnings
+    )), '[]')
+    from (
+        select *
+        from repository_tracking_run
+        where repository_id = v_repository_id
+        order by started_at desc
+    ) rtr;
+end
+$$ language plpgsql;
@@ -1,5 +1,5 @@
 -- set_last_tracking_results updates the timestamp and errors of the last
--- tracking.
+-- tracking, finishing the tracking run in progress if there is one.
 create or replace function set_last_tracking_results(
     p_repository_id uuid,
     p_last_tracking_errors text,
@@ -26,5 +26,13 @@ begin
 		last_tracking_ts = current_timestamp,
 		last_tracking_errors = v_last_tracking_errors
 	where repository_id = p_repository_id;
+
+    -- Finish tracking run in progress
+    update repository_tracking_run set
+        finished_at = current_timestamp,
+        digest_after = (select digest from repository where repository_id = p_repository_id),
+        warnings = v_last_tracking_errors
+    where repository_id = p_repository_id
+    and finished_at is null;
 end
 $$ language plpgsql;
@@ -0,0 +1,8 @@
+-- set_repository_vulnerability_allowlist updates the vulnerability allowlist
+-- of the provided repository.
+create or replace function set_repository_vulnerability_allowlist(p_repository_id uuid, p_allowlist jsonb)
+returns void as $$
+    update repository set
+        vulnerability_allowlist = nullif(nullif(p_allowlist, 'null'), '[]')
+    where repository_id = p_repository_id;
+$$ language sql;
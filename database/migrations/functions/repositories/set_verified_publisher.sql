@@ -1,8 +1,19 @@
 -- set_verified_publisher updates the verified publisher flag of the provided
--- repository.
+-- repository, registering a repository verified publisher changed event when
+-- the flag's value changes.
 create or replace function set_verified_publisher(p_repository_id uuid, p_verified boolean)
 returns void as $$
+begin
     update repository set
         verified_publisher = p_verified
-    where repository_id = p_repository_id;
-$$ language sql;
+    where repository_id = p_repository_id
+    and verified_publisher <> p_verified;
+
+    if found then
+        insert into event (repository_id, event_kind_id, data)
+        values (p_repository_id, 7, jsonb_build_object(
+            'verified_publisher', p_verified
+        ));
+    end if;
+end
+$$ language plpgsql;
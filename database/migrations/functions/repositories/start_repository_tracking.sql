@@ -0,0 +1,36 @@
+-- start_repository_tracking registers that the tracking of the provided
+-- repository has started, clearing any pending tracking request. A new
+-- tracking run is registered as well, keeping only the most recent ones.
+create or replace function start_repository_tracking(p_repository_id uuid)
+returns void as $$
+begin
+    -- Clear pending tracking request
+    update repository set
+        tracking_requested_at = null
+    where repository_id = p_repository_id;
+
+    -- Finish previous runs left unfinished (i.e. the tracker was stopped while
+    -- the repository was being tracked)
+    update repository_tracking_run set
+        finished_at = current_timestamp
+    where repository_id = p_repository_id
+    and finished_at is null;
+
+    -- Register new tracking run
+    insert into repository_tracking_run (repository_id, digest_before)
+    select repository_id, digest
+    from repository
+    where repository_id = p_repository_id;
+
+    -- Delete old tracking runs
+    delete from repository_tracking_run
+    where repository_id = p_repository_id
+    and repository_tracking_run_id not in (
+        select repository_tracking_run_id
+        from repository_tracking_run
+        where repository_id = p_repository_id
+        order by started_at desc
+        limit 50
+    );
+end
+$$ language plpgsql;
@@ -2,7 +2,8 @@
 -- to the requesting user or an organization he belongs to. The user must own
 -- the repository transferred or belong to the organization which owns it,
 -- unless this transfer is part of an ownership claim request that has been
--- previously authorized.
+-- previously authorized. A repository transferred event is registered for
+-- every transfer.
 create or replace function transfer_repository(
     p_repository_name text,
     p_user_id uuid,
@@ -12,6 +13,7 @@ create or replace function transfer_repository(
 declare
     v_owner_user_id uuid;
     v_owner_organization_name text;
+    v_previous_owner jsonb;
 begin
     -- Validate repository ownership unless this transfer is part of an
     -- ownership claim request
@@ -50,6 +52,25 @@ begin
         from repository where name = p_repository_name;
     end if;
 
+    -- Register repository transferred event
+    -- The previous owner and the subscriptors are stored before the transfer,
+    -- as users subscribed through the organization which owned the repository
+    -- should be notified as well.
+    select jsonb_strip_nulls(jsonb_build_object(
+        'user_alias', u.alias,
+        'organization_name', o.name
+    )) into v_previous_owner
+    from repository r
+    left join "user" u using (user_id)
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+    insert into event (repository_id, event_kind_id, data)
+    select repository_id, 6, jsonb_build_object(
+        'previous_owner', v_previous_owner,
+        'subscriptors', get_repository_event_subscriptors(repository_id, 6)
+    )
+    from repository where name = p_repository_name;
+
     -- Transfer repository ownership
     if p_org_name is null then
         update repository set
@@ -38,7 +38,8 @@ begin
         auth_user = nullif(p_repository->>'auth_user', ''),
         auth_pass = nullif(p_repository->>'auth_pass', ''),
         disabled = (p_repository->>'disabled')::boolean,
-        scanner_disabled = (p_repository->>'scanner_disabled')::boolean
+        scanner_disabled = (p_repository->>'scanner_disabled')::boolean,
+        tracking_interval = nullif((p_repository->>'tracking_interval')::int, 0)
     where repository_id = v_repository_id;
 
     -- If the repository has been disabled, remove packages belonging to it
@@ -1,13 +1,59 @@
--- add_subscription adds the provided subscription to the database.
+-- add_subscription adds the provided subscription to the database. The
+-- subscription target can be a package, a repository or an organization. If
+-- the subscription already exists, its filter is updated. Subscribing to a
+-- package again removes any previous opt-out from its notifications.
 create or replace function add_subscription(p_subscription jsonb)
 returns void as $$
-    insert into subscription (
-        user_id,
-        package_id,
-        event_kind_id
-    ) values (
-        (p_subscription->>'user_id')::uuid,
-        (p_subscription->>'package_id')::uuid,
-        (p_subscription->>'event_kind')::int
-    );
-$$ language sql;
+declare
+    v_filter jsonb := nullif(p_subscription->'filter', 'null'::jsonb);
+begin
+    if p_subscription->>'repository_id' is not null then
+        insert into repository_subscription (
+            user_id,
+            repository_id,
+            event_kind_id,
+            filter
+        ) values (
+            (p_subscription->>'user_id')::uuid,
+            (p_subscription->>'repository_id')::uuid,
+            (p_subscription->>'event_kind')::int,
+            v_filter
+        )
+        on conflict (user_id, repository_id, event_kind_id) do update
+        set filter = excluded.filter;
+    elsif p_subscription->>'organization_name' is not null then
+        insert into organization_subscription (
+            user_id,
+            organization_id,
+            event_kind_id,
+            filter
+        ) values (
+            (p_subscription->>'user_id')::uuid,
+            (select organization_id from organization where name = p_subscription->>'organization_name'),
+            (p_subscription->>'event_kind')::int,
+            v_filter
+        )
+        on conflict (user_id, organization_id, event_kind_id) do update
+        set filter = excluded.filter;
+    else
+        insert into subscription (
+            user_id,
+            package_id,
+            event_kind_id,
+            filter
+        ) values (
+            (p_subscription->>'user_id')::uuid,
+            (p_subscription->>'package_id')::uuid,
+            (p_subscription->>'event_kind')::int,
+            v_filter
+        )
+        on conflict (user_id, package_id, event_kind_id) do update
+        set filter = excluded.filter;
+
+        delete from package_opt_out
+        where user_id = (p_subscription->>'user_id')::uuid
+        and package_id = (p_subscription->>'package_id')::uuid
+        and event_kind_id = (p_subscription->>'event_kind')::int;
+    end if;
+end
+$$ language plpgsql;
@@ -1,8 +1,25 @@
 -- delete_subscription deletes the provided subscription from the database.
+-- The subscription target can be a package, a repository or an organization.
 create or replace function delete_subscription(p_subscription jsonb)
 returns void as $$
-    delete from subscription
-    where user_id = (p_subscription->>'user_id')::uuid
-    and package_id = (p_subscription->>'package_id')::uuid
-    and event_kind_id = (p_subscription->>'event_kind')::int;
-$$ language sql;
+begin
+    if p_subscription->>'repository_id' is not null then
+        delete from repository_subscription
+        where user_id = (p_subscription->>'user_id')::uuid
+        and repository_id = (p_subscription->>'repository_id')::uuid
+        and event_kind_id = (p_subscription->>'event_kind')::int;
+    elsif p_subscription->>'organization_name' is not null then
+        delete from organization_subscription
+        where user_id = (p_subscription->>'user_id')::uuid
+        and organization_id = (
+            select organization_id from organization where name = p_subscription->>'organization_name'
+        )
+        and event_kind_id = (p_subscription->>'event_kind')::int;
+    else
+        delete from subscription
+        where user_id = (p_subscription->>'user_id')::uuid
+        and package_id = (p_subscription->>'package_id')::uuid
+        and event_kind_id = (p_subscription->>'event_kind')::int;
+    end if;
+end
+$$ language plpgsql;
@@ -1,12 +1,55 @@
 -- get_package_subscriptors returns the users subscribed to the package
--- provided for the given event kind.
+-- provided for the given event kind. Users subscribed to the repository or the
+-- organization the package belongs to are also returned, unless they have
+-- opted out of notifications for the package or the package's repository and
+-- event kind.
+-- When a user is subscribed through more than one target, the filter of the
+-- most specific subscription (package, repository, organization) is returned.
 create or replace function get_package_subscriptors(p_package_id uuid, p_event_kind int)
 returns setof json as $$
-    select coalesce(json_agg(json_build_object(
-        'user_id', u.user_id
-    )), '[]')
-    from subscription s
-    join "user" u using (user_id)
-    where s.package_id = p_package_id
-    and s.event_kind_id = p_event_kind;
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'user_id', user_id,
+        'filter', filter
+    ))), '[]')
+    from (
+        select distinct on (user_id) user_id, filter
+        from (
+            select s.user_id, s.filter, 0 as priority
+            from subscription s
+            where s.package_id = p_package_id
+            and s.event_kind_id = p_event_kind
+            union all
+            select scoped.user_id, scoped.filter, scoped.priority
+            from (
+                select rs.user_id, r.repository_id, rs.filter, 1 as priority
+                from package p
+                join repository r using (repository_id)
+                join repository_subscription rs using (repository_id)
+                where p.package_id = p_package_id
+                and rs.event_kind_id = p_event_kind
+                union all
+                select os.user_id, r.repository_id, os.filter, 2 as priority
+                from package p
+                join repository r using (repository_id)
+                join organization_subscription os using (organization_id)
+                where p.package_id = p_package_id
+                and os.event_kind_id = p_event_kind
+            ) scoped
+            where not exists (
+                select 1
+                from opt_out oo
+                where oo.user_id = scoped.user_id
+                and oo.repository_id = scoped.repository_id
+                and oo.event_kind_id = p_event_kind
+            )
+            and not exists (
+                select 1
+                from package_opt_out poo
+                where poo.user_id = scoped.user_id
+                and poo.package_id = p_package_id
+                and poo.event_kind_id = p_event_kind
+            )
+        ) subscriptions
+        order by user_id asc, priority asc
+    ) subscriptors;
 $$ language sql;
@@ -0,0 +1,29 @@
+-- get_repository_event_subscriptors returns the users subscribed to the
+-- repository provided for the given event kind, either directly or through
+-- the organization which owns the repository, unless they have opted out of
+-- notifications for that repository and event kind.
+create or replace function get_repository_event_subscriptors(p_repository_id uuid, p_event_kind_id int)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'user_id', user_id
+    )), '[]')
+    from (
+        select rs.user_id
+        from repository_subscription rs
+        where rs.repository_id = p_repository_id
+        and rs.event_kind_id = p_event_kind_id
+        union
+        select os.user_id
+        from repository r
+        join organization_subscription os using (organization_id)
+        where r.repository_id = p_repository_id
+        and os.event_kind_id = p_event_kind_id
+        order by user_id asc
+    ) subscriptors
+    where user_id not in (
+        select user_id
+        from opt_out
+        where repository_id = p_repository_id
+        and event_kind_id = p_event_kind_id
+    );
+$$ language sql;
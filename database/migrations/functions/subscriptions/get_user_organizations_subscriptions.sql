@@ -0,0 +1,24 @@
+-- get_user_organizations_subscriptions returns all the organizations
+-- subscriptions for the provided user as a json array.
+create or replace function get_user_organizations_subscriptions(p_user_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'organization_name', os.name,
+        'organization_display_name', os.display_name,
+        'logo_image_id', os.logo_image_id,
+        'event_kinds', (
+            select json_agg(distinct(event_kind_id))
+            from organization_subscription
+            where organization_id = os.organization_id
+            and user_id = p_user_id
+        )
+    ))), '[]')
+    from (
+        select o.organization_id, o.name, o.display_name, o.logo_image_id
+        from organization o
+        where o.organization_id in (
+            select distinct(organization_id) from organization_subscription where user_id = p_user_id
+        )
+        order by o.name asc
+    ) os;
+$$ language sql;
@@ -0,0 +1,22 @@
+-- get_user_repositories_subscriptions returns all the repositories
+-- subscriptions for the provided user as a json array.
+create or replace function get_user_repositories_subscriptions(p_user_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'repository', (select get_repository_summary(repository_id)),
+        'event_kinds', (
+            select json_agg(distinct(event_kind_id))
+            from repository_subscription
+            where repository_id = rs.repository_id
+            and user_id = p_user_id
+        )
+    )), '[]')
+    from (
+        select r.repository_id
+        from repository r
+        where r.repository_id in (
+            select distinct(repository_id) from repository_subscription where user_id = p_user_id
+        )
+        order by r.name asc
+    ) rs;
+$$ language sql;
@@ -0,0 +1,50 @@
+-- unsubscribe stops the notifications of the event kind provided about the
+-- given package or repository from being delivered to the user. Package events
+-- are unsubscribed by deleting the user's subscription to the package and, if
+-- the user would still be notified through a repository or organization
+-- subscription, by opting out of the notifications of that package only.
+-- Repository events are unsubscribed by opting out of the repository
+-- notifications.
+create or replace function unsubscribe(p_unsubscribe jsonb)
+returns void as $$
+declare
+    v_user_id uuid := (p_unsubscribe->>'user_id')::uuid;
+    v_event_kind_id int := (p_unsubscribe->>'event_kind')::int;
+    v_package_id uuid := (p_unsubscribe->>'package_id')::uuid;
+    v_repository_id uuid := (p_unsubscribe->>'repository_id')::uuid;
+begin
+    if v_package_id is not null then
+        delete from subscription
+        where user_id = v_user_id
+        and package_id = v_package_id
+        and event_kind_id = v_event_kind_id;
+
+        select repository_id into v_repository_id
+        from package
+        where package_id = v_package_id;
+        if exists (
+            select 1
+            from repository_subscription rs
+            where rs.user_id = v_user_id
+            and rs.repository_id = v_repository_id
+            and rs.event_kind_id = v_event_kind_id
+            union all
+            select 1
+            from organization_subscription os
+            join repository r using (organization_id)
+            where os.user_id = v_user_id
+            and r.repository_id = v_repository_id
+            and os.event_kind_id = v_event_kind_id
+        ) then
+            insert into package_opt_out (user_id, package_id, event_kind_id)
+            values (v_user_id, v_package_id, v_event_kind_id)
+            on conflict do nothing;
+        end if;
+        return;
+    end if;
+
+    insert into opt_out (user_id, repository_id, event_kind_id)
+    values (v_user_id, v_repository_id, v_event_kind_id)
+    on conflict do nothing;
+end
+$$ language plpgsql;
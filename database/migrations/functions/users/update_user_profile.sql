@@ -6,6 +6,7 @@ returns void as $$
         alias = p_user->>'alias',
         first_name = nullif(p_user->>'first_name', ''),
         last_name = nullif(p_user->>'last_name', ''),
-        profile_image_id = nullif(p_user->>'profile_image_id', '')::uuid
+        profile_image_id = nullif(p_user->>'profile_image_id', '')::uuid,
+        email_delivery_preference_id = coalesce((p_user->>'email_delivery_preference')::int, email_delivery_preference_id)
     where user_id = p_requesting_user_id;
 $$ language sql;
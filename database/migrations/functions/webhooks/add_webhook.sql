@@ -10,6 +10,8 @@ declare
     v_webhook_id uuid;
     v_event_kind integer;
     v_package jsonb;
+    v_repository jsonb;
+    v_organization jsonb;
 begin
     if p_org_name <> '' then
         if not user_belongs_to_organization(p_user_id, p_org_name) then
@@ -26,6 +28,10 @@ begin
         description,
         url,
         secret,
+        secondary_secret,
+        sign_payloads,
+        webhook_channel_id,
+        filter,
         content_type,
         template,
         active,
@@ -36,6 +42,10 @@ begin
         nullif(p_webhook->>'description', ''),
         p_webhook->>'url',
         nullif(p_webhook->>'secret', ''),
+        nullif(p_webhook->>'secondary_secret', ''),
+        coalesce((p_webhook->>'sign_payloads')::boolean, false),
+        coalesce((p_webhook->>'channel')::integer, 0),
+        nullif(p_webhook->'filter', 'null'::jsonb),
         nullif(p_webhook->>'content_type', ''),
         nullif(p_webhook->>'template', ''),
         (p_webhook->>'active')::boolean,
@@ -57,5 +67,21 @@ begin
         insert into webhook__package (webhook_id, package_id)
         values (v_webhook_id, (v_package->>'package_id')::uuid);
     end loop;
+
+    -- Repositories this webhook is interested in
+    for v_repository in select * from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    loop
+        insert into webhook__repository (webhook_id, repository_id)
+        values (v_webhook_id, (v_repository->>'repository_id')::uuid);
+    end loop;
+
+    -- Organizations this webhook is interested in
+    for v_organization in select * from jsonb_array_elements(nullif(p_webhook->'organizations', 'null'::jsonb))
+    loop
+        insert into webhook__organization (webhook_id, organization_id)
+        select v_webhook_id, organization_id
+        from organization
+        where name = v_organization->>'name';
+    end loop;
 end
 $$ language plpgsql;
@@ -0,0 +1,27 @@
+-- add_webhook_delivery registers the provided webhook delivery attempt.
+create or replace function add_webhook_delivery(p_delivery jsonb)
+returns void as $$
+    insert into webhook_delivery (
+        attempt,
+        request_payload,
+        response_status,
+        response_body,
+        latency,
+        success,
+        error,
+        notification_id,
+        webhook_id
+    )
+    select
+        (p_delivery->>'attempt')::integer,
+        nullif(p_delivery->>'request_payload', ''),
+        nullif((p_delivery->>'response_status')::integer, 0),
+        nullif(p_delivery->>'response_body', ''),
+        (p_delivery->>'latency')::integer,
+        (p_delivery->>'success')::boolean,
+        nullif(p_delivery->>'error', ''),
+        n.notification_id,
+        n.webhook_id
+    from notification n
+    where n.notification_id = (p_delivery->>'notification_id')::uuid;
+$$ language sql;
@@ -0,0 +1,7 @@
+-- delete_old_webhook_deliveries deletes the webhook deliveries older than the
+-- retention period provided (in seconds).
+create or replace function delete_old_webhook_deliveries(p_retention integer)
+returns void as $$
+    delete from webhook_delivery
+    where created_at < current_timestamp - make_interval(secs => p_retention);
+$$ language sql;
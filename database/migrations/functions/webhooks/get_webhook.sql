@@ -13,6 +13,10 @@ begin
         'description', wh.description,
         'url', wh.url,
         'secret', wh.secret,
+        'secondary_secret', wh.secondary_secret,
+        'sign_payloads', wh.sign_payloads,
+        'channel', wh.webhook_channel_id,
+        'filter', wh.filter,
         'content_type', wh.content_type,
         'template', wh.template,
         'active', wh.active,
@@ -32,6 +36,26 @@ begin
             ) wp
             cross join get_package_summary(wp.package_id) as pkgJSON
         ),
+        'repositories', (
+            select json_agg(repoJSON)
+            from (
+                select repository_id
+                from repository r
+                join webhook__repository wr using (repository_id)
+                where wr.webhook_id = wh.webhook_id
+                order by r.name asc
+            ) wr
+            cross join get_repository_summary(wr.repository_id) as repoJSON
+        ),
+        'organizations', (
+            select json_agg(json_build_object(
+                'name', o.name,
+                'display_name', o.display_name
+            ) order by o.name asc)
+            from organization o
+            join webhook__organization wo using (organization_id)
+            where wo.webhook_id = wh.webhook_id
+        ),
         'last_notifications', (
             select json_agg(json_build_object(
                 'notification_id', notification_id,
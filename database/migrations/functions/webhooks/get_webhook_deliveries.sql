@@ -0,0 +1,46 @@
+-- get_webhook_deliveries returns the latest delivery attempts of the provided
+-- webhook as a json array.
+create or replace function get_webhook_deliveries(p_user_id uuid, p_webhook_id uuid)
+returns setof json as $$
+begin
+    if not user_has_access_to_webhook(p_user_id, p_webhook_id) then
+        raise insufficient_privilege;
+    end if;
+
+    return query select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'webhook_delivery_id', webhook_delivery_id,
+        'notification_id', notification_id,
+        'event_id', event_id,
+        'event_kind', event_kind_id,
+        'created_at', floor(extract(epoch from created_at)),
+        'attempt', attempt,
+        'request_payload', request_payload,
+        'response_status', response_status,
+        'response_body', response_body,
+        'latency', latency,
+        'success', success,
+        'error', error
+    ))), '[]')
+    from (
+        select
+            wd.webhook_delivery_id,
+            wd.notification_id,
+            e.event_id,
+            e.event_kind_id,
+            wd.created_at,
+            wd.attempt,
+            wd.request_payload,
+            wd.response_status,
+            wd.response_body,
+            wd.latency,
+            wd.success,
+            wd.error
+        from webhook_delivery wd
+        join notification n using (notification_id)
+        join event e using (event_id)
+        where wd.webhook_id = p_webhook_id
+        order by wd.created_at desc
+        limit 50
+    ) wd;
+end
+$$ language plpgsql;
@@ -0,0 +1,12 @@
+-- get_webhooks_by_ids returns the webhooks identified by the ids provided that
+-- are still active and subscribed to the event kind given.
+create or replace function get_webhooks_by_ids(p_event_kind_id integer, p_webhooks_ids uuid[])
+returns setof json as $$
+    select coalesce(json_agg(wh), '[]')
+    from webhook
+    join webhook__event_kind wek using (webhook_id)
+    cross join get_webhook(null::uuid, webhook_id) as wh
+    where webhook_id = any(p_webhooks_ids)
+    and wek.event_kind_id = p_event_kind_id
+    and active = true;
+$$ language sql;
@@ -1,13 +1,28 @@
 -- get_webhooks_subscribed_to_package returns the webhooks subscribed to the
--- event kind and package provided.
+-- event kind and package provided. Webhooks can be subscribed to the package
+-- directly or to the repository or organization the package belongs to.
 create or replace function get_webhooks_subscribed_to_package(p_event_kind_id integer, p_package_id uuid)
 returns setof json as $$
     select coalesce(json_agg(wh), '[]')
-    from webhook
+    from (
+        select webhook_id
+        from webhook__package
+        where package_id = p_package_id
+        union
+        select wr.webhook_id
+        from webhook__repository wr
+        join package p using (repository_id)
+        where p.package_id = p_package_id
+        union
+        select wo.webhook_id
+        from webhook__organization wo
+        join repository r using (organization_id)
+        join package p using (repository_id)
+        where p.package_id = p_package_id
+    ) s
+    join webhook using (webhook_id)
     join webhook__event_kind wek using (webhook_id)
-    join webhook__package wp using (webhook_id)
     cross join get_webhook(null::uuid, webhook_id) as wh
     where wek.event_kind_id = p_event_kind_id
-    and wp.package_id = p_package_id
     and active = true;
 $$ language sql;
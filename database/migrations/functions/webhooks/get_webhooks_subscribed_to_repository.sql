@@ -0,0 +1,22 @@
+-- get_webhooks_subscribed_to_repository returns the webhooks subscribed to the
+-- event kind and repository provided. Webhooks can be subscribed to the
+-- repository directly or to the organization the repository belongs to.
+create or replace function get_webhooks_subscribed_to_repository(p_event_kind_id integer, p_repository_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(wh), '[]')
+    from (
+        select webhook_id
+        from webhook__repository
+        where repository_id = p_repository_id
+        union
+        select wo.webhook_id
+        from webhook__organization wo
+        join repository r using (organization_id)
+        where r.repository_id = p_repository_id
+    ) s
+    join webhook using (webhook_id)
+    join webhook__event_kind wek using (webhook_id)
+    cross join get_webhook(null::uuid, webhook_id) as wh
+    where wek.event_kind_id = p_event_kind_id
+    and active = true;
+$$ language sql;
@@ -0,0 +1,30 @@
+-- redeliver_webhook_notification queues again for delivery the notification
+-- corresponding to the webhook delivery provided. The notification attempts are
+-- reset, so it gets the full number of retries again.
+create or replace function redeliver_webhook_notification(
+    p_user_id uuid,
+    p_webhook_id uuid,
+    p_webhook_delivery_id uuid
+) returns void as $$
+begin
+    if not user_has_access_to_webhook(p_user_id, p_webhook_id) then
+        raise insufficient_privilege;
+    end if;
+
+    update notification set
+        processed = false,
+        processed_at = null,
+        next_attempt_at = null,
+        attempts = 0,
+        dead = false
+    where notification_id = (
+        select notification_id
+        from webhook_delivery
+        where webhook_delivery_id = p_webhook_delivery_id
+        and webhook_id = p_webhook_id
+    );
+    if not found then
+        raise no_data_found;
+    end if;
+end
+$$ language plpgsql;
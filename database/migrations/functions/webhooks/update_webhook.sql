@@ -7,6 +7,8 @@ declare
     v_owner_organization_name text;
     v_event_kind integer;
     v_package jsonb;
+    v_repository jsonb;
+    v_organization jsonb;
 begin
     if not user_has_access_to_webhook(p_user_id, v_webhook_id) then
         raise insufficient_privilege;
@@ -18,6 +20,10 @@ begin
         description = nullif(p_webhook->>'description', ''),
         url = p_webhook->>'url',
         secret = nullif(p_webhook->>'secret', ''),
+        secondary_secret = nullif(p_webhook->>'secondary_secret', ''),
+        sign_payloads = coalesce((p_webhook->>'sign_payloads')::boolean, false),
+        webhook_channel_id = coalesce((p_webhook->>'channel')::integer, 0),
+        filter = nullif(p_webhook->'filter', 'null'::jsonb),
         content_type = nullif(p_webhook->>'content_type', ''),
         template = nullif(p_webhook->>'template', ''),
         active = (p_webhook->>'active')::boolean
@@ -47,12 +53,47 @@ begin
         on conflict do nothing;
     end loop;
 
-    -- Unbind deleted event kinds from webhook
+    -- Unbind deleted packages from webhook
     delete from webhook__package
     where webhook_id = v_webhook_id
     and package_id not in (
         select (value->>'package_id')::uuid
         from jsonb_array_elements(nullif(p_webhook->'packages', 'null'::jsonb))
     );
+
+    -- Bind webhook with repositories if needed
+    for v_repository in select * from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    loop
+        insert into webhook__repository (webhook_id, repository_id)
+        values (v_webhook_id, (v_repository->>'repository_id')::uuid)
+        on conflict do nothing;
+    end loop;
+
+    -- Unbind deleted repositories from webhook
+    delete from webhook__repository
+    where webhook_id = v_webhook_id
+    and repository_id not in (
+        select (value->>'repository_id')::uuid
+        from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    );
+
+    -- Bind webhook with organizations if needed
+    for v_organization in select * from jsonb_array_elements(nullif(p_webhook->'organizations', 'null'::jsonb))
+    loop
+        insert into webhook__organization (webhook_id, organization_id)
+        select v_webhook_id, organization_id
+        from organization
+        where name = v_organization->>'name'
+        on conflict do nothing;
+    end loop;
+
+    -- Unbind deleted organizations from webhook
+    delete from webhook__organization
+    where webhook_id = v_webhook_id
+    and organization_id not in (
+        select o.organization_id
+        from jsonb_array_elements(nullif(p_webhook->'organizations', 'null'::jsonb)) as orgs
+        join organization o on o.name = orgs.value->>'name'
+    );
 end
 $$ language plpgsql;
@@ -0,0 +1,7 @@
+alter table repository add column tracking_interval integer check (tracking_interval > 0);
+alter table repository add column tracking_requested_at timestamptz;
+
+---- create above / drop below ----
+
+alter table repository drop column tracking_requested_at;
+alter table repository drop column tracking_interval;
@@ -0,0 +1,17 @@
+create table if not exists repository_tracking_run (
+    repository_tracking_run_id uuid primary key default gen_random_uuid(),
+    repository_id uuid not null references repository on delete cascade,
+    started_at timestamptz default current_timestamp not null,
+    finished_at timestamptz,
+    packages_registered integer not null default 0,
+    packages_unregistered integer not null default 0,
+    digest_before text,
+    digest_after text,
+    warnings text check (warnings <> '')
+);
+
+create index repository_tracking_run_repository_id_started_at_idx on repository_tracking_run (repository_id, started_at);
+
+---- create above / drop below ----
+
+drop table if exists repository_tracking_run;
@@ -0,0 +1,7 @@
+alter table repository add column vulnerability_allowlist jsonb;
+alter table snapshot add column vulnerability_allowlist jsonb;
+
+---- create above / drop below ----
+
+alter table repository drop column vulnerability_allowlist;
+alter table snapshot drop column vulnerability_allowlist;
@@ -0,0 +1,7 @@
+alter table webhook add column secondary_secret text check (secondary_secret <> '');
+alter table webhook add column sign_payloads boolean not null default false;
+
+---- create above / drop below ----
+
+alter table webhook drop column secondary_secret;
+alter table webhook drop column sign_payloads;
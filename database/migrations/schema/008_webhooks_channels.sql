@@ -0,0 +1,16 @@
+create table if not exists webhook_channel (
+    webhook_channel_id integer primary key,
+    name text not null check (name <> '')
+);
+
+insert into webhook_channel values (0, 'Generic');
+insert into webhook_channel values (1, 'Slack');
+insert into webhook_channel values (2, 'Microsoft Teams');
+insert into webhook_channel values (3, 'Discord');
+
+alter table webhook add column webhook_channel_id integer not null default 0 references webhook_channel on delete restrict;
+
+---- create above / drop below ----
+
+alter table webhook drop column webhook_channel_id;
+drop table if exists webhook_channel;
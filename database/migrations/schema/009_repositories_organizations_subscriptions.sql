@@ -0,0 +1,36 @@
+create table if not exists repository_subscription (
+    user_id uuid not null references "user" on delete cascade,
+    repository_id uuid not null references repository on delete cascade,
+    event_kind_id integer not null references event_kind on delete restrict,
+    primary key (user_id, repository_id, event_kind_id)
+);
+
+create index repository_subscription_repository_id_idx on repository_subscription (repository_id);
+
+create table if not exists organization_subscription (
+    user_id uuid not null references "user" on delete cascade,
+    organization_id uuid not null references organization on delete cascade,
+    event_kind_id integer not null references event_kind on delete restrict,
+    primary key (user_id, organization_id, event_kind_id)
+);
+
+create index organization_subscription_organization_id_idx on organization_subscription (organization_id);
+
+create table if not exists webhook__repository (
+    webhook_id uuid not null references webhook on delete cascade,
+    repository_id uuid not null references repository on delete cascade,
+    primary key (webhook_id, repository_id)
+);
+
+create table if not exists webhook__organization (
+    webhook_id uuid not null references webhook on delete cascade,
+    organization_id uuid not null references organization on delete cascade,
+    primary key (webhook_id, organization_id)
+);
+
+---- create above / drop below ----
+
+drop table if exists webhook__organization;
+drop table if exists webhook__repository;
+drop table if exists organization_subscription;
+drop table if exists repository_subscription;
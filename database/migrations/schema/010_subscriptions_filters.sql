@@ -0,0 +1,11 @@
+alter table subscription add column filter jsonb;
+alter table repository_subscription add column filter jsonb;
+alter table organization_subscription add column filter jsonb;
+alter table webhook add column filter jsonb;
+
+---- create above / drop below ----
+
+alter table webhook drop column filter;
+alter table organization_subscription drop column filter;
+alter table repository_subscription drop column filter;
+alter table subscription drop column filter;
@@ -0,0 +1,20 @@
+create table if not exists email_delivery_preference (
+    email_delivery_preference_id integer primary key,
+    name text not null check (name <> '')
+);
+
+insert into email_delivery_preference values (0, 'Immediate');
+insert into email_delivery_preference values (1, 'Daily digest');
+insert into email_delivery_preference values (2, 'Weekly digest');
+
+alter table "user" add column email_delivery_preference_id integer not null default 0 references email_delivery_preference on delete restrict;
+alter table "user" add column last_digest_sent_at timestamptz;
+
+create index notification_user_id_idx on notification (user_id) where processed = 'false';
+
+---- create above / drop below ----
+
+drop index if exists notification_user_id_idx;
+alter table "user" drop column last_digest_sent_at;
+alter table "user" drop column email_delivery_preference_id;
+drop table if exists email_delivery_preference;
@@ -0,0 +1,14 @@
+insert into event_kind values (4, 'Package version removed');
+insert into event_kind values (5, 'Package deprecated');
+insert into event_kind values (6, 'Repository transferred');
+insert into event_kind values (7, 'Repository verified publisher changed');
+
+---- create above / drop below ----
+
+delete from event where event_kind_id in (4, 5, 6, 7);
+delete from subscription where event_kind_id in (4, 5, 6, 7);
+delete from repository_subscription where event_kind_id in (4, 5, 6, 7);
+delete from organization_subscription where event_kind_id in (4, 5, 6, 7);
+delete from opt_out where event_kind_id in (4, 5, 6, 7);
+delete from webhook__event_kind where event_kind_id in (4, 5, 6, 7);
+delete from event_kind where event_kind_id in (4, 5, 6, 7);
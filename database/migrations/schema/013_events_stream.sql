@@ -0,0 +1,5 @@
+create index event_processed_at_event_id_idx on event (processed_at, event_id) where processed = 'true';
+
+---- create above / drop below ----
+
+drop index if exists event_processed_at_event_id_idx;
@@ -0,0 +1,30 @@
+create table if not exists snapshot_dependency (
+    snapshot_dependency_id uuid primary key default gen_random_uuid(),
+    package_id uuid not null,
+    version text not null,
+    name text not null check (name <> ''),
+    version_constraint text check (version_constraint <> ''),
+    repository_url text check (repository_url <> ''),
+    alias text check (alias <> ''),
+    foreign key (package_id, version) references snapshot on delete cascade
+);
+
+create index snapshot_dependency_package_id_version_idx on snapshot_dependency (package_id, version);
+create index snapshot_dependency_name_idx on snapshot_dependency (name);
+
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, alias)
+select
+    s.package_id,
+    s.version,
+    d->>'name',
+    nullif(d->>'version', ''),
+    nullif(d->>'repository', ''),
+    nullif(d->>'alias', '')
+from snapshot s
+cross join jsonb_array_elements(s.data->'dependencies') as d
+where jsonb_typeof(s.data->'dependencies') = 'array'
+and nullif(d->>'name', '') is not null;
+
+---- create above / drop below ----
+
+drop table if exists snapshot_dependency;
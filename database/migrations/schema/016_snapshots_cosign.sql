@@ -0,0 +1,5 @@
+alter table snapshot add column cosign_verification jsonb;
+
+---- create above / drop below ----
+
+alter table snapshot drop column if exists cosign_verification;
@@ -0,0 +1,11 @@
+create table if not exists package_opt_out (
+    package_opt_out_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references "user" on delete cascade,
+    package_id uuid not null references package on delete cascade,
+    event_kind_id integer not null references event_kind on delete restrict,
+    unique (user_id, package_id, event_kind_id)
+);
+
+---- create above / drop below ----
+
+drop table if exists package_opt_out;
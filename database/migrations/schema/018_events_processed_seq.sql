@@ -0,0 +1,19 @@
+create sequence if not exists event_processed_seq;
+alter table event add column processed_seq bigint;
+update event e set processed_seq = s.seq
+from (
+    select event_id, row_number() over (order by processed_at, event_id) as seq
+    from event
+    where processed = true
+) s
+where e.event_id = s.event_id;
+select setval('event_processed_seq', (select coalesce(max(processed_seq), 0) + 1 from event), false);
+drop index if exists event_processed_at_event_id_idx;
+create index event_processed_seq_idx on event (processed_seq) where processed_seq is not null;
+
+---- create above / drop below ----
+
+drop index if exists event_processed_seq_idx;
+create index event_processed_at_event_id_idx on event (processed_at, event_id) where processed = 'true';
+alter table event drop column if exists processed_seq;
+drop sequence if exists event_processed_seq;
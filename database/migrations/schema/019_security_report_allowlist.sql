@@ -0,0 +1,5 @@
+alter table snapshot add column security_report_allowlist jsonb;
+
+---- create above / drop below ----
+
+alter table snapshot drop column security_report_allowlist;
@@ -0,0 +1,5 @@
+alter table repository add column signing_keys_digest text;
+
+---- create above / drop below ----
+
+alter table repository drop column signing_keys_digest;
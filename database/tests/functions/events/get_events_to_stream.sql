@@ -0,0 +1,118 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+\set event4ID '00000000-0000-0000-0000-000000000004'
+\set event5ID '00000000-0000-0000-0000-000000000005'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, processed, processed_at, processed_seq, package_version, package_id, event_kind_id)
+values (:'event1ID', true, '2020-06-16 11:20:31+02', 1, '0.0.9', :'package1ID', 0);
+insert into event (event_id, processed, processed_at, processed_seq, package_version, package_id, event_kind_id)
+values (:'event2ID', true, '2020-06-16 11:20:30+02', 2, '1.0.0', :'package1ID', 0);
+insert into event (event_id, processed, processed_at, processed_seq, repository_id, event_kind_id, data)
+values (:'event3ID', true, '2020-06-16 11:20:33+02', 3, :'repo1ID', 2, null);
+insert into event (event_id, processed, processed_at, processed_seq, repository_id, event_kind_id, data)
+values (:'event4ID', true, '2020-06-16 11:20:34+02', 4, :'repo1ID', 6, '{
+    "previous_owner": {"user_alias": "user2"},
+    "subscriptors": [{"user_id": "00000000-0000-0000-0000-000000000002"}]
+}');
+insert into event (event_id, processed, processed_at, package_version, package_id, event_kind_id)
+values (:'event5ID', true, '2020-06-16 11:20:35+02', '1.0.1', :'package1ID', 0);
+insert into notification (event_id, user_id) values (:'event2ID', :'user1ID');
+insert into notification (event_id, user_id) values (:'event3ID', :'user1ID');
+insert into notification (event_id, user_id) values (:'event4ID', :'user2ID');
+
+-- Run some tests
+select is(
+    get_events_to_stream(:'user1ID', '{"last_event_id": "00000000-0000-0000-0000-000000000001"}')::jsonb,
+    '[
+        {
+            "event_id": "00000000-0000-0000-0000-000000000002",
+            "event_kind": 0,
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_version": "1.0.0"
+        },
+        {
+            "event_id": "00000000-0000-0000-0000-000000000003",
+            "event_kind": 2,
+            "repository_id": "00000000-0000-0000-0000-000000000001"
+        }
+    ]'::jsonb,
+    'Events user1 is subscribed to processed after event1 should be returned (in commit order)'
+);
+select is(
+    get_events_to_stream(:'user2ID', '{"last_event_id": "00000000-0000-0000-0000-000000000001", "all": true}')::jsonb,
+    '[
+        {
+            "event_id": "00000000-0000-0000-0000-000000000002",
+            "event_kind": 0,
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_version": "1.0.0"
+        },
+        {
+            "event_id": "00000000-0000-0000-0000-000000000004",
+            "event_kind": 6,
+            "repository_id": "00000000-0000-0000-0000-000000000001",
+            "data": {"previous_owner": {"user_alias": "user2"}}
+        }
+    ]'::jsonb,
+    'Public events processed after event1 should be returned to user2, without private events or subscriptors'
+);
+select is(
+    get_events_to_stream(:'user1ID', '{"last_event_id": "00000000-0000-0000-0000-000000000001", "limit": 1}')::jsonb,
+    '[
+        {
+            "event_id": "00000000-0000-0000-0000-000000000002",
+            "event_kind": 0,
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_version": "1.0.0"
+        }
+    ]'::jsonb,
+    'Only one event should be returned when limit is 1'
+);
+select is(
+    get_events_to_stream(:'user1ID', '{"last_event_id": "00000000-0000-0000-0000-000000000099"}')::jsonb,
+    '[]'::jsonb,
+    'No events should be returned when the last event does not exist'
+);
+select is(
+    get_events_to_stream(:'user2ID', '{"event_id": "00000000-0000-0000-0000-000000000004"}')::jsonb,
+    '[
+        {
+            "event_id": "00000000-0000-0000-0000-000000000004",
+            "event_kind": 6,
+            "repository_id": "00000000-0000-0000-0000-000000000001",
+            "data": {"previous_owner": {"user_alias": "user2"}}
+        }
+    ]'::jsonb,
+    'Event4 should be returned to user2'
+);
+select is(
+    get_events_to_stream(:'user2ID', '{"event_id": "00000000-0000-0000-0000-000000000003", "all": true}')::jsonb,
+    '[]'::jsonb,
+    'Event3 should not be returned to user2 as it is not public'
+);
+select is(
+    get_events_to_stream(:'user2ID', '{"event_id": "00000000-0000-0000-0000-000000000005", "all": true}')::jsonb,
+    '[]'::jsonb,
+    'Event5 should not be returned as its processing has not been committed yet'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
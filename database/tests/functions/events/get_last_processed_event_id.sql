@@ -0,0 +1,39 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+
+-- No events processed yet
+select is(
+    get_last_processed_event_id(),
+    null,
+    'No event id should be returned'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into event (event_id, processed, processed_seq, repository_id, event_kind_id)
+values (:'event1ID', true, 2, :'repo1ID', 2);
+insert into event (event_id, processed, processed_seq, repository_id, event_kind_id)
+values (:'event2ID', true, 1, :'repo1ID', 2);
+insert into event (event_id, repository_id, event_kind_id)
+values (:'event3ID', :'repo1ID', 2);
+
+-- Run some tests
+select is(
+    get_last_processed_event_id(),
+    :'event1ID'::uuid,
+    'Event1 id should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
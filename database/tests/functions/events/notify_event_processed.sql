@@ -0,0 +1,36 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into event (event_id, processed, processed_at, repository_id, event_kind_id)
+values (:'event1ID', true, current_timestamp, :'repo1ID', 2);
+insert into event (event_id, processed, processed_at, repository_id, event_kind_id)
+values (:'event2ID', true, current_timestamp, :'repo1ID', 2);
+
+-- Run some tests
+select notify_event_processed(:'event2ID');
+select notify_event_processed(:'event1ID');
+select isnt(
+    (select processed_seq from event where event_id = :'event2ID'),
+    null,
+    'Event2 should have been assigned a position in the processed events sequence'
+);
+select ok(
+    (select processed_seq from event where event_id = :'event1ID') >
+    (select processed_seq from event where event_id = :'event2ID'),
+    'Event1 should be placed after event2 in the processed events sequence'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,92 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set notification2ID '00000000-0000-0000-0000-000000000002'
+\set notification3ID '00000000-0000-0000-0000-000000000003'
+
+-- No pending digests available yet
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a digest'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_delivery_preference_id)
+values (:'user1ID', 'user1', 'user1@email.com', 1);
+insert into "user" (user_id, alias, email, email_delivery_preference_id, last_digest_sent_at)
+values (:'user2ID', 'user2', 'user2@email.com', 2, current_timestamp - '1 day'::interval);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id, data)
+values (:'event1ID', '1.0.0', :'package1ID', 1, '{"vulnerabilities": ["CVE-1"]}');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event2ID', '1.0.0', :'package1ID', 0);
+insert into notification (notification_id, event_id, user_id, created_at)
+values (:'notification1ID', :'event1ID', :'user1ID', current_timestamp - '1 minute'::interval);
+insert into notification (notification_id, event_id, user_id)
+values (:'notification2ID', :'event2ID', :'user1ID');
+insert into notification (notification_id, event_id, user_id)
+values (:'notification3ID', :'event2ID', :'user2ID');
+
+-- Run some tests
+select is(
+    get_pending_digest()::jsonb,
+    '{
+        "user": {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "email": "user1@email.com",
+            "email_delivery_preference": 1
+        },
+        "notifications": [
+            {
+                "notification_id": "00000000-0000-0000-0000-000000000001",
+                "attempts": 0,
+                "event": {
+                    "event_id": "00000000-0000-0000-0000-000000000001",
+                    "event_kind": 1,
+                    "package_id": "00000000-0000-0000-0000-000000000001",
+                    "package_version": "1.0.0",
+                    "data": {
+                        "vulnerabilities": ["CVE-1"]
+                    }
+                }
+            },
+            {
+                "notification_id": "00000000-0000-0000-0000-000000000002",
+                "attempts": 0,
+                "event": {
+                    "event_id": "00000000-0000-0000-0000-000000000002",
+                    "event_kind": 0,
+                    "package_id": "00000000-0000-0000-0000-000000000001",
+                    "package_version": "1.0.0"
+                }
+            }
+        ]
+    }'::jsonb,
+    'A digest for user1 should be returned'
+);
+select is(
+    (select last_digest_sent_at from "user" where user_id = :'user1ID'),
+    null,
+    'User1 last digest timestamp should not have been updated yet'
+);
+update notification set processed = true where user_id = :'user1ID';
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'No digest should be returned as user2 weekly digest is not due yet'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
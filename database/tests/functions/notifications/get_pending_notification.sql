@@ -1,15 +1,17 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(4);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set webhook1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 \set event1ID '00000000-0000-0000-0000-000000000001'
 \set notification1ID '00000000-0000-0000-0000-000000000001'
 \set notification2ID '00000000-0000-0000-0000-000000000002'
+\set notification3ID '00000000-0000-0000-0000-000000000003'
 
 -- No pending events available yet
 select is_empty(
@@ -42,8 +44,8 @@ insert into webhook (
     true,
     :'user1ID'
 );
-insert into event (event_id, package_version, package_id, event_kind_id)
-values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into event (event_id, package_version, package_id, event_kind_id, data)
+values (:'event1ID', '1.0.0', :'package1ID', 1, '{"vulnerabilities": ["CVE-1"]}');
 
 -- Add notification for user1 and check we get it successfully
 insert into notification (notification_id, event_id, user_id)
@@ -52,13 +54,18 @@ select is(
     get_pending_notification()::jsonb,
     '{
         "notification_id": "00000000-0000-0000-0000-000000000001",
+        "attempts": 0,
         "event": {
             "event_id": "00000000-0000-0000-0000-000000000001",
-            "event_kind": 0,
+            "event_kind": 1,
             "package_id": "00000000-0000-0000-0000-000000000001",
-            "package_version": "1.0.0"
+            "package_version": "1.0.0",
+            "data": {
+                "vulnerabilities": ["CVE-1"]
+            }
         },
         "user": {
+            "user_id": "00000000-0000-0000-0000-000000000001",
             "email": "user1@email.com"
         }
 	}'::jsonb,
@@ -73,22 +80,40 @@ select is(
     get_pending_notification()::jsonb,
     '{
         "notification_id": "00000000-0000-0000-0000-000000000002",
+        "attempts": 0,
         "event": {
             "event_id": "00000000-0000-0000-0000-000000000001",
-            "event_kind": 0,
+            "event_kind": 1,
             "package_id": "00000000-0000-0000-0000-000000000001",
-            "package_version": "1.0.0"
+            "package_version": "1.0.0",
+            "data": {
+                "vulnerabilities": ["CVE-1"]
+            }
         },
         "webhook": {
             "name": "webhook1",
             "url": "http://webhook1.url",
             "secret": "very",
+            "sign_payloads": false,
+            "channel": 0,
             "content_type": "application/json",
             "template": "custom payload"
         }
 	}'::jsonb,
     'A notification for webhook1 should be returned'
 );
+update notification set processed=true where notification_id=:'notification2ID';
+
+-- Add notification for user2, who prefers to receive digests, and check it
+-- is not returned
+insert into "user" (user_id, alias, email, email_delivery_preference_id)
+values (:'user2ID', 'user2', 'user2@email.com', 1);
+insert into notification (notification_id, event_id, user_id)
+values (:'notification3ID', :'event1ID', :'user2ID');
+select is_empty(
+    $$ select get_pending_notification()::jsonb $$,
+    'Notifications of users who prefer digests should not be returned'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
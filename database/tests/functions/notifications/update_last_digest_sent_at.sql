@@ -0,0 +1,27 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_delivery_preference_id)
+values (:'user1ID', 'user1', 'user1@email.com', 1);
+
+-- Run some tests
+select is(
+    (select last_digest_sent_at from "user" where user_id = :'user1ID'),
+    null,
+    'User1 last digest timestamp should be null'
+);
+select update_last_digest_sent_at(:'user1ID');
+select is(
+    (select last_digest_sent_at from "user" where user_id = :'user1ID'),
+    current_timestamp,
+    'User1 last digest timestamp should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
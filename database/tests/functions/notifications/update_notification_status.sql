@@ -23,11 +23,11 @@ values (:'notification1ID', :'event1ID', :'user1ID');
 -- Run some tests
 select results_eq(
     $$
-        select processed, processed_at, error from notification
+        select processed, processed_at, error, attempts from notification
         where notification_id = '00000000-0000-0000-0000-000000000001'
     $$,
     $$
-        values (false, null::timestamptz, null::text)
+        values (false, null::timestamptz, null::text, 0)
     $$,
     'Notification has not been processed yet'
 );
@@ -38,11 +38,11 @@ select update_notification_status(:'notification1ID', true, 'fake error');
 -- Run some tests
 select results_eq(
     $$
-        select processed, error from notification
+        select processed, error, attempts from notification
         where notification_id = '00000000-0000-0000-0000-000000000001'
     $$,
     $$
-        values (true, 'fake error')
+        values (true, 'fake error', 1)
     $$,
     'Notification has been processed'
 );
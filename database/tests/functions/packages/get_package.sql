@@ -90,6 +90,7 @@ insert into snapshot (
     deprecated,
     license,
     signed,
+    provenance_verification,
     content_url,
     containers_images,
     provider,
@@ -119,6 +120,7 @@ insert into snapshot (
     true,
     'Apache-2.0',
     true,
+    '{"verified": true, "key_fingerprint": "ABCDEF0123456789", "signer": "signer1 <signer1@email.com>"}',
     'https://content.url/pkg1.tgz',
     '[{"image": "quay.io/org/img:1.0.0"}]',
     'Org Inc',
@@ -275,6 +277,11 @@ select is(
         "prerelease": true,
         "license": "Apache-2.0",
         "signed": true,
+        "provenance_verification": {
+            "verified": true,
+            "key_fingerprint": "ABCDEF0123456789",
+            "signer": "signer1 <signer1@email.com>"
+        },
         "content_url": "https://content.url/pkg1.tgz",
         "containers_images": [
             {
@@ -388,6 +395,11 @@ select is(
         "prerelease": true,
         "license": "Apache-2.0",
         "signed": true,
+        "provenance_verification": {
+            "verified": true,
+            "key_fingerprint": "ABCDEF0123456789",
+            "signer": "signer1 <signer1@email.com>"
+        },
         "content_url": "https://content.url/pkg1.tgz",
         "containers_images": [
             {
@@ -0,0 +1,63 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- No dependencies registered yet
+select is(
+    get_package_dependencies(:'package1ID', '1.0.0')::jsonb,
+    '[]'::jsonb,
+    'No dependencies expected'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'https://repo2.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '2.0.0', :'repo2ID');
+insert into snapshot (package_id, version) values (:'package1ID', '1.0.0');
+insert into snapshot (package_id, version) values (:'package2ID', '2.0.0');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, alias)
+values (:'package1ID', '1.0.0', 'package2', '^2.0.0', 'https://repo2.com', 'db');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url)
+values (:'package1ID', '1.0.0', 'external', '1.2.3', 'https://external.com');
+
+-- Run some tests
+select results_eq(
+    $$
+        select
+            d->>'name',
+            d->>'version',
+            d->>'repository_url',
+            d->>'alias',
+            d->'package'->>'package_id'
+        from json_array_elements(get_package_dependencies(
+            '00000000-0000-0000-0000-000000000001', '1.0.0'
+        )) d
+    $$,
+    $$ values
+        ('external', '1.2.3', 'https://external.com', null::text, null::text),
+        ('package2', '^2.0.0', 'https://repo2.com', 'db', '00000000-0000-0000-0000-000000000002')
+    $$,
+    'Dependencies should be returned, including the details of the ones registered in the hub'
+);
+select is(
+    get_package_dependencies(:'package1ID', '0.1.0')::jsonb,
+    '[]'::jsonb,
+    'No dependencies expected for a version that does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
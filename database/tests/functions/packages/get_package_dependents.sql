@@ -0,0 +1,72 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+\set package4ID '00000000-0000-0000-0000-000000000004'
+
+-- No dependents registered yet
+select is(
+    get_package_dependents(:'package1ID')::jsonb,
+    '[]'::jsonb,
+    'No dependents expected'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'https://repo2.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '2.0.0', :'repo2ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'package3', '3.0.0', :'repo2ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package4ID', 'package4', '4.0.0', :'repo2ID');
+insert into snapshot (package_id, version) values (:'package1ID', '1.0.0');
+insert into snapshot (package_id, version) values (:'package2ID', '2.0.0');
+insert into snapshot (package_id, version) values (:'package3ID', '2.9.0');
+insert into snapshot (package_id, version) values (:'package3ID', '3.0.0');
+insert into snapshot (package_id, version) values (:'package4ID', '4.0.0');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url)
+values (:'package2ID', '2.0.0', 'package1', '^1.0.0', 'https://repo1.com');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url)
+values (:'package3ID', '2.9.0', 'package1', '^1.0.0', 'https://repo1.com');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url)
+values (:'package4ID', '4.0.0', 'package1', '^1.0.0', 'https://other-repo.com');
+
+-- Run some tests
+select results_eq(
+    $$
+        select
+            d->'package'->>'package_id',
+            d->'dependency'->>'name',
+            d->'dependency'->>'version',
+            d->'dependency'->>'repository_url'
+        from json_array_elements(get_package_dependents(
+            '00000000-0000-0000-0000-000000000001'
+        )) d
+    $$,
+    $$ values
+        ('00000000-0000-0000-0000-000000000002', 'package1', '^1.0.0', 'https://repo1.com')
+    $$,
+    'Only packages whose latest version depends on package1 should be returned'
+);
+select is(
+    get_package_dependents(:'package2ID')::jsonb,
+    '[]'::jsonb,
+    'No dependents expected for package2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
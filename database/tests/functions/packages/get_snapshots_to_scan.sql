@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(4);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -12,10 +12,11 @@ select plan(2);
 \set package2ID '00000000-0000-0000-0000-000000000002'
 \set package3ID '00000000-0000-0000-0000-000000000003'
 \set package4ID '00000000-0000-0000-0000-000000000004'
+\set package5ID '00000000-0000-0000-0000-000000000005'
 
 -- No snapshots at this point
 select is(
-    get_snapshots_to_scan()::jsonb,
+    get_snapshots_to_scan(10080)::jsonb,
     '[]'::jsonb,
     'No snapshots to scan expected'
 );
@@ -24,8 +25,10 @@ select is(
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into organization (organization_id, name, display_name, description, home_url)
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
-insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
-values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, vulnerability_allowlist)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', '[
+    {"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2021-01-01"}
+]');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
 values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
 insert into repository (repository_id, name, display_name, url, scanner_disabled, repository_kind_id, organization_id)
@@ -44,12 +47,18 @@ insert into package (
 insert into snapshot (
     package_id,
     version,
+    license,
     containers_images,
+    data,
+    vulnerability_allowlist,
     created_at
 ) values (
     :'package1ID',
     '1.0.0',
+    'Apache-2.0',
     '[{"image": "quay.io/org/pkg1:1.0.0"}]',
+    '{"dependencies": [{"name": "dep1", "version": "1.0.0", "repository": "https://repo.url"}]}',
+    '[{"vulnerability_id": "CVE-2020-0002", "justification": "Fixed upstream", "expires_at": "2021-02-01"}]',
     '2020-06-16 11:20:38+02'
 );
 insert into snapshot (
@@ -152,47 +161,236 @@ insert into snapshot (
 
 -- Run some tests
 select is(
-    get_snapshots_to_scan()::jsonb,
+    get_snapshots_to_scan(10080)::jsonb,
     '[
         {
             "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_name": "package1",
             "version": "1.0.0",
+            "license": "Apache-2.0",
             "containers_images": [
                 {
                     "image": "quay.io/org/pkg1:1.0.0"
                 }
+            ],
+            "dependencies": [
+                {
+                    "name": "dep1",
+                    "version": "1.0.0",
+                    "repository": "https://repo.url"
+                }
+            ],
+            "vulnerability_allowlist": [
+                {
+                    "vulnerability_id": "CVE-2020-0001",
+                    "justification": "Not exploitable",
+                    "expires_at": "2021-01-01"
+                },
+                {
+                    "vulnerability_id": "CVE-2020-0002",
+                    "justification": "Fixed upstream",
+                    "expires_at": "2021-02-01"
+                }
             ]
         },
+        {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "package_name": "package2",
+            "version": "1.0.0",
+            "license": null,
+            "containers_images": [
+                {
+                    "image": "quay.io/org/pkg2:1.0.0",
+                    "whitelisted": false
+                }
+            ],
+            "dependencies": null,
+            "vulnerability_allowlist": null
+        },
         {
             "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_name": "package1",
             "version": "0.0.9",
+            "license": null,
             "containers_images": [
                 {
                     "image": "quay.io/org/pkg1:0.0.9"
                 }
+            ],
+            "dependencies": null,
+            "vulnerability_allowlist": [
+                {
+                    "vulnerability_id": "CVE-2020-0001",
+                    "justification": "Not exploitable",
+                    "expires_at": "2021-01-01"
+                }
+            ]
+        },
+        {
+            "package_id": "00000000-0000-0000-0000-000000000003",
+            "package_name": "package3",
+            "version": "0.0.9",
+            "license": null,
+            "containers_images": [
+                {
+                    "image": "quay.io/org/pkg3:0.0.9"
+                }
+            ],
+            "dependencies": null,
+            "vulnerability_allowlist": null
+        }
+    ]'::jsonb,
+    'Some snapshots to scan were expected, latest versions first'
+);
+select is(
+    get_snapshots_to_scan(52560000)::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_name": "package1",
+            "version": "1.0.0",
+            "license": "Apache-2.0",
+            "containers_images": [
+                {
+                    "image": "quay.io/org/pkg1:1.0.0"
+                }
+            ],
+            "dependencies": [
+                {
+                    "name": "dep1",
+                    "version": "1.0.0",
+                    "repository": "https://repo.url"
+                }
+            ],
+            "vulnerability_allowlist": [
+                {
+                    "vulnerability_id": "CVE-2020-0001",
+                    "justification": "Not exploitable",
+                    "expires_at": "2021-01-01"
+                },
+                {
+                    "vulnerability_id": "CVE-2020-0002",
+                    "justification": "Fixed upstream",
+                    "expires_at": "2021-02-01"
+                }
             ]
         },
         {
             "package_id": "00000000-0000-0000-0000-000000000002",
+            "package_name": "package2",
             "version": "1.0.0",
+            "license": null,
             "containers_images": [
                 {
                     "image": "quay.io/org/pkg2:1.0.0",
                     "whitelisted": false
                 }
-            ]
+            ],
+            "dependencies": null,
+            "vulnerability_allowlist": null
         },
         {
-            "package_id": "00000000-0000-0000-0000-000000000003",
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_name": "package1",
             "version": "0.0.9",
+            "license": null,
             "containers_images": [
                 {
-                    "image": "quay.io/org/pkg3:0.0.9"
+                    "image": "quay.io/org/pkg1:0.0.9"
+                }
+            ],
+            "dependencies": null,
+            "vulnerability_allowlist": [
+                {
+                    "vulnerability_id": "CVE-2020-0001",
+                    "justification": "Not exploitable",
+                    "expires_at": "2021-01-01"
                 }
             ]
         }
     ]'::jsonb,
-    'Some snapshots to scan were expected'
+    'Snapshots with recent enough security reports should not be returned'
+);
+
+-- Snapshots whose vulnerability allowlist changed or expired since the last scan
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package5ID',
+    'package5',
+    '3.0.0',
+    :'repo2ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    containers_images,
+    vulnerability_allowlist,
+    security_report,
+    security_report_created_at,
+    security_report_allowlist
+) values (
+    :'package5ID',
+    '3.0.0',
+    '[{"image": "quay.io/org/pkg5:3.0.0"}]',
+    '[{"vulnerability_id": "CVE-2020-0002", "justification": "Fixed upstream", "expires_at": "2100-01-01"}]',
+    '{"k": "v"}',
+    current_timestamp,
+    '[{"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2100-01-01"}]'
+);
+insert into snapshot (
+    package_id,
+    version,
+    containers_images,
+    vulnerability_allowlist,
+    security_report,
+    security_report_created_at,
+    security_report_allowlist
+) values (
+    :'package5ID',
+    '2.0.0',
+    '[{"image": "quay.io/org/pkg5:2.0.0"}]',
+    jsonb_build_array(jsonb_build_object(
+        'vulnerability_id', 'CVE-2020-0001',
+        'justification', 'Not exploitable',
+        'expires_at', to_char(current_date - 2, 'YYYY-MM-DD')
+    )),
+    '{"k": "v"}',
+    current_timestamp - '3 days'::interval,
+    jsonb_build_array(jsonb_build_object(
+        'vulnerability_id', 'CVE-2020-0001',
+        'justification', 'Not exploitable',
+        'expires_at', to_char(current_date - 2, 'YYYY-MM-DD')
+    ))
+);
+insert into snapshot (
+    package_id,
+    version,
+    containers_images,
+    vulnerability_allowlist,
+    security_report,
+    security_report_created_at,
+    security_report_allowlist
+) values (
+    :'package5ID',
+    '1.0.0',
+    '[{"image": "quay.io/org/pkg5:1.0.0"}]',
+    '[{"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2100-01-01"}]',
+    '{"k": "v"}',
+    current_timestamp,
+    '[{"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2100-01-01"}]'
+);
+select is(
+    (
+        select jsonb_agg(s->>'version')
+        from jsonb_array_elements(get_snapshots_to_scan(52560000)::jsonb) s
+        where s->>'package_id' = :'package5ID'
+    ),
+    '["3.0.0", "2.0.0"]'::jsonb,
+    'Snapshots whose vulnerability allowlist changed or expired since the last scan should be returned'
 );
 
 -- Finish tests and rollback transaction
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(14);
+select plan(18);
 
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
@@ -13,6 +13,7 @@ values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com'
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into repository_tracking_run (repository_id) values (:'repo1ID');
 
 -- Register package
 select register_package('
@@ -80,6 +81,13 @@ select register_package('
     ],
     "contains_security_updates": true,
     "prerelease": true,
+    "vulnerability_allowlist": [
+        {
+            "vulnerability_id": "CVE-2020-0001",
+            "justification": "Not exploitable",
+            "expires_at": "2021-01-01"
+        }
+    ],
     "created_at": 1592299234,
     "maintainers": [
         {
@@ -160,6 +168,7 @@ select results_eq(
             s.changes,
             s.contains_security_updates,
             s.prerelease,
+            s.vulnerability_allowlist,
             s.created_at
         from snapshot s
         join package p using (package_id)
@@ -195,6 +204,7 @@ select results_eq(
             }'::text[],
             true,
             true,
+            '[{"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2021-01-01"}]'::jsonb,
             '2020-06-16 11:20:34+02'::timestamptz
         )
     $$,
@@ -245,6 +255,16 @@ select register_package('
     "digest": "digest-package1-2.0.0",
     "deprecated": true,
     "signed": true,
+    "provenance_verification": {
+        "verified": true,
+        "key_fingerprint": "ABCDEF0123456789",
+        "signer": "signer1 <signer1@email.com>"
+    },
+    "cosign_verification": {
+        "verified": true,
+        "digest": "sha256:0123",
+        "key_fingerprint": "0123456789abcdef"
+    },
     "is_operator": false,
     "capabilities": "seamless upgrades",
     "containers_images": [
@@ -291,6 +311,8 @@ select results_eq(
             s.capabilities,
             s.deprecated,
             s.signed,
+            s.provenance_verification,
+            s.cosign_verification,
             s.containers_images,
             s.provider,
             s.values_schema,
@@ -318,6 +340,8 @@ select results_eq(
             'seamless upgrades',
             true,
             true,
+            '{"verified": true, "key_fingerprint": "ABCDEF0123456789", "signer": "signer1 <signer1@email.com>"}'::jsonb,
+            '{"verified": true, "digest": "sha256:0123", "key_fingerprint": "0123456789abcdef"}'::jsonb,
             '[{"image": "quay.io/org/img:2.0.0"}]'::jsonb,
             'Org Inc 2',
             null::jsonb,
@@ -353,6 +377,20 @@ select is_empty(
     $$,
     'Orphan maintainers were deleted'
 );
+select results_eq(
+    $$
+        select e.data
+        from event e
+        join package p using (package_id)
+        where p.name = 'package1'
+        and e.package_version = '2.0.0'
+        and e.event_kind_id = 0
+    $$,
+    $$
+        values ('{"previous_version": "1.0.0", "prerelease": false, "contains_security_updates": false}'::jsonb)
+    $$,
+    'New release event should exist for package1 version 2.0.0'
+);
 select isnt_empty(
     $$
         select *
@@ -360,8 +398,9 @@ select isnt_empty(
         join package p using (package_id)
         where p.name = 'package1'
         and e.package_version = '2.0.0'
+        and e.event_kind_id = 5
     $$,
-    'New release event should exist for package1 version 2.0.0'
+    'Package deprecated event should exist for package1 version 2.0.0'
 );
 
 -- Register an old version of the package previously registered
@@ -490,6 +529,84 @@ select is_empty(
     'No new release event should exist for package1 version 0.0.9'
 );
 
+-- Check repository tracking run in progress stats have been updated
+select is(packages_registered, 3, 'Tracking run in progress should have 3 packages registered')
+from repository_tracking_run where repository_id = :'repo1ID';
+
+-- Register package with some dependencies and check they have been stored
+select register_package('
+{
+    "name": "package3",
+    "version": "1.0.0",
+    "data": {
+        "dependencies": [
+            {
+                "name": "dep1",
+                "version": "^1.0.0",
+                "repository": "https://repo1.com"
+            },
+            {
+                "name": "dep2",
+                "version": "2.x.x",
+                "repository": "oci://registry.io/charts",
+                "alias": "dep2-alias"
+            }
+        ]
+    },
+    "repository": {
+        "repository_id": "00000000-0000-0000-0000-000000000001"
+    }
+}
+');
+select results_eq(
+    $$
+        select d.name, d.version_constraint, d.repository_url, d.alias
+        from snapshot_dependency d
+        join package p using (package_id)
+        where p.name = 'package3'
+        and d.version = '1.0.0'
+        order by d.name
+    $$,
+    $$
+        values
+            ('dep1', '^1.0.0', 'https://repo1.com', null::text),
+            ('dep2', '2.x.x', 'oci://registry.io/charts', 'dep2-alias')
+    $$,
+    'Package3 version 1.0.0 dependencies should exist'
+);
+
+-- Register same package version again with different dependencies and check
+-- they have been replaced
+select register_package('
+{
+    "name": "package3",
+    "version": "1.0.0",
+    "data": {
+        "dependencies": [
+            {
+                "name": "dep3",
+                "version": "~3.1.0",
+                "repository": "https://repo3.com"
+            }
+        ]
+    },
+    "repository": {
+        "repository_id": "00000000-0000-0000-0000-000000000001"
+    }
+}
+');
+select results_eq(
+    $$
+        select d.name, d.version_constraint, d.repository_url, d.alias
+        from snapshot_dependency d
+        join package p using (package_id)
+        where p.name = 'package3'
+        and d.version = '1.0.0'
+    $$,
+    $$ values ('dep3', '~3.1.0', 'https://repo3.com', null::text) $$,
+    'Package3 version 1.0.0 dependencies should have been replaced'
+);
+
 -- Disable repository and check that trying to register a package raises an error
 update repository set disabled = true where repository_id = :'repo1ID';
 select throws_ok(
@@ -0,0 +1,58 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'https://repo1.com/charts/', 0, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'oci://registry.io/charts/package2', 0, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo3ID', 'repo3', 'https://repo3.com', 1, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo2ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'package3', '1.0.0', :'repo3ID');
+
+-- Run some tests
+select is(
+    resolve_package_dependency('package1', 'https://repo1.com/charts'),
+    :'package1ID'::uuid,
+    'Dependency should resolve to package1 (trailing slash in repository url ignored)'
+);
+select is(
+    resolve_package_dependency('package2', 'oci://registry.io/charts'),
+    :'package2ID'::uuid,
+    'OCI dependency should resolve to package2'
+);
+select is(
+    resolve_package_dependency('package1', 'https://repo2.com'),
+    null,
+    'Dependency in a repository not registered should not be resolved'
+);
+select is(
+    resolve_package_dependency('package3', 'https://repo3.com'),
+    null,
+    'Dependency in a non Helm repository should not be resolved'
+);
+select is(
+    resolve_package_dependency('package1', null),
+    null,
+    'Dependency without repository url should not be resolved'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(27);
+select plan(28);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -72,6 +72,7 @@ insert into snapshot (
     digest,
     readme,
     capabilities,
+    provenance_verification,
     created_at
 ) values (
     :'package1ID',
@@ -85,6 +86,7 @@ insert into snapshot (
     'digest-package1-1.0.0',
     'readme',
     'basic install',
+    '{"verified": true, "key_fingerprint": "ABCDEF0123456789"}',
     '2020-06-16 11:20:34+02'
 );
 insert into snapshot (
@@ -139,6 +141,7 @@ insert into snapshot (
     readme,
     deprecated,
     signed,
+    cosign_verification,
     created_at
 ) values (
     :'package2ID',
@@ -152,6 +155,7 @@ insert into snapshot (
     'readme',
     true,
     true,
+    '{"verified": true, "digest": "sha256:0123"}',
     '2020-06-16 11:20:34+02'
 );
 insert into snapshot (
@@ -518,6 +522,42 @@ select is(
     }'::jsonb,
     'VerifiedPublisher: true | Package 1 expected - No facets expected'
 );
+select is(
+    search_packages('{
+        "verified_signature": true
+    }')::jsonb,
+    '{
+        "data": {
+            "packages": [{
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "logo_image_id": "00000000-0000-0000-0000-000000000001",
+                "stars": 10,
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": "12.1.0",
+                "license": "Apache-2.0",
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "verified_publisher": true,
+                    "official": true,
+                    "user_alias": "user1"
+                }
+            }]
+        },
+        "metadata": {
+            "total": 1
+        }
+    }'::jsonb,
+    'VerifiedSignature: true | Package 1 expected - No facets expected'
+);
 select is(
     search_packages('{
         "official": true
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(10);
+select plan(13);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -8,11 +8,13 @@ select plan(10);
 \set package1ID '00000000-0000-0000-0000-000000000001'
 \set image1ID '00000000-0000-0000-0000-000000000001'
 \set maintainer1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository_tracking_run (repository_id) values (:'repo1ID');
 insert into package (
     package_id,
     name,
@@ -32,6 +34,12 @@ insert into maintainer (maintainer_id, name, email)
 values (:'maintainer1ID', 'name1', 'email1');
 insert into package__maintainer (package_id, maintainer_id)
 values (:'package1ID', :'maintainer1ID');
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 4);
+insert into webhook (webhook_id, name, url, active, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', true, :'user1ID');
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 4);
+insert into webhook__package (webhook_id, package_id) values (:'webhook1ID', :'package1ID');
 
 -- Run some tests
 select unregister_package('
@@ -53,6 +61,27 @@ select is_empty(
     $$ select * from snapshot where version='1.0.0' $$,
     'Package snapshot version 1.0.0 should have been deleted'
 );
+select results_eq(
+    $$
+        select package_id, package_version, data
+        from event
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+        and event_kind_id = 4
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '1.0.0',
+            '{
+                "package_name": "package1",
+                "package_normalized_name": "package1",
+                "subscriptors": [{"user_id": "00000000-0000-0000-0000-000000000001"}],
+                "webhooks": ["00000000-0000-0000-0000-000000000001"]
+            }'::jsonb
+        )
+    $$,
+    'Package version removed event should exist for package1 version 1.0.0'
+);
 select unregister_package('
 {
     "kind": 0,
@@ -105,6 +134,22 @@ select is_empty(
     $$ select * from package $$,
     'Package should have been deleted'
 );
+select results_eq(
+    $$
+        select package_id, data->'subscriptors', data->'webhooks'
+        from event
+        where package_version = '0.0.9-rc2'
+        and event_kind_id = 4
+    $$,
+    $$
+        values (
+            null::uuid,
+            '[{"user_id": "00000000-0000-0000-0000-000000000001"}]'::jsonb,
+            '["00000000-0000-0000-0000-000000000001"]'::jsonb
+        )
+    $$,
+    'Package version removed event should exist for deleted package1 including its subscriptors and webhooks'
+);
 select is_empty(
     $$ select * from package $$,
     'All package snapshots should have been deleted'
@@ -117,6 +162,8 @@ select is_empty(
     $$ select * from maintainer $$,
     'Orphan maintainer should have been deleted'
 );
+select is(packages_unregistered, 4, 'Tracking run in progress should have 4 packages unregistered')
+from repository_tracking_run where repository_id = :'repo1ID';
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(16);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -39,6 +39,10 @@ select is(security_report_created_at, null, 'Security report created at should b
 from snapshot where package_id = :'package1ID' and version = '1.0.0';
 select is(security_report_summary, null, 'Security report summary should be null')
 from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select is(sbom, null, 'SBOM should be null')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select is(security_report_allowlist, null, 'Security report allowlist should be null')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
 select update_snapshot_security_report('{
     "package_id": "00000000-0000-0000-0000-000000000001",
     "version": "1.0.0",
@@ -51,7 +55,14 @@ select update_snapshot_security_report('{
         "quay.io/org/pkg1:1.0.0": [
             {"k": "v"}
         ]
-    }
+    },
+    "sbom": {
+        "cyclonedx": {"bomFormat": "CycloneDX"},
+        "spdx": {"spdxVersion": "SPDX-2.2"}
+    },
+    "vulnerability_allowlist": [
+        {"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2021-01-01"}
+    ]
 }');
 select is(security_report, '{
     "quay.io/org/pkg1:1.0.0": [
@@ -67,6 +78,132 @@ select is(security_report_summary, '{
     "low": 10
 }', 'Security report summary should exist')
 from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select is(sbom, '{
+    "cyclonedx": {"bomFormat": "CycloneDX"},
+    "spdx": {"spdxVersion": "SPDX-2.2"}
+}', 'SBOM should exist')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select is(security_report_allowlist, '[
+    {"vulnerability_id": "CVE-2020-0001", "justification": "Not exploitable", "expires_at": "2021-01-01"}
+]', 'Security report allowlist should exist')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
+
+select is_empty(
+    $$ select * from event $$,
+    'No security alert event should be registered on the first scan'
+);
+
+-- Scan again finding new critical and high vulnerabilities
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": {
+        "critical": 1,
+        "high": 1,
+        "low": 1
+    },
+    "full": {
+        "quay.io/org/pkg1:1.0.0": [
+            {
+                "Target": "quay.io/org/pkg1:1.0.0 (alpine 3.12.0)",
+                "Vulnerabilities": [
+                    {"VulnerabilityID": "CVE-2", "Severity": "HIGH"},
+                    {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+                    {"VulnerabilityID": "CVE-3", "Severity": "LOW"}
+                ]
+            }
+        ]
+    }
+}');
+select results_eq(
+    $$
+        select package_id, package_version, event_kind_id, data
+        from event
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '1.0.0',
+            1,
+            '{"vulnerabilities": ["CVE-1", "CVE-2"]}'::jsonb
+        )
+    $$,
+    'Security alert event should be registered with the new vulnerabilities'
+);
+
+-- Scan again without finding new critical or high vulnerabilities (suppressed
+-- ones are not considered)
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": {
+        "critical": 1,
+        "low": 2,
+        "suppressed": 1
+    },
+    "full": {
+        "quay.io/org/pkg1:1.0.0": [
+            {
+                "Target": "quay.io/org/pkg1:1.0.0 (alpine 3.12.0)",
+                "Vulnerabilities": [
+                    {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+                    {"VulnerabilityID": "CVE-3", "Severity": "LOW"},
+                    {"VulnerabilityID": "CVE-4", "Severity": "LOW"},
+                    {
+                        "VulnerabilityID": "CVE-5",
+                        "Severity": "CRITICAL",
+                        "Suppression": {"Justification": "Not exploitable", "ExpiresAt": "2999-01-01"}
+                    }
+                ]
+            }
+        ]
+    }
+}');
+select is(
+    (select count(*) from event)::int,
+    1,
+    'No new security alert event should be registered'
+);
+select is(security_report_summary, '{
+    "critical": 1,
+    "low": 2,
+    "suppressed": 1
+}', 'Security report summary should have been updated')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
+
+-- Scan again failing to get a security report and then finding new critical
+-- vulnerabilities (the new report is used as baseline)
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": null,
+    "full": null
+}');
+select is(jsonb_typeof(security_report), 'null', 'Security report should be null after a failed scan')
+from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": {
+        "critical": 2
+    },
+    "full": {
+        "quay.io/org/pkg1:1.0.0": [
+            {
+                "Target": "quay.io/org/pkg1:1.0.0 (alpine 3.12.0)",
+                "Vulnerabilities": [
+                    {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+                    {"VulnerabilityID": "CVE-6", "Severity": "CRITICAL"}
+                ]
+            }
+        ]
+    }
+}');
+select is(
+    (select count(*) from event)::int,
+    1,
+    'No new security alert event should be registered when there is no previous report'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
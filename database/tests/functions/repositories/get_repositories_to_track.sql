@@ -0,0 +1,46 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set repo4ID '00000000-0000-0000-0000-000000000004'
+\set repo5ID '00000000-0000-0000-0000-000000000005'
+
+-- No repositories at this point
+select is(
+    get_repositories_to_track(30)::jsonb,
+    '[]'::jsonb,
+    'With no repositories an empty json array is returned'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, last_tracking_ts)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID', current_timestamp - '1 hour'::interval);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, last_tracking_ts, tracking_interval)
+values (:'repo3ID', 'repo3', 'Repo 3', 'https://repo3.com', 0, :'user1ID', current_timestamp - '1 hour'::interval, 120);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, last_tracking_ts, tracking_requested_at)
+values (:'repo4ID', 'repo4', 'Repo 4', 'https://repo4.com', 0, :'user1ID', current_timestamp, current_timestamp);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, disabled)
+values (:'repo5ID', 'repo5', 'Repo 5', 'https://repo5.com', 0, :'user1ID', true);
+
+-- Run some tests
+select is(
+    (
+        select json_agg(r->>'name')::jsonb
+        from json_array_elements((select get_repositories_to_track(30))) r
+    ),
+    '["repo4", "repo1", "repo2"]'::jsonb,
+    'Repositories 4 (requested), 1 (never tracked) and 2 (default interval elapsed) are returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -27,7 +27,8 @@ insert into repository (
     repository_kind_id,
     user_id,
     last_tracking_ts,
-    last_tracking_errors
+    last_tracking_errors,
+    signing_keys_digest
 )
 values (
     :'repo1ID',
@@ -41,7 +42,8 @@ values (
     0,
     :'user1ID',
     '2020-06-16 11:20:34+02',
-    'error1\nerror2\n'
+    'error1\nerror2\n',
+    'keys-digest'
 );
 
 -- One repository has just been seeded
@@ -83,6 +85,7 @@ select is(
         "digest": "digest",
         "last_tracking_ts": 1592299234,
         "last_tracking_errors": "error1\\nerror2\\n",
+        "signing_keys_digest": "keys-digest",
         "user_alias": "user1"
     }'::jsonb,
     'Repository just seeded is returned as a json object which includes the credentials'
@@ -0,0 +1,123 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set run1ID '00000000-0000-0000-0000-000000000001'
+\set run2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+
+-- Try to get the tracking runs of a repository that does not exist
+select throws_ok(
+    $$
+        select get_repository_tracking_runs('00000000-0000-0000-0000-000000000001', 'repo3')
+    $$,
+    'P0002',
+    'no_data_found',
+    'Getting tracking runs should fail because the repository does not exist'
+);
+
+-- Try to get the tracking runs of a repository owned by a user by other user
+select throws_ok(
+    $$
+        select get_repository_tracking_runs('00000000-0000-0000-0000-000000000002', 'repo1')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking runs should fail because requesting user is not the owner'
+);
+
+-- Try to get the tracking runs of a repository owned by organization by user
+-- not belonging to it
+select throws_ok(
+    $$
+        select get_repository_tracking_runs('00000000-0000-0000-0000-000000000001', 'repo2')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking runs should fail because requesting user does not belong to owning organization'
+);
+
+-- Run some tests before registering any tracking runs
+select is(
+    get_repository_tracking_runs(:'user1ID', 'repo1')::jsonb,
+    '[]'::jsonb,
+    'An empty list should be returned when the repository has no tracking runs'
+);
+
+-- Register some tracking runs and run some more tests
+insert into repository_tracking_run (
+    repository_tracking_run_id,
+    repository_id,
+    started_at,
+    finished_at,
+    packages_registered,
+    packages_unregistered,
+    digest_before,
+    digest_after,
+    warnings
+) values (
+    :'run1ID',
+    :'repo1ID',
+    '2020-06-16 11:00:00+02',
+    '2020-06-16 11:01:00+02',
+    2,
+    1,
+    'digest1',
+    'digest2',
+    'some warnings'
+);
+insert into repository_tracking_run (
+    repository_tracking_run_id,
+    repository_id,
+    started_at,
+    digest_before
+) values (
+    :'run2ID',
+    :'repo1ID',
+    '2020-06-16 12:00:00+02',
+    'digest2'
+);
+select is(
+    get_repository_tracking_runs(:'user1ID', 'repo1')::jsonb,
+    '[
+        {
+            "tracking_run_id": "00000000-0000-0000-0000-000000000002",
+            "started_at": 1592301600,
+            "finished_at": null,
+            "packages_registered": 0,
+            "packages_unregistered": 0,
+            "digest_before": "digest2",
+            "digest_after": null,
+            "warnings": null
+        },
+        {
+            "tracking_run_id": "00000000-0000-0000-0000-000000000001",
+            "started_at": 1592298000,
+            "finished_at": 1592298060,
+            "packages_registered": 2,
+            "packages_unregistered": 1,
+            "digest_before": "digest1",
+            "digest_after": "digest2",
+            "warnings": "some warnings"
+        }
+    ]'::jsonb,
+    'Tracking runs should be returned as a json array, most recent first'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
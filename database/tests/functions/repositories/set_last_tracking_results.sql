@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(15);
+select plan(19);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -62,6 +62,19 @@ from repository where name = 'repo1';
 select is(count(*), 2::bigint, 'No more tracking error events should have been registered')
 from event where repository_id=:'repo1ID' and event_kind_id = 2;
 
+-- Set last tracking results with a tracking run in progress and run some more tests
+update repository set digest = 'digest-after' where repository_id = :'repo1ID';
+insert into repository_tracking_run (repository_id, digest_before) values (:'repo1ID', 'digest-before');
+select set_last_tracking_results(:'repo1ID', 'some warnings', false);
+select isnt(finished_at, null, 'Tracking run should have been finished')
+from repository_tracking_run where repository_id = :'repo1ID';
+select is(digest_before, 'digest-before', 'Tracking run digest before should not have changed')
+from repository_tracking_run where repository_id = :'repo1ID';
+select is(digest_after, 'digest-after', 'Tracking run digest after should have been set')
+from repository_tracking_run where repository_id = :'repo1ID';
+select is(warnings, 'some warnings', 'Tracking run warnings should have been set')
+from repository_tracking_run where repository_id = :'repo1ID';
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
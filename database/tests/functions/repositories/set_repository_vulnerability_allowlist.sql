@@ -0,0 +1,47 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+
+-- Run some tests before setting the vulnerability allowlist for the first time
+select is(vulnerability_allowlist, null, 'Vulnerability allowlist should be null initially')
+from repository where name = 'repo1';
+
+-- Set vulnerability allowlist and run some more tests
+select set_repository_vulnerability_allowlist(:'repo1ID', '[
+    {
+        "vulnerability_id": "CVE-2020-0001",
+        "justification": "Not exploitable",
+        "expires_at": "2021-01-01"
+    }
+]');
+select is(
+    vulnerability_allowlist,
+    '[
+        {
+            "vulnerability_id": "CVE-2020-0001",
+            "justification": "Not exploitable",
+            "expires_at": "2021-01-01"
+        }
+    ]'::jsonb,
+    'Vulnerability allowlist should have been set'
+)
+from repository where name = 'repo1';
+
+-- Clear vulnerability allowlist and run some more tests
+select set_repository_vulnerability_allowlist(:'repo1ID', '[]');
+select is(vulnerability_allowlist, null, 'Vulnerability allowlist should be null after clearing it')
+from repository where name = 'repo1';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
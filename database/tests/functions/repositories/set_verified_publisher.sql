@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(5);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -20,6 +20,26 @@ from repository where name = 'repo1';
 select set_verified_publisher(:'repo1ID', true);
 select is(verified_publisher, true, 'Verified publisher should be now true')
 from repository where name = 'repo1';
+select results_eq(
+    $$
+        select data
+        from event
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+        and event_kind_id = 7
+    $$,
+    $$ values ('{"verified_publisher": true}'::jsonb) $$,
+    'Repository verified publisher changed event should have been registered'
+);
+
+-- Set verified publisher to the same value and run some more tests
+select set_verified_publisher(:'repo1ID', true);
+select is(count(*), 1::bigint, 'No new verified publisher changed events should have been registered')
+from event where repository_id = :'repo1ID' and event_kind_id = 7;
+
+-- Unset verified publisher and run some more tests
+select set_verified_publisher(:'repo1ID', false);
+select is(count(*), 2::bigint, 'Another verified publisher changed event should have been registered')
+from event where repository_id = :'repo1ID' and event_kind_id = 7;
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -0,0 +1,51 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, digest, tracking_requested_at)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', 'digest', current_timestamp);
+
+-- Start repository tracking and run some tests
+select start_repository_tracking(:'repo1ID');
+select is(tracking_requested_at, null, 'Pending tracking request should have been cleared')
+from repository where repository_id = :'repo1ID';
+select results_eq(
+    $$
+        select digest_before, finished_at is null
+        from repository_tracking_run
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('digest', true)
+    $$,
+    'A tracking run in progress should have been registered'
+);
+
+-- Start repository tracking again and run some more tests
+select start_repository_tracking(:'repo1ID');
+select is(count(*), 2::bigint, 'Two tracking runs should exist')
+from repository_tracking_run where repository_id = :'repo1ID';
+select is(count(*), 1::bigint, 'Only one tracking run should be in progress')
+from repository_tracking_run where repository_id = :'repo1ID' and finished_at is null;
+
+-- Register some old tracking runs, start repository tracking again and run
+-- some more tests
+insert into repository_tracking_run (repository_id, started_at, finished_at)
+select :'repo1ID', current_timestamp - (n || ' hours')::interval, current_timestamp - (n || ' hours')::interval
+from generate_series(1, 60) as n;
+select start_repository_tracking(:'repo1ID');
+select is(count(*), 50::bigint, 'Only the most recent 50 tracking runs should have been kept')
+from repository_tracking_run where repository_id = :'repo1ID';
+select is(count(*), 1::bigint, 'The new tracking run should have been kept')
+from repository_tracking_run where repository_id = :'repo1ID' and finished_at is null;
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
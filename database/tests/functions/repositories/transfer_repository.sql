@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(13);
+select plan(15);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -28,6 +28,8 @@ insert into repository (repository_id, name, display_name, url, repository_kind_
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
 values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user2ID', :'org1ID', 6);
 
 -- Transfers NOT part of an ownership claim request
 
@@ -124,6 +126,23 @@ select results_eq(
 );
 select is(count(*), 0::bigint, 'No repository ownership claim events should have been registered')
 from event where repository_id=:'repo2ID' and event_kind_id = 3;
+select results_eq(
+    $$
+        select data
+        from event
+        where repository_id = '00000000-0000-0000-0000-000000000002'
+        and event_kind_id = 6
+        order by created_at desc
+        limit 1
+    $$,
+    $$
+        values ('{
+            "previous_owner": {"organization_name": "org1"},
+            "subscriptors": [{"user_id": "00000000-0000-0000-0000-000000000002"}]
+        }'::jsonb)
+    $$,
+    'Repository transferred event should have been registered including the previous owner subscriptors'
+);
 
 -- Transfer user owned repository to org
 select transfer_repository(
@@ -202,6 +221,8 @@ select results_eq(
 );
 select is(count(*), 2::bigint, 'Another repository ownership claim event should have been registered')
 from event where repository_id=:'repo1ID' and event_kind_id = 3;
+select is(count(*), 3::bigint, 'A repository transferred event should have been registered for each transfer')
+from event where repository_id=:'repo1ID' and event_kind_id = 6;
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -1,20 +1,25 @@
 -- Start transaction and plan tests
 begin;
-select plan(1);
+select plan(5);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into package (package_id, name, latest_version, repository_id)
 values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
 
--- Add subscription
+-- Add package subscription
 select add_subscription('
 {
     "user_id": "00000000-0000-0000-0000-000000000001",
@@ -22,8 +27,6 @@ select add_subscription('
     "event_kind": 0
 }
 '::jsonb);
-
--- Check if subscription was added successfully
 select results_eq(
     $$
         select
@@ -41,6 +44,90 @@ select results_eq(
     $$,
     'Subscription should exist'
 );
+select is_empty(
+    $$ select * from package_opt_out $$,
+    'Package opt-out entry should have been deleted'
+);
+
+-- Add repository subscription
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 1,
+    "filter": {
+        "exclude_prereleases": true
+    }
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            user_id,
+            repository_id,
+            event_kind_id,
+            filter
+        from repository_subscription
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            1,
+            '{"exclude_prereleases": true}'::jsonb
+        )
+    $$,
+    'Repository subscription should exist'
+);
+
+-- Add organization subscription
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "organization_name": "org1",
+    "event_kind": 0
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            user_id,
+            organization_id,
+            event_kind_id
+        from organization_subscription
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            0
+        )
+    $$,
+    'Organization subscription should exist'
+);
+
+-- Adding an existing subscription updates its filter
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0,
+    "filter": {
+        "version_constraint": ">=2.0.0 <3",
+        "update_level": "minor"
+    }
+}
+'::jsonb);
+select results_eq(
+    $$
+        select filter
+        from subscription
+    $$,
+    $$
+        values ('{"version_constraint": ">=2.0.0 <3", "update_level": "minor"}'::jsonb)
+    $$,
+    'Subscription filter should have been updated'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
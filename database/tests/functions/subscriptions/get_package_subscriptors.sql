@@ -1,32 +1,57 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(6);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
 \set user3ID '00000000-0000-0000-0000-000000000003'
+\set user4ID '00000000-0000-0000-0000-000000000004'
+\set user5ID '00000000-0000-0000-0000-000000000005'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 \set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email)
 values (:'user1ID', 'user1', 'user1@email.com');
 insert into "user" (user_id, alias, email)
 values (:'user2ID', 'user2', 'user2@email.com');
 insert into "user" (user_id, alias, email)
 values (:'user3ID', 'user3', 'user3@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user4ID', 'user4', 'user4@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user5ID', 'user5', 'user5@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
 insert into package (package_id, name, latest_version, repository_id)
 values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'Package 3', '1.0.0', :'repo2ID');
 insert into subscription (user_id, package_id, event_kind_id)
 values (:'user1ID', :'package1ID', 0);
-insert into subscription (user_id, package_id, event_kind_id)
-values (:'user2ID', :'package1ID', 0);
+insert into subscription (user_id, package_id, event_kind_id, filter)
+values (:'user2ID', :'package1ID', 0, '{"update_level": "major"}');
 insert into subscription (user_id, package_id, event_kind_id)
 values (:'user3ID', :'package1ID', 1);
+insert into repository_subscription (user_id, repository_id, event_kind_id, filter)
+values (:'user4ID', :'repo1ID', 0, '{"security_updates_only": true}');
+insert into repository_subscription (user_id, repository_id, event_kind_id, filter)
+values (:'user2ID', :'repo1ID', 0, '{"exclude_prereleases": true}');
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user4ID', :'org1ID', 1);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user5ID', :'org1ID', 1);
+insert into opt_out (user_id, repository_id, event_kind_id)
+values (:'user5ID', :'repo2ID', 1);
 
 -- Run some tests
 select is(
@@ -36,16 +61,55 @@ select is(
             "user_id": "00000000-0000-0000-0000-000000000001"
         },
         {
-            "user_id": "00000000-0000-0000-0000-000000000002"
+            "user_id": "00000000-0000-0000-0000-000000000002",
+            "filter": {
+                "update_level": "major"
+            }
+        },
+        {
+            "user_id": "00000000-0000-0000-0000-000000000004",
+            "filter": {
+                "security_updates_only": true
+            }
+        }
+    ]'::jsonb,
+    'Three subscriptors expected for package1 and kind new releases (package subscription filter takes precedence)'
+);
+select is(
+    get_package_subscriptors(:'package1ID', 1)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000003"
         }
     ]'::jsonb,
-    'Two subscriptors expected for package1 and kind new releases'
+    'One subscriptor expected for package1 and kind security alert'
 );
 select is(
     get_package_subscriptors(:'package2ID', 0)::jsonb,
     '[]'::jsonb,
     'No subscriptors expected for package2 and kind new releases'
 );
+select is(
+    get_package_subscriptors(:'package3ID', 1)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000004"
+        }
+    ]'::jsonb,
+    'One subscriptor expected for package3 and kind security alert (user5 opted out from repo2)'
+);
+select is(
+    get_package_subscriptors(:'package3ID', 0)::jsonb,
+    '[]'::jsonb,
+    'No subscriptors expected for package3 and kind new releases'
+);
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user4ID', :'package3ID', 1);
+select is(
+    get_package_subscriptors(:'package3ID', 1)::jsonb,
+    '[]'::jsonb,
+    'No subscriptors expected for package3 and kind security alert (user4 opted out from package3)'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
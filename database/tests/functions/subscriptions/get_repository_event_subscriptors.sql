@@ -0,0 +1,66 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user3ID', 'user3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo2ID', 6);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user2ID', :'repo2ID', 7);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user2ID', :'org1ID', 6);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user3ID', :'org1ID', 6);
+insert into opt_out (user_id, repository_id, event_kind_id) values (:'user3ID', :'repo2ID', 6);
+
+-- Run some tests
+select is(
+    get_repository_event_subscriptors(:'repo1ID', 6)::jsonb,
+    '[]'::jsonb,
+    'No subscriptors expected for repo1'
+);
+select is(
+    get_repository_event_subscriptors(:'repo2ID', 6)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000001"
+        },
+        {
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }
+    ]'::jsonb,
+    'Two subscriptors expected for repo2 and kind6'
+);
+select is(
+    get_repository_event_subscriptors(:'repo2ID', 7)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }
+    ]'::jsonb,
+    'One subscriptor expected for repo2 and kind7'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
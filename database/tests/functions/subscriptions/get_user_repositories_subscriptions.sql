@@ -0,0 +1,71 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo1ID', 0);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo1ID', 1);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo2ID', 0);
+
+-- Run some tests
+select is(
+    get_user_repositories_subscriptions(:'user1ID')::jsonb,
+    '[{
+        "repository": {
+            "repository_id": "00000000-0000-0000-0000-000000000001",
+            "name": "repo1",
+            "display_name": "Repo 1",
+            "url": "https://repo1.com",
+            "private": false,
+            "kind": 0,
+            "verified_publisher": false,
+            "official": false,
+            "user_alias": "user1"
+        },
+        "event_kinds": [0, 1]
+    }, {
+        "repository": {
+            "repository_id": "00000000-0000-0000-0000-000000000002",
+            "name": "repo2",
+            "display_name": "Repo 2",
+            "url": "https://repo2.com",
+            "private": false,
+            "kind": 0,
+            "verified_publisher": false,
+            "official": false,
+            "organization_name": "org1",
+            "organization_display_name": "Organization 1"
+        },
+        "event_kinds": [0]
+    }]'::jsonb,
+    'Two repositories subscriptions should be returned'
+);
+select is(
+    get_user_repositories_subscriptions(:'user2ID')::jsonb,
+    '[]',
+    'No repositories subscriptions expected for user2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
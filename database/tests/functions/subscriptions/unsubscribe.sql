@@ -0,0 +1,113 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user2ID', :'package1ID', 0);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user2ID', :'repo1ID', 0);
+
+-- Unsubscribe user1 from package1 new releases
+select unsubscribe('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0,
+    "package_id": "00000000-0000-0000-0000-000000000001"
+}
+'::jsonb);
+select is_empty(
+    $$
+        select * from subscription
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    'User1 subscription to package1 should have been deleted'
+);
+select is_empty(
+    $$
+        select * from opt_out
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    'No opt-out entry should have been added for user1'
+);
+select is_empty(
+    $$
+        select * from package_opt_out
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    'No package opt-out entry should have been added for user1'
+);
+
+-- Unsubscribe user2 (also subscribed to the repository) from package1 new releases
+select unsubscribe('
+{
+    "user_id": "00000000-0000-0000-0000-000000000002",
+    "event_kind": 0,
+    "package_id": "00000000-0000-0000-0000-000000000001"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            (select count(*) from subscription where user_id = '00000000-0000-0000-0000-000000000002'),
+            user_id,
+            package_id,
+            event_kind_id
+        from package_opt_out
+    $$,
+    $$
+        values (
+            0::bigint,
+            '00000000-0000-0000-0000-000000000002'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            0
+        )
+    $$,
+    'User2 subscription should have been deleted and a package opt-out entry added'
+);
+select is_empty(
+    $$ select * from opt_out $$,
+    'No repository opt-out entry should have been added for user2'
+);
+
+-- Unsubscribe user1 from repo1 tracking errors
+select unsubscribe('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 2,
+    "repository_id": "00000000-0000-0000-0000-000000000001"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select user_id, repository_id, event_kind_id
+        from opt_out
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            2
+        )
+    $$,
+    'An opt-out entry for user1 tracking errors should have been added'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -32,7 +32,8 @@ select is(
         "first_name": "firstname",
         "last_name": "lastname",
         "email": "user1@email.com",
-        "profile_image_id": "00000000-0000-0000-0000-000000000001"
+        "profile_image_id": "00000000-0000-0000-0000-000000000001",
+        "email_delivery_preference": 0
     }
     '::jsonb,
     'User1 should exist'
@@ -30,7 +30,8 @@ select update_user_profile(:'user1ID', '
     "alias": "user1 updated",
     "first_name": "firstname updated",
     "last_name": "lastname updated",
-    "profile_image_id": "00000000-0000-0000-0000-000000000002"
+    "profile_image_id": "00000000-0000-0000-0000-000000000002",
+    "email_delivery_preference": 2
 }
 '::jsonb);
 
@@ -43,7 +44,8 @@ select results_eq(
             last_name,
             email,
             password,
-            profile_image_id
+            profile_image_id,
+            email_delivery_preference_id
         from "user"
     $$,
     $$
@@ -53,10 +55,11 @@ select results_eq(
             'lastname updated',
             'user1@email.com',
             'password',
-            '00000000-0000-0000-0000-000000000002'::uuid
+            '00000000-0000-0000-0000-000000000002'::uuid,
+            2
         )
     $$,
-    'User first and last name should have been updated'
+    'User profile should have been updated'
 );
 
 -- Finish tests and rollback transaction
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(5);
+select plan(7);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -26,6 +26,12 @@ select add_webhook(:'user1ID', null, '
     "description": "description",
     "url": "http://webhook1.url",
     "secret": "very",
+    "secondary_secret": "very2",
+    "sign_payloads": true,
+    "channel": 1,
+    "filter": {
+        "exclude_prereleases": true
+    },
     "content_type": "application/json",
     "template": "custom payload",
     "active": true,
@@ -34,6 +40,16 @@ select add_webhook(:'user1ID', null, '
         {
             "package_id": "00000000-0000-0000-0000-000000000001"
         }
+    ],
+    "repositories": [
+        {
+            "repository_id": "00000000-0000-0000-0000-000000000001"
+        }
+    ],
+    "organizations": [
+        {
+            "name": "org1"
+        }
     ]
 }
 '::jsonb);
@@ -44,6 +60,10 @@ select results_eq(
             description,
             url,
             secret,
+            secondary_secret,
+            sign_payloads,
+            webhook_channel_id,
+            filter,
             content_type,
             template,
             active,
@@ -58,6 +78,10 @@ select results_eq(
             'description',
             'http://webhook1.url',
             'very',
+            'very2',
+            true,
+            1,
+            '{"exclude_prereleases": true}'::jsonb,
             'application/json',
             'custom payload',
             true,
@@ -91,6 +115,30 @@ select results_eq(
     $$,
     'Webhook1 should be linked to package1'
 );
+select results_eq(
+    $$
+        select repository_id
+        from webhook__repository wr
+        join webhook w using (webhook_id)
+        where w.name = 'webhook1'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook1 should be linked to repo1'
+);
+select results_eq(
+    $$
+        select organization_id
+        from webhook__organization wo
+        join webhook w using (webhook_id)
+        where w.name = 'webhook1'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook1 should be linked to org1'
+);
 
 -- When an owning user and organization are provided, the organization takes precedence
 select add_webhook(:'user1ID', 'org1', '
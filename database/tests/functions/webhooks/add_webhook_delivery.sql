@@ -0,0 +1,73 @@
+-- Start transaction and plan tests
+begin;
+select plan(1);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+
+-- Add webhook delivery
+select add_webhook_delivery('
+{
+    "notification_id": "00000000-0000-0000-0000-000000000001",
+    "attempt": 1,
+    "request_payload": "{\"key\": \"value\"}",
+    "response_status": 500,
+    "response_body": "internal error",
+    "latency": 120,
+    "success": false,
+    "error": "unexpected status code: 500"
+}
+');
+
+-- Run some tests
+select results_eq(
+    $$
+        select
+            attempt,
+            request_payload,
+            response_status,
+            response_body,
+            latency,
+            success,
+            error,
+            notification_id,
+            webhook_id
+        from webhook_delivery
+    $$,
+    $$
+        values (
+            1,
+            '{"key": "value"}',
+            500,
+            'internal error',
+            120,
+            false,
+            'unexpected status code: 500',
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid
+        )
+    $$,
+    'Webhook delivery should have been registered'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,42 @@
+-- Start transaction and plan tests
+begin;
+select plan(1);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set delivery1ID '00000000-0000-0000-0000-000000000001'
+\set delivery2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+insert into webhook_delivery (webhook_delivery_id, attempt, latency, success, notification_id, webhook_id, created_at)
+values (:'delivery1ID', 1, 100, false, :'notification1ID', :'webhook1ID', current_timestamp - '31 days'::interval);
+insert into webhook_delivery (webhook_delivery_id, attempt, latency, success, notification_id, webhook_id, created_at)
+values (:'delivery2ID', 2, 100, true, :'notification1ID', :'webhook1ID', current_timestamp - '1 day'::interval);
+
+-- Run some tests
+select delete_old_webhook_deliveries(2592000);
+select results_eq(
+    $$ select webhook_delivery_id from webhook_delivery $$,
+    $$ values ('00000000-0000-0000-0000-000000000002'::uuid) $$,
+    'Only the delivery within the retention period should remain'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -74,6 +74,8 @@ select is(
             "description": "description",
             "url": "http://webhook1.url",
             "secret": "very",
+            "sign_payloads": false,
+            "channel": 0,
             "content_type": "application/json",
             "template": "custom payload",
             "active": true,
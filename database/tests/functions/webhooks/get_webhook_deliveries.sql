@@ -0,0 +1,132 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set delivery1ID '00000000-0000-0000-0000-000000000001'
+\set delivery2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+
+-- No deliveries yet
+select is(
+    get_webhook_deliveries(:'user1ID', :'webhook1ID')::jsonb,
+    '[]'::jsonb,
+    'No deliveries expected'
+);
+
+-- Register some deliveries
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+insert into webhook_delivery (
+    webhook_delivery_id,
+    created_at,
+    attempt,
+    request_payload,
+    response_status,
+    response_body,
+    latency,
+    success,
+    error,
+    notification_id,
+    webhook_id
+) values (
+    :'delivery1ID',
+    '2020-06-16 11:20:34+02',
+    1,
+    'payload',
+    500,
+    'internal error',
+    100,
+    false,
+    'unexpected status code: 500',
+    :'notification1ID',
+    :'webhook1ID'
+);
+insert into webhook_delivery (
+    webhook_delivery_id,
+    created_at,
+    attempt,
+    request_payload,
+    response_status,
+    latency,
+    success,
+    notification_id,
+    webhook_id
+) values (
+    :'delivery2ID',
+    '2020-06-16 11:21:34+02',
+    2,
+    'payload',
+    200,
+    50,
+    true,
+    :'notification1ID',
+    :'webhook1ID'
+);
+
+-- Run some tests
+select is(
+    get_webhook_deliveries(:'user1ID', :'webhook1ID')::jsonb,
+    '[
+        {
+            "webhook_delivery_id": "00000000-0000-0000-0000-000000000002",
+            "notification_id": "00000000-0000-0000-0000-000000000001",
+            "event_id": "00000000-0000-0000-0000-000000000001",
+            "event_kind": 0,
+            "created_at": 1592299294,
+            "attempt": 2,
+            "request_payload": "payload",
+            "response_status": 200,
+            "latency": 50,
+            "success": true
+        },
+        {
+            "webhook_delivery_id": "00000000-0000-0000-0000-000000000001",
+            "notification_id": "00000000-0000-0000-0000-000000000001",
+            "event_id": "00000000-0000-0000-0000-000000000001",
+            "event_kind": 0,
+            "created_at": 1592299234,
+            "attempt": 1,
+            "request_payload": "payload",
+            "response_status": 500,
+            "response_body": "internal error",
+            "latency": 100,
+            "success": false,
+            "error": "unexpected status code: 500"
+        }
+    ]'::jsonb,
+    'Webhook deliveries should be returned, most recent first'
+);
+select throws_ok(
+    $$
+        select get_webhook_deliveries(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Deliveries should not be returned to users without access to the webhook'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
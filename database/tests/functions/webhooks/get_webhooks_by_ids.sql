@@ -0,0 +1,86 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set webhook3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook1ID',
+    'webhook1',
+    'http://webhook1.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 4);
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook2ID',
+    'webhook2',
+    'http://webhook2.url',
+    false,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook2ID', 4);
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook3ID',
+    'webhook3',
+    'http://webhook3.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook3ID', 0);
+
+-- Run some tests
+select is(
+    get_webhooks_by_ids(4, array[:'webhook1ID', :'webhook2ID', :'webhook3ID']::uuid[])::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000001",
+            "name": "webhook1",
+            "url": "http://webhook1.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [4]
+        }
+    ]'::jsonb,
+    'Only active webhook1 subscribed to kind4 should be returned'
+);
+select is(
+    get_webhooks_by_ids(4, array[:'webhook3ID']::uuid[])::jsonb,
+    '[]',
+    'No webhooks should be returned for kind4 and webhook3'
+);
+select is(
+    get_webhooks_by_ids(4, '{}'::uuid[])::jsonb,
+    '[]',
+    'No webhooks should be returned when no ids are provided'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,21 +1,30 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(5);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 \set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
 \set image1ID '00000000-0000-0000-0000-000000000001'
 \set webhook1ID '00000000-0000-0000-0000-000000000001'
 \set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set webhook3ID '00000000-0000-0000-0000-000000000003'
+\set webhook4ID '00000000-0000-0000-0000-000000000004'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email)
 values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
 insert into package (
     package_id,
     name,
@@ -76,6 +85,47 @@ insert into webhook (
 );
 insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook2ID', 0);
 insert into webhook__package (webhook_id, package_id) values (:'webhook2ID', :'package1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package3ID',
+    'Package 3',
+    '1.0.0',
+    :'repo2ID'
+);
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook3ID',
+    'webhook3',
+    'http://webhook3.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook3ID', 0);
+insert into webhook__organization (webhook_id, organization_id) values (:'webhook3ID', :'org1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook4ID',
+    'webhook4',
+    'http://webhook4.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook4ID', 1);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook4ID', :'repo2ID');
 
 -- Run some tests
 select is(
@@ -87,6 +137,8 @@ select is(
             "description": "description",
             "url": "http://webhook1.url",
             "secret": "very",
+            "sign_payloads": false,
+            "channel": 0,
             "content_type": "application/json",
             "template": "custom payload",
             "active": true,
@@ -127,6 +179,56 @@ select is(
     '[]',
     'No webhooks should be returned for kind0 and package2'
 );
+select is(
+    get_webhooks_subscribed_to_package(0, :'package3ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000003",
+            "name": "webhook3",
+            "url": "http://webhook3.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [0],
+            "organizations": [
+                {
+                    "name": "org1",
+                    "display_name": "Organization 1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook3 should be returned when asking for kind0 and package3 (organization scope)'
+);
+select is(
+    get_webhooks_subscribed_to_package(1, :'package3ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000004",
+            "name": "webhook4",
+            "url": "http://webhook4.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [1],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000002",
+                    "kind": 0,
+                    "name": "repo2",
+                    "display_name": "Repo 2",
+                    "url": "https://repo2.com",
+                    "private": false,
+                    "verified_publisher": false,
+                    "official": false,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook4 should be returned when asking for kind1 and package3 (repository scope)'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
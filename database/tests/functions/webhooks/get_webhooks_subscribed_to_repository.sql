@@ -0,0 +1,176 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set webhook3ID '00000000-0000-0000-0000-000000000003'
+\set webhook4ID '00000000-0000-0000-0000-000000000004'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook1ID',
+    'webhook1',
+    'http://webhook1.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 6);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook1ID', :'repo1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook2ID',
+    'webhook2',
+    'http://webhook2.url',
+    false,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook2ID', 6);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook2ID', :'repo1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook3ID',
+    'webhook3',
+    'http://webhook3.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook3ID', 7);
+insert into webhook__organization (webhook_id, organization_id) values (:'webhook3ID', :'org1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook4ID',
+    'webhook4',
+    'http://webhook4.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook4ID', 2);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook4ID', :'repo1ID');
+
+-- Run some tests
+select is(
+    get_webhooks_subscribed_to_repository(6, :'repo1ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000001",
+            "name": "webhook1",
+            "url": "http://webhook1.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [6],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "private": false,
+                    "verified_publisher": false,
+                    "official": false,
+                    "user_alias": "user1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook1 should be returned when asking for kind6 and repo1'
+);
+select is(
+    get_webhooks_subscribed_to_repository(7, :'repo1ID')::jsonb,
+    '[]',
+    'No webhooks should be returned for kind7 and repo1'
+);
+select is(
+    get_webhooks_subscribed_to_repository(6, :'repo2ID')::jsonb,
+    '[]',
+    'No webhooks should be returned for kind6 and repo2'
+);
+select is(
+    get_webhooks_subscribed_to_repository(7, :'repo2ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000003",
+            "name": "webhook3",
+            "url": "http://webhook3.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [7],
+            "organizations": [
+                {
+                    "name": "org1",
+                    "display_name": "Organization 1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook3 should be returned when asking for kind7 and repo2 (organization scope)'
+);
+select is(
+    get_webhooks_subscribed_to_repository(2, :'repo1ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000004",
+            "name": "webhook4",
+            "url": "http://webhook4.url",
+            "sign_payloads": false,
+            "channel": 0,
+            "active": true,
+            "event_kinds": [2],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "private": false,
+                    "verified_publisher": false,
+                    "official": false,
+                    "user_alias": "user1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook4 should be returned when asking for kind2 (tracking errors) and repo1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,71 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set delivery1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into notification (notification_id, event_id, webhook_id, processed, processed_at, attempts, dead, error)
+values (:'notification1ID', :'event1ID', :'webhook1ID', true, current_timestamp, 10, true, 'fake error');
+insert into webhook_delivery (webhook_delivery_id, attempt, latency, success, notification_id, webhook_id)
+values (:'delivery1ID', 10, 100, false, :'notification1ID', :'webhook1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select redeliver_webhook_notification(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Redelivery should fail because requesting user does not have access to the webhook'
+);
+select throws_ok(
+    $$
+        select redeliver_webhook_notification(
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000002'
+        )
+    $$,
+    'P0002',
+    'no_data_found',
+    'Redelivery should fail because the delivery does not exist'
+);
+select redeliver_webhook_notification(:'user1ID', :'webhook1ID', :'delivery1ID');
+select results_eq(
+    $$
+        select processed, processed_at, next_attempt_at, attempts, dead
+        from notification
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (false, null::timestamptz, null::timestamptz, 0, false)
+    $$,
+    'Notification should have been queued again for delivery'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
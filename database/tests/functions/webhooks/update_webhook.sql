@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(8);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -28,6 +28,7 @@ insert into webhook (webhook_id, name, url, user_id)
 values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
 insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 0);
 insert into webhook__package (webhook_id, package_id) values (:'webhook1ID', :'package1ID');
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook1ID', :'repo1ID');
 insert into webhook (webhook_id, name, url, organization_id)
 values (:'webhook2ID', 'webhook2', 'http://webhook2.url', :'org1ID');
 
@@ -71,6 +72,12 @@ select update_webhook('00000000-0000-0000-0000-000000000001', '
     "description": "description updated",
     "url": "http://webhook1.url/updated",
     "secret": "very updated",
+    "secondary_secret": "very",
+    "sign_payloads": true,
+    "channel": 3,
+    "filter": {
+        "exclude_prereleases": true
+    },
     "content_type": "text/xml",
     "template": "custom payload updated",
     "active": false,
@@ -79,6 +86,11 @@ select update_webhook('00000000-0000-0000-0000-000000000001', '
         {
             "package_id": "00000000-0000-0000-0000-000000000002"
         }
+    ],
+    "organizations": [
+        {
+            "name": "org1"
+        }
     ]
 }
 '::jsonb);
@@ -89,6 +101,10 @@ select results_eq(
             description,
             url,
             secret,
+            secondary_secret,
+            sign_payloads,
+            webhook_channel_id,
+            filter,
             content_type,
             template,
             active,
@@ -103,6 +119,10 @@ select results_eq(
             'description updated',
             'http://webhook1.url/updated',
             'very updated',
+            'very',
+            true,
+            3,
+            '{"exclude_prereleases": true}'::jsonb,
             'text/xml',
             'custom payload updated',
             false,
@@ -136,6 +156,25 @@ select results_eq(
     $$,
     'Webhook1 should now be linked to package2'
 );
+select is_empty(
+    $$
+        select repository_id
+        from webhook__repository
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    'Webhook1 should not be linked to any repository anymore'
+);
+select results_eq(
+    $$
+        select organization_id
+        from webhook__organization
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook1 should now be linked to org1'
+);
 
 -- Update webhook owned by organization (requesting user belongs to organization)
 select update_webhook('00000000-0000-0000-0000-000000000001', '
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(133);
+select plan(180);
 
 -- Check default_text_search_config is correct
 select results_eq(
@@ -15,6 +15,7 @@ select has_extension('pgcrypto');
 -- Check expected tables exist
 select tables_are(array[
     'api_key',
+    'email_delivery_preference',
     'email_verification_code',
     'event',
     'event_kind',
@@ -24,12 +25,17 @@ select tables_are(array[
     'notification',
     'opt_out',
     'organization',
+    'organization_subscription',
     'package',
     'package__maintainer',
+    'package_opt_out',
     'repository',
     'repository_kind',
+    'repository_subscription',
+    'repository_tracking_run',
     'session',
     'snapshot',
+    'snapshot_dependency',
     'subscription',
     'user',
     'user_starred_package',
@@ -38,7 +44,11 @@ select tables_are(array[
     'version_schema',
     'webhook',
     'webhook__event_kind',
-    'webhook__package'
+    'webhook__organization',
+    'webhook__package',
+    'webhook__repository',
+    'webhook_channel',
+    'webhook_delivery'
 ]);
 
 -- Check tables have expected columns
@@ -49,6 +59,10 @@ select columns_are('api_key', array[
     'user_id',
     'created_at'
 ]);
+select columns_are('email_delivery_preference', array[
+    'email_delivery_preference_id',
+    'name'
+]);
 select columns_are('email_verification_code', array[
     'email_verification_code_id',
     'user_id',
@@ -63,7 +77,8 @@ select columns_are('event', array[
     'package_id',
     'package_version',
     'repository_id',
-    'data'
+    'data',
+    'processed_seq'
 ]);
 select columns_are('event_kind', array[
     'event_kind_id',
@@ -89,6 +104,9 @@ select columns_are('notification', array[
     'processed',
     'processed_at',
     'error',
+    'attempts',
+    'next_attempt_at',
+    'dead',
     'event_id',
     'user_id',
     'webhook_id'
@@ -112,6 +130,12 @@ select columns_are('organization', array[
     'custom_policy',
     'policy_data'
 ]);
+select columns_are('organization_subscription', array[
+    'user_id',
+    'organization_id',
+    'event_kind_id',
+    'filter'
+]);
 select columns_are('package', array[
     'package_id',
     'name',
@@ -130,6 +154,12 @@ select columns_are('package__maintainer', array[
     'package_id',
     'maintainer_id'
 ]);
+select columns_are('package_opt_out', array[
+    'package_opt_out_id',
+    'user_id',
+    'package_id',
+    'event_kind_id'
+]);
 select columns_are('repository', array[
     'repository_id',
     'name',
@@ -147,12 +177,33 @@ select columns_are('repository', array[
     'digest',
     'repository_kind_id',
     'user_id',
-    'organization_id'
+    'organization_id',
+    'tracking_interval',
+    'tracking_requested_at',
+    'vulnerability_allowlist',
+    'signing_keys_digest'
 ]);
 select columns_are('repository_kind', array[
     'repository_kind_id',
     'name'
 ]);
+select columns_are('repository_subscription', array[
+    'user_id',
+    'repository_id',
+    'event_kind_id',
+    'filter'
+]);
+select columns_are('repository_tracking_run', array[
+    'repository_tracking_run_id',
+    'repository_id',
+    'started_at',
+    'finished_at',
+    'packages_registered',
+    'packages_unregistered',
+    'digest_before',
+    'digest_after',
+    'warnings'
+]);
 select columns_are('session', array[
     'session_id',
     'user_id',
@@ -177,11 +228,16 @@ select columns_are('snapshot', array[
     'security_report',
     'security_report_created_at',
     'security_report_summary',
+    'security_report_allowlist',
+    'sbom',
+    'vulnerability_allowlist',
     'capabilities',
     'data',
     'deprecated',
     'license',
     'signed',
+    'provenance_verification',
+    'cosign_verification',
     'content_url',
     'containers_images',
     'provider',
@@ -191,10 +247,20 @@ select columns_are('snapshot', array[
     'prerelease',
     'created_at'
 ]);
+select columns_are('snapshot_dependency', array[
+    'snapshot_dependency_id',
+    'package_id',
+    'version',
+    'name',
+    'version_constraint',
+    'repository_url',
+    'alias'
+]);
 select columns_are('subscription', array[
     'user_id',
     'package_id',
-    'event_kind_id'
+    'event_kind_id',
+    'filter'
 ]);
 select columns_are('user', array[
     'user_id',
@@ -205,7 +271,9 @@ select columns_are('user', array[
     'email_verified',
     'password',
     'profile_image_id',
-    'created_at'
+    'created_at',
+    'email_delivery_preference_id',
+    'last_digest_sent_at'
 ]);
 select columns_are('user_starred_package', array[
     'user_id',
@@ -234,29 +302,62 @@ select columns_are('webhook', array[
     'created_at',
     'updated_at',
     'user_id',
-    'organization_id'
+    'organization_id',
+    'secondary_secret',
+    'sign_payloads',
+    'webhook_channel_id',
+    'filter'
 ]);
 select columns_are('webhook__event_kind', array[
     'webhook_id',
     'event_kind_id'
 ]);
+select columns_are('webhook__organization', array[
+    'webhook_id',
+    'organization_id'
+]);
 select columns_are('webhook__package', array[
     'webhook_id',
     'package_id'
 ]);
+select columns_are('webhook__repository', array[
+    'webhook_id',
+    'repository_id'
+]);
+select columns_are('webhook_channel', array[
+    'webhook_channel_id',
+    'name'
+]);
+select columns_are('webhook_delivery', array[
+    'webhook_delivery_id',
+    'created_at',
+    'attempt',
+    'request_payload',
+    'response_status',
+    'response_body',
+    'latency',
+    'success',
+    'error',
+    'notification_id',
+    'webhook_id'
+]);
 
 -- Check tables have expected indexes
 select indexes_are('api_key', array[
     'api_key_pkey',
     'api_key_user_id_idx'
 ]);
+select indexes_are('email_delivery_preference', array[
+    'email_delivery_preference_pkey'
+]);
 select indexes_are('email_verification_code', array[
     'email_verification_code_pkey',
     'email_verification_code_user_id_key'
 ]);
 select indexes_are('event', array[
     'event_pkey',
-    'event_not_processed_idx'
+    'event_not_processed_idx',
+    'event_processed_seq_idx'
 ]);
 select indexes_are('image', array[
     'image_pkey',
@@ -274,7 +375,8 @@ select indexes_are('notification', array[
     'notification_not_processed_idx',
     'notification_event_id_user_id_key',
     'notification_event_id_webhook_id_key',
-    'notification_webhook_id_created_at_idx'
+    'notification_webhook_id_created_at_idx',
+    'notification_user_id_idx'
 ]);
 select indexes_are('opt_out', array[
     'opt_out_pkey',
@@ -284,6 +386,10 @@ select indexes_are('organization', array[
     'organization_pkey',
     'organization_name_key'
 ]);
+select indexes_are('organization_subscription', array[
+    'organization_subscription_pkey',
+    'organization_subscription_organization_id_idx'
+]);
 select indexes_are('package', array[
     'package_pkey',
     'package_tsdoc_idx',
@@ -294,6 +400,10 @@ select indexes_are('package', array[
 select indexes_are('package__maintainer', array[
     'package__maintainer_pkey'
 ]);
+select indexes_are('package_opt_out', array[
+    'package_opt_out_pkey',
+    'package_opt_out_user_id_package_id_event_kind_id_key'
+]);
 select indexes_are('repository', array[
     'repository_pkey',
     'repository_name_key',
@@ -305,6 +415,14 @@ select indexes_are('repository', array[
 select indexes_are('repository_kind', array[
     'repository_kind_pkey'
 ]);
+select indexes_are('repository_subscription', array[
+    'repository_subscription_pkey',
+    'repository_subscription_repository_id_idx'
+]);
+select indexes_are('repository_tracking_run', array[
+    'repository_tracking_run_pkey',
+    'repository_tracking_run_repository_id_started_at_idx'
+]);
 select indexes_are('session', array[
     'session_pkey'
 ]);
@@ -313,6 +431,11 @@ select indexes_are('snapshot', array[
     'snapshot_package_id_digest_key',
     'snapshot_not_deprecated_with_readme_idx'
 ]);
+select indexes_are('snapshot_dependency', array[
+    'snapshot_dependency_pkey',
+    'snapshot_dependency_package_id_version_idx',
+    'snapshot_dependency_name_idx'
+]);
 select indexes_are('subscription', array[
     'subscription_pkey'
 ]);
@@ -335,9 +458,23 @@ select indexes_are('webhook', array[
 select indexes_are('webhook__event_kind', array[
     'webhook__event_kind_pkey'
 ]);
+select indexes_are('webhook__organization', array[
+    'webhook__organization_pkey'
+]);
 select indexes_are('webhook__package', array[
     'webhook__package_pkey'
 ]);
+select indexes_are('webhook__repository', array[
+    'webhook__repository_pkey'
+]);
+select indexes_are('webhook_channel', array[
+    'webhook_channel_pkey'
+]);
+select indexes_are('webhook_delivery', array[
+    'webhook_delivery_pkey',
+    'webhook_delivery_webhook_id_created_at_idx',
+    'webhook_delivery_notification_id_idx'
+]);
 
 -- Check expected functions exist
 -- API keys
@@ -349,13 +486,20 @@ select has_function('update_api_key');
 -- Authz
 select has_function('notify_authorization_policies_updates');
 -- Events
+select has_function('get_events_to_stream');
+select has_function('get_last_processed_event_id');
 select has_function('get_pending_event');
+select has_function('notify_event_processed');
 -- Images
 select has_function('get_image');
 select has_function('register_image');
 -- Notifications
 select has_function('add_notification');
+select has_function('get_pending_digest');
 select has_function('get_pending_notification');
+select has_function('mark_notification_as_dead');
+select has_function('schedule_notification_retry');
+select has_function('update_last_digest_sent_at');
 select has_function('update_notification_status');
 -- Organizations
 select has_function('add_organization');
@@ -376,6 +520,8 @@ select has_function('generate_package_tsdoc');
 select has_function('get_harbor_replication_dump');
 select has_function('get_package');
 select has_function('get_package_changelog');
+select has_function('get_package_dependencies');
+select has_function('get_package_dependents');
 select has_function('get_package_summary');
 select has_function('get_packages_starred_by_user');
 select has_function('get_package_stars');
@@ -383,6 +529,7 @@ select has_function('get_packages_stats');
 select has_function('get_random_packages');
 select has_function('get_snapshots_to_scan');
 select has_function('register_package');
+select has_function('resolve_package_dependency');
 select has_function('search_packages');
 select has_function('search_packages_monocular');
 select has_function('semver_gt');
@@ -395,14 +542,19 @@ select has_function('add_repository');
 select has_function('delete_repository');
 select has_function('get_all_repositories');
 select has_function('get_repositories_by_kind');
+select has_function('get_repositories_to_track');
 select has_function('get_repository_by_id');
 select has_function('get_repository_by_name');
 select has_function('get_repository_packages_digest');
+select has_function('get_repository_tracking_runs');
 select has_function('get_repository_summary');
 select has_function('get_org_repositories');
 select has_function('get_user_repositories');
+select has_function('request_repository_tracking');
 select has_function('set_last_tracking_results');
 select has_function('set_verified_publisher');
+select has_function('set_repository_vulnerability_allowlist');
+select has_function('start_repository_tracking');
 select has_function('transfer_repository');
 select has_function('update_repository');
 -- Subscriptions
@@ -411,10 +563,14 @@ select has_function('add_subscription');
 select has_function('delete_opt_out');
 select has_function('delete_subscription');
 select has_function('get_package_subscriptors');
+select has_function('get_repository_event_subscriptors');
 select has_function('get_repository_subscriptors');
 select has_function('get_user_opt_out_entries');
+select has_function('get_user_organizations_subscriptions');
 select has_function('get_user_package_subscriptions');
+select has_function('get_user_repositories_subscriptions');
 select has_function('get_user_subscriptions');
+select has_function('unsubscribe');
 -- Users
 select has_function('check_user_alias_availability');
 select has_function('get_user_profile');
@@ -425,11 +581,17 @@ select has_function('update_user_profile');
 select has_function('verify_email');
 -- Webhooks
 select has_function('add_webhook');
+select has_function('add_webhook_delivery');
+select has_function('delete_old_webhook_deliveries');
 select has_function('delete_webhook');
 select has_function('get_webhook');
+select has_function('get_webhook_deliveries');
 select has_function('get_org_webhooks');
 select has_function('get_user_webhooks');
+select has_function('get_webhooks_by_ids');
 select has_function('get_webhooks_subscribed_to_package');
+select has_function('get_webhooks_subscribed_to_repository');
+select has_function('redeliver_webhook_notification');
 select has_function('update_webhook');
 select has_function('user_has_access_to_webhook');
 
@@ -455,11 +617,38 @@ select results_eq(
         (0, 'New package release'),
         (1, 'Security alert'),
         (2, 'Repository tracking errors'),
-        (3, 'Repository ownership claim')
+        (3, 'Repository ownership claim'),
+        (4, 'Package version removed'),
+        (5, 'Package deprecated'),
+        (6, 'Repository transferred'),
+        (7, 'Repository verified publisher changed')
     $$,
     'Event kinds should exist'
 );
 
+-- Check webhook channels exist
+select results_eq(
+    'select * from webhook_channel',
+    $$ values
+        (0, 'Generic'),
+        (1, 'Slack'),
+        (2, 'Microsoft Teams'),
+        (3, 'Discord')
+    $$,
+    'Webhook channels should exist'
+);
+
+-- Check email delivery preferences exist
+select results_eq(
+    'select * from email_delivery_preference',
+    $$ values
+        (0, 'Immediate'),
+        (1, 'Daily digest'),
+        (2, 'Weekly digest')
+    $$,
+    'Email delivery preferences should exist'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
@@ -15,11 +15,14 @@ import (
 // set up.
 var ErrSenderNotAvailable = errors.New("email sender not available")
 
-// Data describes the different pieces of data used to compose an email.
+// Data describes the different pieces of data used to compose an email. When
+// an unsubscribe url is provided, the List-Unsubscribe headers are added to
+// the email so that email clients can offer a one-click unsubscribe option.
 type Data struct {
-	To      string
-	Subject string
-	Body    []byte
+	To             string
+	Subject        string
+	Body           []byte
+	UnsubscribeURL string
 }
 
 // Sender is in charge of sending emails.
@@ -71,6 +74,13 @@ func (s *Sender) SendEmail(d *Data) error {
 	email.ReplyTo(s.replyTo)
 	email.To(d.To)
 	email.Subject(d.Subject)
+	if d.UnsubscribeURL != "" {
+		email.AddHeader("List-Unsubscribe", "<"+d.UnsubscribeURL+">")
+		email.AddHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	if _, err := email.Plain().Write(HTMLToPlainText(d.Body)); err != nil {
+		return err
+	}
 	if _, err := email.HTML().Write(d.Body); err != nil {
 		return err
 	}
@@ -0,0 +1,49 @@
+package email
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	hiddenElementsRE = regexp.MustCompile(`(?is)<(head|style|script)\b[^>]*>.*?</(head|style|script)>`)
+	linkRE           = regexp.MustCompile(`(?is)<a\b[^>]*\bhref="([^"]*)"[^>]*>(.*?)</a>`)
+	lineBreakRE      = regexp.MustCompile(`(?i)<br\b[^>]*>`)
+	listItemRE       = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	blockElementsRE  = regexp.MustCompile(`(?i)</?(p|div|tr|h[1-6]|ul|ol|table|hr)\b[^>]*>`)
+	tagRE            = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacesRE         = regexp.MustCompile(`\s+`)
+	blankLinesRE     = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToPlainText returns a plain text version of the html email body
+// provided, suitable to be used as the text/plain alternative of the email.
+// Links are preserved by appending their url to the link text.
+func HTMLToPlainText(body []byte) []byte {
+	s := hiddenElementsRE.ReplaceAllString(string(body), "")
+	s = spacesRE.ReplaceAllString(s, " ")
+	s = linkRE.ReplaceAllStringFunc(s, func(link string) string {
+		m := linkRE.FindStringSubmatch(link)
+		href := html.UnescapeString(m[1])
+		text := strings.TrimSpace(tagRE.ReplaceAllString(m[2], ""))
+		if text == "" || html.UnescapeString(text) == href {
+			return href
+		}
+		return text + " (" + href + ")"
+	})
+	s = lineBreakRE.ReplaceAllString(s, "\n")
+	s = listItemRE.ReplaceAllString(s, "\n- ")
+	s = blockElementsRE.ReplaceAllString(s, "\n\n")
+	s = tagRE.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var buf bytes.Buffer
+	for _, line := range strings.Split(s, "\n") {
+		buf.WriteString(strings.TrimSpace(line))
+		buf.WriteString("\n")
+	}
+	s = blankLinesRE.ReplaceAllString(buf.String(), "\n\n")
+	return []byte(strings.TrimSpace(s) + "\n")
+}
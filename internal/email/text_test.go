@@ -0,0 +1,47 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToPlainText(t *testing.T) {
+	body := []byte(`
+<!doctype html>
+<html>
+  <head>
+    <title>Title</title>
+    <style>
+      p { color: red; }
+    </style>
+  </head>
+  <body>
+    <h2>Package   1</h2>
+    <p>Version <b>1.0.0</b> has been
+    released &amp; is ready<br>Enjoy!</p>
+
+
+    <ul>
+      <li>Cool feature</li>
+      <li>Bug fixed</li>
+    </ul>
+    <a href="https://artifacthub.io/packages/helm/repo1/package1" target="_blank">View in Artifact Hub</a>
+    <p><a href="https://artifacthub.io">https://artifacthub.io</a></p>
+  </body>
+</html>
+`)
+	expected := `Package 1
+
+Version 1.0.0 has been released & is ready
+Enjoy!
+
+- Cool feature
+- Bug fixed
+
+View in Artifact Hub (https://artifacthub.io/packages/helm/repo1/package1)
+
+https://artifacthub.io
+`
+	assert.Equal(t, expected, string(HTMLToPlainText(body)))
+}
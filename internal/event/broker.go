@@ -0,0 +1,135 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// eventProcessedChannel represents the database notifications channel
+	// where the ids of the events processed are published.
+	eventProcessedChannel = "event_processed"
+
+	// subscriberBufferSize represents the number of events ids that can be
+	// queued for a subscriber. Subscribers that fall behind are disconnected.
+	subscriberBufferSize = 100
+)
+
+// Broker listens for database notifications about the events processed by
+// the workers and broadcasts their ids to all the subscribers registered.
+type Broker struct {
+	db          hub.DB
+	mu          sync.Mutex
+	subscribers map[chan string]struct{}
+}
+
+// NewBroker creates a new Broker instance.
+func NewBroker(db hub.DB) *Broker {
+	return &Broker{
+		db:          db,
+		subscribers: make(map[chan string]struct{}),
+	}
+}
+
+// Run listens for events processed notifications until it's asked to stop via
+// the context provided. When the listening connection fails, it's established
+// again after a pause. All subscribers are disconnected when the broker stops
+// or the listening connection fails, as some notifications may be missed. They
+// are expected to subscribe again and catch up from the last event received.
+func (b *Broker) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer b.closeSubscribers()
+
+	for {
+		if err := b.listen(ctx); err != nil {
+			log.Error().Err(err).Msg("error listening for events processed notifications")
+			b.closeSubscribers()
+		}
+		select {
+		case <-time.After(pauseOnError):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// listen waits for events processed notifications on a dedicated database
+// connection, broadcasting the id of each event received to the subscribers.
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring database connection: %w", err)
+	}
+	defer func() {
+		// The connection is closed so that it's not returned to the pool
+		// still listening on the notifications channel.
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+	if _, err := conn.Exec(ctx, "listen "+eventProcessedChannel); err != nil {
+		return fmt.Errorf("error listening to notifications channel: %w", err)
+	}
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error waiting for notification: %w", err)
+		}
+		b.broadcast(n.Payload)
+	}
+}
+
+// broadcast sends the event id provided to all the subscribers registered.
+// Subscribers whose buffer is full are disconnected, as they are not keeping
+// up with the events being processed.
+func (b *Broker) broadcast(eventID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- eventID:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber, returning a channel where the ids of
+// the events processed will be sent and a function to unsubscribe. The channel
+// is closed when the subscriber is disconnected by the broker.
+func (b *Broker) Subscribe() (<-chan string, func()) {
+	ch := make(chan string, subscriberBufferSize)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// closeSubscribers disconnects all the subscribers registered.
+func (b *Broker) closeSubscribers() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
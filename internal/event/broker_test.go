@@ -0,0 +1,95 @@
+package event
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBroker(t *testing.T) {
+	t.Run("events ids are broadcasted to all subscribers", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroker(nil)
+		ch1, unsubscribe1 := b.Subscribe()
+		defer unsubscribe1()
+		ch2, unsubscribe2 := b.Subscribe()
+		defer unsubscribe2()
+
+		b.broadcast(validUUID)
+		assert.Equal(t, validUUID, <-ch1)
+		assert.Equal(t, validUUID, <-ch2)
+	})
+
+	t.Run("unsubscribed subscribers do not receive events ids", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroker(nil)
+		ch, unsubscribe := b.Subscribe()
+		unsubscribe()
+		unsubscribe()
+
+		b.broadcast(validUUID)
+		_, ok := <-ch
+		assert.False(t, ok)
+	})
+
+	t.Run("subscribers falling behind are disconnected", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroker(nil)
+		ch, unsubscribe := b.Subscribe()
+		defer unsubscribe()
+
+		for i := 0; i <= subscriberBufferSize; i++ {
+			b.broadcast(strconv.Itoa(i))
+		}
+		received := 0
+		for range ch {
+			received++
+		}
+		assert.Equal(t, subscriberBufferSize, received)
+	})
+
+	t.Run("subscribers are disconnected when the broker stops", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		db := &tests.DBMock{}
+		db.On("Acquire", mock.Anything).Run(func(args mock.Arguments) { cancel() }).Return(nil, tests.ErrFakeDB)
+		b := NewBroker(db)
+		ch, unsubscribe := b.Subscribe()
+		defer unsubscribe()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go b.Run(ctx, &wg)
+		wg.Wait()
+
+		_, ok := <-ch
+		assert.False(t, ok)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("subscribers are disconnected when the listening connection fails", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		db := &tests.DBMock{}
+		db.On("Acquire", mock.Anything).Return(nil, tests.ErrFakeDB)
+		b := NewBroker(db)
+		ch, unsubscribe := b.Subscribe()
+		defer unsubscribe()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go b.Run(ctx, &wg)
+
+		_, ok := <-ch
+		assert.False(t, ok)
+		cancel()
+		wg.Wait()
+		db.AssertExpectations(t)
+	})
+}
@@ -3,22 +3,36 @@ package event
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
+	"github.com/satori/uuid"
 )
 
 const (
 	// Database queries
-	getPendingEventDBQ = `select get_pending_event()`
+	getEventsToStreamDBQ       = `select get_events_to_stream($1::uuid, $2::jsonb)`
+	getLastProcessedEventIDDBQ = `select coalesce(get_last_processed_event_id()::text, '')`
+	getPendingEventDBQ         = `select get_pending_event()`
+	notifyEventProcessedDBQ    = `select notify_event_processed($1::uuid)`
+
+	// maxEventsToStreamLimit represents the maximum number of events that can
+	// be requested at once to be sent to a stream.
+	maxEventsToStreamLimit = 100
 )
 
 // Manager provides an API to manage events.
-type Manager struct{}
+type Manager struct {
+	db hub.DB
+}
 
 // NewManager creates a new Manager instance.
-func NewManager() *Manager {
-	return &Manager{}
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
 }
 
 // GetPending returns a pending event to be processed if available.
@@ -33,3 +47,50 @@ func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Event, error)
 	}
 	return e, nil
 }
+
+// GetLastProcessedID returns the id of the last event processed. An empty
+// string is returned when no events have been processed yet.
+func (m *Manager) GetLastProcessedID(ctx context.Context) (string, error) {
+	var eventID string
+	if err := m.db.QueryRow(ctx, getLastProcessedEventIDDBQ).Scan(&eventID); err != nil {
+		return "", err
+	}
+	return eventID, nil
+}
+
+// NotifyProcessed records that the event provided has been processed and
+// notifies the events processed listeners. It must be the last operation of
+// the transaction in which the event was processed, as it serializes the
+// commit of the events processed to keep their order consistent.
+func (m *Manager) NotifyProcessed(ctx context.Context, tx pgx.Tx, eventID string) error {
+	_, err := tx.Exec(ctx, notifyEventProcessedDBQ, eventID)
+	return err
+}
+
+// GetToStreamJSON returns the processed events visible to the user doing the
+// request that match the input provided as a json array.
+func (m *Manager) GetToStreamJSON(ctx context.Context, input *hub.GetEventsToStreamInput) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if input.EventID == "" && input.LastEventID == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "event id or last event id required")
+	}
+	if input.EventID != "" {
+		if _, err := uuid.FromString(input.EventID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event id")
+		}
+	}
+	if input.LastEventID != "" {
+		if _, err := uuid.FromString(input.LastEventID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid last event id")
+		}
+	}
+	if input.Limit < 0 || input.Limit > maxEventsToStreamLimit {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit")
+	}
+
+	// Get events from database
+	inputJSON, _ := json.Marshal(input)
+	return util.DBQueryJSON(ctx, m.db, getEventsToStreamDBQ, userID, inputJSON)
+}
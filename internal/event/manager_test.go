@@ -2,21 +2,53 @@ package event
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var validUUID = "00000000-0000-0000-0000-000000000001"
+
 func TestMain(m *testing.M) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	os.Exit(m.Run())
 }
 
+func TestGetLastProcessedID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getLastProcessedEventIDDBQ).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		eventID, err := m.GetLastProcessedID(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Empty(t, eventID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getLastProcessedEventIDDBQ).Return(validUUID, nil)
+		m := NewManager(db)
+
+		eventID, err := m.GetLastProcessedID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, validUUID, eventID)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetPending(t *testing.T) {
 	ctx := context.Background()
 
@@ -24,7 +56,7 @@ func TestGetPending(t *testing.T) {
 		t.Parallel()
 		tx := &tests.TXMock{}
 		tx.On("QueryRow", ctx, getPendingEventDBQ).Return(nil, tests.ErrFakeDB)
-		m := NewManager()
+		m := NewManager(nil)
 
 		dataJSON, err := m.GetPending(ctx, tx)
 		assert.Equal(t, tests.ErrFakeDB, err)
@@ -50,7 +82,7 @@ func TestGetPending(t *testing.T) {
 			"event_kind": 0
 		}
 		`), nil)
-		m := NewManager()
+		m := NewManager(nil)
 
 		e, err := m.GetPending(ctx, tx)
 		require.NoError(t, err)
@@ -58,3 +90,128 @@ func TestGetPending(t *testing.T) {
 		tx.AssertExpectations(t)
 	})
 }
+
+func TestNotifyProcessed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, notifyEventProcessedDBQ, validUUID).Return(tests.ErrFakeDB)
+		m := NewManager(nil)
+
+		err := m.NotifyProcessed(ctx, tx, validUUID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, notifyEventProcessedDBQ, validUUID).Return(nil)
+		m := NewManager(nil)
+
+		err := m.NotifyProcessed(ctx, tx, validUUID)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestGetToStreamJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetToStreamJSON(context.Background(), &hub.GetEventsToStreamInput{})
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetEventsToStreamInput
+		}{
+			{
+				"event id or last event id required",
+				&hub.GetEventsToStreamInput{},
+			},
+			{
+				"invalid event id",
+				&hub.GetEventsToStreamInput{EventID: "invalid"},
+			},
+			{
+				"invalid last event id",
+				&hub.GetEventsToStreamInput{LastEventID: "invalid"},
+			},
+			{
+				"invalid limit",
+				&hub.GetEventsToStreamInput{LastEventID: validUUID, Limit: -1},
+			},
+			{
+				"invalid limit",
+				&hub.GetEventsToStreamInput{LastEventID: validUUID, Limit: 101},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				_, err := m.GetToStreamJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getEventsToStreamDBQ, "userID", []byte(`{"all":false,"event_id":"`+validUUID+`"}`)).
+					Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetToStreamJSON(ctx, &hub.GetEventsToStreamInput{EventID: validUUID})
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getEventsToStreamDBQ, "userID", []byte(`{"all":true,"last_event_id":"`+validUUID+`","limit":10}`)).
+			Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		input := &hub.GetEventsToStreamInput{
+			All:         true,
+			LastEventID: validUUID,
+			Limit:       10,
+		}
+		dataJSON, err := m.GetToStreamJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
@@ -13,9 +13,41 @@ type ManagerMock struct {
 	mock.Mock
 }
 
+// GetLastProcessedID implements the EventManager interface.
+func (m *ManagerMock) GetLastProcessedID(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
 // GetPending implements the EventManager interface.
 func (m *ManagerMock) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Event, error) {
 	args := m.Called(ctx, tx)
 	data, _ := args.Get(0).(*hub.Event)
 	return data, args.Error(1)
 }
+
+// GetToStreamJSON implements the EventManager interface.
+func (m *ManagerMock) GetToStreamJSON(ctx context.Context, input *hub.GetEventsToStreamInput) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// NotifyProcessed implements the EventManager interface.
+func (m *ManagerMock) NotifyProcessed(ctx context.Context, tx pgx.Tx, eventID string) error {
+	args := m.Called(ctx, tx, eventID)
+	return args.Error(0)
+}
+
+// BrokerMock is a mock implementation of the EventsBroker interface.
+type BrokerMock struct {
+	mock.Mock
+}
+
+// Subscribe implements the EventsBroker interface.
+func (m *BrokerMock) Subscribe() (<-chan string, func()) {
+	args := m.Called()
+	events, _ := args.Get(0).(chan string)
+	unsubscribe, _ := args.Get(1).(func())
+	return events, unsubscribe
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/rs/zerolog/log"
@@ -73,15 +74,18 @@ func (w *Worker) processEvent(ctx context.Context) error {
 
 		// Register event notifications
 		// Email notifications
-		users, err := w.svc.SubscriptionManager.GetSubscriptors(ctx, e)
+		subscriptors, err := w.svc.SubscriptionManager.GetSubscriptors(ctx, e)
 		if err != nil {
 			log.Error().Err(err).Msg("error getting subscriptors")
 			return err
 		}
-		for _, u := range users {
+		for _, s := range subscriptors {
+			if !subscription.MatchesFilter(s.Filter, e) {
+				continue
+			}
 			n := &hub.Notification{
 				Event: e,
-				User:  u,
+				User:  &hub.User{UserID: s.UserID},
 			}
 			if err := w.svc.NotificationManager.Add(ctx, tx, n); err != nil {
 				log.Error().Err(err).Msg("error adding notification")
@@ -95,6 +99,9 @@ func (w *Worker) processEvent(ctx context.Context) error {
 			return err
 		}
 		for _, wh := range webhooks {
+			if !subscription.MatchesFilter(wh.Filter, e) {
+				continue
+			}
 			n := &hub.Notification{
 				Event:   e,
 				Webhook: wh,
@@ -106,6 +113,12 @@ func (w *Worker) processEvent(ctx context.Context) error {
 			}
 		}
 
+		// Notify event processed (must be the last operation before commit)
+		if err := w.svc.EventManager.NotifyProcessed(ctx, tx, e.EventID); err != nil {
+			log.Error().Err(err).Msg("error notifying event processed")
+			return err
+		}
+
 		return nil
 	})
 }
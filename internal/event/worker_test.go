@@ -16,9 +16,25 @@ import (
 
 func TestWorker(t *testing.T) {
 	e := &hub.Event{
-		EventID:   "eventID",
-		EventKind: hub.NewRelease,
-		PackageID: "packageID",
+		EventID:        "eventID",
+		EventKind:      hub.NewRelease,
+		PackageID:      "packageID",
+		PackageVersion: "1.0.1",
+		Data: map[string]interface{}{
+			"previous_version": "1.0.0",
+		},
+	}
+	s1 := &hub.Subscription{
+		UserID: "user1ID",
+	}
+	s2 := &hub.Subscription{
+		UserID: "user2ID",
+	}
+	s3 := &hub.Subscription{
+		UserID: "user3ID",
+		Filter: &hub.SubscriptionFilter{
+			UpdateLevel: hub.MinorUpdate,
+		},
 	}
 	u1 := &hub.User{
 		UserID: "user1ID",
@@ -32,6 +48,12 @@ func TestWorker(t *testing.T) {
 	wh2 := &hub.Webhook{
 		WebhookID: "webhook2ID",
 	}
+	wh3 := &hub.Webhook{
+		WebhookID: "webhook3ID",
+		Filter: &hub.SubscriptionFilter{
+			VersionConstraint: ">=2.0.0",
+		},
+	}
 
 	t.Run("error getting pending event", func(t *testing.T) {
 		t.Parallel()
@@ -63,8 +85,9 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc)
@@ -72,12 +95,27 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("error notifying event processed", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(tests.ErrFakeDB)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error adding email notification", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{s1}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
 
@@ -91,9 +129,10 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{s1}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc)
@@ -106,10 +145,27 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1, u2}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{s1, s2}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u2}).Return(nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("subscriptors not matching their filter are skipped", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{s1, s3}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc)
@@ -122,7 +178,7 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
@@ -137,9 +193,10 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc)
@@ -152,10 +209,27 @@ func TestWorker(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
-		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{}, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1, wh2}, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh2}).Return(nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhooks not matching their filter are skipped", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.Subscription{}, nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1, wh3}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(nil)
+		sw.em.On("NotifyProcessed", sw.ctx, sw.tx, e.EventID).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc)
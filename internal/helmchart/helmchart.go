@@ -0,0 +1,105 @@
+package helmchart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// ContentLayerMediaType represents the media type of the layer that contains
+// the chart archive in charts stored in OCI registries.
+const ContentLayerMediaType = "application/tar+gzip"
+
+// ErrArchiveTooBig indicates that the chart archive exceeds the maximum size
+// allowed.
+var ErrArchiveTooBig = errors.New("chart archive too big")
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// LoadInput represents the input used to load a chart from a remote archive.
+type LoadInput struct {
+	URL      *url.URL
+	Username string
+	Password string
+
+	// MaxSize represents the maximum size of the chart archive. When it is
+	// zero no limit is applied.
+	MaxSize int64
+
+	// PrepareRequest allows customizing the request used to get the chart
+	// archive when it is located at an http(s) url.
+	PrepareRequest func(req *http.Request)
+}
+
+// Load loads a chart from a remote archive located at the url provided. The
+// archive data is returned as well.
+func Load(
+	ctx context.Context,
+	hc HTTPClient,
+	op hub.OCIPuller,
+	input *LoadInput,
+) (*chart.Chart, []byte, error) {
+	var data []byte
+
+	switch input.URL.Scheme {
+	case "http", "https":
+		// Get chart content
+		req, _ := http.NewRequest("GET", input.URL.String(), nil)
+		if input.Username != "" || input.Password != "" {
+			req.SetBasicAuth(input.Username, input.Password)
+		}
+		if input.PrepareRequest != nil {
+			input.PrepareRequest(req)
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+		}
+		var body io.Reader = resp.Body
+		if input.MaxSize > 0 {
+			body = io.LimitReader(resp.Body, input.MaxSize+1)
+		}
+		data, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "oci":
+		// Pull chart content layer from OCI registry
+		ref := strings.TrimPrefix(input.URL.String(), hub.RepositoryOCIPrefix)
+		var err error
+		_, data, err = op.PullLayer(ctx, ref, ContentLayerMediaType, input.Username, input.Password)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, repo.ErrSchemeNotSupported
+	}
+	if input.MaxSize > 0 && int64(len(data)) > input.MaxSize {
+		return nil, nil, ErrArchiveTooBig
+	}
+
+	// Load chart from the archive data previously fetched
+	chrt, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	return chrt, data, nil
+}
@@ -0,0 +1,127 @@
+package helmchart
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	chartData, err := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+	require.NoError(t, err)
+
+	t.Run("scheme not supported", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("ftp://repo.url/pkg1-1.0.0.tgz")
+		chrt, data, err := Load(context.Background(), nil, nil, &LoadInput{URL: u})
+		assert.Equal(t, repo.ErrSchemeNotSupported, err)
+		assert.Nil(t, chrt)
+		assert.Nil(t, data)
+	})
+
+	t.Run("error getting chart archive", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("https://repo.url/pkg1-1.0.0.tgz")
+		hc := &tests.HTTPClientMock{}
+		req, _ := http.NewRequest("GET", u.String(), nil)
+		hc.On("Do", req).Return(nil, tests.ErrFake)
+
+		chrt, data, err := Load(context.Background(), hc, nil, &LoadInput{URL: u})
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, chrt)
+		assert.Nil(t, data)
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("unexpected status code getting chart archive", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("https://repo.url/pkg1-1.0.0.tgz")
+		hc := &tests.HTTPClientMock{}
+		req, _ := http.NewRequest("GET", u.String(), nil)
+		hc.On("Do", req).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusNotFound,
+		}, nil)
+
+		chrt, data, err := Load(context.Background(), hc, nil, &LoadInput{URL: u})
+		assert.Error(t, err)
+		assert.Nil(t, chrt)
+		assert.Nil(t, data)
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("chart archive too big", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("https://repo.url/pkg1-1.0.0.tgz")
+		hc := &tests.HTTPClientMock{}
+		req, _ := http.NewRequest("GET", u.String(), nil)
+		hc.On("Do", req).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		chrt, data, err := Load(context.Background(), hc, nil, &LoadInput{
+			URL:     u,
+			MaxSize: int64(len(chartData) - 1),
+		})
+		assert.Equal(t, ErrArchiveTooBig, err)
+		assert.Nil(t, chrt)
+		assert.Nil(t, data)
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("chart loaded from http url", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("https://repo.url/pkg1-1.0.0.tgz")
+		hc := &tests.HTTPClientMock{}
+		req, _ := http.NewRequest("GET", u.String(), nil)
+		req.SetBasicAuth("user", "pass")
+		req.Header.Set("Authorization-Extra", "value")
+		hc.On("Do", req).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		chrt, data, err := Load(context.Background(), hc, nil, &LoadInput{
+			URL:      u,
+			Username: "user",
+			Password: "pass",
+			MaxSize:  int64(len(chartData)),
+			PrepareRequest: func(req *http.Request) {
+				req.Header.Set("Authorization-Extra", "value")
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pkg1", chrt.Name())
+		assert.Equal(t, chartData, data)
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("chart loaded from oci url", func(t *testing.T) {
+		t.Parallel()
+		u, _ := url.Parse("oci://registry.url/pkg1:1.0.0")
+		op := &oci.PullerMock{}
+		op.On("PullLayer", context.Background(), "registry.url/pkg1:1.0.0", ContentLayerMediaType, "user", "pass").
+			Return(nil, chartData, nil)
+
+		chrt, data, err := Load(context.Background(), nil, op, &LoadInput{
+			URL:      u,
+			Username: "user",
+			Password: "pass",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pkg1", chrt.Name())
+		assert.Equal(t, chartData, data)
+		op.AssertExpectations(t)
+	})
+}
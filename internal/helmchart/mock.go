@@ -0,0 +1,24 @@
+package helmchart
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// TemplateRendererMock is a mock implementation of the ChartTemplateRenderer
+// interface.
+type TemplateRendererMock struct {
+	mock.Mock
+}
+
+// RenderJSON implements the ChartTemplateRenderer interface.
+func (m *TemplateRendererMock) RenderJSON(
+	ctx context.Context,
+	input *hub.RenderChartTemplatesInput,
+) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
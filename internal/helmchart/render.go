@@ -0,0 +1,226 @@
+package helmchart
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+const (
+	// rendererEnvVar represents the environment variable used to request a
+	// process to run as a chart templates renderer.
+	rendererEnvVar = "HUB_HELMCHART_RENDERER"
+
+	// rendererMaxMemory represents the maximum amount of memory a renderer
+	// process can use.
+	rendererMaxMemory = 512 * 1024 * 1024
+
+	// rendererMaxCPUTime represents the maximum amount of cpu time, in
+	// seconds, a renderer process can use.
+	rendererMaxCPUTime = 30
+
+	// maxConcurrentRenders represents the maximum number of charts that can
+	// be rendered at the same time.
+	maxConcurrentRenders = 5
+
+	// maxRenderedSize represents the maximum size of the output produced when
+	// rendering the templates of a chart.
+	maxRenderedSize = 5 * 1024 * 1024
+)
+
+var (
+	// ErrRenderedOutputTooBig indicates that the output produced when
+	// rendering the chart templates exceeds the maximum size allowed.
+	ErrRenderedOutputTooBig = errors.New("rendered output too big")
+
+	// ErrRenderTimeout indicates that the chart templates could not be
+	// rendered before the deadline of the context provided.
+	ErrRenderTimeout = errors.New("timeout rendering templates")
+
+	// renderSem is used to limit the number of charts rendered concurrently.
+	renderSem = make(chan struct{}, maxConcurrentRenders)
+)
+
+// renderInput represents the input sent to the renderer process.
+type renderInput struct {
+	ChartData []byte                 `json:"chart_data"`
+	Values    map[string]interface{} `json:"values"`
+	LintMode  bool                   `json:"lint_mode"`
+}
+
+// renderOutput represents the output produced by the renderer process.
+type renderOutput struct {
+	Manifests map[string]string `json:"manifests"`
+	Error     string            `json:"error"`
+}
+
+// RenderTemplates renders the templates of the chart archive provided using
+// the values given, which are merged with the chart's default ones. Rendering
+// happens offline, so templates are not allowed to interact with a Kubernetes
+// cluster. When lint mode is enabled, missing required values do not make the
+// rendering fail.
+//
+// Charts templates are untrusted, so they are rendered in a separate process
+// with limited memory and cpu time. The process is killed as soon as the
+// context provided is done or the output produced exceeds the maximum size
+// allowed. The number of charts rendered concurrently is limited as well.
+func RenderTemplates(
+	ctx context.Context,
+	chartData []byte,
+	values map[string]interface{},
+	lintMode bool,
+) (map[string]string, error) {
+	// Wait for a render slot to be available
+	select {
+	case renderSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ErrRenderTimeout
+	}
+	defer func() { <-renderSem }()
+
+	// Prepare renderer process
+	input, err := json.Marshal(&renderInput{
+		ChartData: chartData,
+		Values:    values,
+		LintMode:  lintMode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdout := &limitedBuffer{max: maxRenderedSize, onExceeded: cancel}
+	cmd := exec.CommandContext(cmdCtx, executable)
+	cmd.Env = []string{rendererEnvVar + "=true"}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+
+	// Run renderer process and process its output
+	err = cmd.Run()
+	switch {
+	case stdout.exceeded:
+		return nil, ErrRenderedOutputTooBig
+	case ctx.Err() != nil:
+		return nil, ErrRenderTimeout
+	case err != nil:
+		return nil, fmt.Errorf("error running renderer: %w", err)
+	}
+	var output *renderOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil || output == nil {
+		return nil, errors.New("invalid renderer output")
+	}
+	if output.Error != "" {
+		return nil, errors.New(output.Error)
+	}
+	return output.Manifests, nil
+}
+
+// RunRendererIfRequested runs the current process as a chart templates
+// renderer when it was launched for that purpose by RenderTemplates, exiting
+// once the rendering is done. It's expected to be called at the beginning of
+// the main function of the programs that render charts templates.
+func RunRendererIfRequested() {
+	if os.Getenv(rendererEnvVar) == "" {
+		return
+	}
+	if err := setRendererResourcesLimits(); err != nil {
+		os.Exit(1)
+	}
+	if err := runRenderer(os.Stdin, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// runRenderer renders the chart templates as requested in the input read from
+// the reader provided, writing the output to the writer given.
+func runRenderer(r io.Reader, w io.Writer) error {
+	var input *renderInput
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return err
+	}
+	output := &renderOutput{}
+	chrt, err := loader.LoadArchive(bytes.NewReader(input.ChartData))
+	if err == nil {
+		output.Manifests, err = renderTemplates(chrt, input.Values, input.LintMode)
+	}
+	if err != nil {
+		output.Error = err.Error()
+	}
+	return json.NewEncoder(w).Encode(output)
+}
+
+// renderTemplates renders the templates of the chart provided using the values
+// given with Helm's engine.
+func renderTemplates(
+	chrt *chart.Chart,
+	values map[string]interface{},
+	lintMode bool,
+) (map[string]string, error) {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	if err := chartutil.ProcessDependencies(chrt, values); err != nil {
+		return nil, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      chrt.Name(),
+		Namespace: "default",
+		Revision:  1,
+		IsInstall: true,
+	}
+	// The values provided are coalesced with the chart defaults, and the
+	// result is validated against the values schemas of the chart and its
+	// dependencies, so required values can be supplied by the defaults.
+	renderValues, err := chartutil.ToRenderValues(chrt, values, options, nil)
+	if err != nil {
+		return nil, err
+	}
+	e := engine.Engine{LintMode: lintMode}
+	return e.Render(chrt, renderValues)
+}
+
+// limitedBuffer is a buffer that stops accepting data once the maximum size
+// allowed is exceeded, calling the onExceeded function provided. The buffer is
+// not embedded on purpose, so that io.Copy cannot bypass the limit using the
+// buffer's ReadFrom method.
+type limitedBuffer struct {
+	buf        bytes.Buffer
+	max        int
+	exceeded   bool
+	onExceeded func()
+}
+
+// Write implements the io.Writer interface.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, ErrRenderedOutputTooBig
+	}
+	if b.buf.Len()+len(p) > b.max {
+		b.exceeded = true
+		if b.onExceeded != nil {
+			b.onExceeded()
+		}
+		return 0, ErrRenderedOutputTooBig
+	}
+	return b.buf.Write(p)
+}
+
+// Bytes returns the data written to the buffer.
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
@@ -0,0 +1,18 @@
+package helmchart
+
+import "syscall"
+
+// setRendererResourcesLimits limits the memory and cpu time the renderer
+// process can use.
+func setRendererResourcesLimits() error {
+	if err := syscall.Setrlimit(syscall.RLIMIT_DATA, &syscall.Rlimit{
+		Cur: rendererMaxMemory,
+		Max: rendererMaxMemory,
+	}); err != nil {
+		return err
+	}
+	return syscall.Setrlimit(syscall.RLIMIT_CPU, &syscall.Rlimit{
+		Cur: rendererMaxCPUTime,
+		Max: rendererMaxCPUTime + 1,
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package helmchart
+
+// setRendererResourcesLimits is a no-op on platforms other than Linux. The
+// renderer process is still killed when it takes too long or its output
+// exceeds the maximum size allowed.
+func setRendererResourcesLimits() error {
+	return nil
+}
@@ -0,0 +1,177 @@
+package helmchart
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	RunRendererIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestRenderTemplates(t *testing.T) {
+	chartData, err := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+	require.NoError(t, err)
+
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		manifests, err := RenderTemplates(ctx, chartData, nil, false)
+		assert.Equal(t, ErrRenderTimeout, err)
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("invalid chart archive", func(t *testing.T) {
+		t.Parallel()
+		manifests, err := RenderTemplates(context.Background(), []byte("invalid"), nil, false)
+		assert.Error(t, err)
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("error rendering templates", func(t *testing.T) {
+		t.Parallel()
+		data := newTestChartArchive(t, map[string]string{
+			"templates/configmap.yaml": `name: {{ required "name is required" .Values.name }}`,
+		})
+		manifests, err := RenderTemplates(context.Background(), data, nil, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name is required")
+		assert.Nil(t, manifests)
+
+		manifests, err = RenderTemplates(context.Background(), data, nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, "name: ", manifests["pkg2/templates/configmap.yaml"])
+	})
+
+	t.Run("values do not match values schema", func(t *testing.T) {
+		t.Parallel()
+		values := map[string]interface{}{
+			"replicaCount": "two",
+		}
+		manifests, err := RenderTemplates(context.Background(), chartData, values, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "replicaCount")
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("required values supplied by the chart defaults", func(t *testing.T) {
+		t.Parallel()
+		data := newTestChartArchive(t, map[string]string{
+			"values.yaml":              "name: app\n",
+			"values.schema.json":       `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`,
+			"templates/configmap.yaml": `name: {{ .Values.name }}`,
+		})
+		manifests, err := RenderTemplates(context.Background(), data, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, "name: app", manifests["pkg2/templates/configmap.yaml"])
+
+		values := map[string]interface{}{
+			"name": 1,
+		}
+		manifests, err = RenderTemplates(context.Background(), data, values, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name")
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("rendering takes too long", func(t *testing.T) {
+		t.Parallel()
+		data := newTestChartArchive(t, map[string]string{
+			"templates/loop.yaml": `{{ range until 100000 }}{{ range until 100000 }}{{ end }}{{ end }}`,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+		start := time.Now()
+		manifests, err := RenderTemplates(ctx, data, nil, false)
+		assert.Equal(t, ErrRenderTimeout, err)
+		assert.Nil(t, manifests)
+		assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	})
+
+	t.Run("rendered output too big", func(t *testing.T) {
+		t.Parallel()
+		data := newTestChartArchive(t, map[string]string{
+			"templates/big.yaml": `{{ range until 100000 }}{{ "x" | repeat 100 }}{{ end }}`,
+		})
+		manifests, err := RenderTemplates(context.Background(), data, nil, false)
+		assert.Equal(t, ErrRenderedOutputTooBig, err)
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("rendering uses too much memory", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("memory limits are only applied on linux")
+		}
+		t.Parallel()
+		data := newTestChartArchive(t, map[string]string{
+			"templates/mem.yaml": `{{ $s := list }}{{ range until 10 }}{{ $s = append $s ("x" | repeat 100000000) }}{{ end }}`,
+		})
+		manifests, err := RenderTemplates(context.Background(), data, nil, false)
+		assert.Error(t, err)
+		assert.Nil(t, manifests)
+	})
+
+	t.Run("templates rendered successfully", func(t *testing.T) {
+		t.Parallel()
+		values := map[string]interface{}{
+			"replicaCount": 3,
+		}
+		manifests, err := RenderTemplates(context.Background(), chartData, values, false)
+		require.NoError(t, err)
+		assert.Contains(t, manifests["pkg1/templates/deployment.yaml"], "replicas: 3")
+		assert.Contains(t, manifests["pkg1/templates/deployment.yaml"], "image: repo/pkg1:1.0.0")
+	})
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	var exceeded bool
+	b := &limitedBuffer{max: 5, onExceeded: func() { exceeded = true }}
+
+	n, err := b.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, exceeded)
+
+	n, err = b.Write([]byte("def"))
+	assert.Equal(t, ErrRenderedOutputTooBig, err)
+	assert.Equal(t, 0, n)
+	assert.True(t, exceeded)
+	assert.Equal(t, []byte("abc"), b.Bytes())
+}
+
+func newTestChartArchive(t *testing.T, templates map[string]string) []byte {
+	t.Helper()
+	files := map[string]string{
+		"pkg2/Chart.yaml": "apiVersion: v2\nname: pkg2\nversion: 1.0.0\n",
+	}
+	for name, content := range templates {
+		files["pkg2/"+name] = content
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
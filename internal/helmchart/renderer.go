@@ -0,0 +1,184 @@
+package helmchart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+const (
+	// maxArchiveSize represents the maximum size of the charts archives that
+	// can be rendered.
+	maxArchiveSize = 10 * 1024 * 1024
+
+	// renderTimeout represents the maximum amount of time the rendering of
+	// the chart templates can take.
+	renderTimeout = 10 * time.Second
+)
+
+// TemplateRenderer provides a mechanism to render the templates of the Helm
+// charts versions available in the hub.
+type TemplateRenderer struct {
+	pm hub.PackageManager
+	rm hub.RepositoryManager
+	hc HTTPClient
+	op hub.OCIPuller
+}
+
+// NewTemplateRenderer creates a new TemplateRenderer instance.
+func NewTemplateRenderer(
+	pm hub.PackageManager,
+	rm hub.RepositoryManager,
+	opts ...func(tr *TemplateRenderer),
+) *TemplateRenderer {
+	tr := &TemplateRenderer{
+		pm: pm,
+		rm: rm,
+	}
+	for _, o := range opts {
+		o(tr)
+	}
+	if tr.hc == nil {
+		tr.hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	if tr.op == nil {
+		tr.op = &oci.Puller{}
+	}
+	return tr
+}
+
+// WithHTTPClient allows providing a specific HTTPClient implementation for a
+// TemplateRenderer instance.
+func WithHTTPClient(hc HTTPClient) func(tr *TemplateRenderer) {
+	return func(tr *TemplateRenderer) {
+		tr.hc = hc
+	}
+}
+
+// WithOCIPuller allows providing a specific OCIPuller implementation for a
+// TemplateRenderer instance.
+func WithOCIPuller(op hub.OCIPuller) func(tr *TemplateRenderer) {
+	return func(tr *TemplateRenderer) {
+		tr.op = op
+	}
+}
+
+// RenderJSON renders the templates of the Helm chart version identified by
+// the input provided using the values given, returning the manifests produced
+// grouped by template file as a json array. Values are merged with the chart
+// defaults and validated against the chart values schemas before rendering.
+func (tr *TemplateRenderer) RenderJSON(
+	ctx context.Context,
+	input *hub.RenderChartTemplatesInput,
+) ([]byte, error) {
+	// Validate input
+	if input.PackageID == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package id not provided")
+	}
+	if input.Version == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "version not provided")
+	}
+	values, err := chartutil.ReadValues(input.Values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid values", err.Error())
+	}
+
+	// Get package version details
+	p, err := tr.pm.Get(ctx, &hub.GetPackageInput{
+		PackageID: input.PackageID,
+		Version:   input.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.Repository == nil || p.Repository.Kind != hub.Helm {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package is not a helm chart")
+	}
+
+	// Load chart from the remote archive
+	u, err := url.Parse(p.ContentURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart url %s: %w", p.ContentURL, err)
+	}
+	var username, password string
+	if p.Repository.Private {
+		r, err := tr.rm.GetByID(ctx, p.Repository.RepositoryID, true)
+		if err != nil {
+			return nil, err
+		}
+		username, password = r.AuthUser, r.AuthPass
+	}
+	_, chartData, err := Load(ctx, tr.hc, tr.op, &LoadInput{
+		URL:      u,
+		Username: username,
+		Password: password,
+		MaxSize:  maxArchiveSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading chart: %w", err)
+	}
+
+	// Render chart templates
+	renderCtx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+	manifests, err := RenderTemplates(renderCtx, chartData, values, false)
+	if err != nil {
+		if errors.Is(err, ErrRenderTimeout) && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+	}
+
+	return json.Marshal(groupManifestsByTemplate(manifests))
+}
+
+// groupManifestsByTemplate splits the rendered templates provided into the
+// manifests they contain, returning them sorted by template name. Partials,
+// notes and templates that did not produce any manifest are omitted.
+func groupManifestsByTemplate(manifests map[string]string) []*hub.ChartTemplate {
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	templates := make([]*hub.ChartTemplate, 0, len(names))
+	for _, name := range names {
+		docs := releaseutil.SplitManifests(manifests[name])
+		keys := make([]string, 0, len(docs))
+		for key := range docs {
+			keys = append(keys, key)
+		}
+		sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+		var t *hub.ChartTemplate
+		for _, key := range keys {
+			if docs[key] == "" {
+				continue
+			}
+			if t == nil {
+				t = &hub.ChartTemplate{Name: name}
+			}
+			t.Manifests = append(t.Manifests, docs[key])
+		}
+		if t != nil {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
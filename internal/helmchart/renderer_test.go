@@ -0,0 +1,248 @@
+package helmchart
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJSON(t *testing.T) {
+	ctx := context.Background()
+	chartURL := "https://repo.url/pkg1-1.0.0.tgz"
+	chartData, err := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+	require.NoError(t, err)
+	p := &hub.Package{
+		ContentURL: chartURL,
+		Repository: &hub.Repository{
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+			Kind:         hub.Helm,
+		},
+	}
+	getPkgInput := &hub.GetPackageInput{
+		PackageID: "pkg1",
+		Version:   "1.0.0",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.RenderChartTemplatesInput
+		}{
+			{
+				"package id not provided",
+				&hub.RenderChartTemplatesInput{},
+			},
+			{
+				"version not provided",
+				&hub.RenderChartTemplatesInput{
+					PackageID: "pkg1",
+				},
+			},
+			{
+				"invalid values",
+				&hub.RenderChartTemplatesInput{
+					PackageID: "pkg1",
+					Version:   "1.0.0",
+					Values:    []byte("{"),
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				tr := NewTemplateRenderer(nil, nil)
+				dataJSON, err := tr.RenderJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("error getting package", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(nil, hub.ErrNotFound)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+		})
+		assert.Equal(t, hub.ErrNotFound, err)
+		assert.Nil(t, dataJSON)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("package is not a helm chart", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(&hub.Package{
+			Repository: &hub.Repository{
+				Kind: hub.OLM,
+			},
+		}, nil)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+		})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Nil(t, dataJSON)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("values do not match values schema", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(p, nil)
+		req, _ := http.NewRequest("GET", chartURL, nil)
+		tw.hc.On("Do", req).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+			Values:    []byte("replicaCount: two"),
+		})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "replicaCount")
+		assert.Nil(t, dataJSON)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("error getting repository credentials", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(&hub.Package{
+			ContentURL: chartURL,
+			Repository: &hub.Repository{
+				RepositoryID: "00000000-0000-0000-0000-000000000001",
+				Kind:         hub.Helm,
+				Private:      true,
+			},
+		}, nil)
+		tw.rm.On("GetByID", ctx, "00000000-0000-0000-0000-000000000001").Return(nil, tests.ErrFakeDB)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+		})
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("error loading chart", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(p, nil)
+		req, _ := http.NewRequest("GET", chartURL, nil)
+		tw.hc.On("Do", req).Return(nil, tests.ErrFake)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+		})
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Nil(t, dataJSON)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("chart templates rendered successfully", func(t *testing.T) {
+		t.Parallel()
+		tw := newTemplateRendererWrapper()
+		tw.pm.On("Get", ctx, getPkgInput).Return(&hub.Package{
+			ContentURL: chartURL,
+			Repository: &hub.Repository{
+				RepositoryID: "00000000-0000-0000-0000-000000000001",
+				Kind:         hub.Helm,
+				Private:      true,
+			},
+		}, nil)
+		tw.rm.On("GetByID", ctx, "00000000-0000-0000-0000-000000000001").Return(&hub.Repository{
+			AuthUser: "user",
+			AuthPass: "pass",
+		}, nil)
+		req, _ := http.NewRequest("GET", chartURL, nil)
+		req.SetBasicAuth("user", "pass")
+		tw.hc.On("Do", req).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		dataJSON, err := tw.tr.RenderJSON(ctx, &hub.RenderChartTemplatesInput{
+			PackageID: "pkg1",
+			Version:   "1.0.0",
+			Values:    []byte("replicaCount: 2"),
+		})
+		require.NoError(t, err)
+		var templates []*hub.ChartTemplate
+		require.NoError(t, json.Unmarshal(dataJSON, &templates))
+		assert.Equal(t, []*hub.ChartTemplate{
+			{
+				Name: "pkg1/templates/deployment.yaml",
+				Manifests: []string{`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: pkg1
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: app
+          image: repo/pkg1:1.0.0`,
+				},
+			},
+			{
+				Name: "pkg1/templates/service.yaml",
+				Manifests: []string{
+					"apiVersion: v1\nkind: Service\nmetadata:\n  name: pkg1",
+					"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: pkg1",
+				},
+			},
+		}, templates)
+		tw.assertExpectations(t)
+	})
+}
+
+type templateRendererWrapper struct {
+	pm *pkg.ManagerMock
+	rm *repo.ManagerMock
+	hc *tests.HTTPClientMock
+	tr *TemplateRenderer
+}
+
+func newTemplateRendererWrapper() *templateRendererWrapper {
+	pm := &pkg.ManagerMock{}
+	rm := &repo.ManagerMock{}
+	hc := &tests.HTTPClientMock{}
+	tr := NewTemplateRenderer(pm, rm, WithHTTPClient(hc))
+
+	return &templateRendererWrapper{
+		pm: pm,
+		rm: rm,
+		hc: hc,
+		tr: tr,
+	}
+}
+
+func (tw *templateRendererWrapper) assertExpectations(t *testing.T) {
+	tw.pm.AssertExpectations(t)
+	tw.rm.AssertExpectations(t)
+	tw.hc.AssertExpectations(t)
+}
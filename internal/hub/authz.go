@@ -32,6 +32,10 @@ const (
 	// authorization policy.
 	GetAuthorizationPolicy Action = "getAuthorizationPolicy"
 
+	// TrackOrganizationRepository represents the action of requesting the
+	// tracking of a repository that belongs to an organization.
+	TrackOrganizationRepository Action = "trackOrganizationRepository"
+
 	// TransferOrganizationRepository represents the action of transferring a
 	// repository that belongs to an organization.
 	TransferOrganizationRepository Action = "transferOrganizationRepository"
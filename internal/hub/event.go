@@ -33,10 +33,44 @@ const (
 	// RepositoryOwnershipClaim represents an event for a repository ownership
 	// claim.
 	RepositoryOwnershipClaim EventKind = 3
+
+	// PackageVersionRemoved represents an event for a package version that
+	// has been removed from the repository.
+	PackageVersionRemoved EventKind = 4
+
+	// PackageDeprecated represents an event for a package that has been
+	// deprecated.
+	PackageDeprecated EventKind = 5
+
+	// RepositoryTransferred represents an event for a repository that has
+	// been transferred to a different owner.
+	RepositoryTransferred EventKind = 6
+
+	// RepositoryVerifiedPublisherChanged represents an event for a change in
+	// the verified publisher status of a repository.
+	RepositoryVerifiedPublisherChanged EventKind = 7
 )
 
 // EventManager describes the methods an EventManager implementation must
 // provide.
 type EventManager interface {
+	GetLastProcessedID(ctx context.Context) (string, error)
 	GetPending(ctx context.Context, tx pgx.Tx) (*Event, error)
+	GetToStreamJSON(ctx context.Context, input *GetEventsToStreamInput) ([]byte, error)
+	NotifyProcessed(ctx context.Context, tx pgx.Tx, eventID string) error
+}
+
+// EventsBroker describes the methods an EventsBroker implementation must
+// provide.
+type EventsBroker interface {
+	Subscribe() (<-chan string, func())
+}
+
+// GetEventsToStreamInput represents the input used to get the events to send
+// to a stream.
+type GetEventsToStreamInput struct {
+	All         bool   `json:"all"`
+	EventID     string `json:"event_id,omitempty"`
+	LastEventID string `json:"last_event_id,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
 }
@@ -2,6 +2,7 @@ package hub
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 )
@@ -9,16 +10,36 @@ import (
 // Notification represents the details of a notification pending to be delivered.
 type Notification struct {
 	NotificationID string   `json:"notification_id"`
+	Attempts       int      `json:"attempts"`
 	Event          *Event   `json:"event"`
 	User           *User    `json:"user"`
 	Webhook        *Webhook `json:"webhook"`
 }
 
+// NotificationsDigest represents a set of notifications pending to be
+// delivered to a user grouped in a single digest.
+type NotificationsDigest struct {
+	User          *User           `json:"user"`
+	Notifications []*Notification `json:"notifications"`
+}
+
 // NotificationManager describes the methods an NotificationManager
 // implementation must provide.
 type NotificationManager interface {
 	Add(ctx context.Context, tx pgx.Tx, n *Notification) error
+	AddWebhookDelivery(ctx context.Context, tx pgx.Tx, d *WebhookDelivery) error
+	DeleteOldWebhookDeliveries(ctx context.Context, tx pgx.Tx, retention time.Duration) error
 	GetPending(ctx context.Context, tx pgx.Tx) (*Notification, error)
+	GetPendingDigest(ctx context.Context, tx pgx.Tx) (*NotificationsDigest, error)
+	MarkAsDead(ctx context.Context, tx pgx.Tx, notificationID string, deliveryErr error) error
+	ScheduleRetry(
+		ctx context.Context,
+		tx pgx.Tx,
+		notificationID string,
+		delay time.Duration,
+		deliveryErr error,
+	) error
+	UpdateLastDigestSentAt(ctx context.Context, tx pgx.Tx, userID string) error
 	UpdateStatus(
 		ctx context.Context,
 		tx pgx.Tx,
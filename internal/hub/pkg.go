@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 const (
@@ -33,9 +34,30 @@ type Link struct {
 
 // ContainerImage represents a container image associated with a package.
 type ContainerImage struct {
-	Name        string `json:"name" yaml:"name"`
-	Image       string `json:"image" yaml:"image"`
-	Whitelisted bool   `json:"whitelisted" yaml:"whitelisted"`
+	Name               string              `json:"name" yaml:"name"`
+	Image              string              `json:"image" yaml:"image"`
+	Whitelisted        bool                `json:"whitelisted" yaml:"whitelisted"`
+	AutoDetected       bool                `json:"auto_detected,omitempty" yaml:"-"`
+	CosignVerification *CosignVerification `json:"cosign_verification,omitempty" yaml:"-"`
+}
+
+// CosignVerification represents the result of verifying the cosign signatures
+// of an OCI artifact, like a container image or a Helm chart stored in an OCI
+// registry, using the signing keys provided by the publisher in the repository
+// metadata file.
+type CosignVerification struct {
+	Verified       bool   `json:"verified"`
+	Digest         string `json:"digest,omitempty"`
+	KeyFingerprint string `json:"key_fingerprint,omitempty"`
+}
+
+// Dependency represents a dependency of a package, like the ones Helm charts
+// list in their Chart.yaml file.
+type Dependency struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Repository string `json:"repository"`
+	Alias      string `json:"alias,omitempty"`
 }
 
 // Maintainer represents a package's maintainer.
@@ -47,46 +69,62 @@ type Maintainer struct {
 
 // Package represents a Kubernetes package.
 type Package struct {
-	PackageID               string                 `json:"package_id"`
-	Name                    string                 `json:"name"`
-	NormalizedName          string                 `json:"normalized_name"`
-	LogoURL                 string                 `json:"logo_url"`
-	LogoImageID             string                 `json:"logo_image_id"`
-	IsOperator              bool                   `json:"is_operator"`
-	Channels                []*Channel             `json:"channels"`
-	DefaultChannel          string                 `json:"default_channel"`
-	DisplayName             string                 `json:"display_name"`
-	Description             string                 `json:"description"`
-	Keywords                []string               `json:"keywords"`
-	HomeURL                 string                 `json:"home_url"`
-	Readme                  string                 `json:"readme"`
-	Install                 string                 `json:"install"`
-	Links                   []*Link                `json:"links"`
-	Capabilities            string                 `json:"capabilities"`
-	CRDs                    []interface{}          `json:"crds"`
-	CRDsExamples            []interface{}          `json:"crds_examples"`
-	SecurityReportSummary   *SecurityReportSummary `json:"security_report_summary"`
-	SecurityReportCreatedAt int64                  `json:"security_report_created_at,omitempty"`
-	Data                    map[string]interface{} `json:"data"`
-	Version                 string                 `json:"version"`
-	AvailableVersions       []*Version             `json:"available_versions"`
-	AppVersion              string                 `json:"app_version"`
-	Digest                  string                 `json:"digest"`
-	Deprecated              bool                   `json:"deprecated"`
-	License                 string                 `json:"license"`
-	Signed                  bool                   `json:"signed"`
-	ContentURL              string                 `json:"content_url"`
-	ContainersImages        []*ContainerImage      `json:"containers_images"`
-	Provider                string                 `json:"provider"`
-	HasValuesSchema         bool                   `json:"has_values_schema"`
-	ValuesSchema            json.RawMessage        `json:"values_schema"`
-	HasChangeLog            bool                   `json:"has_changelog"`
-	Changes                 []string               `json:"changes"`
-	ContainsSecurityUpdates bool                   `json:"contains_security_updates"`
-	Prerelease              bool                   `json:"prerelease"`
-	Maintainers             []*Maintainer          `json:"maintainers"`
-	Repository              *Repository            `json:"repository"`
-	CreatedAt               int64                  `json:"created_at,omitempty"`
+	PackageID               string                         `json:"package_id"`
+	Name                    string                         `json:"name"`
+	NormalizedName          string                         `json:"normalized_name"`
+	LogoURL                 string                         `json:"logo_url"`
+	LogoImageID             string                         `json:"logo_image_id"`
+	IsOperator              bool                           `json:"is_operator"`
+	Channels                []*Channel                     `json:"channels"`
+	DefaultChannel          string                         `json:"default_channel"`
+	DisplayName             string                         `json:"display_name"`
+	Description             string                         `json:"description"`
+	Keywords                []string                       `json:"keywords"`
+	HomeURL                 string                         `json:"home_url"`
+	Readme                  string                         `json:"readme"`
+	Install                 string                         `json:"install"`
+	Links                   []*Link                        `json:"links"`
+	Capabilities            string                         `json:"capabilities"`
+	CRDs                    []interface{}                  `json:"crds"`
+	CRDsExamples            []interface{}                  `json:"crds_examples"`
+	SecurityReportSummary   *SecurityReportSummary         `json:"security_report_summary"`
+	SecurityReportCreatedAt int64                          `json:"security_report_created_at,omitempty"`
+	Data                    map[string]interface{}         `json:"data"`
+	Version                 string                         `json:"version"`
+	AvailableVersions       []*Version                     `json:"available_versions"`
+	AppVersion              string                         `json:"app_version"`
+	Digest                  string                         `json:"digest"`
+	Deprecated              bool                           `json:"deprecated"`
+	License                 string                         `json:"license"`
+	Signed                  bool                           `json:"signed"`
+	ProvenanceVerification  *ProvenanceVerification        `json:"provenance_verification,omitempty"`
+	CosignVerification      *CosignVerification            `json:"cosign_verification,omitempty"`
+	ContentURL              string                         `json:"content_url"`
+	ContainersImages        []*ContainerImage              `json:"containers_images"`
+	Provider                string                         `json:"provider"`
+	HasValuesSchema         bool                           `json:"has_values_schema"`
+	ValuesSchema            json.RawMessage                `json:"values_schema"`
+	HasChangeLog            bool                           `json:"has_changelog"`
+	Changes                 []string                       `json:"changes"`
+	ContainsSecurityUpdates bool                           `json:"contains_security_updates"`
+	Prerelease              bool                           `json:"prerelease"`
+	VulnerabilityAllowlist  []*VulnerabilityAllowlistEntry `json:"vulnerability_allowlist"`
+	Maintainers             []*Maintainer                  `json:"maintainers"`
+	Repository              *Repository                    `json:"repository"`
+	CreatedAt               int64                          `json:"created_at,omitempty"`
+}
+
+// ChartTemplate represents a Helm chart template file rendered, including the
+// manifests it produced.
+type ChartTemplate struct {
+	Name      string   `json:"name"`
+	Manifests []string `json:"manifests"`
+}
+
+// ChartTemplateRenderer describes the methods a ChartTemplateRenderer
+// implementation must provide.
+type ChartTemplateRenderer interface {
+	RenderJSON(ctx context.Context, input *RenderChartTemplatesInput) ([]byte, error)
 }
 
 // PackageManager describes the methods a PackageManager implementation must
@@ -94,11 +132,14 @@ type Package struct {
 type PackageManager interface {
 	Get(ctx context.Context, input *GetPackageInput) (*Package, error)
 	GetChangeLogJSON(ctx context.Context, pkgID string) ([]byte, error)
+	GetDependenciesJSON(ctx context.Context, pkgID, version string) ([]byte, error)
+	GetDependentsJSON(ctx context.Context, pkgID string) ([]byte, error)
 	GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error)
 	GetJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
 	GetRandomJSON(ctx context.Context) ([]byte, error)
+	GetSnapshotSBOMJSON(ctx context.Context, pkgID, version, format string) ([]byte, error)
 	GetSnapshotSecurityReportJSON(ctx context.Context, pkgID, version string) ([]byte, error)
-	GetSnapshotsToScan(ctx context.Context) ([]*SnapshotToScan, error)
+	GetSnapshotsToScan(ctx context.Context, reportMaxAge time.Duration) ([]*SnapshotToScan, error)
 	GetStarredByUserJSON(ctx context.Context) ([]byte, error)
 	GetStarsJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetStatsJSON(ctx context.Context) ([]byte, error)
@@ -141,30 +182,119 @@ type PackageMetadata struct {
 	Ignore                  []string          `yaml:"ignore"`
 }
 
+// RenderChartTemplatesInput represents the input used to render the templates
+// of a Helm chart version.
+type RenderChartTemplatesInput struct {
+	PackageID string `json:"package_id"`
+	Version   string `json:"version"`
+	Values    []byte `json:"values"`
+}
+
 // SnapshotSecurityReport represents some information about the security
 // vulnerabilities the images used by a given package's snapshot may have.
 type SnapshotSecurityReport struct {
-	PackageID string                   `json:"package_id"`
-	Version   string                   `json:"version"`
-	Summary   *SecurityReportSummary   `json:"summary"`
-	Full      map[string][]interface{} `json:"full"`
+	PackageID string                             `json:"package_id"`
+	Version   string                             `json:"version"`
+	Summary   *SecurityReportSummary             `json:"summary"`
+	Full      map[string][]*SecurityReportTarget `json:"full"` // K: image
+	SBOM      *SnapshotSBOM                      `json:"sbom,omitempty"`
+
+	// VulnerabilityAllowlist represents the allowlist applied when the
+	// report was generated.
+	VulnerabilityAllowlist []*VulnerabilityAllowlistEntry `json:"vulnerability_allowlist,omitempty"`
+}
+
+// SnapshotSBOM represents the software bill of materials of a package's
+// snapshot, available in the CycloneDX and SPDX formats.
+type SnapshotSBOM struct {
+	CycloneDX json.RawMessage `json:"cyclonedx"`
+	SPDX      json.RawMessage `json:"spdx"`
+}
+
+// SecurityReportTarget represents a target scanned in an image, like the
+// operating system packages or a language specific lock file, and the
+// vulnerabilities found in it. The json fields names are the ones used in the
+// security reports stored before scanners backends were made pluggable, so
+// that existing reports are still valid.
+type SecurityReportTarget struct {
+	Target          string           `json:"Target"`
+	Type            string           `json:"Type"`
+	Vulnerabilities []*Vulnerability `json:"Vulnerabilities"`
+}
+
+// Vulnerability represents a security vulnerability found in a package.
+type Vulnerability struct {
+	VulnerabilityID  string                    `json:"VulnerabilityID"`
+	PkgName          string                    `json:"PkgName"`
+	InstalledVersion string                    `json:"InstalledVersion"`
+	FixedVersion     string                    `json:"FixedVersion,omitempty"`
+	Severity         string                    `json:"Severity"`
+	SeveritySource   string                    `json:"SeveritySource,omitempty"`
+	Title            string                    `json:"Title,omitempty"`
+	Description      string                    `json:"Description,omitempty"`
+	References       []string                  `json:"References,omitempty"`
+	CVSS             map[string]*CVSS          `json:"CVSS,omitempty"` // K: source
+	LastModifiedDate string                    `json:"LastModifiedDate,omitempty"`
+	Suppression      *VulnerabilitySuppression `json:"Suppression,omitempty"`
+}
+
+// VulnerabilitySuppression represents the reason why a vulnerability has been
+// suppressed from the security report summary. Vulnerabilities are suppressed
+// when they match an entry in the vulnerability allowlist that has not expired
+// yet.
+type VulnerabilitySuppression struct {
+	Justification string `json:"Justification"`
+	ExpiresAt     string `json:"ExpiresAt"`
+}
+
+// CVSS represents the CVSS vectors and scores of a vulnerability provided by
+// a given source.
+type CVSS struct {
+	V2Vector string  `json:"V2Vector,omitempty"`
+	V3Vector string  `json:"V3Vector,omitempty"`
+	V2Score  float64 `json:"V2Score,omitempty"`
+	V3Score  float64 `json:"V3Score,omitempty"`
 }
 
 // SecurityReportSummary represents a summary of the security report.
 type SecurityReportSummary struct {
-	Critical int `json:"critical"`
-	High     int `json:"high"`
-	Medium   int `json:"medium"`
-	Low      int `json:"low"`
-	Unknown  int `json:"unknown"`
+	Critical   int `json:"critical"`
+	High       int `json:"high"`
+	Medium     int `json:"medium"`
+	Low        int `json:"low"`
+	Unknown    int `json:"unknown"`
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// ProvenanceVerification represents the result of verifying the provenance
+// file of a package version using the signing keys provided by the publisher
+// in the repository metadata file.
+type ProvenanceVerification struct {
+	Verified       bool   `json:"verified"`
+	KeyFingerprint string `json:"key_fingerprint,omitempty"`
+	Signer         string `json:"signer,omitempty"`
 }
 
 // SnapshotToScan represents some information about a package's snapshot that
 // needs to be scanned for security vulnerabilities.
 type SnapshotToScan struct {
-	PackageID        string            `json:"package_id"`
-	Version          string            `json:"version"`
-	ContainersImages []*ContainerImage `json:"containers_images"`
+	PackageID              string                         `json:"package_id"`
+	PackageName            string                         `json:"package_name"`
+	Version                string                         `json:"version"`
+	License                string                         `json:"license"`
+	ContainersImages       []*ContainerImage              `json:"containers_images"`
+	Dependencies           []*Dependency                  `json:"dependencies"`
+	VulnerabilityAllowlist []*VulnerabilityAllowlistEntry `json:"vulnerability_allowlist"`
+}
+
+// VulnerabilityAllowlistEntry represents an entry in the vulnerability
+// allowlist. Publishers can use this list to accept specific vulnerabilities,
+// that won't be counted in the security report summary until the entry
+// expires. The expiration date must be provided using the YYYY-MM-DD format.
+type VulnerabilityAllowlistEntry struct {
+	VulnerabilityID string `json:"vulnerability_id" yaml:"vulnerabilityID"`
+	Justification   string `json:"justification" yaml:"justification"`
+	ExpiresAt       string `json:"expires_at" yaml:"expiresAt"`
 }
 
 // Provider represents a package's provider.
@@ -187,6 +317,7 @@ type SearchPackageInput struct {
 	Official          bool             `json:"official"`
 	Operators         bool             `json:"operators"`
 	Deprecated        bool             `json:"deprecated"`
+	VerifiedSignature bool             `json:"verified_signature"`
 	Licenses          []string         `json:"licenses,omitempty"`
 	Capabilities      []string         `json:"capabilities,omitempty"`
 }
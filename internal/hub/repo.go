@@ -3,7 +3,9 @@ package hub
 import (
 	"context"
 	"errors"
+	"time"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
@@ -95,6 +97,24 @@ type HelmIndexLoader interface {
 	LoadIndex(r *Repository) (*helmrepo.IndexFile, string, error)
 }
 
+// OCIPuller describes the methods an OCIPuller implementation must provide.
+type OCIPuller interface {
+	Digest(ctx context.Context, ref, username, password string) (string, error)
+	PullLayer(ctx context.Context, ref, mediaType, username, password string) (v1.Descriptor, []byte, error)
+}
+
+// OCISignatureVerifier describes the methods an OCISignatureVerifier
+// implementation must provide.
+type OCISignatureVerifier interface {
+	Verify(ctx context.Context, ref string, keys []string, username, password string) (*CosignVerification, error)
+}
+
+// OCITagsGetter describes the methods an OCITagsGetter implementation must
+// provide.
+type OCITagsGetter interface {
+	Tags(ctx context.Context, r *Repository) ([]string, error)
+}
+
 // OLMRepositoryExporter describes the methods an OLMRepositoryExporter
 // implementation must provide.
 type OLMRepositoryExporter interface {
@@ -109,26 +129,29 @@ type Owner struct {
 
 // Repository represents a packages repository.
 type Repository struct {
-	RepositoryID            string         `json:"repository_id"`
-	Name                    string         `json:"name"`
-	DisplayName             string         `json:"display_name"`
-	URL                     string         `json:"url"`
-	Branch                  string         `json:"branch"`
-	Private                 bool           `json:"private"`
-	AuthUser                string         `json:"auth_user"`
-	AuthPass                string         `json:"auth_pass"`
-	Digest                  string         `json:"digest"`
-	Kind                    RepositoryKind `json:"kind"`
-	UserID                  string         `json:"user_id"`
-	UserAlias               string         `json:"user_alias"`
-	OrganizationID          string         `json:"organization_id"`
-	OrganizationName        string         `json:"organization_name"`
-	OrganizationDisplayName string         `json:"organization_display_name"`
-	LastTrackingErrors      string         `json:"last_tracking_errors"`
-	VerifiedPublisher       bool           `json:"verified_publisher"`
-	Official                bool           `json:"official"`
-	Disabled                bool           `json:"disabled"`
-	ScannerDisabled         bool           `json:"scanner_disabled"`
+	RepositoryID            string                         `json:"repository_id"`
+	Name                    string                         `json:"name"`
+	DisplayName             string                         `json:"display_name"`
+	URL                     string                         `json:"url"`
+	Branch                  string                         `json:"branch"`
+	Private                 bool                           `json:"private"`
+	AuthUser                string                         `json:"auth_user"`
+	AuthPass                string                         `json:"auth_pass"`
+	Digest                  string                         `json:"digest"`
+	Kind                    RepositoryKind                 `json:"kind"`
+	UserID                  string                         `json:"user_id"`
+	UserAlias               string                         `json:"user_alias"`
+	OrganizationID          string                         `json:"organization_id"`
+	OrganizationName        string                         `json:"organization_name"`
+	OrganizationDisplayName string                         `json:"organization_display_name"`
+	LastTrackingErrors      string                         `json:"last_tracking_errors"`
+	TrackingInterval        int                            `json:"tracking_interval"`
+	VerifiedPublisher       bool                           `json:"verified_publisher"`
+	Official                bool                           `json:"official"`
+	Disabled                bool                           `json:"disabled"`
+	ScannerDisabled         bool                           `json:"scanner_disabled"`
+	VulnerabilityAllowlist  []*VulnerabilityAllowlistEntry `json:"vulnerability_allowlist"`
+	SigningKeysDigest       string                         `json:"signing_keys_digest"`
 }
 
 // RepositoryCloner describes the methods a RepositoryCloner implementation
@@ -141,6 +164,14 @@ type RepositoryCloner interface {
 	CloneRepository(ctx context.Context, r *Repository) (tmpDir string, packagesPath string, err error)
 }
 
+// RepositoryHeadGetter describes the methods a RepositoryHeadGetter
+// implementation must provide.
+type RepositoryHeadGetter interface {
+	// GetHead returns the commit the branch of the git repository provided is
+	// pointing to, without cloning it.
+	GetHead(ctx context.Context, r *Repository) (string, error)
+}
+
 // RepositoryManager describes the methods an RepositoryManager
 // implementation must provide.
 type RepositoryManager interface {
@@ -159,20 +190,38 @@ type RepositoryManager interface {
 	GetOwnedByOrgJSON(ctx context.Context, orgName string, includeCredentials bool) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context, includeCredentials bool) ([]byte, error)
 	GetRemoteDigest(ctx context.Context, r *Repository) (string, error)
+	GetToTrack(ctx context.Context, defaultTrackingInterval time.Duration) ([]*Repository, error)
+	GetTrackingRunsJSON(ctx context.Context, name string) ([]byte, error)
+	RequestTracking(ctx context.Context, name string) error
 	SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error
 	SetVerifiedPublisher(ctx context.Context, repositorID string, verified bool) error
+	SetVulnerabilityAllowlist(ctx context.Context, repositoryID string, allowlist []*VulnerabilityAllowlistEntry) error
+	StartTracking(ctx context.Context, repositoryID string) error
 	Transfer(ctx context.Context, name, orgName string, ownershipClaim bool) error
 	Update(ctx context.Context, r *Repository) error
 	UpdateDigest(ctx context.Context, repositorID, digest string) error
+	UpdateSigningKeysDigest(ctx context.Context, repositoryID, digest string) error
 }
 
 // RepositoryMetadata represents some metadata about a given repository. It's
 // usually provided by repositories publishers, to provide some extra context
 // about the repository they'd like to publish.
 type RepositoryMetadata struct {
-	RepositoryID string                   `yaml:"repositoryID"`
-	Owners       []*Owner                 `yaml:"owners"`
-	Ignore       []*RepositoryIgnoreEntry `yaml:"ignore"`
+	RepositoryID           string                         `yaml:"repositoryID"`
+	Owners                 []*Owner                       `yaml:"owners"`
+	Ignore                 []*RepositoryIgnoreEntry       `yaml:"ignore"`
+	VulnerabilityAllowlist []*VulnerabilityAllowlistEntry `yaml:"vulnerabilityAllowlist"`
+	SigningKeys            *RepositorySigningKeys         `yaml:"signingKeys"`
+}
+
+// RepositorySigningKeys represents the public keys publishers use to sign the
+// packages in their repositories. PGP keys must be provided in ASCII armored
+// format and are used to verify Helm charts provenance files. Cosign keys must
+// be provided in PEM format and are used to verify the signatures of OCI Helm
+// charts and containers images.
+type RepositorySigningKeys struct {
+	PGP    []string `yaml:"pgp"`
+	Cosign []string `yaml:"cosign"`
 }
 
 // RepositoryIgnoreEntry represents an entry in the ignore list. This list is
@@ -12,13 +12,62 @@ type OptOut struct {
 }
 
 // Subscription represents a user's subscription to receive notifications about
-// a given package and event kind.
+// a given event kind. The subscription target can be a package, a repository or
+// an organization. When the target is a repository or an organization, the
+// user will be notified about events in any of the packages they contain.
 type Subscription struct {
-	UserID    string    `json:"user_id"`
-	PackageID string    `json:"package_id"`
-	EventKind EventKind `json:"event_kind"`
+	UserID           string              `json:"user_id"`
+	PackageID        string              `json:"package_id,omitempty"`
+	RepositoryID     string              `json:"repository_id,omitempty"`
+	OrganizationName string              `json:"organization_name,omitempty"`
+	EventKind        EventKind           `json:"event_kind"`
+	Filter           *SubscriptionFilter `json:"filter,omitempty"`
 }
 
+// SubscriptionFilter represents a set of conditions new releases must match to
+// be notified to a subscriptor or a webhook.
+type SubscriptionFilter struct {
+	// VersionConstraint is a semver constraint the new version must satisfy
+	// (i.e. ">=2.0.0 <3").
+	VersionConstraint string `json:"version_constraint,omitempty"`
+
+	// UpdateLevel is the minimum level the update from the previous version
+	// must have to be notified.
+	UpdateLevel UpdateLevel `json:"update_level,omitempty"`
+
+	// ExcludePrereleases indicates whether prereleases should be skipped.
+	ExcludePrereleases bool `json:"exclude_prereleases,omitempty"`
+
+	// SecurityUpdatesOnly indicates whether only the releases that contain
+	// security updates should be notified.
+	SecurityUpdatesOnly bool `json:"security_updates_only,omitempty"`
+}
+
+// UnsubscribeRequest represents a request to stop receiving the notifications
+// of a given event kind about a package or a repository. Unsubscribe requests
+// are encoded in the signed tokens included in the notifications emails, so
+// that users can unsubscribe without having to log in.
+type UnsubscribeRequest struct {
+	UserID       string    `json:"user_id"`
+	EventKind    EventKind `json:"event_kind"`
+	PackageID    string    `json:"package_id,omitempty"`
+	RepositoryID string    `json:"repository_id,omitempty"`
+}
+
+// UpdateLevel represents the level of an update between two versions.
+type UpdateLevel string
+
+const (
+	// MajorUpdate represents an update of the major version.
+	MajorUpdate UpdateLevel = "major"
+
+	// MinorUpdate represents an update of the minor version.
+	MinorUpdate UpdateLevel = "minor"
+
+	// PatchUpdate represents an update of the patch version.
+	PatchUpdate UpdateLevel = "patch"
+)
+
 // SubscriptionManager describes the methods a SubscriptionManager
 // implementation must provide.
 type SubscriptionManager interface {
@@ -29,5 +78,8 @@ type SubscriptionManager interface {
 	GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetByUserJSON(ctx context.Context) ([]byte, error)
 	GetOptOutListJSON(ctx context.Context) ([]byte, error)
-	GetSubscriptors(ctx context.Context, e *Event) ([]*User, error)
+	GetOrganizationsByUserJSON(ctx context.Context) ([]byte, error)
+	GetRepositoriesByUserJSON(ctx context.Context) ([]byte, error)
+	GetSubscriptors(ctx context.Context, e *Event) ([]*Subscription, error)
+	Unsubscribe(ctx context.Context, r *UnsubscribeRequest) error
 }
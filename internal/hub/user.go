@@ -32,16 +32,49 @@ type Session struct {
 	UserAgent string `json:"user_agent"`
 }
 
-// User represents a Hub user.
+// EmailDeliveryPreference represents how a user prefers to receive the email
+// notifications.
+type EmailDeliveryPreference int64
+
+const (
+	// ImmediateEmailDelivery represents the preference of receiving each email
+	// notification as soon as it is available.
+	ImmediateEmailDelivery EmailDeliveryPreference = 0
+
+	// DailyDigestEmailDelivery represents the preference of receiving the
+	// email notifications grouped in a daily digest.
+	DailyDigestEmailDelivery EmailDeliveryPreference = 1
+
+	// WeeklyDigestEmailDelivery represents the preference of receiving the
+	// email notifications grouped in a weekly digest.
+	WeeklyDigestEmailDelivery EmailDeliveryPreference = 2
+)
+
+// IsValidEmailDeliveryPreference checks if the email delivery preference
+// provided is supported.
+func IsValidEmailDeliveryPreference(p EmailDeliveryPreference) bool {
+	switch p {
+	case ImmediateEmailDelivery,
+		DailyDigestEmailDelivery,
+		WeeklyDigestEmailDelivery:
+		return true
+	default:
+		return false
+	}
+}
+
+// User represents a Hub user. The email delivery preference is a pointer so
+// that profile updates that do not include it leave it untouched.
 type User struct {
-	UserID         string `json:"user_id"`
-	Alias          string `json:"alias"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
-	Email          string `json:"email"`
-	EmailVerified  bool   `json:"email_verified"`
-	Password       string `json:"password"`
-	ProfileImageID string `json:"profile_image_id"`
+	UserID                  string                   `json:"user_id"`
+	Alias                   string                   `json:"alias"`
+	FirstName               string                   `json:"first_name"`
+	LastName                string                   `json:"last_name"`
+	Email                   string                   `json:"email"`
+	EmailVerified           bool                     `json:"email_verified"`
+	EmailDeliveryPreference *EmailDeliveryPreference `json:"email_delivery_preference,omitempty"`
+	Password                string                   `json:"password"`
+	ProfileImageID          string                   `json:"profile_image_id"`
 }
 
 type userIDKey struct{}
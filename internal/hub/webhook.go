@@ -5,16 +5,79 @@ import "context"
 // Webhook represents the configuration of a webhook where notifications will
 // be posted to.
 type Webhook struct {
-	WebhookID   string      `json:"webhook_id"`
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	URL         string      `json:"url"`
-	Secret      string      `json:"secret"`
-	ContentType string      `json:"content_type"`
-	Template    string      `json:"template"`
-	Active      bool        `json:"active"`
-	EventKinds  []EventKind `json:"event_kinds"`
-	Packages    []*Package  `json:"packages"`
+	WebhookID       string              `json:"webhook_id"`
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	URL             string              `json:"url"`
+	Secret          string              `json:"secret"`
+	SecondarySecret string              `json:"secondary_secret"`
+	SignPayloads    bool                `json:"sign_payloads"`
+	Channel         WebhookChannel      `json:"channel"`
+	Filter          *SubscriptionFilter `json:"filter"`
+	ContentType     string              `json:"content_type"`
+	Template        string              `json:"template"`
+	Active          bool                `json:"active"`
+	EventKinds      []EventKind         `json:"event_kinds"`
+	Packages        []*Package          `json:"packages"`
+	Repositories    []*Repository       `json:"repositories"`
+	Organizations   []*Organization     `json:"organizations"`
+}
+
+// WebhookChannel represents the kind of channel a webhook delivers the
+// notifications to. Channels other than the generic one have built-in payload
+// builders for their platform.
+type WebhookChannel int64
+
+const (
+	// GenericWebhookChannel represents a generic webhook endpoint.
+	GenericWebhookChannel WebhookChannel = 0
+
+	// SlackWebhookChannel represents a Slack incoming webhook.
+	SlackWebhookChannel WebhookChannel = 1
+
+	// MicrosoftTeamsWebhookChannel represents a Microsoft Teams incoming
+	// webhook.
+	MicrosoftTeamsWebhookChannel WebhookChannel = 2
+
+	// DiscordWebhookChannel represents a Discord webhook.
+	DiscordWebhookChannel WebhookChannel = 3
+)
+
+// IsValidWebhookChannel checks if the webhook channel provided is supported.
+func IsValidWebhookChannel(c WebhookChannel) bool {
+	switch c {
+	case GenericWebhookChannel,
+		SlackWebhookChannel,
+		MicrosoftTeamsWebhookChannel,
+		DiscordWebhookChannel:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDelivery represents an attempt to deliver a notification to a
+// webhook. Latency is expressed in milliseconds.
+type WebhookDelivery struct {
+	WebhookDeliveryID string `json:"webhook_delivery_id"`
+	NotificationID    string `json:"notification_id"`
+	Attempt           int    `json:"attempt"`
+	RequestPayload    string `json:"request_payload"`
+	ResponseStatus    int    `json:"response_status"`
+	ResponseBody      string `json:"response_body"`
+	Latency           int64  `json:"latency"`
+	Success           bool   `json:"success"`
+	Error             string `json:"error"`
+}
+
+// WebhookPreviewInput represents the input used to preview the payload a
+// webhook would receive when an event of the given kind happens in the
+// package provided.
+type WebhookPreviewInput struct {
+	Webhook   *Webhook  `json:"webhook"`
+	PackageID string    `json:"package_id"`
+	Version   string    `json:"version"`
+	EventKind EventKind `json:"event_kind"`
 }
 
 // WebhookManager describes the methods a WebhookManager implementation must
@@ -22,9 +85,11 @@ type Webhook struct {
 type WebhookManager interface {
 	Add(ctx context.Context, orgName string, wh *Webhook) error
 	Delete(ctx context.Context, webhookID string) error
+	GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error)
 	GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 	GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
 	GetSubscribedTo(ctx context.Context, e *Event) ([]*Webhook, error)
+	Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error
 	Update(ctx context.Context, wh *Webhook) error
 }
@@ -0,0 +1,445 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// maxChannelMessageDetails represents the maximum number of details items
+	// (changes, vulnerabilities, etc) included in channels messages.
+	maxChannelMessageDetails = 10
+
+	// channelMessageUsername represents the name used to post the messages in
+	// the channels that support it.
+	channelMessageUsername = "Artifact Hub"
+)
+
+// Colors used to highlight channels messages depending on the event kind.
+const (
+	newReleaseColor     = 0x417598
+	securityAlertColor  = 0xDF2A19
+	trackingErrorsColor = 0xF7860F
+	pkgChangeColor      = 0x6C757D
+	repoChangeColor     = 0x659DBD
+)
+
+// errUnsupportedEventKind indicates that the channel payload builders do not
+// support the event kind provided.
+var errUnsupportedEventKind = errors.New("unsupported event kind")
+
+// channelMessage represents a platform agnostic message built from the
+// notification template data that channels payload builders render using
+// each platform's format.
+type channelMessage struct {
+	title        string
+	text         string
+	url          string
+	linkText     string
+	detailsTitle string
+	details      []string
+	color        int
+}
+
+// BuildWebhookPayload builds the payload that will be delivered to the webhook
+// provided for an event of the given kind. When the webhook has a custom
+// template, it is used to render the template data provided. Otherwise the
+// built-in payload builder of the webhook's channel is used.
+func BuildWebhookPayload(
+	wh *hub.Webhook,
+	eventKind hub.EventKind,
+	tmplData interface{},
+) ([]byte, error) {
+	// Custom template
+	if wh.Template != "" {
+		tmpl, err := ParseWebhookTemplate(wh.Template)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template: %w", err)
+		}
+		return executeTemplate(tmpl, tmplData)
+	}
+
+	// Built-in payload builders
+	if wh.Channel == hub.GenericWebhookChannel {
+		switch eventKind {
+		case hub.NewRelease:
+			return executeTemplate(DefaultWebhookPayloadTmpl, tmplData)
+		case hub.SecurityAlert:
+			return executeTemplate(DefaultSecurityAlertWebhookPayloadTmpl, tmplData)
+		case hub.RepositoryTrackingErrors:
+			return executeTemplate(DefaultTrackingErrorsWebhookPayloadTmpl, tmplData)
+		case hub.PackageVersionRemoved, hub.PackageDeprecated:
+			return executeTemplate(DefaultPackageChangeWebhookPayloadTmpl, tmplData)
+		case hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+			return executeTemplate(DefaultRepositoryChangeWebhookPayloadTmpl, tmplData)
+		default:
+			return nil, errUnsupportedEventKind
+		}
+	}
+	m, err := newChannelMessage(eventKind, tmplData)
+	if err != nil {
+		return nil, err
+	}
+	switch wh.Channel {
+	case hub.SlackWebhookChannel:
+		return json.Marshal(newSlackPayload(m))
+	case hub.MicrosoftTeamsWebhookChannel:
+		return json.Marshal(newTeamsPayload(m))
+	case hub.DiscordWebhookChannel:
+		return json.Marshal(newDiscordPayload(m))
+	default:
+		return nil, fmt.Errorf("unsupported webhook channel: %d", wh.Channel)
+	}
+}
+
+// executeTemplate executes the template provided using the data given.
+func executeTemplate(tmpl *template.Template, tmplData interface{}) ([]byte, error) {
+	var payload bytes.Buffer
+	if err := tmpl.Execute(&payload, tmplData); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
+	}
+	return payload.Bytes(), nil
+}
+
+// newChannelMessage creates a new channel message for the event kind and
+// template data provided.
+func newChannelMessage(eventKind hub.EventKind, tmplData interface{}) (*channelMessage, error) {
+	switch d := tmplData.(type) {
+	case *hub.PackageNotificationTemplateData:
+		name, version := toString(d.Package["name"]), toString(d.Package["version"])
+		repo, _ := d.Package["repository"].(map[string]interface{})
+		publisher := toString(repo["publisher"])
+		switch eventKind {
+		case hub.NewRelease:
+			text := fmt.Sprintf("Version %s of %s has been released by %s.", version, name, publisher)
+			if containsSecurityUpdates, _ := d.Package["containsSecurityUpdates"].(bool); containsSecurityUpdates {
+				text += " This version contains security updates."
+			}
+			if prerelease, _ := d.Package["prerelease"].(bool); prerelease {
+				text += " This version is a pre-release."
+			}
+			return &channelMessage{
+				title:        fmt.Sprintf("%s %s released", name, version),
+				text:         text,
+				url:          toString(d.Package["url"]),
+				linkText:     "View package",
+				detailsTitle: "Changes",
+				details:      toStrings(d.Package["changes"]),
+				color:        newReleaseColor,
+			}, nil
+		case hub.SecurityAlert:
+			return &channelMessage{
+				title: fmt.Sprintf("%s %s security alert", name, version),
+				text: fmt.Sprintf(
+					"New vulnerabilities have been detected in version %s of %s published by %s.",
+					version, name, publisher,
+				),
+				url:          toString(d.Package["securityReportURL"]),
+				linkText:     "View security report",
+				detailsTitle: "Vulnerabilities",
+				details:      toStrings(d.Package["vulnerabilities"]),
+				color:        securityAlertColor,
+			}, nil
+		case hub.PackageVersionRemoved:
+			return &channelMessage{
+				title:    fmt.Sprintf("%s %s removed", name, version),
+				text:     fmt.Sprintf("Version %s of %s published by %s has been removed.", version, name, publisher),
+				url:      toString(d.Package["url"]),
+				linkText: "View package",
+				color:    pkgChangeColor,
+			}, nil
+		case hub.PackageDeprecated:
+			return &channelMessage{
+				title:    fmt.Sprintf("%s deprecated", name),
+				text:     fmt.Sprintf("%s published by %s has been deprecated in version %s.", name, publisher, version),
+				url:      toString(d.Package["url"]),
+				linkText: "View package",
+				color:    pkgChangeColor,
+			}, nil
+		}
+	case *hub.RepositoryNotificationTemplateData:
+		name := toString(d.Repository["name"])
+		switch eventKind {
+		case hub.RepositoryTransferred:
+			return &channelMessage{
+				title: fmt.Sprintf("Repository %s transferred", name),
+				text: fmt.Sprintf("The %s repository %s has been transferred to %s.",
+					toString(d.Repository["kind"]), name, getRepositoryOwner(d.Repository),
+				),
+				url:      fmt.Sprintf("%s/packages/search?repo=%s", d.BaseURL, name),
+				linkText: "View repository",
+				color:    repoChangeColor,
+			}, nil
+		case hub.RepositoryVerifiedPublisherChanged:
+			text := fmt.Sprintf("The publisher of the %s repository %s is no longer verified.", toString(d.Repository["kind"]), name)
+			if verified, _ := d.Repository["verifiedPublisher"].(bool); verified {
+				text = fmt.Sprintf("The publisher of the %s repository %s is now verified.", toString(d.Repository["kind"]), name)
+			}
+			return &channelMessage{
+				title:    fmt.Sprintf("Repository %s verified publisher status changed", name),
+				text:     text,
+				url:      fmt.Sprintf("%s/packages/search?repo=%s", d.BaseURL, name),
+				linkText: "View repository",
+				color:    repoChangeColor,
+			}, nil
+		case hub.RepositoryTrackingErrors:
+			return &channelMessage{
+				title: fmt.Sprintf("Something went wrong tracking repository %s", name),
+				text:  fmt.Sprintf("Some errors occurred while tracking the %s repository %s.", toString(d.Repository["kind"]), name),
+				url: fmt.Sprintf("%s/control-panel/repositories?user-alias=%s&org-name=%s&repo-name=%s",
+					d.BaseURL,
+					toString(d.Repository["userAlias"]),
+					toString(d.Repository["organizationName"]),
+					name,
+				),
+				linkText:     "View repository",
+				detailsTitle: "Errors",
+				details:      toStrings(d.Repository["lastTrackingErrors"]),
+				color:        trackingErrorsColor,
+			}, nil
+		}
+	}
+	return nil, errUnsupportedEventKind
+}
+
+// limitedDetails returns the message details, limited to the maximum number
+// of details items allowed in channels messages.
+func (m *channelMessage) limitedDetails() []string {
+	if len(m.details) <= maxChannelMessageDetails {
+		return m.details
+	}
+	details := make([]string, 0, maxChannelMessageDetails+1)
+	details = append(details, m.details[:maxChannelMessageDetails]...)
+	details = append(details, fmt.Sprintf("and %d more", len(m.details)-maxChannelMessageDetails))
+	return details
+}
+
+// slackPayload represents the payload of a Slack incoming webhook message.
+type slackPayload struct {
+	Text   string        `json:"text"`
+	Blocks []*slackBlock `json:"blocks"`
+}
+
+// slackBlock represents a layout block of a Slack message.
+type slackBlock struct {
+	Type     string          `json:"type"`
+	Text     *slackText      `json:"text,omitempty"`
+	Elements []*slackElement `json:"elements,omitempty"`
+}
+
+// slackText represents a text object of a Slack message.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackElement represents an interactive element of a Slack message.
+type slackElement struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text"`
+	URL  string     `json:"url"`
+}
+
+// newSlackPayload creates a new Slack payload from the message provided.
+func newSlackPayload(m *channelMessage) *slackPayload {
+	p := &slackPayload{
+		Text: m.title,
+		Blocks: []*slackBlock{
+			{
+				Type: "section",
+				Text: &slackText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*<%s|%s>*\n%s", m.url, slackEscape(m.title), slackEscape(m.text)),
+				},
+			},
+		},
+	}
+	if details := m.limitedDetails(); len(details) > 0 {
+		var text strings.Builder
+		fmt.Fprintf(&text, "*%s*", m.detailsTitle)
+		for _, item := range details {
+			fmt.Fprintf(&text, "\n• %s", slackEscape(item))
+		}
+		p.Blocks = append(p.Blocks, &slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: text.String()},
+		})
+	}
+	p.Blocks = append(p.Blocks, &slackBlock{
+		Type: "actions",
+		Elements: []*slackElement{
+			{
+				Type: "button",
+				Text: &slackText{Type: "plain_text", Text: m.linkText},
+				URL:  m.url,
+			},
+		},
+	})
+	return p
+}
+
+// slackEscape escapes the control characters of the text provided as required
+// by Slack's mrkdwn format.
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// teamsPayload represents the payload of a Microsoft Teams incoming webhook
+// message (message card).
+type teamsPayload struct {
+	Type            string          `json:"@type"`
+	Context         string          `json:"@context"`
+	ThemeColor      string          `json:"themeColor"`
+	Summary         string          `json:"summary"`
+	Title           string          `json:"title"`
+	Text            string          `json:"text"`
+	Sections        []*teamsSection `json:"sections,omitempty"`
+	PotentialAction []*teamsAction  `json:"potentialAction"`
+}
+
+// teamsSection represents a section of a Microsoft Teams message card.
+type teamsSection struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// teamsAction represents an action of a Microsoft Teams message card.
+type teamsAction struct {
+	Type    string         `json:"@type"`
+	Name    string         `json:"name"`
+	Targets []*teamsTarget `json:"targets"`
+}
+
+// teamsTarget represents the target of a Microsoft Teams message card action.
+type teamsTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// newTeamsPayload creates a new Microsoft Teams payload from the message
+// provided.
+func newTeamsPayload(m *channelMessage) *teamsPayload {
+	p := &teamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%06X", m.color),
+		Summary:    m.title,
+		Title:      m.title,
+		Text:       m.text,
+		PotentialAction: []*teamsAction{
+			{
+				Type:    "OpenUri",
+				Name:    m.linkText,
+				Targets: []*teamsTarget{{OS: "default", URI: m.url}},
+			},
+		},
+	}
+	if details := m.limitedDetails(); len(details) > 0 {
+		items := make([]string, 0, len(details))
+		for _, item := range details {
+			items = append(items, "- "+item)
+		}
+		p.Sections = []*teamsSection{
+			{
+				Title: m.detailsTitle,
+				Text:  strings.Join(items, "\n\n"),
+			},
+		}
+	}
+	return p
+}
+
+// discordPayload represents the payload of a Discord webhook message.
+type discordPayload struct {
+	Username string          `json:"username"`
+	Embeds   []*discordEmbed `json:"embeds"`
+}
+
+// discordEmbed represents an embed of a Discord message.
+type discordEmbed struct {
+	Title       string          `json:"title"`
+	URL         string          `json:"url"`
+	Description string          `json:"description"`
+	Color       int             `json:"color"`
+	Fields      []*discordField `json:"fields,omitempty"`
+}
+
+// discordField represents a field of a Discord message embed.
+type discordField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Discord embeds limits.
+const (
+	discordMaxTitleLen      = 256
+	discordMaxFieldValueLen = 1024
+)
+
+// newDiscordPayload creates a new Discord payload from the message provided.
+func newDiscordPayload(m *channelMessage) *discordPayload {
+	embed := &discordEmbed{
+		Title:       truncate(m.title, discordMaxTitleLen),
+		URL:         m.url,
+		Description: m.text,
+		Color:       m.color,
+	}
+	if details := m.limitedDetails(); len(details) > 0 {
+		items := make([]string, 0, len(details))
+		for _, item := range details {
+			items = append(items, "• "+item)
+		}
+		embed.Fields = []*discordField{
+			{
+				Name:  m.detailsTitle,
+				Value: truncate(strings.Join(items, "\n"), discordMaxFieldValueLen),
+			},
+		}
+	}
+	return &discordPayload{
+		Username: channelMessageUsername,
+		Embeds:   []*discordEmbed{embed},
+	}
+}
+
+// truncate truncates the text provided so that it does not exceed the
+// maximum length (in characters) given.
+func truncate(text string, maxLen int) string {
+	r := []rune(text)
+	if len(r) <= maxLen {
+		return text
+	}
+	return string(r[:maxLen-1]) + "…"
+}
+
+// toString returns the string value of the template data value provided.
+func toString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// toStrings returns the list of strings of the template data value provided.
+func toStrings(v interface{}) []string {
+	var items []string
+	switch t := v.(type) {
+	case []string:
+		for _, item := range t {
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "" {
+				items = append(items, s)
+			}
+		}
+	}
+	return items
+}
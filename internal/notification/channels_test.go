@@ -0,0 +1,398 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWebhookPayload(t *testing.T) {
+	pkgTmplData := &hub.PackageNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "package.new-release",
+		},
+		Package: map[string]interface{}{
+			"name":                    "pkg1",
+			"version":                 "1.0.0",
+			"url":                     "http://baseURL/packages/helm/repo1/pkg1/1.0.0",
+			"changes":                 []string{"Cool feature", "Fix <bug> & more"},
+			"containsSecurityUpdates": true,
+			"prerelease":              false,
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	}
+	securityAlertTmplData := &hub.PackageNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "package.security-alert",
+		},
+		Package: map[string]interface{}{
+			"name":              "pkg1",
+			"version":           "1.0.0",
+			"url":               "http://baseURL/packages/helm/repo1/pkg1/1.0.0",
+			"securityReportURL": "http://baseURL/packages/helm/repo1/pkg1/1.0.0?modal=security-report",
+			"vulnerabilities":   []interface{}{"CVE-2020-0001", "CVE-2020-0002"},
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	}
+	repoTmplData := &hub.RepositoryNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "repository.tracking-errors",
+		},
+		Repository: map[string]interface{}{
+			"kind":               "helm",
+			"name":               "repo1",
+			"userAlias":          "user1",
+			"organizationName":   "",
+			"lastTrackingErrors": []string{`error "1"`, "error 2", ""},
+		},
+	}
+	versionRemovedTmplData := &hub.PackageNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "package.version-removed",
+		},
+		Package: map[string]interface{}{
+			"name":    "pkg1",
+			"version": "1.0.0",
+			"url":     "http://baseURL/packages/helm/repo1/pkg1",
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	}
+	repoTransferredTmplData := &hub.RepositoryNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "repository.transferred",
+		},
+		Repository: map[string]interface{}{
+			"kind":             "helm",
+			"name":             "repo1",
+			"userAlias":        "",
+			"organizationName": "org2",
+			"previousOwner": map[string]interface{}{
+				"userAlias":        "user1",
+				"organizationName": nil,
+			},
+		},
+	}
+	verifiedPublisherTmplData := &hub.RepositoryNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "repository.verified-publisher-changed",
+		},
+		Repository: map[string]interface{}{
+			"kind":              "helm",
+			"name":              "repo1",
+			"userAlias":         "user1",
+			"organizationName":  "",
+			"verifiedPublisher": true,
+		},
+	}
+
+	t.Run("custom template", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{
+			Channel:  hub.SlackWebhookChannel,
+			Template: "Package {{ .Package.name }} {{ .Package.version }} updated!",
+		}
+		payload, err := BuildWebhookPayload(wh, hub.NewRelease, pkgTmplData)
+		require.NoError(t, err)
+		assert.Equal(t, "Package pkg1 1.0.0 updated!", string(payload))
+	})
+
+	t.Run("custom template parsing error", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Template: "{{ .."}
+		_, err := BuildWebhookPayload(wh, hub.NewRelease, pkgTmplData)
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "error parsing template"))
+	})
+
+	t.Run("custom template execution error", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Template: "{{ .nonExistent }}"}
+		_, err := BuildWebhookPayload(wh, hub.NewRelease, pkgTmplData)
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "error executing template"))
+	})
+
+	t.Run("unsupported event kind", func(t *testing.T) {
+		testCases := []hub.WebhookChannel{
+			hub.GenericWebhookChannel,
+			hub.SlackWebhookChannel,
+			hub.MicrosoftTeamsWebhookChannel,
+			hub.DiscordWebhookChannel,
+		}
+		for _, channel := range testCases {
+			channel := channel
+			t.Run(fmt.Sprintf("channel %d", channel), func(t *testing.T) {
+				t.Parallel()
+				wh := &hub.Webhook{Channel: channel}
+				_, err := BuildWebhookPayload(wh, hub.RepositoryOwnershipClaim, repoTmplData)
+				assert.Equal(t, errUnsupportedEventKind, err)
+			})
+		}
+	})
+
+	t.Run("unsupported channel", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Channel: hub.WebhookChannel(100)}
+		_, err := BuildWebhookPayload(wh, hub.NewRelease, pkgTmplData)
+		assert.Error(t, err)
+	})
+
+	t.Run("generic channel tracking errors", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{}
+		payload, err := BuildWebhookPayload(wh, hub.RepositoryTrackingErrors, repoTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"specversion": "1.0",
+			"id": "eventID",
+			"source": "https://artifacthub.io/cloudevents",
+			"type": "io.artifacthub.repository.tracking-errors",
+			"datacontenttype": "application/json",
+			"data": {
+				"repository": {
+					"kind": "helm",
+					"name": "repo1",
+					"lastTrackingErrors": ["error \"1\"", "error 2", ""]
+				}
+			}
+		}`, string(payload))
+	})
+
+	t.Run("generic channel package version removed", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{}
+		payload, err := BuildWebhookPayload(wh, hub.PackageVersionRemoved, versionRemovedTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"specversion": "1.0",
+			"id": "eventID",
+			"source": "https://artifacthub.io/cloudevents",
+			"type": "io.artifacthub.package.version-removed",
+			"datacontenttype": "application/json",
+			"data": {
+				"package": {
+					"name": "pkg1",
+					"version": "1.0.0",
+					"url": "http://baseURL/packages/helm/repo1/pkg1",
+					"repository": {
+						"kind": "helm",
+						"name": "repo1",
+						"publisher": "org1"
+					}
+				}
+			}
+		}`, string(payload))
+	})
+
+	t.Run("generic channel repository transferred", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{}
+		payload, err := BuildWebhookPayload(wh, hub.RepositoryTransferred, repoTransferredTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"specversion": "1.0",
+			"id": "eventID",
+			"source": "https://artifacthub.io/cloudevents",
+			"type": "io.artifacthub.repository.transferred",
+			"datacontenttype": "application/json",
+			"data": {
+				"repository": {
+					"kind": "helm",
+					"name": "repo1",
+					"userAlias": "",
+					"organizationName": "org2",
+					"previousOwner": {"userAlias": "user1", "organizationName": null}
+				}
+			}
+		}`, string(payload))
+	})
+
+	t.Run("generic channel verified publisher changed", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{}
+		payload, err := BuildWebhookPayload(wh, hub.RepositoryVerifiedPublisherChanged, verifiedPublisherTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"specversion": "1.0",
+			"id": "eventID",
+			"source": "https://artifacthub.io/cloudevents",
+			"type": "io.artifacthub.repository.verified-publisher-changed",
+			"datacontenttype": "application/json",
+			"data": {
+				"repository": {
+					"kind": "helm",
+					"name": "repo1",
+					"userAlias": "user1",
+					"organizationName": "",
+					"verifiedPublisher": true
+				}
+			}
+		}`, string(payload))
+	})
+
+	t.Run("slack new release", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Channel: hub.SlackWebhookChannel}
+		payload, err := BuildWebhookPayload(wh, hub.NewRelease, pkgTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"text": "pkg1 1.0.0 released",
+			"blocks": [
+				{
+					"type": "section",
+					"text": {
+						"type": "mrkdwn",
+						"text": "*<http://baseURL/packages/helm/repo1/pkg1/1.0.0|pkg1 1.0.0 released>*\nVersion 1.0.0 of pkg1 has been released by org1. This version contains security updates."
+					}
+				},
+				{
+					"type": "section",
+					"text": {
+						"type": "mrkdwn",
+						"text": "*Changes*\n• Cool feature\n• Fix &lt;bug&gt; &amp; more"
+					}
+				},
+				{
+					"type": "actions",
+					"elements": [
+						{
+							"type": "button",
+							"text": {"type": "plain_text", "text": "View package"},
+							"url": "http://baseURL/packages/helm/repo1/pkg1/1.0.0"
+						}
+					]
+				}
+			]
+		}`, string(payload))
+	})
+
+	t.Run("microsoft teams security alert", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Channel: hub.MicrosoftTeamsWebhookChannel}
+		payload, err := BuildWebhookPayload(wh, hub.SecurityAlert, securityAlertTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"@type": "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"themeColor": "DF2A19",
+			"summary": "pkg1 1.0.0 security alert",
+			"title": "pkg1 1.0.0 security alert",
+			"text": "New vulnerabilities have been detected in version 1.0.0 of pkg1 published by org1.",
+			"sections": [
+				{
+					"title": "Vulnerabilities",
+					"text": "- CVE-2020-0001\n\n- CVE-2020-0002"
+				}
+			],
+			"potentialAction": [
+				{
+					"@type": "OpenUri",
+					"name": "View security report",
+					"targets": [
+						{"os": "default", "uri": "http://baseURL/packages/helm/repo1/pkg1/1.0.0?modal=security-report"}
+					]
+				}
+			]
+		}`, string(payload))
+	})
+
+	t.Run("discord tracking errors", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Channel: hub.DiscordWebhookChannel}
+		payload, err := BuildWebhookPayload(wh, hub.RepositoryTrackingErrors, repoTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"username": "Artifact Hub",
+			"embeds": [
+				{
+					"title": "Something went wrong tracking repository repo1",
+					"url": "http://baseURL/control-panel/repositories?user-alias=user1&org-name=&repo-name=repo1",
+					"description": "Some errors occurred while tracking the helm repository repo1.",
+					"color": 16221711,
+					"fields": [
+						{
+							"name": "Errors",
+							"value": "• error \"1\"\n• error 2"
+						}
+					]
+				}
+			]
+		}`, string(payload))
+	})
+
+	t.Run("discord repository transferred", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{Channel: hub.DiscordWebhookChannel}
+		payload, err := BuildWebhookPayload(wh, hub.RepositoryTransferred, repoTransferredTmplData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"username": "Artifact Hub",
+			"embeds": [
+				{
+					"title": "Repository repo1 transferred",
+					"url": "http://baseURL/packages/search?repo=repo1",
+					"description": "The helm repository repo1 has been transferred to organization org2.",
+					"color": 6659517
+				}
+			]
+		}`, string(payload))
+	})
+
+	t.Run("details are limited", func(t *testing.T) {
+		t.Parallel()
+		var changes []string
+		for i := 0; i < 15; i++ {
+			changes = append(changes, fmt.Sprintf("change %d", i))
+		}
+		tmplData := &hub.PackageNotificationTemplateData{
+			Package: map[string]interface{}{
+				"name":    "pkg1",
+				"version": "1.0.0",
+				"changes": changes,
+			},
+		}
+		wh := &hub.Webhook{Channel: hub.DiscordWebhookChannel}
+		payload, err := BuildWebhookPayload(wh, hub.NewRelease, tmplData)
+		require.NoError(t, err)
+		var p *discordPayload
+		require.NoError(t, json.Unmarshal(payload, &p))
+		items := strings.Split(p.Embeds[0].Fields[0].Value, "\n")
+		assert.Len(t, items, maxChannelMessageDetails+1)
+		assert.Equal(t, "• and 5 more", items[maxChannelMessageDetails])
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "ab…", truncate("abcd", 3))
+	assert.Equal(t, "ñá…", truncate("ñáéí", 3))
+}
@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
@@ -15,6 +18,18 @@ const (
 	defaultNumWorkers      = 2
 	cacheDefaultExpiration = 5 * time.Minute
 	cacheCleanupInterval   = 10 * time.Minute
+
+	// digestsCheckInterval represents how often the dispatcher checks if
+	// there are notifications digests due to be delivered.
+	digestsCheckInterval = 15 * time.Minute
+
+	// webhookDeliveriesCleanupInterval represents how often the dispatcher
+	// deletes the webhook deliveries older than the retention period.
+	webhookDeliveriesCleanupInterval = 1 * time.Hour
+
+	// webhookDeliveriesRetention represents for how long the webhook
+	// deliveries are kept.
+	webhookDeliveriesRetention = 30 * 24 * time.Hour
 )
 
 // Services is a wrapper around several internal services used to handle
@@ -29,15 +44,20 @@ type Services struct {
 }
 
 // Dispatcher handles a group of workers in charge of delivering notifications.
+// It also schedules the delivery of the notifications digests, which is
+// handled by a dedicated worker, and the cleanup of old webhook deliveries.
 type Dispatcher struct {
-	numWorkers int
-	workers    []*Worker
+	svc           *Services
+	numWorkers    int
+	workers       []*Worker
+	digestsWorker *Worker
 }
 
 // NewDispatcher creates a new Dispatcher instance.
 func NewDispatcher(cfg *viper.Viper, svc *Services, opts ...func(d *Dispatcher)) *Dispatcher {
 	// Setup dispatcher
 	d := &Dispatcher{
+		svc:        svc,
 		numWorkers: defaultNumWorkers,
 	}
 	for _, o := range opts {
@@ -47,11 +67,13 @@ func NewDispatcher(cfg *viper.Viper, svc *Services, opts ...func(d *Dispatcher))
 	// Setup and launch workers
 	c := cache.New(cacheDefaultExpiration, cacheCleanupInterval)
 	baseURL := cfg.GetString("server.baseURL")
+	unsubscribeKey := []byte(cfg.GetString("email.unsubscribeKey"))
 	httpClient := &http.Client{Timeout: 10 * time.Second}
 	d.workers = make([]*Worker, 0, d.numWorkers)
 	for i := 0; i < d.numWorkers; i++ {
-		d.workers = append(d.workers, NewWorker(svc, c, baseURL, httpClient))
+		d.workers = append(d.workers, NewWorker(svc, c, baseURL, unsubscribeKey, httpClient))
 	}
+	d.digestsWorker = NewWorker(svc, c, baseURL, unsubscribeKey, httpClient)
 
 	return d
 }
@@ -64,8 +86,8 @@ func WithNumWorkers(n int) func(d *Dispatcher) {
 	}
 }
 
-// Run starts the workers and lets them run until the dispatcher is asked to
-// stop via the context provided.
+// Run starts the workers and the digests scheduler and lets them run until the
+// dispatcher is asked to stop via the context provided.
 func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -76,9 +98,59 @@ func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
 		wwg.Add(1)
 		go w.Run(wctx, wwg)
 	}
+	wwg.Add(1)
+	go d.runDigestsScheduler(wctx, wwg)
+	wwg.Add(1)
+	go d.runWebhookDeliveriesCleaner(wctx, wwg)
 
 	// Stop workers when dispatcher is asked to stop
 	<-ctx.Done()
 	stopWorkers()
 	wwg.Wait()
 }
+
+// runDigestsScheduler checks periodically if there are notifications digests
+// due and delivers them until it's asked to stop via the context provided.
+func (d *Dispatcher) runDigestsScheduler(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(digestsCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.digestsWorker.processDigests(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runWebhookDeliveriesCleaner deletes periodically the webhook deliveries
+// older than the retention period until it's asked to stop via the context
+// provided.
+func (d *Dispatcher) runWebhookDeliveriesCleaner(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(webhookDeliveriesCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.cleanWebhookDeliveries(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanWebhookDeliveries deletes the webhook deliveries older than the
+// retention period.
+func (d *Dispatcher) cleanWebhookDeliveries(ctx context.Context) {
+	err := util.DBTransact(ctx, d.svc.DB, func(tx pgx.Tx) error {
+		return d.svc.NotificationManager.DeleteOldWebhookDeliveries(ctx, tx, webhookDeliveriesRetention)
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("error deleting old webhook deliveries")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/artifacthub/hub/internal/tests"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +32,31 @@ func TestDispatcher(t *testing.T) {
 		return true
 	}, 2*time.Second, 100*time.Millisecond)
 }
+
+func TestDispatcherCleanWebhookDeliveries(t *testing.T) {
+	t.Run("error deleting old webhook deliveries", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("DeleteOldWebhookDeliveries", sw.ctx, sw.tx, webhookDeliveriesRetention).Return(tests.ErrFakeDB)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		d := NewDispatcher(viper.New(), sw.svc, WithNumWorkers(0))
+		d.cleanWebhookDeliveries(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("old webhook deliveries deleted successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("DeleteOldWebhookDeliveries", sw.ctx, sw.tx, webhookDeliveriesRetention).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		d := NewDispatcher(viper.New(), sw.svc, WithNumWorkers(0))
+		d.cleanWebhookDeliveries(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+}
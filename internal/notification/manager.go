@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/jackc/pgx/v4"
@@ -12,9 +13,15 @@ import (
 
 const (
 	// Database queries
-	addNotificationDBQ          = `select add_notification($1::jsonb)`
-	getPendingNotificationDBQ   = `select get_pending_notification()`
-	updateNotificationStatusDBQ = `select update_notification_status($1::uuid, $2::boolean, $3::text)`
+	addNotificationDBQ            = `select add_notification($1::jsonb)`
+	addWebhookDeliveryDBQ         = `select add_webhook_delivery($1::jsonb)`
+	deleteOldWebhookDeliveriesDBQ = `select delete_old_webhook_deliveries($1::integer)`
+	getPendingDigestDBQ           = `select get_pending_digest()`
+	getPendingNotificationDBQ     = `select get_pending_notification()`
+	markNotificationAsDeadDBQ     = `select mark_notification_as_dead($1::uuid, $2::text)`
+	scheduleNotificationRetryDBQ  = `select schedule_notification_retry($1::uuid, $2::integer, $3::text)`
+	updateLastDigestSentAtDBQ     = `select update_last_digest_sent_at($1::uuid)`
+	updateNotificationStatusDBQ   = `select update_notification_status($1::uuid, $2::boolean, $3::text)`
 )
 
 // Manager provides an API to manage notifications.
@@ -51,6 +58,24 @@ func (m *Manager) Add(ctx context.Context, tx pgx.Tx, n *hub.Notification) error
 	return err
 }
 
+// AddWebhookDelivery registers the provided webhook delivery attempt in the
+// database.
+func (m *Manager) AddWebhookDelivery(ctx context.Context, tx pgx.Tx, d *hub.WebhookDelivery) error {
+	if _, err := uuid.FromString(d.NotificationID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid notification id")
+	}
+	dJSON, _ := json.Marshal(d)
+	_, err := tx.Exec(ctx, addWebhookDeliveryDBQ, dJSON)
+	return err
+}
+
+// DeleteOldWebhookDeliveries deletes the webhook deliveries older than the
+// retention period provided.
+func (m *Manager) DeleteOldWebhookDeliveries(ctx context.Context, tx pgx.Tx, retention time.Duration) error {
+	_, err := tx.Exec(ctx, deleteOldWebhookDeliveriesDBQ, int(retention.Seconds()))
+	return err
+}
+
 // GetPending returns a pending notification to be delivered if available.
 func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification, error) {
 	var dataJSON []byte
@@ -64,6 +89,69 @@ func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification,
 	return n, nil
 }
 
+// GetPendingDigest returns the notifications pending to be delivered to a user
+// grouped in a digest, if any user's digest is due.
+func (m *Manager) GetPendingDigest(ctx context.Context, tx pgx.Tx) (*hub.NotificationsDigest, error) {
+	var dataJSON []byte
+	if err := tx.QueryRow(ctx, getPendingDigestDBQ).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var d *hub.NotificationsDigest
+	if err := json.Unmarshal(dataJSON, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MarkAsDead registers the last failed delivery attempt of the provided
+// notification, marking it as dead so that it is not retried again.
+func (m *Manager) MarkAsDead(
+	ctx context.Context,
+	tx pgx.Tx,
+	notificationID string,
+	deliveryErr error,
+) error {
+	if _, err := uuid.FromString(notificationID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid notification id")
+	}
+	var deliveryErrStr string
+	if deliveryErr != nil {
+		deliveryErrStr = deliveryErr.Error()
+	}
+	_, err := tx.Exec(ctx, markNotificationAsDeadDBQ, notificationID, deliveryErrStr)
+	return err
+}
+
+// ScheduleRetry registers a failed delivery attempt of the provided
+// notification, scheduling the next attempt after the delay provided.
+func (m *Manager) ScheduleRetry(
+	ctx context.Context,
+	tx pgx.Tx,
+	notificationID string,
+	delay time.Duration,
+	deliveryErr error,
+) error {
+	if _, err := uuid.FromString(notificationID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid notification id")
+	}
+	var deliveryErrStr string
+	if deliveryErr != nil {
+		deliveryErrStr = deliveryErr.Error()
+	}
+	_, err := tx.Exec(ctx, scheduleNotificationRetryDBQ, notificationID, int(delay.Seconds()), deliveryErrStr)
+	return err
+}
+
+// UpdateLastDigestSentAt records that a notifications digest has just been
+// sent successfully to the provided user.
+func (m *Manager) UpdateLastDigestSentAt(ctx context.Context, tx pgx.Tx, userID string) error {
+	if _, err := uuid.FromString(userID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid user id")
+	}
+	_, err := tx.Exec(ctx, updateLastDigestSentAtDBQ, userID)
+	return err
+}
+
 // UpdateStatus the provided notification status in the database.
 func (m *Manager) UpdateStatus(
 	ctx context.Context,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
@@ -104,6 +105,74 @@ func TestAdd(t *testing.T) {
 	})
 }
 
+func TestAddWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+	d := &hub.WebhookDelivery{
+		NotificationID: validUUID,
+		Attempt:        1,
+		RequestPayload: "payload",
+		ResponseStatus: 200,
+		Latency:        100,
+		Success:        true,
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		err := m.AddWebhookDelivery(ctx, nil, &hub.WebhookDelivery{NotificationID: "invalid"})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid notification id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, addWebhookDeliveryDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.AddWebhookDelivery(ctx, tx, d)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, addWebhookDeliveryDBQ, mock.Anything).Return(nil)
+		m := NewManager()
+
+		err := m.AddWebhookDelivery(ctx, tx, d)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestDeleteOldWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, deleteOldWebhookDeliveriesDBQ, 86400).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.DeleteOldWebhookDeliveries(ctx, tx, 24*time.Hour)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, deleteOldWebhookDeliveriesDBQ, 86400).Return(nil)
+		m := NewManager()
+
+		err := m.DeleteOldWebhookDeliveries(ctx, tx, 24*time.Hour)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
 func TestGetPending(t *testing.T) {
 	ctx := context.Background()
 
@@ -123,6 +192,7 @@ func TestGetPending(t *testing.T) {
 		t.Parallel()
 		expectedNotification := &hub.Notification{
 			NotificationID: "notificationID",
+			Attempts:       2,
 			Event: &hub.Event{
 				EventKind:      hub.NewRelease,
 				PackageID:      "packageID",
@@ -137,6 +207,7 @@ func TestGetPending(t *testing.T) {
 		tx.On("QueryRow", ctx, getPendingNotificationDBQ).Return([]byte(`
 		{
 			"notification_id": "notificationID",
+			"attempts": 2,
 			"event": {
 				"event_kind": 0,
 				"package_id": "packageID",
@@ -156,6 +227,178 @@ func TestGetPending(t *testing.T) {
 	})
 }
 
+func TestGetPendingDigest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, getPendingDigestDBQ).Return(nil, tests.ErrFakeDB)
+		m := NewManager()
+
+		d, err := m.GetPendingDigest(ctx, tx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, d)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		weeklyDigest := hub.WeeklyDigestEmailDelivery
+		expectedDigest := &hub.NotificationsDigest{
+			User: &hub.User{
+				UserID:                  "userID",
+				Email:                   "user1@email.com",
+				EmailDeliveryPreference: &weeklyDigest,
+			},
+			Notifications: []*hub.Notification{
+				{
+					NotificationID: "notificationID",
+					Attempts:       1,
+					Event: &hub.Event{
+						EventKind:      hub.NewRelease,
+						PackageID:      "packageID",
+						PackageVersion: "1.0.0",
+					},
+				},
+			},
+		}
+
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, getPendingDigestDBQ).Return([]byte(`
+		{
+			"user": {
+				"user_id": "userID",
+				"email": "user1@email.com",
+				"email_delivery_preference": 2
+			},
+			"notifications": [
+				{
+					"notification_id": "notificationID",
+					"attempts": 1,
+					"event": {
+						"event_kind": 0,
+						"package_id": "packageID",
+						"package_version": "1.0.0"
+					}
+				}
+			]
+		}
+		`), nil)
+		m := NewManager()
+
+		d, err := m.GetPendingDigest(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedDigest, d)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestMarkAsDead(t *testing.T) {
+	ctx := context.Background()
+	notificationID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		err := m.MarkAsDead(ctx, nil, "invalidNotificationID", nil)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid notification id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, markNotificationAsDeadDBQ, notificationID, tests.ErrFake.Error()).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.MarkAsDead(ctx, tx, notificationID, tests.ErrFake)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, markNotificationAsDeadDBQ, notificationID, tests.ErrFake.Error()).Return(nil)
+		m := NewManager()
+
+		err := m.MarkAsDead(ctx, tx, notificationID, tests.ErrFake)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestScheduleRetry(t *testing.T) {
+	ctx := context.Background()
+	notificationID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		err := m.ScheduleRetry(ctx, nil, "invalidNotificationID", time.Minute, nil)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid notification id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, scheduleNotificationRetryDBQ, notificationID, 120, tests.ErrFake.Error()).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.ScheduleRetry(ctx, tx, notificationID, 2*time.Minute, tests.ErrFake)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, scheduleNotificationRetryDBQ, notificationID, 120, tests.ErrFake.Error()).Return(nil)
+		m := NewManager()
+
+		err := m.ScheduleRetry(ctx, tx, notificationID, 2*time.Minute, tests.ErrFake)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestUpdateLastDigestSentAt(t *testing.T) {
+	ctx := context.Background()
+	userID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		err := m.UpdateLastDigestSentAt(ctx, nil, "invalidUserID")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid user id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, updateLastDigestSentAtDBQ, userID).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.UpdateLastDigestSentAt(ctx, tx, userID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, updateLastDigestSentAtDBQ, userID).Return(nil)
+		m := NewManager()
+
+		err := m.UpdateLastDigestSentAt(ctx, tx, userID)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
 func TestUpdateStatus(t *testing.T) {
 	ctx := context.Background()
 	notificationID := "00000000-0000-0000-0000-000000000001"
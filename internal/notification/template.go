@@ -0,0 +1,237 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// WebhookTemplateFuncs represents the set of helper functions available to
+// the webhooks payload templates.
+var WebhookTemplateFuncs = template.FuncMap{
+	"json":       tmplJSON,
+	"jsonEscape": tmplJSONEscape,
+	"date":       tmplDate,
+	"now":        time.Now,
+	"join":       tmplJoin,
+}
+
+// ParseWebhookTemplate parses the webhook payload template provided, making
+// the webhook template helper functions available to it.
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("").Funcs(WebhookTemplateFuncs).Parse(text)
+}
+
+// ValidateWebhookTemplate checks that the custom template of the webhook
+// provided, if any, can be parsed and executed using the sample template data
+// of each of the event kinds selected.
+func ValidateWebhookTemplate(wh *hub.Webhook) error {
+	if wh.Template == "" {
+		return nil
+	}
+	tmpl, err := ParseWebhookTemplate(wh.Template)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %w", err)
+	}
+	for _, eventKind := range wh.EventKinds {
+		tmplData, ok := WebhookSampleTemplateData[eventKind]
+		if !ok {
+			return fmt.Errorf("%w: %d", errUnsupportedEventKind, eventKind)
+		}
+		if _, err := executeTemplate(tmpl, tmplData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tmplJSON returns the json representation of the value provided.
+func tmplJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// tmplJSONEscape escapes the string provided so that it can be safely used
+// within a json string.
+func tmplJSONEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
+
+// tmplDate formats the date provided using the layout given. Dates can be
+// provided as time.Time values, unix timestamps or RFC3339 strings.
+func tmplDate(layout string, v interface{}) (string, error) {
+	var t time.Time
+	switch d := v.(type) {
+	case time.Time:
+		t = d
+	case int64:
+		t = time.Unix(d, 0)
+	case int:
+		t = time.Unix(int64(d), 0)
+	case float64:
+		t = time.Unix(int64(d), 0)
+	case string:
+		var err error
+		t, err = time.Parse(time.RFC3339, d)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported date type: %T", v)
+	}
+	return t.UTC().Format(layout), nil
+}
+
+// tmplJoin joins the elements of the list provided using the separator given.
+func tmplJoin(sep string, v interface{}) (string, error) {
+	switch l := v.(type) {
+	case []string:
+		return strings.Join(l, sep), nil
+	case []interface{}:
+		items := make([]string, 0, len(l))
+		for _, item := range l {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, sep), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported list type: %T", v)
+	}
+}
+
+// WebhookSampleTemplateData represents the sample notification template data
+// used to validate and test webhooks for each of the event kinds supported.
+var WebhookSampleTemplateData = map[hub.EventKind]interface{}{
+	hub.NewRelease: &hub.PackageNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "package.new-release",
+		},
+		Package: map[string]interface{}{
+			"name":    "sample-package",
+			"version": "1.0.0",
+			"url":     "https://artifacthub.io/packages/helm/artifacthub/sample-package/1.0.0",
+			"changes": []string{
+				"Cool feature",
+				"Bug fixed",
+			},
+			"containsSecurityUpdates": true,
+			"prerelease":              true,
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	},
+	hub.SecurityAlert: &hub.PackageNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "package.security-alert",
+		},
+		Package: map[string]interface{}{
+			"name":              "sample-package",
+			"version":           "1.0.0",
+			"url":               "https://artifacthub.io/packages/helm/artifacthub/sample-package/1.0.0",
+			"securityReportURL": "https://artifacthub.io/packages/helm/artifacthub/sample-package/1.0.0?modal=security-report",
+			"vulnerabilities": []string{
+				"CVE-2020-0001",
+				"CVE-2020-0002",
+			},
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	},
+	hub.RepositoryTrackingErrors: &hub.RepositoryNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "repository.tracking-errors",
+		},
+		Repository: map[string]interface{}{
+			"kind":             "helm",
+			"name":             "repo1",
+			"userAlias":        "",
+			"organizationName": "org1",
+			"lastTrackingErrors": []string{
+				"error processing package sample-package version 1.0.0: invalid metadata",
+			},
+		},
+	},
+	hub.PackageVersionRemoved: &hub.PackageNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "package.version-removed",
+		},
+		Package: map[string]interface{}{
+			"name":    "sample-package",
+			"version": "1.0.0",
+			"url":     "https://artifacthub.io/packages/helm/artifacthub/sample-package",
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	},
+	hub.PackageDeprecated: &hub.PackageNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "package.deprecated",
+		},
+		Package: map[string]interface{}{
+			"name":    "sample-package",
+			"version": "1.0.0",
+			"url":     "https://artifacthub.io/packages/helm/artifacthub/sample-package/1.0.0",
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	},
+	hub.RepositoryTransferred: &hub.RepositoryNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "repository.transferred",
+		},
+		Repository: map[string]interface{}{
+			"kind":             "helm",
+			"name":             "repo1",
+			"userAlias":        "",
+			"organizationName": "org1",
+			"previousOwner": map[string]interface{}{
+				"userAlias":        "user1",
+				"organizationName": nil,
+			},
+		},
+	},
+	hub.RepositoryVerifiedPublisherChanged: &hub.RepositoryNotificationTemplateData{
+		BaseURL: "https://artifacthub.io",
+		Event: map[string]interface{}{
+			"id":   "00000000-0000-0000-0000-000000000001",
+			"kind": "repository.verified-publisher-changed",
+		},
+		Repository: map[string]interface{}{
+			"kind":              "helm",
+			"name":              "repo1",
+			"userAlias":         "",
+			"organizationName":  "org1",
+			"verifiedPublisher": true,
+		},
+	},
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookTemplateFuncs(t *testing.T) {
+	t.Parallel()
+	tmplData := map[string]interface{}{
+		"name":    `sample "package"`,
+		"changes": []interface{}{"Feature 1", "Bug fix"},
+		"ts":      int64(1592299234),
+		"date":    "2020-06-16T11:20:34+02:00",
+		"time":    time.Date(2020, 6, 16, 9, 20, 34, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		tmpl            string
+		expectedPayload string
+	}{
+		{
+			`{{ json .changes }}`,
+			`["Feature 1","Bug fix"]`,
+		},
+		{
+			`"{{ jsonEscape .name }}"`,
+			`"sample \"package\""`,
+		},
+		{
+			`{{ date "2006-01-02" .ts }}`,
+			`2020-06-16`,
+		},
+		{
+			`{{ date "2006-01-02 15:04" .date }}`,
+			`2020-06-16 09:20`,
+		},
+		{
+			`{{ date "Jan 2, 2006" .time }}`,
+			`Jun 16, 2020`,
+		},
+		{
+			`{{ join ", " .changes }}`,
+			`Feature 1, Bug fix`,
+		},
+	}
+	for _, tc := range testCases {
+		tmpl, err := ParseWebhookTemplate(tc.tmpl)
+		require.NoError(t, err)
+		payload, err := executeTemplate(tmpl, tmplData)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedPayload, string(payload))
+	}
+
+	tmpl, err := ParseWebhookTemplate(`{{ date "2006" .name }}`)
+	require.NoError(t, err)
+	_, err = executeTemplate(tmpl, tmplData)
+	assert.Error(t, err)
+}
+
+func TestValidateWebhookTemplate(t *testing.T) {
+	testCases := []struct {
+		wh          *hub.Webhook
+		expectedErr string
+	}{
+		{
+			&hub.Webhook{
+				EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+			},
+			"",
+		},
+		{
+			&hub.Webhook{
+				Template:   `{{ json .Package.changes }}{{ if eq .Event.kind "package.security-alert" }}{{ join "," .Package.vulnerabilities }}{{ end }}`,
+				EventKinds: []hub.EventKind{hub.NewRelease, hub.SecurityAlert, hub.PackageDeprecated},
+			},
+			"",
+		},
+		{
+			&hub.Webhook{
+				Template:   `{{ .Repository.name }}`,
+				EventKinds: []hub.EventKind{hub.RepositoryTrackingErrors, hub.RepositoryTransferred},
+			},
+			"",
+		},
+		{
+			&hub.Webhook{
+				Template:   `{{ .`,
+				EventKinds: []hub.EventKind{hub.NewRelease},
+			},
+			"error parsing template",
+		},
+		{
+			&hub.Webhook{
+				Template:   `{{ .Package.name }}`,
+				EventKinds: []hub.EventKind{hub.NewRelease, hub.RepositoryVerifiedPublisherChanged},
+			},
+			"error executing template",
+		},
+		{
+			&hub.Webhook{
+				Template:   `{{ .Package.name }}`,
+				EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+			},
+			"unsupported event kind",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.wh.Template, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateWebhookTemplate(tc.wh)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"html/template"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// digestGroupsHeadings represents the heading used for the digest groups of
+// each of the event kinds.
+var digestGroupsHeadings = map[hub.EventKind]string{
+	hub.NewRelease:                         "New releases",
+	hub.SecurityAlert:                      "Security alerts",
+	hub.RepositoryTrackingErrors:           "Tracking errors",
+	hub.RepositoryOwnershipClaim:           "Ownership claims",
+	hub.PackageVersionRemoved:              "Removed versions",
+	hub.PackageDeprecated:                  "Deprecations",
+	hub.RepositoryTransferred:              "Transfers",
+	hub.RepositoryVerifiedPublisherChanged: "Verified publisher changes",
+}
+
+// digestTemplateData represents the data exposed to the digest email template.
+type digestTemplateData struct {
+	BaseURL string
+	Period  string
+	Groups  []*digestGroup
+}
+
+// digestGroup represents a group of digest entries about the same package or
+// repository and event kind.
+type digestGroup struct {
+	Title          string
+	Subtitle       string
+	Heading        string
+	Entries        []*digestEntry
+	UnsubscribeURL string
+}
+
+// digestEntry represents an entry of a digest group. Each entry corresponds to
+// one of the notifications included in the digest.
+type digestEntry struct {
+	Text    string
+	URL     string
+	Details interface{}
+}
+
+var digestEmailTmpl = template.Must(template.New("").Parse(`
+<!doctype html>
+<html>
+  <head>
+    <meta name="viewport" content="width=device-width">
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8">
+    <title>Artifact Hub {{ .Period }} digest</title>
+    <style>
+    @media only screen and (max-width: 620px) {
+      table[class=body] h1 {
+        font-size: 28px !important;
+        margin-bottom: 10px !important;
+      }
+      table[class=body] p,
+            table[class=body] ul,
+            table[class=body] ol,
+            table[class=body] td,
+            table[class=body] span,
+            table[class=body] a {
+        font-size: 16px !important;
+      }
+      table[class=body] .wrapper,
+      table[class=body] .article {
+        padding: 10px !important;
+      }
+      table[class=body] .content {
+        padding: 0 !important;
+      }
+      table[class=body] .container {
+        padding: 0 !important;
+        width: 100% !important;
+      }
+      table[class=body] .main {
+        border-left-width: 0 !important;
+        border-radius: 0 !important;
+        border-right-width: 0 !important;
+      }
+      table[class=body] .btn table {
+        width: 100% !important;
+      }
+      table[class=body] .btn a {
+        width: 100% !important;
+      }
+      table[class=body] .img-responsive {
+        height: auto !important;
+        max-width: 100% !important;
+        width: auto !important;
+      }
+    }
+
+    a[x-apple-data-detectors] {
+      color: inherit !important;
+      text-decoration: none !important;
+      font-size: inherit !important;
+      font-family: inherit !important;
+      font-weight: inherit !important;
+      line-height: inherit !important;
+    }
+
+    @media all {
+      .ExternalClass {
+        width: 100%;
+      }
+      .ExternalClass,
+            .ExternalClass p,
+            .ExternalClass span,
+            .ExternalClass font,
+            .ExternalClass td,
+            .ExternalClass div {
+        line-height: 100%;
+      }
+      .apple-link a {
+        color: inherit !important;
+        font-family: inherit !important;
+        font-size: inherit !important;
+        font-weight: inherit !important;
+        line-height: inherit !important;
+        text-decoration: none !important;
+      }
+      #MessageViewBody a {
+        color: inherit;
+        text-decoration: none;
+        font-size: inherit;
+        font-family: inherit;
+        font-weight: inherit;
+        line-height: inherit;
+      }
+    }
+    </style>
+  </head>
+  <body class="" style="background-color: #f4f4f4; font-family: sans-serif; -webkit-font-smoothing: antialiased; font-size: 14px; line-height: 1.4; margin: 0; padding: 0; -ms-text-size-adjust: 100%; -webkit-text-size-adjust: 100%;">
+    <table border="0" cellpadding="0" cellspacing="0" class="body" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background-color: #f4f4f4;">
+      <tr>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+        <td class="container" style="font-family: sans-serif; font-size: 14px; vertical-align: top; display: block; Margin: 0 auto; max-width: 580px; padding: 10px; width: 580px;">
+          <div class="content" style="box-sizing: border-box; display: block; Margin: 0 auto; max-width: 580px; padding: 10px;">
+
+            <!-- START CENTERED WHITE CONTAINER -->
+            <span class="preheader" style="color: transparent; display: none; height: 0; max-height: 0; max-width: 0; opacity: 0; overflow: hidden; mso-hide: all; visibility: hidden; width: 0;">Your Artifact Hub {{ .Period }} digest</span>
+            <table class="main" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background: #ffffff; border-radius: 3px; border-top: 7px solid #659DBD;">
+
+              <!-- START MAIN CONTENT AREA -->
+              <tr>
+                <td class="wrapper" style="font-family: sans-serif; font-size: 14px; vertical-align: top; box-sizing: border-box; padding: 20px;">
+                  <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px; vertical-align: top; text-align: center;">
+                        <h2 style="color: #39596c; font-family: sans-serif; margin: 0; Margin-top: 30px; Margin-bottom: 15px;">Your {{ .Period }} digest</h2>
+                        <p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 30px;">These are the notifications you have received since your last digest</p>
+                      </td>
+                    </tr>
+
+                    {{ range $group := .Groups }}
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px;">
+                        <hr style="border-top: 1px solid #659DBD; border-bottom: none;" />
+                        <h3 style="color: #39596c; font-family: sans-serif; margin: 0; Margin-top: 20px;">{{ $group.Title }}</h3>
+                        {{ if $group.Subtitle }}
+                          <h4 style="color: #1c2c35; font-family: sans-serif; margin: 0; Margin-top: 5px;">{{ $group.Subtitle }}</h4>
+                        {{ end }}
+                        <h4 style="color: #39596c; font-family: sans-serif; font-size: 12px; Margin-top: 20px;">{{ $group.Heading }}:</h4>
+                        <ul style="Margin-bottom: 20px;">
+                          {{ range $entry := $group.Entries }}
+                            <li>
+                              {{ if $entry.URL }}<a href="{{ $entry.URL }}" target="_blank" style="color: #39596C;">{{ $entry.Text }}</a>{{ else }}{{ $entry.Text }}{{ end }}
+                              {{ if $entry.Details }}
+                                <ul>
+                                  {{ range $detail := $entry.Details }}
+                                    <li>{{ $detail }}</li>
+                                  {{ end }}
+                                </ul>
+                              {{ end }}
+                            </li>
+                          {{ end }}
+                        </ul>
+                        {{ if $group.UnsubscribeURL }}
+                          <p style="color: #545454; font-size: 10px; text-decoration: none; Margin-bottom: 20px;"><a href="{{ $group.UnsubscribeURL }}" target="_blank" style="text-decoration: underline; color: #545454;">Unsubscribe</a> from these notifications</p>
+                        {{ end }}
+                      </td>
+                    </tr>
+                    {{ end }}
+                  </table>
+                </td>
+              </tr>
+
+            <!-- END MAIN CONTENT AREA -->
+            </table>
+
+            <!-- START FOOTER -->
+            <div class="footer" style="clear: both; Margin-top: 10px; text-align: center; width: 100%;">
+              <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 10px; color: #545454; text-align: center;">
+                    <p style="color: #545454; font-size: 10px; text-align: center; text-decoration: none;">You can change how often you receive these notifications <a href="{{ .BaseURL }}/control-panel/settings/profile" target="_blank" style="text-decoration: underline; color: #545454;">here</a> or unsubscribe <a href="{{ .BaseURL }}/control-panel/settings/subscriptions" target="_blank" style="text-decoration: underline; color: #545454;">here</a>.</p>
+                  </td>
+                </tr>
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 12px; color: #39596C; text-align: center;">
+                    <a href="{{ .BaseURL }}" style="color: #39596C; font-size: 12px; text-align: center; text-decoration: none;">© Artifact Hub</a>
+                  </td>
+                </tr>
+              </table>
+            </div>
+            <!-- END FOOTER -->
+
+          <!-- END CENTERED WHITE CONTAINER -->
+          </div>
+        </td>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+      </tr>
+    </table>
+  </body>
+</html>
+`))
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// WebhookSecretHeader represents the header used to send the webhook
+	// secret when payloads signing is not enabled.
+	WebhookSecretHeader = "X-ArtifactHub-Secret"
+
+	// WebhookSignatureHeader represents the header used to send the signature
+	// of the payload when payloads signing is enabled.
+	WebhookSignatureHeader = "X-ArtifactHub-Signature"
+
+	// webhookSignatureVersion represents the version of the signature scheme
+	// used to sign webhooks payloads.
+	webhookSignatureVersion = "v1"
+)
+
+// NewWebhookRequest creates a new http request to deliver the payload provided
+// to the webhook endpoint. When the webhook has payloads signing enabled, the
+// request includes a signature header built from the timestamp and payload
+// provided (one signature per secret configured, to allow rotating them).
+// Otherwise the webhook secret is sent as is.
+func NewWebhookRequest(wh *hub.Webhook, payload []byte, ts time.Time) (*http.Request, error) {
+	req, err := http.NewRequest("POST", wh.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	contentType := wh.ContentType
+	if contentType == "" {
+		contentType = DefaultPayloadContentType
+	}
+	req.Header.Set("Content-Type", contentType)
+	if wh.SignPayloads {
+		req.Header.Set(WebhookSignatureHeader, signWebhookPayload(wh, payload, ts))
+	} else {
+		req.Header.Set(WebhookSecretHeader, wh.Secret)
+	}
+	return req, nil
+}
+
+// signWebhookPayload returns the signature header value for the payload and
+// timestamp provided. It has the form t=<timestamp>,v1=<signature>, including
+// an additional v1 entry when the webhook has a secondary secret. Each
+// signature is the hex encoded HMAC-SHA256 of "<timestamp>.<payload>".
+func signWebhookPayload(wh *hub.Webhook, payload []byte, ts time.Time) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	parts := []string{"t=" + t}
+	for _, secret := range []string{wh.Secret, wh.SecondarySecret} {
+		if secret == "" {
+			continue
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		fmt.Fprintf(mac, "%s.%s", t, payload)
+		parts = append(parts, webhookSignatureVersion+"="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return strings.Join(parts, ",")
+}
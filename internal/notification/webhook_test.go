@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookRequest(t *testing.T) {
+	payload := []byte("payload")
+	ts := time.Unix(1600000000, 0)
+
+	t.Run("invalid url", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{URL: ":invalid"}
+		_, err := NewWebhookRequest(wh, payload, ts)
+		assert.Error(t, err)
+	})
+
+	t.Run("payloads signing disabled", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{
+			URL:    "http://webhook1.url",
+			Secret: "very",
+		}
+		req, err := NewWebhookRequest(wh, payload, ts)
+		require.NoError(t, err)
+		body, _ := ioutil.ReadAll(req.Body)
+
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "http://webhook1.url", req.URL.String())
+		assert.Equal(t, DefaultPayloadContentType, req.Header.Get("Content-Type"))
+		assert.Equal(t, "very", req.Header.Get(WebhookSecretHeader))
+		assert.Empty(t, req.Header.Get(WebhookSignatureHeader))
+		assert.Equal(t, payload, body)
+	})
+
+	t.Run("payloads signing enabled", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{
+			URL:          "http://webhook1.url",
+			Secret:       "very",
+			ContentType:  "custom/type",
+			SignPayloads: true,
+		}
+		req, err := NewWebhookRequest(wh, payload, ts)
+		require.NoError(t, err)
+
+		assert.Equal(t, "custom/type", req.Header.Get("Content-Type"))
+		assert.Empty(t, req.Header.Get(WebhookSecretHeader))
+		assert.Equal(t,
+			"t=1600000000,v1="+computeSignature("very", "1600000000.payload"),
+			req.Header.Get(WebhookSignatureHeader),
+		)
+	})
+
+	t.Run("payloads signing enabled with secondary secret", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{
+			URL:             "http://webhook1.url",
+			Secret:          "very",
+			SecondarySecret: "previous",
+			SignPayloads:    true,
+		}
+		req, err := NewWebhookRequest(wh, payload, ts)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			"t=1600000000,v1="+computeSignature("very", "1600000000.payload")+
+				",v1="+computeSignature("previous", "1600000000.payload"),
+			req.Header.Get(WebhookSignatureHeader),
+		)
+	})
+}
+
+func computeSignature(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
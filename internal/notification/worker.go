@@ -5,7 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -14,6 +18,7 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
@@ -24,6 +29,18 @@ const (
 	pauseOnEmptyQueue = 30 * time.Second
 	pauseOnError      = 10 * time.Second
 
+	// maxAttempts represents the maximum number of times the delivery of a
+	// notification will be attempted before marking it as dead.
+	maxAttempts = 10
+
+	// retryBaseDelay represents the delay applied before the first retry of a
+	// failed delivery. The delay is doubled on each subsequent attempt.
+	retryBaseDelay = 1 * time.Minute
+
+	// maxResponseBodyExcerptLen represents the maximum length of the webhook
+	// response body excerpt recorded for each delivery attempt.
+	maxResponseBodyExcerptLen = 1024
+
 	// DefaultPayloadContentType represents the default content type used for
 	// webhooks notifications.
 	DefaultPayloadContentType = "application/cloudevents+json"
@@ -42,24 +59,29 @@ type HTTPClient interface {
 
 // Worker is in charge of delivering notifications to their intended recipients.
 type Worker struct {
-	svc        *Services
-	cache      *cache.Cache
-	baseURL    string
-	httpClient HTTPClient
+	svc            *Services
+	cache          *cache.Cache
+	baseURL        string
+	unsubscribeKey []byte
+	httpClient     HTTPClient
 }
 
-// NewWorker creates a new Worker instance.
+// NewWorker creates a new Worker instance. The unsubscribe key is used to sign
+// the unsubscribe links included in the emails. When it is not provided, no
+// unsubscribe links will be included.
 func NewWorker(
 	svc *Services,
 	c *cache.Cache,
 	baseURL string,
+	unsubscribeKey []byte,
 	httpClient HTTPClient,
 ) *Worker {
 	return &Worker{
-		svc:        svc,
-		cache:      c,
-		baseURL:    baseURL,
-		httpClient: httpClient,
+		svc:            svc,
+		cache:          c,
+		baseURL:        baseURL,
+		unsubscribeKey: unsubscribeKey,
+		httpClient:     httpClient,
 	}
 }
 
@@ -94,7 +116,9 @@ func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 }
 
 // processNotification gets a pending notification from the database and
-// delivers it.
+// delivers it. When the delivery fails with a retryable error, the next attempt
+// is scheduled using an exponential backoff until the maximum number of
+// attempts is reached. At that point the notification is marked as dead.
 func (w *Worker) processNotification(ctx context.Context) error {
 	return util.DBTransact(ctx, w.svc.DB, func(tx pgx.Tx) error {
 		// Get pending notification to process
@@ -115,89 +139,325 @@ func (w *Worker) processNotification(ctx context.Context) error {
 				err = email.ErrSenderNotAvailable
 			}
 		case n.Webhook != nil:
-			err = w.deliverWebhookNotification(ctx, n)
+			var d *hub.WebhookDelivery
+			d, err = w.deliverWebhookNotification(ctx, n)
+			if d != nil {
+				if err := w.svc.NotificationManager.AddWebhookDelivery(ctx, tx, d); err != nil {
+					log.Error().Err(err).Msg("processNotification: error registering webhook delivery")
+				}
+			}
 		}
+
+		// Update notification status
 		if errors.Is(err, ErrRetryable) {
 			log.Error().Err(err).Msg("processNotification: error delivering notification")
-			return err
 		}
+		if err := w.updateNotificationStatus(ctx, tx, n, err); err != nil {
+			log.Error().Err(err).Msg("processNotification: error updating notification status")
+		}
+		return nil
+	})
+}
 
-		// Update notification status
-		err = w.svc.NotificationManager.UpdateStatus(ctx, tx, n.NotificationID, true, err)
+// processDigests delivers all the notifications digests that are due, stopping
+// when there are no more digests pending or something goes wrong.
+func (w *Worker) processDigests(ctx context.Context) {
+	for {
+		if err := w.processDigest(ctx); err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// processDigest gets a pending notifications digest from the database and
+// delivers it. The status of each of the notifications included in the digest
+// is updated the same way it would be if they were delivered individually.
+func (w *Worker) processDigest(ctx context.Context) error {
+	return util.DBTransact(ctx, w.svc.DB, func(tx pgx.Tx) error {
+		// Get pending digest to process
+		d, err := w.svc.NotificationManager.GetPendingDigest(ctx, tx)
 		if err != nil {
-			log.Error().Err(err).Msg("processNotification: error updating notification status")
+			if !errors.Is(err, pgx.ErrNoRows) {
+				log.Error().Err(err).Msg("processDigest: error getting pending digest")
+			}
+			return err
+		}
+
+		// Process digest
+		if w.svc.ES != nil {
+			err = w.deliverDigest(ctx, d)
+		} else {
+			err = email.ErrSenderNotAvailable
+		}
+
+		// Update digest notifications status
+		if errors.Is(err, ErrRetryable) {
+			log.Error().Err(err).Msg("processDigest: error delivering digest")
+		}
+		for _, n := range d.Notifications {
+			if err := w.updateNotificationStatus(ctx, tx, n, err); err != nil {
+				log.Error().Err(err).Msg("processDigest: error updating notification status")
+			}
+		}
+
+		// Update user's last digest timestamp only when the digest was sent,
+		// so that failed digests are retried as soon as they are due again
+		if err == nil {
+			if err := w.svc.NotificationManager.UpdateLastDigestSentAt(ctx, tx, d.User.UserID); err != nil {
+				log.Error().Err(err).Msg("processDigest: error updating last digest timestamp")
+			}
 		}
 		return nil
 	})
 }
 
-// deliverEmailNotification delivers the provided notification via email.
+// updateNotificationStatus updates the status of the provided notification
+// based on the result of its delivery. When the delivery failed with a
+// retryable error, the next attempt is scheduled using an exponential backoff
+// until the maximum number of attempts is reached. At that point the
+// notification is marked as dead.
+func (w *Worker) updateNotificationStatus(
+	ctx context.Context,
+	tx pgx.Tx,
+	n *hub.Notification,
+	deliveryErr error,
+) error {
+	if !errors.Is(deliveryErr, ErrRetryable) {
+		return w.svc.NotificationManager.UpdateStatus(ctx, tx, n.NotificationID, true, deliveryErr)
+	}
+	if n.Attempts+1 >= maxAttempts {
+		return w.svc.NotificationManager.MarkAsDead(ctx, tx, n.NotificationID, deliveryErr)
+	}
+	delay := getRetryDelay(n.Attempts + 1)
+	var rlErr *rateLimitedError
+	if errors.As(deliveryErr, &rlErr) && rlErr.retryAfter > 0 {
+		delay = rlErr.retryAfter
+	}
+	return w.svc.NotificationManager.ScheduleRetry(ctx, tx, n.NotificationID, delay, deliveryErr)
+}
+
+// getRetryDelay returns the delay to apply before retrying the delivery of a
+// notification that has already been attempted the number of times provided.
+func getRetryDelay(attempts int) time.Duration {
+	return retryBaseDelay * time.Duration(1<<uint(attempts-1))
+}
+
+// parseRetryAfter parses the value of the Retry-After header provided, which
+// can be expressed in seconds or as an http date, returning the delay
+// requested. Zero is returned when the value is missing or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(math.Ceil(seconds)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitedError represents the error returned when a webhook endpoint rate
+// limits a delivery. It is a retryable error and includes the delay requested
+// by the endpoint before retrying, if any.
+type rateLimitedError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrRetryable, e.err)
+}
+
+// Is reports whether the error matches the target provided. Rate limited
+// errors are always retryable.
+func (e *rateLimitedError) Is(target error) bool {
+	return target == ErrRetryable
+}
+
+// deliverEmailNotification delivers the provided notification via email. The
+// email is prepared for each recipient, as it includes a personal unsubscribe
+// link.
 func (w *Worker) deliverEmailNotification(ctx context.Context, n *hub.Notification) error {
 	// Prepare email data
-	var emailData email.Data
-	cKey := "emailData.%" + n.Event.EventID
-	cValue, ok := w.cache.Get(cKey)
-	if ok {
-		emailData = cValue.(email.Data)
-	} else {
-		var err error
-		emailData, err = w.prepareEmailData(ctx, n.Event)
-		if err != nil {
-			return fmt.Errorf("%w: error preparing email data: %v", ErrRetryable, err)
-		}
-		w.cache.SetDefault(cKey, emailData)
+	unsubscribeURL := w.getUnsubscribeURL(n.User.UserID, n.Event)
+	emailData, err := w.prepareEmailData(ctx, n.Event, unsubscribeURL)
+	if err != nil {
+		return fmt.Errorf("%w: error preparing email data: %v", ErrRetryable, err)
 	}
 	emailData.To = n.User.Email
+	emailData.UnsubscribeURL = unsubscribeURL
 
 	// Send email
 	return w.svc.ES.SendEmail(&emailData)
 }
 
+// getUnsubscribeURL returns the url the user provided can use to unsubscribe
+// from the notifications of the events given without having to log in. An
+// empty string is returned when it is not possible to unsubscribe from the
+// notifications of any of the events or the unsubscribe key is not available.
+func (w *Worker) getUnsubscribeURL(userID string, events ...*hub.Event) string {
+	if len(w.unsubscribeKey) == 0 || userID == "" {
+		return ""
+	}
+	var rs []*hub.UnsubscribeRequest
+	seen := make(map[hub.UnsubscribeRequest]struct{})
+	for _, e := range events {
+		r := newUnsubscribeRequest(userID, e)
+		if r == nil {
+			continue
+		}
+		if _, ok := seen[*r]; ok {
+			continue
+		}
+		seen[*r] = struct{}{}
+		rs = append(rs, r)
+	}
+	if len(rs) == 0 {
+		return ""
+	}
+	expiresAt := time.Now().Add(subscription.UnsubscribeTokenLifetime)
+	token := subscription.NewUnsubscribeToken(w.unsubscribeKey, rs, expiresAt)
+	return fmt.Sprintf("%s/api/v1/subscriptions/unsubscribe?token=%s", w.baseURL, token)
+}
+
+// newUnsubscribeRequest returns the request needed to unsubscribe the user
+// provided from the notifications of the event given. Nil is returned when it
+// is not possible to unsubscribe from the notifications of the event kind.
+func newUnsubscribeRequest(userID string, e *hub.Event) *hub.UnsubscribeRequest {
+	r := &hub.UnsubscribeRequest{
+		UserID:    userID,
+		EventKind: e.EventKind,
+	}
+	switch e.EventKind {
+	case hub.NewRelease, hub.SecurityAlert, hub.PackageDeprecated:
+		r.PackageID = e.PackageID
+	case hub.PackageVersionRemoved:
+		if e.PackageID != "" {
+			r.PackageID = e.PackageID
+		} else {
+			r.RepositoryID = e.RepositoryID
+		}
+	case hub.RepositoryTrackingErrors, hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+		r.RepositoryID = e.RepositoryID
+	default:
+		return nil
+	}
+	return r
+}
+
+// deliverDigest delivers the provided notifications digest via email. The
+// digest unsubscribe link stops all the notifications included in it.
+func (w *Worker) deliverDigest(ctx context.Context, d *hub.NotificationsDigest) error {
+	emailData, err := w.prepareDigestEmailData(ctx, d)
+	if err != nil {
+		return fmt.Errorf("%w: error preparing digest email data: %v", ErrRetryable, err)
+	}
+	emailData.To = d.User.Email
+	events := make([]*hub.Event, 0, len(d.Notifications))
+	for _, n := range d.Notifications {
+		events = append(events, n.Event)
+	}
+	emailData.UnsubscribeURL = w.getUnsubscribeURL(d.User.UserID, events...)
+	return w.svc.ES.SendEmail(&emailData)
+}
+
 // deliverWebhookNotification delivers the provided notification via webhook.
-func (w *Worker) deliverWebhookNotification(ctx context.Context, n *hub.Notification) error {
+// When the webhook endpoint is called, the details of the delivery attempt are
+// returned so that they can be recorded. Network errors, server side errors and
+// rate limited requests are considered retryable.
+func (w *Worker) deliverWebhookNotification(
+	ctx context.Context,
+	n *hub.Notification,
+) (*hub.WebhookDelivery, error) {
 	// Get template data
-	tmplData, err := w.preparePkgNotificationTemplateData(ctx, n.Event)
+	var tmplData interface{}
+	var err error
+	switch n.Event.EventKind {
+	case hub.RepositoryTrackingErrors,
+		hub.RepositoryOwnershipClaim,
+		hub.RepositoryTransferred,
+		hub.RepositoryVerifiedPublisherChanged:
+		tmplData, err = w.prepareRepoNotificationTemplateData(ctx, n.Event)
+	default:
+		tmplData, err = w.preparePkgNotificationTemplateData(ctx, n.Event)
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrRetryable, err)
+		return nil, fmt.Errorf("%w: %v", ErrRetryable, err)
 	}
 
 	// Prepare payload
-	var tmpl *template.Template
-	if n.Webhook.Template != "" {
-		var err error
-		tmpl, err = template.New("").Parse(n.Webhook.Template)
-		if err != nil {
-			return err
-		}
-	} else {
-		tmpl = DefaultWebhookPayloadTmpl
-	}
-	var payload bytes.Buffer
-	if err := tmpl.Execute(&payload, tmplData); err != nil {
-		return err
-	}
-	contentType := n.Webhook.ContentType
-	if contentType == "" {
-		contentType = DefaultPayloadContentType
+	payload, err := BuildWebhookPayload(n.Webhook, n.Event.EventKind, tmplData)
+	if err != nil {
+		return nil, err
 	}
 
 	// Call webhook endpoint
-	req, _ := http.NewRequest("POST", n.Webhook.URL, &payload)
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("X-ArtifactHub-Secret", n.Webhook.Secret)
+	d := &hub.WebhookDelivery{
+		NotificationID: n.NotificationID,
+		Attempt:        n.Attempts + 1,
+		RequestPayload: string(payload),
+	}
+	req, err := NewWebhookRequest(n.Webhook, payload, time.Now())
+	if err != nil {
+		d.Error = err.Error()
+		return d, err
+	}
+	start := time.Now()
 	resp, err := w.httpClient.Do(req)
+	d.Latency = time.Since(start).Milliseconds()
 	if err != nil {
-		return err
+		d.Error = err.Error()
+		return d, fmt.Errorf("%w: %v", ErrRetryable, err)
 	}
 	defer resp.Body.Close()
+	d.ResponseStatus = resp.StatusCode
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodyExcerptLen))
+	d.ResponseBody = string(body)
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		err = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		d.Error = err.Error()
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			err = &rateLimitedError{
+				err:        err,
+				retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			}
+		case resp.StatusCode >= 500:
+			err = fmt.Errorf("%w: %v", ErrRetryable, err)
+		}
+		return d, err
 	}
-	return nil
+	d.Success = true
+	return d, nil
+}
+
+// pkgEmailTemplateData represents the data exposed to the packages
+// notifications email templates.
+type pkgEmailTemplateData struct {
+	*hub.PackageNotificationTemplateData
+	UnsubscribeURL string
+}
+
+// repoEmailTemplateData represents the data exposed to the repositories
+// notifications email templates.
+type repoEmailTemplateData struct {
+	*hub.RepositoryNotificationTemplateData
+	UnsubscribeURL string
 }
 
 // prepareEmailData prepares the email data corresponding to the event provided.
-func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data, error) {
+func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event, unsubscribeURL string) (email.Data, error) {
 	var subject string
 	var emailBody bytes.Buffer
 
@@ -208,7 +468,16 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			return email.Data{}, err
 		}
 		subject = fmt.Sprintf("%s version %s released", tmplData.Package["name"], tmplData.Package["version"])
-		if err := newReleaseEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		if err := newReleaseEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.SecurityAlert:
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s version %s security alert", tmplData.Package["name"], tmplData.Package["version"])
+		if err := securityAlertEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
 			return email.Data{}, err
 		}
 	case hub.RepositoryTrackingErrors:
@@ -217,7 +486,7 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			return email.Data{}, err
 		}
 		subject = fmt.Sprintf("Something went wrong tracking repository %s", tmplData.Repository["name"])
-		if err := trackingErrorsEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		if err := trackingErrorsEmailTmpl.Execute(&emailBody, &repoEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
 			return email.Data{}, err
 		}
 	case hub.RepositoryOwnershipClaim:
@@ -226,7 +495,43 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			return email.Data{}, err
 		}
 		subject = fmt.Sprintf("%s repository ownership has been claimed", tmplData.Repository["name"])
-		if err := ownershipClaimEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		if err := ownershipClaimEmailTmpl.Execute(&emailBody, &repoEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.PackageVersionRemoved:
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s version %s removed", tmplData.Package["name"], tmplData.Package["version"])
+		if err := versionRemovedEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.PackageDeprecated:
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s package has been deprecated", tmplData.Package["name"])
+		if err := deprecatedEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.RepositoryTransferred:
+		tmplData, err := w.prepareRepoNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s repository has been transferred", tmplData.Repository["name"])
+		if err := repoTransferredEmailTmpl.Execute(&emailBody, &repoEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.RepositoryVerifiedPublisherChanged:
+		tmplData, err := w.prepareRepoNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s repository verified publisher status has changed", tmplData.Repository["name"])
+		if err := verifiedPublisherEmailTmpl.Execute(&emailBody, &repoEmailTemplateData{tmplData, unsubscribeURL}); err != nil {
 			return email.Data{}, err
 		}
 	}
@@ -237,6 +542,127 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 	}, nil
 }
 
+// prepareDigestEmailData prepares the email data corresponding to the
+// notifications digest provided. The digest entries are grouped by package or
+// repository and event kind, keeping the order in which they were created.
+func (w *Worker) prepareDigestEmailData(ctx context.Context, d *hub.NotificationsDigest) (email.Data, error) {
+	period := "daily"
+	if d.User.EmailDeliveryPreference != nil && *d.User.EmailDeliveryPreference == hub.WeeklyDigestEmailDelivery {
+		period = "weekly"
+	}
+	tmplData := &digestTemplateData{
+		BaseURL: w.baseURL,
+		Period:  period,
+	}
+
+	groups := make(map[string]*digestGroup)
+	addEntry := func(key string, e *hub.Event, title, subtitle string, entry *digestEntry) {
+		key = fmt.Sprintf("%s:%d", key, e.EventKind)
+		g, ok := groups[key]
+		if !ok {
+			g = &digestGroup{
+				Title:          title,
+				Subtitle:       subtitle,
+				Heading:        digestGroupsHeadings[e.EventKind],
+				UnsubscribeURL: w.getUnsubscribeURL(d.User.UserID, e),
+			}
+			groups[key] = g
+			tmplData.Groups = append(tmplData.Groups, g)
+		}
+		g.Entries = append(g.Entries, entry)
+	}
+
+	for _, n := range d.Notifications {
+		e := n.Event
+		switch e.EventKind {
+		case hub.NewRelease, hub.SecurityAlert:
+			td, err := w.preparePkgNotificationTemplateData(ctx, e)
+			if err != nil {
+				return email.Data{}, err
+			}
+			name, _ := td.Package["name"].(string)
+			publisher, _ := td.Package["repository"].(map[string]interface{})["publisher"].(string)
+			entry := &digestEntry{
+				Text: fmt.Sprintf("Version %s", td.Package["version"]),
+			}
+			if e.EventKind == hub.NewRelease {
+				entry.URL, _ = td.Package["url"].(string)
+			} else {
+				entry.URL, _ = td.Package["securityReportURL"].(string)
+				entry.Details = td.Package["vulnerabilities"]
+			}
+			addEntry(e.PackageID, e, name, publisher, entry)
+		case hub.PackageVersionRemoved, hub.PackageDeprecated:
+			td, err := w.preparePkgNotificationTemplateData(ctx, e)
+			if err != nil {
+				return email.Data{}, err
+			}
+			name, _ := td.Package["name"].(string)
+			publisher, _ := td.Package["repository"].(map[string]interface{})["publisher"].(string)
+			entry := &digestEntry{}
+			entry.URL, _ = td.Package["url"].(string)
+			if e.EventKind == hub.PackageVersionRemoved {
+				entry.Text = fmt.Sprintf("Version %s has been removed", td.Package["version"])
+			} else {
+				entry.Text = fmt.Sprintf("Version %s has been deprecated", td.Package["version"])
+			}
+			key := e.PackageID
+			if key == "" {
+				key = e.RepositoryID + ":" + name
+			}
+			addEntry(key, e, name, publisher, entry)
+		case hub.RepositoryTrackingErrors,
+			hub.RepositoryOwnershipClaim,
+			hub.RepositoryTransferred,
+			hub.RepositoryVerifiedPublisherChanged:
+			td, err := w.prepareRepoNotificationTemplateData(ctx, e)
+			if err != nil {
+				return email.Data{}, err
+			}
+			name, _ := td.Repository["name"].(string)
+			var entry *digestEntry
+			switch e.EventKind {
+			case hub.RepositoryTrackingErrors:
+				entry = &digestEntry{
+					Text: "Something went wrong tracking the repository",
+					URL: fmt.Sprintf("%s/control-panel/repositories?user-alias=%s&org-name=%s&repo-name=%s",
+						w.baseURL,
+						td.Repository["userAlias"],
+						td.Repository["organizationName"],
+						name,
+					),
+					Details: td.Repository["lastTrackingErrors"],
+				}
+			case hub.RepositoryOwnershipClaim:
+				entry = &digestEntry{
+					Text: "The repository ownership has been claimed",
+				}
+			case hub.RepositoryTransferred:
+				entry = &digestEntry{
+					Text: fmt.Sprintf("The repository has been transferred to %s", getRepositoryOwner(td.Repository)),
+				}
+			case hub.RepositoryVerifiedPublisherChanged:
+				entry = &digestEntry{
+					Text: "The repository publisher is no longer verified",
+				}
+				if verified, _ := td.Repository["verifiedPublisher"].(bool); verified {
+					entry.Text = "The repository publisher is now verified"
+				}
+			}
+			addEntry(e.RepositoryID, e, name, "", entry)
+		}
+	}
+
+	var emailBody bytes.Buffer
+	if err := digestEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		return email.Data{}, err
+	}
+	return email.Data{
+		Subject: fmt.Sprintf("Your Artifact Hub %s digest", period),
+		Body:    emailBody.Bytes(),
+	}, nil
+}
+
 // preparePkgNotificationTemplateData prepares the data available to packages
 // notifications templates.
 func (w *Worker) preparePkgNotificationTemplateData(
@@ -251,47 +677,103 @@ func (w *Worker) preparePkgNotificationTemplateData(
 		p = cValue.(*hub.Package)
 	} else {
 		var err error
-		p, err = w.svc.PackageManager.Get(ctx, &hub.GetPackageInput{
-			PackageID: e.PackageID,
-			Version:   e.PackageVersion,
-		})
+		if e.EventKind == hub.PackageVersionRemoved {
+			p, err = w.getRemovedPackageVersion(ctx, e)
+		} else {
+			p, err = w.svc.PackageManager.Get(ctx, &hub.GetPackageInput{
+				PackageID: e.PackageID,
+				Version:   e.PackageVersion,
+			})
+		}
 		if err != nil {
 			return nil, err
 		}
 		w.cache.SetDefault(cKey, p)
 	}
 
-	// Prepare template data
+	return NewPkgNotificationTemplateData(w.baseURL, e, p), nil
+}
+
+// NewPkgNotificationTemplateData returns the data available to packages
+// notifications templates for the event and package provided.
+func NewPkgNotificationTemplateData(
+	baseURL string,
+	e *hub.Event,
+	p *hub.Package,
+) *hub.PackageNotificationTemplateData {
 	var eventKindStr string
 	switch e.EventKind {
 	case hub.NewRelease:
 		eventKindStr = "package.new-release"
+	case hub.SecurityAlert:
+		eventKindStr = "package.security-alert"
+	case hub.PackageVersionRemoved:
+		eventKindStr = "package.version-removed"
+	case hub.PackageDeprecated:
+		eventKindStr = "package.deprecated"
 	}
 	publisher := p.Repository.OrganizationName
 	if publisher == "" {
 		publisher = p.Repository.UserAlias
 	}
+	var pkgURL string
+	switch {
+	case e.EventKind != hub.PackageVersionRemoved:
+		pkgURL = pkg.BuildURL(baseURL, p, e.PackageVersion)
+	case e.PackageID != "":
+		// The version removed is no longer available, so we link to the
+		// package's latest version instead
+		pkgURL = pkg.BuildURL(baseURL, p, "")
+	default:
+		// The package has been deleted, so we link to its repository packages
+		pkgURL = fmt.Sprintf("%s/packages/search?repo=%s", baseURL, p.Repository.Name)
+	}
+	pkgData := map[string]interface{}{
+		"name":                    p.Name,
+		"version":                 p.Version,
+		"logoImageID":             p.LogoImageID,
+		"url":                     pkgURL,
+		"changes":                 p.Changes,
+		"containsSecurityUpdates": p.ContainsSecurityUpdates,
+		"prerelease":              p.Prerelease,
+		"repository": map[string]interface{}{
+			"kind":      hub.GetKindName(p.Repository.Kind),
+			"name":      p.Repository.Name,
+			"publisher": publisher,
+		},
+	}
+	if e.EventKind == hub.SecurityAlert {
+		pkgData["securityReportURL"] = pkgURL + "?modal=security-report"
+		pkgData["vulnerabilities"] = e.Data["vulnerabilities"]
+	}
 
 	return &hub.PackageNotificationTemplateData{
-		BaseURL: w.baseURL,
+		BaseURL: baseURL,
 		Event: map[string]interface{}{
 			"id":   e.EventID,
 			"kind": eventKindStr,
 		},
-		Package: map[string]interface{}{
-			"name":                    p.Name,
-			"version":                 p.Version,
-			"logoImageID":             p.LogoImageID,
-			"url":                     pkg.BuildURL(w.baseURL, p, e.PackageVersion),
-			"changes":                 p.Changes,
-			"containsSecurityUpdates": p.ContainsSecurityUpdates,
-			"prerelease":              p.Prerelease,
-			"repository": map[string]interface{}{
-				"kind":      hub.GetKindName(p.Repository.Kind),
-				"name":      p.Repository.Name,
-				"publisher": publisher,
-			},
-		},
+		Package: pkgData,
+	}
+}
+
+// getRemovedPackageVersion returns the package version removed in the event
+// provided. The version (and the package itself, when it was the last version
+// available) is no longer registered, so the package is built from the event
+// data and the repository it belonged to.
+func (w *Worker) getRemovedPackageVersion(ctx context.Context, e *hub.Event) (*hub.Package, error) {
+	r, err := w.svc.RepositoryManager.GetByID(ctx, e.RepositoryID, false)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := e.Data["package_name"].(string)
+	normalizedName, _ := e.Data["package_normalized_name"].(string)
+	return &hub.Package{
+		PackageID:      e.PackageID,
+		Name:           name,
+		NormalizedName: normalizedName,
+		Version:        e.PackageVersion,
+		Repository:     r,
 	}, nil
 }
 
@@ -316,29 +798,62 @@ func (w *Worker) prepareRepoNotificationTemplateData(
 		w.cache.SetDefault(cKey, r)
 	}
 
-	// Prepare template data
+	return NewRepoNotificationTemplateData(w.baseURL, e, r), nil
+}
+
+// NewRepoNotificationTemplateData returns the data available to repositories
+// notifications templates for the event and repository provided.
+func NewRepoNotificationTemplateData(
+	baseURL string,
+	e *hub.Event,
+	r *hub.Repository,
+) *hub.RepositoryNotificationTemplateData {
 	var eventKindStr string
 	switch e.EventKind {
 	case hub.RepositoryTrackingErrors:
 		eventKindStr = "repository.tracking-errors"
 	case hub.RepositoryOwnershipClaim:
 		eventKindStr = "repository.ownership-claim"
+	case hub.RepositoryTransferred:
+		eventKindStr = "repository.transferred"
+	case hub.RepositoryVerifiedPublisherChanged:
+		eventKindStr = "repository.verified-publisher-changed"
+	}
+	repoData := map[string]interface{}{
+		"kind":               hub.GetKindName(r.Kind),
+		"name":               r.Name,
+		"userAlias":          r.UserAlias,
+		"organizationName":   r.OrganizationName,
+		"lastTrackingErrors": strings.Split(r.LastTrackingErrors, "\n"),
+	}
+	switch e.EventKind {
+	case hub.RepositoryTransferred:
+		previousOwner, _ := e.Data["previous_owner"].(map[string]interface{})
+		repoData["previousOwner"] = map[string]interface{}{
+			"userAlias":        previousOwner["user_alias"],
+			"organizationName": previousOwner["organization_name"],
+		}
+	case hub.RepositoryVerifiedPublisherChanged:
+		repoData["verifiedPublisher"], _ = e.Data["verified_publisher"].(bool)
 	}
 
 	return &hub.RepositoryNotificationTemplateData{
-		BaseURL: w.baseURL,
+		BaseURL: baseURL,
 		Event: map[string]interface{}{
 			"id":   e.EventID,
 			"kind": eventKindStr,
 		},
-		Repository: map[string]interface{}{
-			"kind":               hub.GetKindName(r.Kind),
-			"name":               r.Name,
-			"userAlias":          r.UserAlias,
-			"organizationName":   r.OrganizationName,
-			"lastTrackingErrors": strings.Split(r.LastTrackingErrors, "\n"),
-		},
-	}, nil
+		Repository: repoData,
+	}
+}
+
+// getRepositoryOwner returns a description of the owner of the repository in
+// the repositories notifications template data provided.
+func getRepositoryOwner(repoData map[string]interface{}) string {
+	if userAlias, _ := repoData["userAlias"].(string); userAlias != "" {
+		return "user " + userAlias
+	}
+	return fmt.Sprintf("organization %s", repoData["organizationName"])
 }
 
 // DefaultWebhookPayloadTmpl is the template used for the webhook payload when
@@ -367,3 +882,98 @@ var DefaultWebhookPayloadTmpl = template.Must(template.New("").Parse(`
 	}
 }
 `))
+
+// DefaultSecurityAlertWebhookPayloadTmpl is the template used for the webhook
+// payload of security alerts notifications when the webhook uses the default
+// template.
+var DefaultSecurityAlertWebhookPayloadTmpl = template.Must(template.New("").Parse(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"package": {
+			"name": "{{ .Package.name }}",
+			"version": "{{ .Package.version }}",
+			"url": "{{ .Package.url }}",
+			"securityReportURL": "{{ .Package.securityReportURL }}",
+			"vulnerabilities": [{{range $i, $e := .Package.vulnerabilities}}{{if $i}}, {{end}}"{{.}}"{{end}}],
+			"repository": {
+				"kind": "{{ .Package.repository.kind }}",
+				"name": "{{ .Package.repository.name }}",
+				"publisher": "{{ .Package.repository.publisher }}"
+			}
+		}
+	}
+}
+`))
+
+// DefaultTrackingErrorsWebhookPayloadTmpl is the template used for the webhook
+// payload of repository tracking errors notifications when the webhook uses
+// the default template.
+var DefaultTrackingErrorsWebhookPayloadTmpl = template.Must(template.New("").Funcs(WebhookTemplateFuncs).Parse(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"repository": {
+			"kind": "{{ .Repository.kind }}",
+			"name": "{{ .Repository.name }}",
+			"lastTrackingErrors": {{ json .Repository.lastTrackingErrors }}
+		}
+	}
+}
+`))
+
+// DefaultPackageChangeWebhookPayloadTmpl is the template used for the webhook
+// payload of package versions removed and package deprecated notifications
+// when the webhook uses the default template.
+var DefaultPackageChangeWebhookPayloadTmpl = template.Must(template.New("").Parse(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"package": {
+			"name": "{{ .Package.name }}",
+			"version": "{{ .Package.version }}",
+			"url": "{{ .Package.url }}",
+			"repository": {
+				"kind": "{{ .Package.repository.kind }}",
+				"name": "{{ .Package.repository.name }}",
+				"publisher": "{{ .Package.repository.publisher }}"
+			}
+		}
+	}
+}
+`))
+
+// DefaultRepositoryChangeWebhookPayloadTmpl is the template used for the
+// webhook payload of repository transferred and verified publisher changed
+// notifications when the webhook uses the default template.
+var DefaultRepositoryChangeWebhookPayloadTmpl = template.Must(template.New("").Funcs(WebhookTemplateFuncs).Parse(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"repository": {
+			"kind": "{{ .Repository.kind }}",
+			"name": "{{ .Repository.name }}",
+			"userAlias": {{ json .Repository.userAlias }},
+			"organizationName": {{ json .Repository.organizationName }}{{ if .Repository.previousOwner }},
+			"previousOwner": {{ json .Repository.previousOwner }}{{ end }}{{ if eq .Event.kind "repository.verified-publisher-changed" }},
+			"verifiedPublisher": {{ json .Repository.verifiedPublisher }}{{ end }}
+		}
+	}
+}
+`))
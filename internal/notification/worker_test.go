@@ -16,6 +16,7 @@ import (
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -33,6 +34,15 @@ func TestWorker(t *testing.T) {
 		EventKind:    hub.RepositoryTrackingErrors,
 		RepositoryID: "repositoryID",
 	}
+	e3 := &hub.Event{
+		EventID:        "eventID",
+		EventKind:      hub.SecurityAlert,
+		PackageID:      "packageID",
+		PackageVersion: "1.0.0",
+		Data: map[string]interface{}{
+			"vulnerabilities": []interface{}{"CVE-2020-0001", "CVE-2020-0002"},
+		},
+	}
 	u := &hub.User{
 		Email: "user1@email.com",
 	}
@@ -55,6 +65,11 @@ func TestWorker(t *testing.T) {
 		Event:          e2,
 		User:           u,
 	}
+	n4 := &hub.Notification{
+		NotificationID: "notificationID",
+		Event:          e3,
+		User:           u,
+	}
 	gpi := &hub.GetPackageInput{
 		PackageID: e1.PackageID,
 		Version:   e1.PackageVersion,
@@ -88,7 +103,7 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -99,9 +114,10 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n1, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
-		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID", time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -112,9 +128,10 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n3, nil)
 		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(nil, tests.ErrFake)
-		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID", time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -129,7 +146,7 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n1.NotificationID, true, tests.ErrFake).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -144,7 +161,7 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n3.NotificationID, true, tests.ErrFake).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -159,7 +176,139 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n1.NotificationID, true, nil).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("package email notification with unsubscribe link delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			User: &hub.User{
+				UserID: "userID",
+				Email:  "user1@email.com",
+			},
+		}
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			prefix := "http://baseURL/api/v1/subscriptions/unsubscribe?token="
+			if !strings.HasPrefix(data.UnsubscribeURL, prefix) {
+				return false
+			}
+			rs, err := subscription.ParseUnsubscribeToken([]byte("key"), strings.TrimPrefix(data.UnsubscribeURL, prefix))
+			if err != nil || len(rs) != 1 {
+				return false
+			}
+			r := rs[0]
+			return r.UserID == "userID" &&
+				r.EventKind == hub.NewRelease &&
+				r.PackageID == e1.PackageID &&
+				strings.Contains(string(data.Body), data.UnsubscribeURL)
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", []byte("key"), sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("security alert email notification delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n4, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			body := string(data.Body)
+			return data.To == u.Email &&
+				data.Subject == "package1 version 1.0.0 security alert" &&
+				strings.Contains(body, "CVE-2020-0001") &&
+				strings.Contains(body, "CVE-2020-0002") &&
+				strings.Contains(body, "http://baseURL/packages/helm/repo1/package1/1.0.0?modal=security-report")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n4.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("package version removed email notification delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event: &hub.Event{
+				EventID:        "eventID",
+				EventKind:      hub.PackageVersionRemoved,
+				RepositoryID:   "repositoryID",
+				PackageVersion: "1.0.0",
+				Data: map[string]interface{}{
+					"package_name":            "package1",
+					"package_normalized_name": "package1",
+				},
+			},
+			User: &hub.User{
+				UserID: "userID",
+				Email:  "user1@email.com",
+			},
+		}
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			prefix := "http://baseURL/api/v1/subscriptions/unsubscribe?token="
+			rs, err := subscription.ParseUnsubscribeToken([]byte("key"), strings.TrimPrefix(data.UnsubscribeURL, prefix))
+			if err != nil || len(rs) != 1 {
+				return false
+			}
+			ur := rs[0]
+			return ur.EventKind == hub.PackageVersionRemoved &&
+				ur.RepositoryID == "repositoryID" &&
+				data.Subject == "package1 version 1.0.0 removed" &&
+				strings.Contains(string(data.Body), "http://baseURL/packages/search?repo=repo1")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", []byte("key"), sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("verified publisher changed email notification delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event: &hub.Event{
+				EventID:      "eventID",
+				EventKind:    hub.RepositoryVerifiedPublisherChanged,
+				RepositoryID: "repositoryID",
+				Data: map[string]interface{}{
+					"verified_publisher": true,
+				},
+			},
+			User: u,
+		}
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			return data.Subject == "repo1 repository verified publisher status has changed" &&
+				strings.Contains(string(data.Body), "repository publisher is now verified")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -174,7 +323,7 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n3.NotificationID, true, nil).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -185,9 +334,10 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
-		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID", time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -199,10 +349,16 @@ func TestWorker(t *testing.T) {
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.hc.On("Do", mock.Anything).Return(nil, tests.ErrFake)
-		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, tests.ErrFake).Return(nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.NotificationID == n2.NotificationID &&
+				d.Attempt == 1 &&
+				!d.Success &&
+				d.Error == tests.ErrFake.Error()
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, n2.NotificationID, time.Minute, mock.Anything).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -217,10 +373,117 @@ func TestWorker(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader("")),
 			StatusCode: http.StatusNotFound,
 		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.ResponseStatus == http.StatusNotFound && !d.Success
+		})).Return(nil)
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, mock.Anything).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call returned a server error, retry scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			Webhook:        wh,
+			Attempts:       2,
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("internal error")),
+			StatusCode: http.StatusInternalServerError,
+		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.Attempt == 3 &&
+				d.ResponseStatus == http.StatusInternalServerError &&
+				d.ResponseBody == "internal error" &&
+				!d.Success
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID", 4*time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call was rate limited, retry scheduled as requested", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(&http.Response{
+			Header:     http.Header{"Retry-After": []string{"30"}},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusTooManyRequests,
+		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.ResponseStatus == http.StatusTooManyRequests && !d.Success
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID", 30*time.Second, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository tracking errors slack notification delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e2,
+			Webhook: &hub.Webhook{
+				URL:     "http://webhook1.url",
+				Channel: hub.SlackWebhookChannel,
+			},
+		}, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			payload, _ := ioutil.ReadAll(req.Body)
+			return strings.Contains(string(payload), "Something went wrong tracking repository repo1")
+		})).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("ok")),
+			StatusCode: http.StatusOK,
+		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call returned a server error, max attempts reached", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			Webhook:        wh,
+			Attempts:       maxAttempts - 1,
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusServiceUnavailable,
+		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("MarkAsDead", sw.ctx, sw.tx, "notificationID", mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -235,10 +498,13 @@ func TestWorker(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader("")),
 			StatusCode: http.StatusOK,
 		}, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.ResponseStatus == http.StatusOK && d.Success && d.Error == ""
+		})).Return(nil)
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, nil).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
-		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
@@ -319,15 +585,269 @@ func TestWorker(t *testing.T) {
 					},
 				}, nil)
 				sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+				sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
 				sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, nil).Return(nil)
 				sw.tx.On("Commit", sw.ctx).Return(nil)
 
-				w := NewWorker(sw.svc, sw.cache, "http://baseURL", http.DefaultClient)
+				w := NewWorker(sw.svc, sw.cache, "http://baseURL", nil, http.DefaultClient)
 				go w.Run(sw.ctx, sw.wg)
 				sw.assertExpectations(t)
 			})
 		}
 	})
+
+	t.Run("security alert webhook notification delivered successfully (real http server)", func(t *testing.T) {
+		t.Parallel()
+		expectedPayload := []byte(`
+{
+	"specversion" : "1.0",
+	"id" : "eventID",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.package.security-alert",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"package": {
+			"name": "package1",
+			"version": "1.0.0",
+			"url": "http://baseURL/packages/helm/repo1/package1/1.0.0",
+			"securityReportURL": "http://baseURL/packages/helm/repo1/package1/1.0.0?modal=security-report",
+			"vulnerabilities": ["CVE-2020-0001", "CVE-2020-0002"],
+			"repository": {
+				"kind": "helm",
+				"name": "repo1",
+				"publisher": "org1"
+			}
+		}
+	}
+}
+`)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, DefaultPayloadContentType, r.Header.Get("Content-Type"))
+			payload, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, expectedPayload, payload)
+		}))
+		defer ts.Close()
+
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e3,
+			Webhook: &hub.Webhook{
+				URL: ts.URL,
+			},
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", nil, http.DefaultClient)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+}
+
+func TestWorkerDigests(t *testing.T) {
+	weeklyDigest := hub.WeeklyDigestEmailDelivery
+	u := &hub.User{
+		UserID:                  "userID",
+		Email:                   "user1@email.com",
+		EmailDeliveryPreference: &weeklyDigest,
+	}
+	d := &hub.NotificationsDigest{
+		User: u,
+		Notifications: []*hub.Notification{
+			{
+				NotificationID: "notificationID1",
+				Event: &hub.Event{
+					EventID:        "eventID1",
+					EventKind:      hub.NewRelease,
+					PackageID:      "packageID",
+					PackageVersion: "1.0.0",
+				},
+			},
+			{
+				NotificationID: "notificationID2",
+				Attempts:       maxAttempts - 1,
+				Event: &hub.Event{
+					EventID:      "eventID2",
+					EventKind:    hub.RepositoryTrackingErrors,
+					RepositoryID: "repositoryID",
+				},
+			},
+		},
+	}
+	gpi := &hub.GetPackageInput{
+		PackageID: "packageID",
+		Version:   "1.0.0",
+	}
+	p := &hub.Package{
+		Name:           "package1",
+		NormalizedName: "package1",
+		Version:        "1.0.0",
+		Repository: &hub.Repository{
+			Kind:             hub.Helm,
+			Name:             "repo1",
+			OrganizationName: "org1",
+		},
+	}
+	r := &hub.Repository{
+		Kind:               hub.Helm,
+		Name:               "repo2",
+		OrganizationName:   "org1",
+		LastTrackingErrors: "error tracking repo2",
+	}
+
+	t.Run("error getting pending digest", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, tests.ErrFake)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		w.processDigests(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error preparing digest email data", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(d, nil).Once()
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notificationID1", time.Minute, mock.Anything).Return(nil)
+		sw.nm.On("MarkAsDead", sw.ctx, sw.tx, "notificationID2", mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		w.processDigests(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("email sender not available", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.svc.ES = nil
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(d, nil).Once()
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID1", true, email.ErrSenderNotAvailable).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID2", true, email.ErrSenderNotAvailable).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", nil, sw.hc)
+		w.processDigests(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("digest delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(d, nil).Once()
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			body := string(data.Body)
+			return data.To == u.Email &&
+				data.Subject == "Your Artifact Hub weekly digest" &&
+				strings.Contains(body, "package1") &&
+				strings.Contains(body, "New releases") &&
+				strings.Contains(body, "http://baseURL/packages/helm/repo1/package1/1.0.0") &&
+				strings.Contains(body, "repo2") &&
+				strings.Contains(body, "Tracking errors") &&
+				strings.Contains(body, "error tracking repo2")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID1", true, nil).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID2", true, nil).Return(nil)
+		sw.nm.On("UpdateLastDigestSentAt", sw.ctx, sw.tx, "userID").Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", nil, sw.hc)
+		w.processDigests(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("digest with unsubscribe link delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(d, nil).Once()
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			prefix := "http://baseURL/api/v1/subscriptions/unsubscribe?token="
+			if !strings.HasPrefix(data.UnsubscribeURL, prefix) {
+				return false
+			}
+			rs, err := subscription.ParseUnsubscribeToken([]byte("key"), strings.TrimPrefix(data.UnsubscribeURL, prefix))
+			return err == nil && assert.ObjectsAreEqual([]*hub.UnsubscribeRequest{
+				{
+					UserID:    "userID",
+					EventKind: hub.NewRelease,
+					PackageID: "packageID",
+				},
+				{
+					UserID:       "userID",
+					EventKind:    hub.RepositoryTrackingErrors,
+					RepositoryID: "repositoryID",
+				},
+			}, rs)
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID1", true, nil).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID2", true, nil).Return(nil)
+		sw.nm.On("UpdateLastDigestSentAt", sw.ctx, sw.tx, "userID").Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", []byte("key"), sw.hc)
+		w.processDigests(sw.ctx)
+		sw.wg.Done()
+		sw.assertExpectations(t)
+	})
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	assert.Equal(t, 1*time.Minute, getRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, getRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, getRetryDelay(3))
+	assert.Equal(t, 256*time.Minute, getRetryDelay(9))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"invalid", 0},
+		{"-1", 0},
+		{"30", 30 * time.Second},
+		{"0.5", 1 * time.Second},
+		{"Tue, 01 Dec 2020 10:02:00 GMT", 2 * time.Minute},
+		{"Tue, 01 Dec 2020 09:00:00 GMT", 0},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, parseRetryAfter(tc.value, now))
+		})
+	}
 }
 
 type servicesWrapper struct {
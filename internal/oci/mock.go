@@ -0,0 +1,65 @@
+package oci
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/mock"
+)
+
+// PullerMock is a mock implementation of the OCIPuller interface.
+type PullerMock struct {
+	mock.Mock
+}
+
+// Digest implements the OCIPuller interface.
+func (m *PullerMock) Digest(ctx context.Context, ref, username, password string) (string, error) {
+	args := m.Called(ctx, ref, username, password)
+	return args.String(0), args.Error(1)
+}
+
+// PullLayer implements the OCIPuller interface.
+func (m *PullerMock) PullLayer(
+	ctx context.Context,
+	ref,
+	mediaType,
+	username,
+	password string,
+) (v1.Descriptor, []byte, error) {
+	args := m.Called(ctx, ref, mediaType, username, password)
+	desc, _ := args.Get(0).(v1.Descriptor)
+	data, _ := args.Get(1).([]byte)
+	return desc, data, args.Error(2)
+}
+
+// TagsGetterMock is a mock implementation of the OCITagsGetter interface.
+type TagsGetterMock struct {
+	mock.Mock
+}
+
+// Tags implements the OCITagsGetter interface.
+func (m *TagsGetterMock) Tags(ctx context.Context, r *hub.Repository) ([]string, error) {
+	args := m.Called(ctx, r)
+	tags, _ := args.Get(0).([]string)
+	return tags, args.Error(1)
+}
+
+// SignatureVerifierMock is a mock implementation of the OCISignatureVerifier
+// interface.
+type SignatureVerifierMock struct {
+	mock.Mock
+}
+
+// Verify implements the OCISignatureVerifier interface.
+func (m *SignatureVerifierMock) Verify(
+	ctx context.Context,
+	ref string,
+	keys []string,
+	username,
+	password string,
+) (*hub.CosignVerification, error) {
+	args := m.Called(ctx, ref, keys, username, password)
+	v, _ := args.Get(0).(*hub.CosignVerification)
+	return v, args.Error(1)
+}
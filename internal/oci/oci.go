@@ -0,0 +1,144 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ErrLayerNotFound indicates that the requested layer was not found in the
+// image.
+var ErrLayerNotFound = errors.New("layer not found")
+
+// TagsGetter provides a mechanism to get all the tags available for a given
+// repository in a OCI registry.
+type TagsGetter struct{}
+
+// Tags returns a list with the tags available for the provided repository.
+// Tags that are valid semver versions are sorted in descending order, and the
+// rest of them are placed at the end of the list.
+func (tg *TagsGetter) Tags(ctx context.Context, r *hub.Repository) ([]string, error) {
+	u := strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix)
+	ociRepo, err := name.NewRepository(u)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := remote.ListWithContext(ctx, ociRepo, authOptions(r.AuthUser, r.AuthPass)...)
+	if err != nil {
+		return nil, err
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+// Puller provides a mechanism to pull content from OCI registries.
+type Puller struct{}
+
+// Digest returns the digest of the manifest of the image reference provided.
+func (p *Puller) Digest(ctx context.Context, ref, username, password string) (string, error) {
+	r, err := name.ParseReference(strings.TrimPrefix(ref, hub.RepositoryOCIPrefix))
+	if err != nil {
+		return "", err
+	}
+	options := []remote.Option{remote.WithContext(ctx)}
+	options = append(options, authOptions(username, password)...)
+	desc, err := remote.Head(r, options...)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// PullLayer pulls the first layer of the media type provided from the image
+// reference given, returning its descriptor and content. The image config blob
+// is also considered, so it can be pulled by using its media type.
+func (p *Puller) PullLayer(
+	ctx context.Context,
+	ref,
+	mediaType,
+	username,
+	password string,
+) (v1.Descriptor, []byte, error) {
+	r, err := name.ParseReference(strings.TrimPrefix(ref, hub.RepositoryOCIPrefix))
+	if err != nil {
+		return v1.Descriptor{}, nil, err
+	}
+	options := []remote.Option{remote.WithContext(ctx)}
+	options = append(options, authOptions(username, password)...)
+	img, err := remote.Image(r, options...)
+	if err != nil {
+		return v1.Descriptor{}, nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return v1.Descriptor{}, nil, err
+	}
+
+	// Config blob
+	if string(manifest.Config.MediaType) == mediaType {
+		data, err := img.RawConfigFile()
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		return manifest.Config, data, nil
+	}
+
+	// Layers
+	for _, desc := range manifest.Layers {
+		if string(desc.MediaType) != mediaType {
+			continue
+		}
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		return desc, data, nil
+	}
+
+	return v1.Descriptor{}, nil, ErrLayerNotFound
+}
+
+// sortTags sorts the tags provided in descending order by version. Tags that
+// are not valid semver versions are placed at the end.
+func sortTags(tags []string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, errI := semver.NewVersion(tags[i])
+		vj, errJ := semver.NewVersion(tags[j])
+		if errI != nil || errJ != nil {
+			return errI == nil && errJ != nil
+		}
+		return vj.LessThan(vi)
+	})
+}
+
+// authOptions returns the remote options required to authenticate using the
+// credentials provided, if any.
+func authOptions(username, password string) []remote.Option {
+	if username == "" && password == "" {
+		return nil
+	}
+	return []remote.Option{
+		remote.WithAuth(&authn.Basic{
+			Username: username,
+			Password: password,
+		}),
+	}
+}
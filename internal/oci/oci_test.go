@@ -0,0 +1,118 @@
+package oci
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	configMediaType = "application/vnd.cncf.helm.config.v1+json"
+	layerMediaType  = "application/tar+gzip"
+)
+
+func TestTagsGetter(t *testing.T) {
+	t.Run("invalid repository url", func(t *testing.T) {
+		t.Parallel()
+		tg := &TagsGetter{}
+		r := &hub.Repository{URL: hub.RepositoryOCIPrefix + "INVALID/@@"}
+		tags, err := tg.Tags(context.Background(), r)
+		assert.Error(t, err)
+		assert.Nil(t, tags)
+	})
+
+	t.Run("tags sorted by version", func(t *testing.T) {
+		t.Parallel()
+		tags := []string{"1.0.0", "latest", "2.0.0", "1.10.0", "stable"}
+		sortTags(tags)
+		assert.Equal(t, []string{"2.0.0", "1.10.0", "1.0.0", "latest", "stable"}, tags)
+	})
+}
+
+func TestPuller(t *testing.T) {
+	ctx := context.Background()
+	host := setupRegistry(t)
+	manifestDigest := pushArtifact(t, host, "repo/chart", "1.0.0", "config", "layer")
+	ref := hub.RepositoryOCIPrefix + host + "/repo/chart:1.0.0"
+
+	t.Run("digest: reference not found", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		digest, err := p.Digest(ctx, host+"/repo/chart:2.0.0", "", "")
+		assert.Error(t, err)
+		assert.Empty(t, digest)
+	})
+
+	t.Run("digest: success", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		digest, err := p.Digest(ctx, ref, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, manifestDigest, digest)
+	})
+
+	t.Run("pull layer: reference not found", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		_, data, err := p.PullLayer(ctx, host+"/repo/chart:2.0.0", layerMediaType, "", "")
+		assert.Error(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("pull layer: layer not found", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		_, data, err := p.PullLayer(ctx, ref, "application/unknown", "", "")
+		assert.Equal(t, ErrLayerNotFound, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("pull layer: config pulled successfully", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		desc, data, err := p.PullLayer(ctx, ref, configMediaType, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, configMediaType, string(desc.MediaType))
+		assert.Equal(t, []byte("config"), data)
+	})
+
+	t.Run("pull layer: layer pulled successfully", func(t *testing.T) {
+		t.Parallel()
+		p := &Puller{}
+		desc, data, err := p.PullLayer(ctx, ref, layerMediaType, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, layerMediaType, string(desc.MediaType))
+		assert.Equal(t, "sha256", desc.Digest.Algorithm)
+		assert.Equal(t, []byte("layer"), data)
+	})
+}
+
+func setupRegistry(t *testing.T) string {
+	t.Helper()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	u, _ := url.Parse(s.URL)
+	return u.Host
+}
+
+func pushArtifact(t *testing.T, host, repository, tag, config, layer string) string {
+	t.Helper()
+	digest, err := tests.PushOCIArtifact(
+		host,
+		repository,
+		tag,
+		&tests.OCIBlob{MediaType: configMediaType, Data: []byte(config)},
+		&tests.OCIBlob{MediaType: layerMediaType, Data: []byte(layer)},
+	)
+	require.NoError(t, err)
+	return digest
+}
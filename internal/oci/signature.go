@@ -0,0 +1,217 @@
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const (
+	// cosignPayloadMediaType represents the media type of the layers that
+	// contain the payloads signed in cosign signatures artifacts.
+	cosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// cosignSignatureAnnotation represents the annotation of the payload
+	// layer that contains its base64 encoded signature.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignSignatureTagSuffix represents the suffix of the tag used to
+	// store the cosign signatures of an artifact.
+	cosignSignatureTagSuffix = ".sig"
+)
+
+var (
+	// ErrInvalidCosignKey indicates that the cosign public key provided is
+	// not valid.
+	ErrInvalidCosignKey = errors.New("invalid cosign key")
+
+	// errInvalidSignature indicates that the signature could not be verified
+	// using the key provided.
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// cosignPublicKey represents a public key used to verify cosign signatures.
+type cosignPublicKey struct {
+	key         crypto.PublicKey
+	fingerprint string
+}
+
+// cosignPayload represents the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// SignatureVerifier provides a mechanism to verify the cosign signatures of
+// artifacts stored in OCI registries.
+type SignatureVerifier struct{}
+
+// Verify verifies the cosign signatures of the artifact reference provided
+// using the PEM encoded public keys given. When the artifact has not been
+// signed, no verification is returned. The artifact is considered verified
+// when at least one of its signatures was created with any of the keys and
+// it refers to the artifact's manifest digest.
+func (sv *SignatureVerifier) Verify(
+	ctx context.Context,
+	ref string,
+	keys []string,
+	username,
+	password string,
+) (*hub.CosignVerification, error) {
+	pubKeys, err := parseCosignKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	r, err := name.ParseReference(strings.TrimPrefix(ref, hub.RepositoryOCIPrefix))
+	if err != nil {
+		return nil, err
+	}
+	options := []remote.Option{remote.WithContext(ctx)}
+	options = append(options, authOptions(username, password)...)
+
+	// Get artifact's manifest digest
+	desc, err := remote.Head(r, options...)
+	if err != nil {
+		return nil, err
+	}
+	digest := desc.Digest.String()
+
+	// Get artifact's signatures
+	sigTag := r.Context().Tag(strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix)
+	sigImg, err := remote.Image(sigTag, options...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	manifest, err := sigImg.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify signatures
+	v := &hub.CosignVerification{
+		Digest: digest,
+	}
+	for _, layerDesc := range manifest.Layers {
+		if string(layerDesc.MediaType) != cosignPayloadMediaType {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(layerDesc.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		layer, err := sigImg.LayerByDigest(layerDesc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		var p *cosignPayload
+		if err := json.Unmarshal(payload, &p); err != nil || p == nil {
+			continue
+		}
+		if p.Critical.Image.DockerManifestDigest != digest {
+			continue
+		}
+		for _, pubKey := range pubKeys {
+			if err := verifySignature(pubKey.key, payload, sig); err == nil {
+				v.Verified = true
+				v.KeyFingerprint = pubKey.fingerprint
+				return v, nil
+			}
+		}
+	}
+	return v, nil
+}
+
+// ValidateCosignKey checks that the PEM encoded public key provided can be
+// used to verify cosign signatures.
+func ValidateCosignKey(key string) error {
+	_, err := parseCosignKey(key)
+	return err
+}
+
+// parseCosignKeys parses the PEM encoded public keys provided.
+func parseCosignKeys(keys []string) ([]*cosignPublicKey, error) {
+	pubKeys := make([]*cosignPublicKey, 0, len(keys))
+	for _, key := range keys {
+		pubKey, err := parseCosignKey(key)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
+// parseCosignKey parses the PEM encoded public key provided.
+func parseCosignKey(key string) (*cosignPublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, ErrInvalidCosignKey
+	}
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCosignKey, err.Error())
+	}
+	switch pubKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidCosignKey, pubKey)
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return &cosignPublicKey{
+		key:         pubKey,
+		fingerprint: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// verifySignature verifies that the signature provided was created signing
+// the payload given using the private key corresponding to the public key.
+func verifySignature(pubKey crypto.PublicKey, payload, sig []byte) error {
+	h := sha256.Sum256(payload)
+	switch k := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, h[:], sig) {
+			return errInvalidSignature
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig); err != nil {
+			return errInvalidSignature
+		}
+		return nil
+	default:
+		return errInvalidSignature
+	}
+}
@@ -0,0 +1,131 @@
+package oci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureVerifier(t *testing.T) {
+	ctx := context.Background()
+	host := setupRegistry(t)
+	key1, key1PEM, key1Fingerprint := newTestCosignKey(t)
+	_, key2PEM, _ := newTestCosignKey(t)
+
+	// Unsigned artifact
+	pushArtifact(t, host, "repo/unsigned", "1.0.0", "config", "layer")
+
+	// Signed artifact
+	digest := pushArtifact(t, host, "repo/signed", "1.0.0", "config", "layer")
+	pushCosignSignature(t, host, "repo/signed", digest, digest, key1)
+
+	// Artifact with a signature referring to a different digest
+	digest = pushArtifact(t, host, "repo/mismatch", "1.0.0", "config", "layer2")
+	pushCosignSignature(t, host, "repo/mismatch", digest, "sha256:0123", key1)
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, host+"/repo/signed:1.0.0", []string{"invalid"}, "", "")
+		assert.True(t, errors.Is(err, ErrInvalidCosignKey))
+		assert.Nil(t, v)
+	})
+
+	t.Run("reference not found", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, host+"/repo/signed:2.0.0", []string{key1PEM}, "", "")
+		assert.Error(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("artifact not signed", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, host+"/repo/unsigned:1.0.0", []string{key1PEM}, "", "")
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("artifact signed with an unknown key", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, hub.RepositoryOCIPrefix+host+"/repo/signed:1.0.0", []string{key2PEM}, "", "")
+		assert.NoError(t, err)
+		require.NotNil(t, v)
+		assert.False(t, v.Verified)
+		assert.Empty(t, v.KeyFingerprint)
+	})
+
+	t.Run("signature refers to a different digest", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, host+"/repo/mismatch:1.0.0", []string{key1PEM}, "", "")
+		assert.NoError(t, err)
+		require.NotNil(t, v)
+		assert.False(t, v.Verified)
+	})
+
+	t.Run("artifact signature verified", func(t *testing.T) {
+		t.Parallel()
+		sv := &SignatureVerifier{}
+		v, err := sv.Verify(ctx, hub.RepositoryOCIPrefix+host+"/repo/signed:1.0.0", []string{key2PEM, key1PEM}, "", "")
+		assert.NoError(t, err)
+		require.NotNil(t, v)
+		assert.True(t, v.Verified)
+		assert.True(t, strings.HasPrefix(v.Digest, "sha256:"))
+		assert.Equal(t, key1Fingerprint, v.KeyFingerprint)
+	})
+}
+
+func newTestCosignKey(t *testing.T) (*ecdsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	sum := sha256.Sum256(der)
+	return key, string(keyPEM), hex.EncodeToString(sum[:])
+}
+
+func pushCosignSignature(t *testing.T, host, repository, digest, signedDigest string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(
+		`{"critical":{"identity":{"docker-reference":"%s/%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		host, repository, signedDigest,
+	))
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	require.NoError(t, err)
+	tag := strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix
+	_, err = tests.PushOCIArtifact(
+		host,
+		repository,
+		tag,
+		&tests.OCIBlob{MediaType: "application/vnd.oci.image.config.v1+json", Data: []byte("{}")},
+		&tests.OCIBlob{
+			MediaType: cosignPayloadMediaType,
+			Data:      payload,
+			Annotations: map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+			},
+		},
+	)
+	require.NoError(t, err)
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/sbom"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/satori/uuid"
 )
@@ -18,11 +20,14 @@ const (
 	getHarborReplicationDumpDBQ     = `select get_harbor_replication_dump()`
 	getPkgDBQ                       = `select get_package($1::jsonb)`
 	getPkgChangeLogDBQ              = `select get_package_changelog($1::uuid)`
+	getPkgDependenciesDBQ           = `select get_package_dependencies($1::uuid, $2::text)`
+	getPkgDependentsDBQ             = `select get_package_dependents($1::uuid)`
 	getPkgStarsDBQ                  = `select get_package_stars($1::uuid, $2::uuid)`
 	getPkgsStarredByUserDBQ         = `select get_packages_starred_by_user($1::uuid)`
 	getPkgsStatsDBQ                 = `select get_packages_stats()`
+	getSnapshotSBOMDBQ              = `select sbom->$3::text from snapshot where package_id = $1 and version = $2 and sbom is not null`
 	getSnapshotSecurityReportDBQ    = `select security_report from snapshot where package_id = $1 and version = $2`
-	getSnapshotsToScanDBQ           = `select get_snapshots_to_scan()`
+	getSnapshotsToScanDBQ           = `select get_snapshots_to_scan($1::int)`
 	getRandomPkgsDBQ                = `select get_random_packages()`
 	getValuesSchemaDBQ              = `select values_schema from snapshot where package_id = $1 and version = $2`
 	registerPkgDBQ                  = `select register_package($1::jsonb)`
@@ -74,6 +79,18 @@ func (m *Manager) GetChangeLogJSON(ctx context.Context, pkgID string) ([]byte, e
 	return util.DBQueryJSON(ctx, m.db, getPkgChangeLogDBQ, pkgID)
 }
 
+// GetDependenciesJSON returns the dependencies of the package's snapshot
+// identified by the package id and version provided.
+func (m *Manager) GetDependenciesJSON(ctx context.Context, pkgID, version string) ([]byte, error) {
+	return util.DBQueryJSON(ctx, m.db, getPkgDependenciesDBQ, pkgID, version)
+}
+
+// GetDependentsJSON returns the packages whose latest version depends on the
+// package identified by the id provided.
+func (m *Manager) GetDependentsJSON(ctx context.Context, pkgID string) ([]byte, error) {
+	return util.DBQueryJSON(ctx, m.db, getPkgDependentsDBQ, pkgID)
+}
+
 // GetHarborReplicationDumpJSON returns a json list with all packages versions
 // of kind Helm available so that they can be synchronized in Harbor.
 func (m *Manager) GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error) {
@@ -99,6 +116,16 @@ func (m *Manager) GetRandomJSON(ctx context.Context) ([]byte, error) {
 	return util.DBQueryJSON(ctx, m.db, getRandomPkgsDBQ)
 }
 
+// GetSnapshotSBOMJSON returns the software bill of materials of the package's
+// snapshot identified by the package id and version provided, in the format
+// requested.
+func (m *Manager) GetSnapshotSBOMJSON(ctx context.Context, pkgID, version, format string) ([]byte, error) {
+	if !sbom.IsValidFormat(format) {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid format")
+	}
+	return util.DBQueryJSON(ctx, m.db, getSnapshotSBOMDBQ, pkgID, version, format)
+}
+
 // GetSnapshotSecurityReportJSON returns the security report of the package's
 // snapshot identified by the package id and version provided.
 func (m *Manager) GetSnapshotSecurityReportJSON(ctx context.Context, pkgID, version string) ([]byte, error) {
@@ -106,10 +133,15 @@ func (m *Manager) GetSnapshotSecurityReportJSON(ctx context.Context, pkgID, vers
 }
 
 // GetSnapshotsToScan returns the packages' snapshots that need to be scanned
-// for security vulnerabilities.
-func (m *Manager) GetSnapshotsToScan(ctx context.Context) ([]*hub.SnapshotToScan, error) {
+// for security vulnerabilities. Snapshots never scanned before are returned,
+// as well as the ones whose security report is older than the maximum age
+// provided.
+func (m *Manager) GetSnapshotsToScan(
+	ctx context.Context,
+	reportMaxAge time.Duration,
+) ([]*hub.SnapshotToScan, error) {
 	var s []*hub.SnapshotToScan
-	err := util.DBQueryUnmarshal(ctx, m.db, &s, getSnapshotsToScanDBQ)
+	err := util.DBQueryUnmarshal(ctx, m.db, &s, getSnapshotsToScanDBQ, int(reportMaxAge.Minutes()))
 	return s, err
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
@@ -260,6 +261,62 @@ func TestGetChangeLogJSON(t *testing.T) {
 	})
 }
 
+func TestGetDependenciesJSON(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependenciesDBQ, "pkg1", "1.0.0").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesJSON(ctx, "pkg1", "1.0.0")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependenciesDBQ, "pkg1", "1.0.0").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesJSON(ctx, "pkg1", "1.0.0")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetDependentsJSON(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependentsDBQ, "pkg1").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependentsJSON(ctx, "pkg1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependentsDBQ, "pkg1").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependentsJSON(ctx, "pkg1")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetHarborReplicationDumpJSON(t *testing.T) {
 	ctx := context.Background()
 
@@ -351,6 +408,42 @@ func TestGetRandomJSON(t *testing.T) {
 	})
 }
 
+func TestGetSnapshotSBOMJSON(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid format", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		dataJSON, err := m.GetSnapshotSBOMJSON(ctx, "pkg1", "1.0.0", "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Nil(t, dataJSON)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSnapshotSBOMDBQ, "pkg1", "1.0.0", "spdx").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetSnapshotSBOMJSON(ctx, "pkg1", "1.0.0", "spdx")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSnapshotSBOMDBQ, "pkg1", "1.0.0", "cyclonedx").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetSnapshotSBOMJSON(ctx, "pkg1", "1.0.0", "cyclonedx")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetSnapshotSecurityReportJSON(t *testing.T) {
 	ctx := context.Background()
 
@@ -385,10 +478,10 @@ func TestGetSnapshotsToScan(t *testing.T) {
 	t.Run("database error", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getSnapshotsToScanDBQ).Return(nil, tests.ErrFakeDB)
+		db.On("QueryRow", ctx, getSnapshotsToScanDBQ, 10080).Return(nil, tests.ErrFakeDB)
 		m := NewManager(db)
 
-		s, err := m.GetSnapshotsToScan(ctx)
+		s, err := m.GetSnapshotsToScan(ctx, 7*24*time.Hour)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, s)
 		db.AssertExpectations(t)
@@ -397,7 +490,7 @@ func TestGetSnapshotsToScan(t *testing.T) {
 	t.Run("database query succeeded", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getSnapshotsToScanDBQ).Return([]byte(`
+		db.On("QueryRow", ctx, getSnapshotsToScanDBQ, 10080).Return([]byte(`
 		[
 			{
 				"package_id": "00000000-0000-0000-0000-000000000001",
@@ -413,7 +506,7 @@ func TestGetSnapshotsToScan(t *testing.T) {
 		`), nil)
 		m := NewManager(db)
 
-		s, err := m.GetSnapshotsToScan(ctx)
+		s, err := m.GetSnapshotsToScan(ctx, 7*24*time.Hour)
 		assert.NoError(t, err)
 		require.Len(t, s, 1)
 		assert.Equal(t, "00000000-0000-0000-0000-000000000001", s[0].PackageID)
@@ -952,10 +1045,11 @@ func TestUpdateSnapshotSecurityReport(t *testing.T) {
 			High:   2,
 			Medium: 1,
 		},
-		Full: map[string][]interface{}{
+		Full: map[string][]*hub.SecurityReportTarget{
 			"organization/image:tag": {
-				map[string]interface{}{
-					"k": "v",
+				{
+					Target: "target",
+					Type:   "type",
 				},
 			},
 		},
@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"context"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/stretchr/testify/mock"
@@ -26,6 +27,20 @@ func (m *ManagerMock) GetChangeLogJSON(ctx context.Context, pkgID string) ([]byt
 	return data, args.Error(1)
 }
 
+// GetDependenciesJSON implements the PackageManager interface.
+func (m *ManagerMock) GetDependenciesJSON(ctx context.Context, pkgID, version string) ([]byte, error) {
+	args := m.Called(ctx, pkgID, version)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetDependentsJSON implements the PackageManager interface.
+func (m *ManagerMock) GetDependentsJSON(ctx context.Context, pkgID string) ([]byte, error) {
+	args := m.Called(ctx, pkgID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetHarborReplicationDumpJSON implements the PackageManager interface.
 func (m *ManagerMock) GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error) {
 	args := m.Called(ctx)
@@ -47,6 +62,13 @@ func (m *ManagerMock) GetRandomJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetSnapshotSBOMJSON implements the PackageManager interface.
+func (m *ManagerMock) GetSnapshotSBOMJSON(ctx context.Context, pkgID, version, format string) ([]byte, error) {
+	args := m.Called(ctx, pkgID, version, format)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetSnapshotSecurityReportJSON implements the PackageManager interface.
 func (m *ManagerMock) GetSnapshotSecurityReportJSON(ctx context.Context, pkgID, version string) ([]byte, error) {
 	args := m.Called(ctx, pkgID, version)
@@ -55,8 +77,11 @@ func (m *ManagerMock) GetSnapshotSecurityReportJSON(ctx context.Context, pkgID,
 }
 
 // GetSnapshotsToScan implements the PackageManager interface.
-func (m *ManagerMock) GetSnapshotsToScan(ctx context.Context) ([]*hub.SnapshotToScan, error) {
-	args := m.Called(ctx)
+func (m *ManagerMock) GetSnapshotsToScan(
+	ctx context.Context,
+	reportMaxAge time.Duration,
+) ([]*hub.SnapshotToScan, error) {
+	args := m.Called(ctx, reportMaxAge)
 	data, _ := args.Get(0).([]*hub.SnapshotToScan)
 	return data, args.Error(1)
 }
@@ -4,15 +4,23 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 const (
 	defaultBranch = "master"
+
+	// lsRemoteTimeout represents the maximum amount of time getting the
+	// references from a remote git repository can take.
+	lsRemoteTimeout = 30 * time.Second
 )
 
 // Cloner is a hub.RepositoryCloner implementation.
@@ -21,19 +29,9 @@ type Cloner struct{}
 // CloneRepository implements the hub.RepositoryCloner interface.
 func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string, string, error) {
 	// Parse repository url
-	var repoBaseURL, packagesPath string
-	switch r.Kind {
-	case hub.Falco, hub.HelmPlugin, hub.Krew, hub.OLM, hub.OPA, hub.TBAction:
-		matches := GitRepoURLRE.FindStringSubmatch(r.URL)
-		if len(matches) < 2 {
-			return "", "", fmt.Errorf("invalid repository url")
-		}
-		if len(matches) >= 3 {
-			repoBaseURL = matches[1]
-		}
-		if len(matches) == 4 {
-			packagesPath = strings.TrimSuffix(matches[3], "/")
-		}
+	repoBaseURL, packagesPath, err := parseGitRepoURL(r)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Clone git repository
@@ -41,13 +39,9 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 	if err != nil {
 		return "", "", fmt.Errorf("error creating temp dir: %w", err)
 	}
-	branch := r.Branch
-	if branch == "" {
-		branch = defaultBranch
-	}
 	_, err = git.PlainCloneContext(ctx, tmpDir, false, &git.CloneOptions{
 		URL:           repoBaseURL,
-		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		ReferenceName: plumbing.NewBranchReferenceName(getBranch(r)),
 		SingleBranch:  true,
 		Depth:         1,
 	})
@@ -57,3 +51,101 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 
 	return tmpDir, packagesPath, nil
 }
+
+// HeadGetter is a hub.RepositoryHeadGetter implementation.
+type HeadGetter struct{}
+
+// GetHead implements the hub.RepositoryHeadGetter interface.
+func (g *HeadGetter) GetHead(ctx context.Context, r *hub.Repository) (string, error) {
+	repoBaseURL, _, err := parseGitRepoURL(r)
+	if err != nil {
+		return "", err
+	}
+	return lsRemote(ctx, repoBaseURL, getBranch(r))
+}
+
+// lsRemote returns the commit the branch provided is pointing to in the remote
+// git repository located at the url given. Only the references advertised by
+// the remote are fetched, so this is much cheaper than cloning the repository.
+// The request to the remote is bound to the context provided and limited by
+// lsRemoteTimeout.
+func lsRemote(ctx context.Context, repoURL, branch string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, lsRemoteTimeout)
+	defer cancel()
+
+	// Get references advertised by the remote
+	ep, err := transport.NewEndpoint(repoURL)
+	if err != nil {
+		return "", err
+	}
+	cli := githttp.NewClient(&http.Client{
+		Transport: &contextTransport{ctx: ctx, base: http.DefaultTransport},
+	})
+	sess, err := cli.NewUploadPackSession(ep, nil)
+	if err != nil {
+		return "", err
+	}
+	defer sess.Close()
+	ar, err := sess.AdvertisedReferences()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	refs, err := ar.AllReferences()
+	if err != nil {
+		return "", err
+	}
+
+	// Find the commit the branch is pointing to
+	ref, ok := refs[plumbing.NewBranchReferenceName(branch)]
+	if !ok {
+		return "", fmt.Errorf("branch %s not found in remote repository", branch)
+	}
+	return ref.Hash().String(), nil
+}
+
+// contextTransport is an http.RoundTripper that binds the requests to the
+// context provided, as the git http transport does not support contexts yet.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// parseGitRepoURL extracts the repository base url and the path where the
+// packages are located from the url of the git based repository provided.
+func parseGitRepoURL(r *hub.Repository) (string, string, error) {
+	var repoBaseURL, packagesPath string
+	switch r.Kind {
+	case hub.Falco, hub.HelmPlugin, hub.Krew, hub.OLM, hub.OPA, hub.TBAction:
+		matches := GitRepoURLRE.FindStringSubmatch(r.URL)
+		if len(matches) < 2 {
+			return "", "", fmt.Errorf("invalid repository url")
+		}
+		if len(matches) >= 3 {
+			repoBaseURL = matches[1]
+		}
+		if len(matches) == 4 {
+			packagesPath = strings.TrimSuffix(matches[3], "/")
+		}
+	}
+	return repoBaseURL, packagesPath, nil
+}
+
+// getBranch returns the branch that should be used for the repository
+// provided, falling back to the default one when none has been set.
+func getBranch(r *hub.Repository) string {
+	if r.Branch != "" {
+		return r.Branch
+	}
+	return defaultBranch
+}
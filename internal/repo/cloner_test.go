@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadGetter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid repository url", func(t *testing.T) {
+		t.Parallel()
+		g := &HeadGetter{}
+		r := &hub.Repository{
+			Kind: hub.OPA,
+			URL:  "https://example.com/org/repo",
+		}
+		head, err := g.GetHead(ctx, r)
+		assert.Error(t, err)
+		assert.Empty(t, head)
+	})
+
+	t.Run("ls-remote: branch not found", func(t *testing.T) {
+		t.Parallel()
+		repoURL, _ := setupGitServer(t)
+		head, err := lsRemote(ctx, repoURL, "not-found")
+		assert.EqualError(t, err, "branch not-found not found in remote repository")
+		assert.Empty(t, head)
+	})
+
+	t.Run("ls-remote: context cancelled", func(t *testing.T) {
+		t.Parallel()
+		repoURL, _ := setupGitServer(t)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		head, err := lsRemote(ctx, repoURL, defaultBranch)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, head)
+	})
+
+	t.Run("ls-remote: deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		t.Cleanup(s.Close)
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		head, err := lsRemote(ctx, s.URL+"/org/repo", defaultBranch)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Empty(t, head)
+	})
+
+	t.Run("ls-remote: success", func(t *testing.T) {
+		t.Parallel()
+		repoURL, commitHash := setupGitServer(t)
+		head, err := lsRemote(ctx, repoURL, defaultBranch)
+		assert.NoError(t, err)
+		assert.Equal(t, commitHash, head)
+	})
+}
+
+// setupGitServer creates a git repository with a single commit in the default
+// branch and serves it using the git smart http protocol. It returns the url
+// of the repository and the hash of the commit created.
+func setupGitServer(t *testing.T) (string, string) {
+	t.Helper()
+
+	// Create git repository
+	dir, err := ioutil.TempDir("", "artifact-hub-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	gitRepo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	gitCfg, err := gitRepo.Config()
+	require.NoError(t, err)
+	require.NoError(t, gitRepo.Storer.SetConfig(gitCfg))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644))
+	wt, err := gitRepo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add("README.md")
+	require.NoError(t, err)
+	commitHash, err := wt.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@email.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	head, err := gitRepo.Head()
+	require.NoError(t, err)
+	if head.Name() != plumbing.NewBranchReferenceName(defaultBranch) {
+		ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(defaultBranch), commitHash)
+		require.NoError(t, gitRepo.Storer.SetReference(ref))
+	}
+
+	// Serve git repository references using the smart http protocol
+	gitServer := server.NewServer(server.NewFilesystemLoader(osfs.New(filepath.Join(dir, git.GitDirName))))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/repo/info/refs" || r.URL.Query().Get("service") != transport.UploadPackServiceName {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sess, err := gitServer.NewUploadPackSession(&transport.Endpoint{Path: "/"}, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ar, err := sess.AdvertisedReferences()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ar.Prefix = [][]byte{
+			[]byte("# service=" + transport.UploadPackServiceName),
+			pktline.Flush,
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		_ = ar.Encode(w)
+	}))
+	t.Cleanup(s.Close)
+
+	return s.URL + "/org/repo", commitHash.String()
+}
@@ -14,17 +14,17 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	"github.com/google/go-github/github"
 	"github.com/satori/uuid"
 	"github.com/spf13/viper"
-	"golang.org/x/oauth2"
 	"gopkg.in/yaml.v2"
 )
 
@@ -40,13 +40,23 @@ const (
 	getRepoByNameDBQ          = `select get_repository_by_name($1::text, $2::boolean)`
 	getRepoPkgsDigestDBQ      = `select get_repository_packages_digest($1::uuid)`
 	getReposByKindDBQ         = `select get_repositories_by_kind($1::int, $2::boolean)`
+	getReposToTrackDBQ        = `select get_repositories_to_track($1::int)`
+	getRepoTrackingRunsDBQ    = `select get_repository_tracking_runs($1::uuid, $2::text)`
 	getUserReposDBQ           = `select get_user_repositories($1::uuid, $2::boolean)`
 	getUserEmailDBQ           = `select email from "user" where user_id = $1`
+	requestRepoTrackingDBQ    = `select request_repository_tracking($1::uuid, $2::text)`
 	setLastTrackingResultsDBQ = `select set_last_tracking_results($1::uuid, $2::text, $3::boolean)`
 	setVerifiedPublisherDBQ   = `select set_verified_publisher($1::uuid, $2::boolean)`
+	setVulnAllowlistDBQ       = `select set_repository_vulnerability_allowlist($1::uuid, $2::jsonb)`
+	startRepoTrackingDBQ      = `select start_repository_tracking($1::uuid)`
 	transferRepoDBQ           = `select transfer_repository($1::text, $2::uuid, $3::text, $4::boolean)`
 	updateRepoDBQ             = `select update_repository($1::uuid, $2::jsonb)`
 	updateRepoDigestDBQ       = `update repository set digest = $2 where repository_id = $1`
+	updateRepoKeysDigestDBQ   = `update repository set signing_keys_digest = nullif($2, '') where repository_id = $1`
+
+	// minTrackingInterval represents the minimum tracking interval (in
+	// minutes) that can be set for a repository.
+	minTrackingInterval = 5
 )
 
 var (
@@ -77,8 +87,10 @@ type Manager struct {
 	hg              HTTPGetter
 	rc              hub.RepositoryCloner
 	helmIndexLoader hub.HelmIndexLoader
+	tg              hub.OCITagsGetter
+	op              hub.OCIPuller
+	rh              hub.RepositoryHeadGetter
 	az              hub.Authorizer
-	gh              *github.Client
 }
 
 // NewManager creates a new Manager instance.
@@ -94,26 +106,26 @@ func NewManager(cfg *viper.Viper, db hub.DB, az hub.Authorizer, opts ...func(m *
 		o(m)
 	}
 
-	// Setup Github token
-	githubToken := cfg.GetString("tracker.githubToken")
-	if githubToken != "" {
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: githubToken},
-		)
-		m.gh = github.NewClient(oauth2.NewClient(context.TODO(), ts))
-	} else {
-		m.gh = github.NewClient(http.DefaultClient)
-	}
-
 	// Setup HTTP getter
 	if m.hg == nil {
 		m.hg = &http.Client{Timeout: 10 * time.Second}
 	}
 
-	// Setup repository cloner
+	// Setup repository cloner and head getter
 	if m.rc == nil {
 		m.rc = &Cloner{}
 	}
+	if m.rh == nil {
+		m.rh = &HeadGetter{}
+	}
+
+	// Setup OCI tags getter and puller
+	if m.tg == nil {
+		m.tg = &oci.TagsGetter{}
+	}
+	if m.op == nil {
+		m.op = &oci.Puller{}
+	}
 
 	return m
 }
@@ -126,6 +138,30 @@ func WithHelmIndexLoader(l hub.HelmIndexLoader) func(m *Manager) {
 	}
 }
 
+// WithRepositoryHeadGetter allows providing a specific RepositoryHeadGetter
+// implementation for a Manager instance.
+func WithRepositoryHeadGetter(rh hub.RepositoryHeadGetter) func(m *Manager) {
+	return func(m *Manager) {
+		m.rh = rh
+	}
+}
+
+// WithOCITagsGetter allows providing a specific OCITagsGetter implementation
+// for a Manager instance.
+func WithOCITagsGetter(tg hub.OCITagsGetter) func(m *Manager) {
+	return func(m *Manager) {
+		m.tg = tg
+	}
+}
+
+// WithOCIPuller allows providing a specific OCIPuller implementation for a
+// Manager instance.
+func WithOCIPuller(op hub.OCIPuller) func(m *Manager) {
+	return func(m *Manager) {
+		m.op = op
+	}
+}
+
 // Add adds the provided repository to the database.
 func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -146,6 +182,9 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 	if err := m.validateCredentials(r); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
 	}
+	if err := validateTrackingInterval(r); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+	}
 
 	// Authorize action if the repository will be added to an organization
 	if orgName != "" {
@@ -480,6 +519,25 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 		hash := sha256.Sum256(indexBytes)
 		digest = hex.EncodeToString(hash[:])
 
+	case r.Kind == hub.Helm && u.Scheme == "oci":
+		// Digest is obtained hashing the list of tags available along with
+		// the digest of the manifest each of them points to
+		tags, err := m.tg.Tags(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(tags)
+		var manifests strings.Builder
+		for _, tag := range tags {
+			manifestDigest, err := m.op.Digest(ctx, r.URL+":"+tag, r.AuthUser, r.AuthPass)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&manifests, "%s@%s\n", tag, manifestDigest)
+		}
+		hash := sha256.Sum256([]byte(manifests.String()))
+		digest = hex.EncodeToString(hash[:])
+
 	case r.Kind == hub.OLM && u.Scheme == "oci":
 		// Digest is obtained from the index image digest
 		refName := strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix)
@@ -493,32 +551,84 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 		}
 		digest = desc.Digest.String()
 
-	case SchemeIsHTTP(u) && u.Host == "github.com":
-		// Digest is obtained from the last commit in the repository
-		pathParts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
-		if len(pathParts) < 2 {
-			break
-		}
-		owner := pathParts[0]
-		repo := pathParts[1]
-		opt := &github.CommitsListOptions{
-			ListOptions: github.ListOptions{
-				Page:    0,
-				PerPage: 1,
-			},
-		}
-		commits, _, err := m.gh.Repositories.ListCommits(ctx, owner, repo, opt)
+	case SchemeIsHTTP(u) && r.Kind != hub.Helm:
+		// Digest is obtained from the commit the repository branch points to
+		headCommit, err := m.rh.GetHead(ctx, r)
 		if err != nil {
-			return digest, err
-		}
-		if len(commits) == 1 {
-			digest = *commits[0].SHA
+			return "", err
 		}
+		digest = headCommit
 	}
 
 	return digest, nil
 }
 
+// GetToTrack returns the repositories that should be tracked now, either
+// because their tracking interval has elapsed since they were last tracked or
+// because a tracking request has been queued for them. The default tracking
+// interval provided is used for repositories that don't have one set.
+func (m *Manager) GetToTrack(
+	ctx context.Context,
+	defaultTrackingInterval time.Duration,
+) ([]*hub.Repository, error) {
+	var r []*hub.Repository
+	interval := int(defaultTrackingInterval.Minutes())
+	err := util.DBQueryUnmarshal(ctx, m.db, &r, getReposToTrackDBQ, interval)
+	return r, err
+}
+
+// GetTrackingRunsJSON returns the most recent tracking runs of the provided
+// repository as a json array. The user doing the request must be the owner of
+// the repository or belong to the organization which owns it.
+func (m *Manager) GetTrackingRunsJSON(ctx context.Context, name string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Get repository tracking runs from database
+	dataJSON, err := util.DBQueryJSON(ctx, m.db, getRepoTrackingRunsDBQ, userID, name)
+	if err != nil && err.Error() == util.ErrDBNoDataFound.Error() {
+		return nil, hub.ErrNotFound
+	}
+	return dataJSON, err
+}
+
+// RequestTracking queues a tracking request for the provided repository, so
+// that it's tracked as soon as possible by the tracker.
+func (m *Manager) RequestTracking(ctx context.Context, name string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Authorize action if the repository is owned by an organization
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return err
+	}
+	if r.OrganizationName != "" {
+		if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+			OrganizationName: r.OrganizationName,
+			UserID:           userID,
+			Action:           hub.TrackOrganizationRepository,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Queue tracking request in database
+	_, err = m.db.Exec(ctx, requestRepoTrackingDBQ, userID, name)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // SetLastTrackingResults updates the timestamp and errors of the last tracking
 // of the provided repository in the database.
 func (m *Manager) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
@@ -546,6 +656,37 @@ func (m *Manager) SetVerifiedPublisher(ctx context.Context, repositoryID string,
 	return err
 }
 
+// SetVulnerabilityAllowlist updates the vulnerability allowlist of the
+// provided repository in the database.
+func (m *Manager) SetVulnerabilityAllowlist(
+	ctx context.Context,
+	repositoryID string,
+	allowlist []*hub.VulnerabilityAllowlistEntry,
+) error {
+	// Validate input
+	if _, err := uuid.FromString(repositoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+
+	// Update vulnerability allowlist in database
+	allowlistJSON, _ := json.Marshal(allowlist)
+	_, err := m.db.Exec(ctx, setVulnAllowlistDBQ, repositoryID, allowlistJSON)
+	return err
+}
+
+// StartTracking registers that the tracking of the provided repository has
+// started, clearing any pending tracking request.
+func (m *Manager) StartTracking(ctx context.Context, repositoryID string) error {
+	// Validate input
+	if _, err := uuid.FromString(repositoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+
+	// Register tracking start in database
+	_, err := m.db.Exec(ctx, startRepoTrackingDBQ, repositoryID)
+	return err
+}
+
 // Transfer transfers the provided repository to a different owner. A user
 // owned repo can be transferred to an organization the requesting user belongs
 // to. An org owned repo can be transfer to the requesting user, provided the
@@ -607,6 +748,9 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 	if err := m.validateCredentials(r); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
 	}
+	if err := validateTrackingInterval(r); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+	}
 
 	// Authorize action if the repository is owned by an organization
 	rBefore, err := m.GetByName(ctx, r.Name, false)
@@ -638,6 +782,13 @@ func (m *Manager) UpdateDigest(ctx context.Context, repositoryID, digest string)
 	return err
 }
 
+// UpdateSigningKeysDigest updates the digest of the signing keys of the
+// provided repository in the database.
+func (m *Manager) UpdateSigningKeysDigest(ctx context.Context, repositoryID, digest string) error {
+	_, err := m.db.Exec(ctx, updateRepoKeysDigestDBQ, repositoryID, digest)
+	return err
+}
+
 // validateURL validates the url of the repository provided.
 func (m *Manager) validateURL(r *hub.Repository) error {
 	if r.URL == "" {
@@ -680,6 +831,15 @@ func (m *Manager) validateCredentials(r *hub.Repository) error {
 	return nil
 }
 
+// validateTrackingInterval validates the tracking interval of the repository
+// provided. A zero value means the default tracking interval will be used.
+func validateTrackingInterval(r *hub.Repository) error {
+	if r.TrackingInterval != 0 && r.TrackingInterval < minTrackingInterval {
+		return fmt.Errorf("tracking interval must be at least %d minutes", minTrackingInterval)
+	}
+	return nil
+}
+
 // SchemeIsHTTP is a helper that checks if the scheme of the url provided is
 // http or https.
 func SchemeIsHTTP(u *url.URL) bool {
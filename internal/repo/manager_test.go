@@ -2,6 +2,8 @@ package repo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,9 +13,11 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/spf13/viper"
@@ -171,6 +175,17 @@ func TestAdd(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"tracking interval must be at least",
+				"org1",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: 1,
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -1202,6 +1217,16 @@ func TestGetRemoteDigest(t *testing.T) {
 		Name: "repo1",
 		URL:  "https://myrepo.url",
 	}
+	helmOCI := &hub.Repository{
+		Kind: hub.Helm,
+		Name: "repo2",
+		URL:  "oci://registry.url/repo2/chart",
+	}
+	gitRepo := &hub.Repository{
+		Kind: hub.OPA,
+		Name: "repo3",
+		URL:  "https://gitlab.com/org3/repo3/path",
+	}
 
 	t.Run("helm-http: error loading index", func(t *testing.T) {
 		t.Parallel()
@@ -1240,6 +1265,281 @@ func TestGetRemoteDigest(t *testing.T) {
 		assert.Equal(t, "a1cbe8e02116f43084632fbf313c4ed02772f93af327bbc16989a30bc04ddc89", digest)
 		assert.Nil(t, err)
 	})
+
+	t.Run("helm-oci: error getting tags", func(t *testing.T) {
+		t.Parallel()
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCI).Return(nil, tests.ErrFake)
+		m := NewManager(cfg, nil, nil, WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCI)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci: error getting tag digest", func(t *testing.T) {
+		t.Parallel()
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCI).Return([]string{"1.0.0"}, nil)
+		op := &oci.PullerMock{}
+		op.On("Digest", ctx, helmOCI.URL+":1.0.0", "", "").Return("", tests.ErrFake)
+		m := NewManager(cfg, nil, nil, WithOCITagsGetter(tg), WithOCIPuller(op))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCI)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		tg.AssertExpectations(t)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci: success", func(t *testing.T) {
+		t.Parallel()
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCI).Return([]string{"2.0.0", "1.0.0"}, nil)
+		op := &oci.PullerMock{}
+		op.On("Digest", ctx, helmOCI.URL+":1.0.0", "", "").Return("sha256:digest1", nil)
+		op.On("Digest", ctx, helmOCI.URL+":2.0.0", "", "").Return("sha256:digest2", nil)
+		m := NewManager(cfg, nil, nil, WithOCITagsGetter(tg), WithOCIPuller(op))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCI)
+		expectedHash := sha256.Sum256([]byte("1.0.0@sha256:digest1\n2.0.0@sha256:digest2\n"))
+		assert.Equal(t, hex.EncodeToString(expectedHash[:]), digest)
+		assert.Nil(t, err)
+		tg.AssertExpectations(t)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("git: error getting repository head", func(t *testing.T) {
+		t.Parallel()
+		rh := &HeadGetterMock{}
+		rh.On("GetHead", ctx, gitRepo).Return("", tests.ErrFake)
+		m := NewManager(cfg, nil, nil, WithRepositoryHeadGetter(rh))
+
+		digest, err := m.GetRemoteDigest(ctx, gitRepo)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		rh.AssertExpectations(t)
+	})
+
+	t.Run("git: success", func(t *testing.T) {
+		t.Parallel()
+		rh := &HeadGetterMock{}
+		rh.On("GetHead", ctx, gitRepo).Return("commitHash", nil)
+		m := NewManager(cfg, nil, nil, WithRepositoryHeadGetter(rh))
+
+		digest, err := m.GetRemoteDigest(ctx, gitRepo)
+		assert.Equal(t, "commitHash", digest)
+		assert.Nil(t, err)
+		rh.AssertExpectations(t)
+	})
+}
+
+func TestGetToTrack(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposToTrackDBQ, 30).Return([]byte(`
+		[{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"url": "https://repo1.com",
+			"kind": 0,
+			"tracking_interval": 60
+		}]
+		`), nil)
+		m := NewManager(cfg, db, nil)
+
+		r, err := m.GetToTrack(ctx, 30*time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, r, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", r[0].RepositoryID)
+		assert.Equal(t, "repo1", r[0].Name)
+		assert.Equal(t, 60, r[0].TrackingInterval)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposToTrackDBQ, 30).Return(nil, tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		r, err := m.GetToTrack(ctx, 30*time.Minute)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, r)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetTrackingRunsJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetTrackingRunsJSON(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		_, err := m.GetTrackingRunsJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+			{
+				util.ErrDBNoDataFound,
+				hub.ErrNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoTrackingRunsDBQ, "userID", "repo1").Return(nil, tc.dbErr)
+				m := NewManager(cfg, db, nil)
+
+				dataJSON, err := m.GetTrackingRunsJSON(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("repository tracking runs data returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoTrackingRunsDBQ, "userID", "repo1").Return([]byte("dataJSON"), nil)
+		m := NewManager(cfg, db, nil)
+
+		dataJSON, err := m.GetTrackingRunsJSON(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRequestTracking(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.RequestTracking(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		err := m.RequestTracking(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.TrackOrganizationRepository,
+		}).Return(tests.ErrFake)
+		m := NewManager(cfg, db, az)
+
+		err := m.RequestTracking(ctx, "repo1")
+		assert.Equal(t, tests.ErrFake, err)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+				{
+					"repository_id": "00000000-0000-0000-0000-000000000001",
+					"name": "repo1",
+					"organization_name": "orgName"
+				}
+				`), nil)
+				db.On("Exec", ctx, requestRepoTrackingDBQ, "userID", "repo1").Return(tc.dbErr)
+				az := &authz.AuthorizerMock{}
+				az.On("Authorize", ctx, &hub.AuthorizeInput{
+					OrganizationName: "orgName",
+					UserID:           "userID",
+					Action:           hub.TrackOrganizationRepository,
+				}).Return(nil)
+				m := NewManager(cfg, db, az)
+
+				err := m.RequestTracking(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+				az.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("tracking request queued successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1"
+		}
+		`), nil)
+		db.On("Exec", ctx, requestRepoTrackingDBQ, "userID", "repo1").Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.RequestTracking(ctx, "repo1")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestSetLastTrackingResults(t *testing.T) {
@@ -1310,6 +1610,82 @@ func TestSetVerifiedPublisher(t *testing.T) {
 	})
 }
 
+func TestSetVulnerabilityAllowlist(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
+	allowlist := []*hub.VulnerabilityAllowlistEntry{
+		{
+			VulnerabilityID: "CVE-2020-0001",
+			Justification:   "Not exploitable",
+			ExpiresAt:       "2021-01-01",
+		},
+	}
+	allowlistJSON, _ := json.Marshal(allowlist)
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		err := m.SetVulnerabilityAllowlist(ctx, "invalid", allowlist)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setVulnAllowlistDBQ, repoID, allowlistJSON).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetVulnerabilityAllowlist(ctx, repoID, allowlist)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setVulnAllowlistDBQ, repoID, allowlistJSON).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetVulnerabilityAllowlist(ctx, repoID, allowlist)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestStartTracking(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		err := m.StartTracking(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, startRepoTrackingDBQ, repoID).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.StartTracking(ctx, repoID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, startRepoTrackingDBQ, repoID).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.StartTracking(ctx, repoID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestTransfer(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 	userID := "userID"
@@ -1520,6 +1896,16 @@ func TestUpdate(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"tracking interval must be at least",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: -1,
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -1704,6 +2090,34 @@ func TestUpdateDigest(t *testing.T) {
 	})
 }
 
+func TestUpdateSigningKeysDigest(t *testing.T) {
+	ctx := context.Background()
+	repositoryID := "00000000-0000-0000-0000-000000000001"
+	digest := "digest"
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateRepoKeysDigestDBQ, repositoryID, digest).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.UpdateSigningKeysDigest(ctx, repositoryID, digest)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateRepoKeysDigestDBQ, repositoryID, digest).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.UpdateSigningKeysDigest(ctx, repositoryID, digest)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func withHTTPGetter(hg HTTPGetter) func(m *Manager) {
 	return func(m *Manager) {
 		m.hg = hg
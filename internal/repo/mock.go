@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/stretchr/testify/mock"
@@ -19,6 +20,18 @@ func (m *ClonerMock) CloneRepository(ctx context.Context, r *hub.Repository) (st
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+// HeadGetterMock is a mock implementation of the RepositoryHeadGetter
+// interface.
+type HeadGetterMock struct {
+	mock.Mock
+}
+
+// GetHead implements the RepositoryHeadGetter interface.
+func (m *HeadGetterMock) GetHead(ctx context.Context, r *hub.Repository) (string, error) {
+	args := m.Called(ctx, r)
+	return args.String(0), args.Error(1)
+}
+
 // HelmIndexLoaderMock is a mock implementation of the HelmIndexLoader
 // interface.
 type HelmIndexLoaderMock struct {
@@ -160,6 +173,29 @@ func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context, includeCredentials
 	return data, args.Error(1)
 }
 
+// GetToTrack implements the RepositoryManager interface.
+func (m *ManagerMock) GetToTrack(
+	ctx context.Context,
+	defaultTrackingInterval time.Duration,
+) ([]*hub.Repository, error) {
+	args := m.Called(ctx, defaultTrackingInterval)
+	repos, _ := args.Get(0).([]*hub.Repository)
+	return repos, args.Error(1)
+}
+
+// GetTrackingRunsJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetTrackingRunsJSON(ctx context.Context, name string) ([]byte, error) {
+	args := m.Called(ctx, name)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// RequestTracking implements the RepositoryManager interface.
+func (m *ManagerMock) RequestTracking(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
 // SetLastTrackingResults implements the RepositoryManager interface.
 func (m *ManagerMock) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
 	args := m.Called(ctx, repositoryID, errs)
@@ -172,6 +208,22 @@ func (m *ManagerMock) SetVerifiedPublisher(ctx context.Context, repositoryID str
 	return args.Error(0)
 }
 
+// SetVulnerabilityAllowlist implements the RepositoryManager interface.
+func (m *ManagerMock) SetVulnerabilityAllowlist(
+	ctx context.Context,
+	repositoryID string,
+	allowlist []*hub.VulnerabilityAllowlistEntry,
+) error {
+	args := m.Called(ctx, repositoryID, allowlist)
+	return args.Error(0)
+}
+
+// StartTracking implements the RepositoryManager interface.
+func (m *ManagerMock) StartTracking(ctx context.Context, repositoryID string) error {
+	args := m.Called(ctx, repositoryID)
+	return args.Error(0)
+}
+
 // Transfer implements the RepositoryManager interface.
 func (m *ManagerMock) Transfer(ctx context.Context, name, orgName string, ownershipClaim bool) error {
 	args := m.Called(ctx, name, orgName, ownershipClaim)
@@ -190,6 +242,12 @@ func (m *ManagerMock) UpdateDigest(ctx context.Context, repositoryID, digest str
 	return args.Error(0)
 }
 
+// UpdateSigningKeysDigest implements the RepositoryManager interface.
+func (m *ManagerMock) UpdateSigningKeysDigest(ctx context.Context, repositoryID, digest string) error {
+	args := m.Called(ctx, repositoryID, digest)
+	return args.Error(0)
+}
+
 // OLMRepositoryExporterMock is a mock implementation of the
 // OLMRepositoryExporter interface.
 type OLMRepositoryExporterMock struct {
@@ -0,0 +1,129 @@
+package sbom
+
+import (
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/satori/uuid"
+)
+
+const (
+	cycloneDXFormat      = "CycloneDX"
+	cycloneDXSpecVersion = "1.2"
+	cycloneDXRootRef     = "root"
+)
+
+// cycloneDXBOM represents a CycloneDX bill of materials (json format).
+type cycloneDXBOM struct {
+	BOMFormat    string                 `json:"bomFormat"`
+	SpecVersion  string                 `json:"specVersion"`
+	SerialNumber string                 `json:"serialNumber"`
+	Version      int                    `json:"version"`
+	Metadata     *cycloneDXMetadata     `json:"metadata"`
+	Components   []*cycloneDXComponent  `json:"components"`
+	Dependencies []*cycloneDXDependency `json:"dependencies"`
+}
+
+// cycloneDXMetadata represents the metadata of a CycloneDX bill of materials.
+type cycloneDXMetadata struct {
+	Timestamp string               `json:"timestamp"`
+	Tools     []*cycloneDXTool     `json:"tools"`
+	Component *cycloneDXComponent  `json:"component"`
+	Licenses  []*cycloneDXLicenses `json:"licenses,omitempty"`
+}
+
+// cycloneDXTool represents the tool used to create a CycloneDX bill of
+// materials.
+type cycloneDXTool struct {
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+// cycloneDXLicenses represents a license expression in a CycloneDX bill of
+// materials.
+type cycloneDXLicenses struct {
+	Expression string `json:"expression"`
+}
+
+// cycloneDXComponent represents a component in a CycloneDX bill of materials.
+type cycloneDXComponent struct {
+	BOMRef             string                        `json:"bom-ref"`
+	Type               string                        `json:"type"`
+	Name               string                        `json:"name"`
+	Version            string                        `json:"version"`
+	Licenses           []*cycloneDXLicenses          `json:"licenses,omitempty"`
+	PURL               string                        `json:"purl,omitempty"`
+	ExternalReferences []*cycloneDXExternalReference `json:"externalReferences,omitempty"`
+}
+
+// cycloneDXExternalReference represents an external reference of a component
+// in a CycloneDX bill of materials.
+type cycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// cycloneDXDependency represents the dependencies of a component in a
+// CycloneDX bill of materials.
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// newCycloneDXBOM creates a new CycloneDX bill of materials for the package's
+// snapshot provided.
+func newCycloneDXBOM(
+	s *hub.SnapshotToScan,
+	components []*component,
+	serial uuid.UUID,
+	createdAt time.Time,
+) *cycloneDXBOM {
+	root := &cycloneDXComponent{
+		BOMRef:  cycloneDXRootRef,
+		Type:    "application",
+		Name:    s.PackageName,
+		Version: s.Version,
+	}
+	if s.License != "" {
+		root.Licenses = []*cycloneDXLicenses{{Expression: s.License}}
+	}
+	bom := &cycloneDXBOM{
+		BOMFormat:    cycloneDXFormat,
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + serial.String(),
+		Version:      1,
+		Metadata: &cycloneDXMetadata{
+			Timestamp: createdAt.UTC().Format(time.RFC3339),
+			Tools:     []*cycloneDXTool{{Vendor: toolVendor, Name: toolName}},
+			Component: root,
+		},
+		Components: make([]*cycloneDXComponent, 0, len(components)),
+	}
+	rootDependencies := &cycloneDXDependency{
+		Ref:       cycloneDXRootRef,
+		DependsOn: make([]string, 0, len(components)),
+	}
+	for _, c := range components {
+		cc := &cycloneDXComponent{
+			BOMRef:  c.id,
+			Name:    c.name,
+			Version: c.version,
+			PURL:    c.purl,
+		}
+		switch c.kind {
+		case containerImage:
+			cc.Type = "container"
+		case dependency:
+			cc.Type = "application"
+			if c.repository != "" {
+				cc.ExternalReferences = []*cycloneDXExternalReference{
+					{Type: "distribution", URL: c.repository},
+				}
+			}
+		}
+		bom.Components = append(bom.Components, cc)
+		rootDependencies.DependsOn = append(rootDependencies.DependsOn, c.id)
+	}
+	bom.Dependencies = []*cycloneDXDependency{rootDependencies}
+	return bom
+}
@@ -0,0 +1,118 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/satori/uuid"
+)
+
+// Formats supported by the software bills of materials generated.
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+const (
+	// toolName represents the name of the tool that generates the SBOMs.
+	toolName = "artifacthub-scanner"
+
+	// toolVendor represents the vendor of the tool that generates the SBOMs.
+	toolVendor = "Artifact Hub"
+)
+
+// Component kinds.
+const (
+	containerImage = "container-image"
+	dependency     = "dependency"
+)
+
+// component represents an item listed in the software bill of materials of a
+// package's snapshot.
+type component struct {
+	id         string
+	kind       string
+	name       string
+	version    string
+	repository string
+	purl       string
+}
+
+// IsValidFormat checks if the format provided is supported.
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatCycloneDX, FormatSPDX:
+		return true
+	default:
+		return false
+	}
+}
+
+// Generate generates the software bill of materials of the package's snapshot
+// provided in all the supported formats. The SBOM lists the containers images
+// used by the package as well as its dependencies.
+func Generate(s *hub.SnapshotToScan, createdAt time.Time) (*hub.SnapshotSBOM, error) {
+	serial := uuid.NewV5(uuid.NamespaceURL, fmt.Sprintf("%s/%s/%d", s.PackageID, s.Version, createdAt.Unix()))
+	components := getComponents(s)
+
+	cycloneDX, err := json.Marshal(newCycloneDXBOM(s, components, serial, createdAt))
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling cyclonedx sbom: %w", err)
+	}
+	spdx, err := json.Marshal(newSPDXDocument(s, components, serial, createdAt))
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling spdx sbom: %w", err)
+	}
+
+	return &hub.SnapshotSBOM{
+		CycloneDX: cycloneDX,
+		SPDX:      spdx,
+	}, nil
+}
+
+// getComponents returns the components of the package's snapshot provided.
+func getComponents(s *hub.SnapshotToScan) []*component {
+	components := make([]*component, 0, len(s.ContainersImages)+len(s.Dependencies))
+	for _, image := range s.ContainersImages {
+		components = append(components, getImageComponent(image.Image))
+	}
+	for _, d := range s.Dependencies {
+		components = append(components, &component{
+			id:         fmt.Sprintf("%s/%s@%s", dependency, d.Name, d.Version),
+			kind:       dependency,
+			name:       d.Name,
+			version:    d.Version,
+			repository: d.Repository,
+		})
+	}
+	return components
+}
+
+// getImageComponent returns the component corresponding to the container
+// image provided. When the image reference is valid, the component includes
+// its package url (docker type).
+func getImageComponent(image string) *component {
+	c := &component{
+		id:   fmt.Sprintf("%s/%s", containerImage, image),
+		kind: containerImage,
+		name: image,
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return c
+	}
+	c.name = ref.Context().Name()
+	c.version = ref.Identifier()
+	c.purl = fmt.Sprintf("pkg:docker/%s@%s",
+		ref.Context().RepositoryStr(),
+		strings.ReplaceAll(c.version, ":", "%3A"),
+	)
+	if registry := ref.Context().RegistryStr(); registry != name.DefaultRegistry {
+		c.purl += "?repository_url=" + registry
+	}
+	return c
+}
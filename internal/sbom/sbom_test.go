@@ -0,0 +1,212 @@
+package sbom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/satori/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidFormat(t *testing.T) {
+	assert.True(t, IsValidFormat(FormatCycloneDX))
+	assert.True(t, IsValidFormat(FormatSPDX))
+	assert.False(t, IsValidFormat("invalid"))
+	assert.False(t, IsValidFormat(""))
+}
+
+func TestGenerate(t *testing.T) {
+	createdAt := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	s := &hub.SnapshotToScan{
+		PackageID:   "00000000-0000-0000-0000-000000000001",
+		PackageName: "pkg1",
+		Version:     "1.0.0",
+		License:     "Apache-2.0",
+		ContainersImages: []*hub.ContainerImage{
+			{Image: "nginx:1.19.5"},
+			{Image: "quay.io/org/image:2.0.0"},
+		},
+		Dependencies: []*hub.Dependency{
+			{
+				Name:       "postgresql",
+				Version:    "10.1.0",
+				Repository: "https://charts.bitnami.com/bitnami",
+			},
+		},
+	}
+
+	sbom, err := Generate(s, createdAt)
+	require.NoError(t, err)
+	serial := uuid.NewV5(uuid.NamespaceURL, "00000000-0000-0000-0000-000000000001/1.0.0/1606816800").String()
+
+	assert.JSONEq(t, `{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.2",
+		"serialNumber": "urn:uuid:`+serial+`",
+		"version": 1,
+		"metadata": {
+			"timestamp": "2020-12-01T10:00:00Z",
+			"tools": [{"vendor": "Artifact Hub", "name": "artifacthub-scanner"}],
+			"component": {
+				"bom-ref": "root",
+				"type": "application",
+				"name": "pkg1",
+				"version": "1.0.0",
+				"licenses": [{"expression": "Apache-2.0"}]
+			}
+		},
+		"components": [
+			{
+				"bom-ref": "container-image/nginx:1.19.5",
+				"type": "container",
+				"name": "index.docker.io/library/nginx",
+				"version": "1.19.5",
+				"purl": "pkg:docker/library/nginx@1.19.5"
+			},
+			{
+				"bom-ref": "container-image/quay.io/org/image:2.0.0",
+				"type": "container",
+				"name": "quay.io/org/image",
+				"version": "2.0.0",
+				"purl": "pkg:docker/org/image@2.0.0?repository_url=quay.io"
+			},
+			{
+				"bom-ref": "dependency/postgresql@10.1.0",
+				"type": "application",
+				"name": "postgresql",
+				"version": "10.1.0",
+				"externalReferences": [{"type": "distribution", "url": "https://charts.bitnami.com/bitnami"}]
+			}
+		],
+		"dependencies": [
+			{
+				"ref": "root",
+				"dependsOn": [
+					"container-image/nginx:1.19.5",
+					"container-image/quay.io/org/image:2.0.0",
+					"dependency/postgresql@10.1.0"
+				]
+			}
+		]
+	}`, string(sbom.CycloneDX))
+
+	assert.JSONEq(t, `{
+		"spdxVersion": "SPDX-2.2",
+		"dataLicense": "CC0-1.0",
+		"SPDXID": "SPDXRef-DOCUMENT",
+		"name": "pkg1-1.0.0",
+		"documentNamespace": "https://artifacthub.io/spdxdocs/00000000-0000-0000-0000-000000000001/1.0.0-`+serial+`",
+		"creationInfo": {
+			"created": "2020-12-01T10:00:00Z",
+			"creators": ["Tool: artifacthub-scanner"]
+		},
+		"documentDescribes": ["SPDXRef-Package-root"],
+		"packages": [
+			{
+				"SPDXID": "SPDXRef-Package-root",
+				"name": "pkg1",
+				"versionInfo": "1.0.0",
+				"downloadLocation": "NOASSERTION",
+				"filesAnalyzed": false,
+				"licenseConcluded": "NOASSERTION",
+				"licenseDeclared": "Apache-2.0",
+				"copyrightText": "NOASSERTION"
+			},
+			{
+				"SPDXID": "SPDXRef-Image-0",
+				"name": "index.docker.io/library/nginx",
+				"versionInfo": "1.19.5",
+				"downloadLocation": "NOASSERTION",
+				"filesAnalyzed": false,
+				"licenseConcluded": "NOASSERTION",
+				"licenseDeclared": "NOASSERTION",
+				"copyrightText": "NOASSERTION",
+				"externalRefs": [
+					{
+						"referenceCategory": "PACKAGE-MANAGER",
+						"referenceType": "purl",
+						"referenceLocator": "pkg:docker/library/nginx@1.19.5"
+					}
+				]
+			},
+			{
+				"SPDXID": "SPDXRef-Image-1",
+				"name": "quay.io/org/image",
+				"versionInfo": "2.0.0",
+				"downloadLocation": "NOASSERTION",
+				"filesAnalyzed": false,
+				"licenseConcluded": "NOASSERTION",
+				"licenseDeclared": "NOASSERTION",
+				"copyrightText": "NOASSERTION",
+				"externalRefs": [
+					{
+						"referenceCategory": "PACKAGE-MANAGER",
+						"referenceType": "purl",
+						"referenceLocator": "pkg:docker/org/image@2.0.0?repository_url=quay.io"
+					}
+				]
+			},
+			{
+				"SPDXID": "SPDXRef-Dependency-2",
+				"name": "postgresql",
+				"versionInfo": "10.1.0",
+				"downloadLocation": "https://charts.bitnami.com/bitnami",
+				"filesAnalyzed": false,
+				"licenseConcluded": "NOASSERTION",
+				"licenseDeclared": "NOASSERTION",
+				"copyrightText": "NOASSERTION"
+			}
+		],
+		"relationships": [
+			{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Package-root"},
+			{"spdxElementId": "SPDXRef-Package-root", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Image-0"},
+			{"spdxElementId": "SPDXRef-Package-root", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Image-1"},
+			{"spdxElementId": "SPDXRef-Package-root", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Dependency-2"}
+		]
+	}`, string(sbom.SPDX))
+}
+
+func TestGetImageComponent(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected *component
+	}{
+		{
+			"nginx",
+			&component{
+				id:      "container-image/nginx",
+				kind:    containerImage,
+				name:    "index.docker.io/library/nginx",
+				version: "latest",
+				purl:    "pkg:docker/library/nginx@latest",
+			},
+		},
+		{
+			"ghcr.io/org/image@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			&component{
+				id:      "container-image/ghcr.io/org/image@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				kind:    containerImage,
+				name:    "ghcr.io/org/image",
+				version: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				purl:    "pkg:docker/org/image@sha256%3A0000000000000000000000000000000000000000000000000000000000000000?repository_url=ghcr.io",
+			},
+		},
+		{
+			"INVALID:image:ref",
+			&component{
+				id:   "container-image/INVALID:image:ref",
+				kind: containerImage,
+				name: "INVALID:image:ref",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.image, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, getImageComponent(tc.image))
+		})
+	}
+}
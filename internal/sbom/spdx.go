@@ -0,0 +1,145 @@
+package sbom
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/satori/uuid"
+)
+
+const (
+	spdxVersion       = "SPDX-2.2"
+	spdxDataLicense   = "CC0-1.0"
+	spdxDocumentID    = "SPDXRef-DOCUMENT"
+	spdxRootPackageID = "SPDXRef-Package-root"
+	spdxNamespaceBase = "https://artifacthub.io/spdxdocs"
+	spdxNoAssertion   = "NOASSERTION"
+)
+
+// spdxDocument represents an SPDX document (json format).
+type spdxDocument struct {
+	SPDXVersion       string              `json:"spdxVersion"`
+	DataLicense       string              `json:"dataLicense"`
+	SPDXID            string              `json:"SPDXID"`
+	Name              string              `json:"name"`
+	DocumentNamespace string              `json:"documentNamespace"`
+	CreationInfo      *spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string            `json:"documentDescribes"`
+	Packages          []*spdxPackage      `json:"packages"`
+	Relationships     []*spdxRelationship `json:"relationships"`
+}
+
+// spdxCreationInfo represents the creation information of an SPDX document.
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// spdxPackage represents a package in an SPDX document.
+type spdxPackage struct {
+	SPDXID           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo,omitempty"`
+	DownloadLocation string             `json:"downloadLocation"`
+	FilesAnalyzed    bool               `json:"filesAnalyzed"`
+	LicenseConcluded string             `json:"licenseConcluded"`
+	LicenseDeclared  string             `json:"licenseDeclared"`
+	CopyrightText    string             `json:"copyrightText"`
+	ExternalRefs     []*spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+// spdxExternalRef represents an external reference of a package in an SPDX
+// document.
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// spdxRelationship represents a relationship between two elements of an SPDX
+// document.
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// newSPDXDocument creates a new SPDX document for the package's snapshot
+// provided.
+func newSPDXDocument(
+	s *hub.SnapshotToScan,
+	components []*component,
+	serial uuid.UUID,
+	createdAt time.Time,
+) *spdxDocument {
+	licenseDeclared := spdxNoAssertion
+	if s.License != "" {
+		licenseDeclared = s.License
+	}
+	doc := &spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxDataLicense,
+		SPDXID:            spdxDocumentID,
+		Name:              fmt.Sprintf("%s-%s", s.PackageName, s.Version),
+		DocumentNamespace: fmt.Sprintf("%s/%s/%s-%s", spdxNamespaceBase, s.PackageID, s.Version, serial),
+		CreationInfo: &spdxCreationInfo{
+			Created:  createdAt.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + toolName},
+		},
+		DocumentDescribes: []string{spdxRootPackageID},
+		Packages: []*spdxPackage{
+			{
+				SPDXID:           spdxRootPackageID,
+				Name:             s.PackageName,
+				VersionInfo:      s.Version,
+				DownloadLocation: spdxNoAssertion,
+				LicenseConcluded: spdxNoAssertion,
+				LicenseDeclared:  licenseDeclared,
+				CopyrightText:    spdxNoAssertion,
+			},
+		},
+		Relationships: []*spdxRelationship{
+			{
+				SPDXElementID:      spdxDocumentID,
+				RelationshipType:   "DESCRIBES",
+				RelatedSPDXElement: spdxRootPackageID,
+			},
+		},
+	}
+	for i, c := range components {
+		p := &spdxPackage{
+			Name:             c.name,
+			VersionInfo:      c.version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+		}
+		switch c.kind {
+		case containerImage:
+			p.SPDXID = fmt.Sprintf("SPDXRef-Image-%d", i)
+		case dependency:
+			p.SPDXID = fmt.Sprintf("SPDXRef-Dependency-%d", i)
+			if c.repository != "" {
+				p.DownloadLocation = c.repository
+			}
+		}
+		if c.purl != "" {
+			p.ExternalRefs = []*spdxExternalRef{
+				{
+					ReferenceCategory: "PACKAGE-MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  c.purl,
+				},
+			}
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, &spdxRelationship{
+			SPDXElementID:      spdxRootPackageID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: p.SPDXID,
+		})
+	}
+	return doc
+}
@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/viper"
+)
+
+// GrypeScanner is an implementation of the Scanner interface that uses the
+// Grype CLI.
+type GrypeScanner struct {
+	Ctx context.Context
+	Cfg *viper.Viper
+}
+
+// Scan implements the Scanner interface.
+func (s *GrypeScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
+	// Setup grype command. Images are pulled directly from the registry, so no
+	// Docker daemon is needed.
+	cmd := exec.CommandContext(s.Ctx, "grype", "registry:"+image, "--quiet", "-o", "json") // #nosec
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// clean environment
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"USER=" + os.Getenv("USER"),
+		"HOME=" + os.Getenv("HOME"),
+	}
+
+	// If the registry is the Docker Hub, include credentials to avoid rate
+	// limiting issues.
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %s ref: %w", image, err)
+	}
+	if strings.HasSuffix(ref.Context().Registry.Name(), "docker.io") {
+		cmd.Env = append(cmd.Env,
+			"GRYPE_REGISTRY_AUTH_AUTHORITY="+ref.Context().Registry.Name(),
+			"GRYPE_REGISTRY_AUTH_USERNAME="+s.Cfg.GetString("scanner.dockerUsername"),
+			"GRYPE_REGISTRY_AUTH_PASSWORD="+s.Cfg.GetString("scanner.dockerPassword"),
+		)
+	}
+
+	// Run grype command
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "MANIFEST_UNKNOWN") {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("error running grype on image %s: %w: %s", image, err, stderr.String())
+	}
+	return parseGrypeReport(image, stdout.Bytes())
+}
+
+// grypeReport represents the parts of the json report produced by Grype we
+// are interested in.
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID             string   `json:"id"`
+			Namespace      string   `json:"namespace"`
+			Severity       string   `json:"severity"`
+			Description    string   `json:"description"`
+			URLs           []string `json:"urls"`
+			Links          []string `json:"links"`
+			FixedInVersion string   `json:"fixedInVersion"`
+			Fix            struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+			CVSS []struct {
+				Version string `json:"version"`
+				Vector  string `json:"vector"`
+				Metrics struct {
+					BaseScore float64 `json:"baseScore"`
+				} `json:"metrics"`
+			} `json:"cvss"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name      string `json:"name"`
+			Version   string `json:"version"`
+			Type      string `json:"type"`
+			Locations []struct {
+				Path string `json:"path"`
+			} `json:"locations"`
+		} `json:"artifact"`
+	} `json:"matches"`
+	Distro struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"distro"`
+}
+
+// parseGrypeReport parses the json report produced by Grype for the image
+// provided. Vulnerabilities found in operating system packages are grouped in
+// a single target, whereas the ones found in language specific packages are
+// grouped by the location where the package was found.
+func parseGrypeReport(image string, data []byte) ([]*hub.SecurityReportTarget, error) {
+	var report grypeReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("error unmarshalling grype report: %w", err)
+	}
+
+	var targets []*hub.SecurityReportTarget
+	targetsByName := make(map[string]*hub.SecurityReportTarget)
+	for _, m := range report.Matches {
+		// Get target the vulnerability belongs to
+		var targetName, targetType string
+		switch m.Artifact.Type {
+		case "apk", "deb", "rpm":
+			targetName = fmt.Sprintf("%s (%s %s)", image, report.Distro.Name, report.Distro.Version)
+			targetType = report.Distro.Name
+		default:
+			targetName = m.Artifact.Type
+			if len(m.Artifact.Locations) > 0 {
+				targetName = strings.TrimPrefix(m.Artifact.Locations[0].Path, "/")
+			}
+			targetType = m.Artifact.Type
+		}
+		target, ok := targetsByName[targetName]
+		if !ok {
+			target = &hub.SecurityReportTarget{
+				Target: targetName,
+				Type:   targetType,
+			}
+			targetsByName[targetName] = target
+			targets = append(targets, target)
+		}
+
+		// Normalize vulnerability and add it to the target
+		v := &hub.Vulnerability{
+			VulnerabilityID:  m.Vulnerability.ID,
+			PkgName:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     m.Vulnerability.FixedInVersion,
+			Severity:         normalizeSeverity(m.Vulnerability.Severity),
+			SeveritySource:   m.Vulnerability.Namespace,
+			Description:      m.Vulnerability.Description,
+			References:       m.Vulnerability.URLs,
+		}
+		if len(m.Vulnerability.Fix.Versions) > 0 {
+			v.FixedVersion = strings.Join(m.Vulnerability.Fix.Versions, ", ")
+		}
+		if len(v.References) == 0 {
+			v.References = m.Vulnerability.Links
+		}
+		for _, c := range m.Vulnerability.CVSS {
+			if v.CVSS == nil {
+				v.CVSS = make(map[string]*hub.CVSS)
+			}
+			cvss, ok := v.CVSS[m.Vulnerability.Namespace]
+			if !ok {
+				cvss = &hub.CVSS{}
+				v.CVSS[m.Vulnerability.Namespace] = cvss
+			}
+			if strings.HasPrefix(c.Version, "2") {
+				cvss.V2Vector = c.Vector
+				cvss.V2Score = c.Metrics.BaseScore
+			} else {
+				cvss.V3Vector = c.Vector
+				cvss.V3Score = c.Metrics.BaseScore
+			}
+		}
+		target.Vulnerabilities = append(target.Vulnerabilities, v)
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGrypeReport(t *testing.T) {
+	image := "repo/image:tag"
+
+	t.Run("invalid report", func(t *testing.T) {
+		t.Parallel()
+		targets, err := parseGrypeReport(image, []byte(`invalid: "`))
+		assert.Error(t, err)
+		assert.Nil(t, targets)
+	})
+
+	t.Run("report parsed successfully", func(t *testing.T) {
+		t.Parallel()
+		targets, err := parseGrypeReport(image, sampleGrypeReportData)
+		assert.NoError(t, err)
+		assert.Equal(t, []*hub.SecurityReportTarget{
+			{
+				Target: "repo/image:tag (alpine 3.12.0)",
+				Type:   "alpine",
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "CVE-2020-28928",
+						PkgName:          "musl",
+						InstalledVersion: "1.1.24-r8",
+						FixedVersion:     "1.1.24-r10",
+						Severity:         SeverityMedium,
+						SeveritySource:   "alpine:3.12",
+						References:       []string{"http://www.openwall.com/lists/oss-security/2020/11/20/4"},
+					},
+					{
+						VulnerabilityID:  "CVE-2020-1971",
+						PkgName:          "libssl1.1",
+						InstalledVersion: "1.1.1g-r0",
+						Severity:         SeverityLow,
+						SeveritySource:   "alpine:3.12",
+					},
+				},
+			},
+			{
+				Target: "app/package-lock.json",
+				Type:   "npm",
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "GHSA-p6mc-m468-83gw",
+						PkgName:          "lodash",
+						InstalledVersion: "4.17.15",
+						FixedVersion:     "4.17.19",
+						Severity:         SeverityHigh,
+						SeveritySource:   "github:npm",
+						Description:      "Prototype Pollution in lodash",
+						References:       []string{"https://github.com/advisories/GHSA-p6mc-m468-83gw"},
+						CVSS: map[string]*hub.CVSS{
+							"github:npm": {
+								V3Vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H",
+								V3Score:  7.4,
+							},
+						},
+					},
+				},
+			},
+		}, targets)
+	})
+}
+
+var sampleGrypeReportData = []byte(`
+{
+  "matches": [
+    {
+      "vulnerability": {
+        "id": "CVE-2020-28928",
+        "namespace": "alpine:3.12",
+        "severity": "Medium",
+        "urls": ["http://www.openwall.com/lists/oss-security/2020/11/20/4"],
+        "fix": {
+          "versions": ["1.1.24-r10"],
+          "state": "fixed"
+        }
+      },
+      "artifact": {
+        "name": "musl",
+        "version": "1.1.24-r8",
+        "type": "apk",
+        "locations": [{"path": "/lib/apk/db/installed"}]
+      }
+    },
+    {
+      "vulnerability": {
+        "id": "CVE-2020-1971",
+        "namespace": "alpine:3.12",
+        "severity": "Negligible",
+        "fix": {
+          "versions": [],
+          "state": "not-fixed"
+        }
+      },
+      "artifact": {
+        "name": "libssl1.1",
+        "version": "1.1.1g-r0",
+        "type": "apk",
+        "locations": [{"path": "/lib/apk/db/installed"}]
+      }
+    },
+    {
+      "vulnerability": {
+        "id": "GHSA-p6mc-m468-83gw",
+        "namespace": "github:npm",
+        "severity": "High",
+        "description": "Prototype Pollution in lodash",
+        "urls": ["https://github.com/advisories/GHSA-p6mc-m468-83gw"],
+        "cvss": [
+          {
+            "version": "3.1",
+            "vector": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H",
+            "metrics": {"baseScore": 7.4}
+          }
+        ],
+        "fix": {
+          "versions": ["4.17.19"],
+          "state": "fixed"
+        }
+      },
+      "artifact": {
+        "name": "lodash",
+        "version": "4.17.15",
+        "type": "npm",
+        "locations": [{"path": "/app/package-lock.json"}]
+      }
+    }
+  ],
+  "distro": {
+    "name": "alpine",
+    "version": "3.12.0"
+  }
+}
+`)
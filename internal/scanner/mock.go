@@ -1,6 +1,9 @@
 package scanner
 
-import "github.com/stretchr/testify/mock"
+import (
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
 
 // Mock is a mock implementation of the Scanner interface.
 type Mock struct {
@@ -8,8 +11,8 @@ type Mock struct {
 }
 
 // Scan implements the Scanner interface.
-func (m *Mock) Scan(image string) ([]byte, error) {
+func (m *Mock) Scan(image string) ([]*hub.SecurityReportTarget, error) {
 	args := m.Called(image)
-	data, _ := args.Get(0).([]byte)
-	return data, args.Error(1)
+	targets, _ := args.Get(0).([]*hub.SecurityReportTarget)
+	return targets, args.Error(1)
 }
@@ -2,88 +2,135 @@ package scanner
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 )
 
-// Scanner describes the methods a Scanner implementation must provide.
+// Severities supported in the vulnerabilities returned by the scanners.
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+	SeverityUnknown  = "UNKNOWN"
+)
+
+// ErrImageNotFound represents that the image provided was not found in the
+// repository.
+var ErrImageNotFound = errors.New("image not found")
+
+// Scanner describes the methods a Scanner implementation must provide. Each
+// Scanner implementation uses a different backend to scan the images, but the
+// vulnerabilities found are returned using the same normalized model.
 type Scanner interface {
-	Scan(image string) ([]byte, error)
+	Scan(image string) ([]*hub.SecurityReportTarget, error)
 }
 
 // ScanSnapshot scans the provided package's snapshot for security
-// vulnerabilities returning a report with the results.
+// vulnerabilities returning a report with the results. Vulnerabilities matching
+// an entry of the snapshot's vulnerability allowlist that has not expired yet
+// are marked as suppressed. The allowlist applied is included in the report,
+// so that the snapshot can be scanned again when it changes.
 func ScanSnapshot(
 	ctx context.Context,
 	scanner Scanner,
 	snapshot *hub.SnapshotToScan,
 ) (*hub.SnapshotSecurityReport, error) {
-	full := make(map[string][]interface{})
+	full := make(map[string][]*hub.SecurityReportTarget)
 
 	for _, image := range snapshot.ContainersImages {
 		parts := strings.Split(image.Image, ":")
 		if len(parts) == 1 || parts[1] == "latest" {
 			continue
 		}
-		reportData, err := scanner.Scan(image.Image)
+		imageFullReport, err := scanner.Scan(image.Image)
 		if err != nil {
 			if errors.Is(err, ErrImageNotFound) {
 				continue
 			}
 			return nil, fmt.Errorf("error scanning image %s: %w", image.Image, err)
 		}
-		var imageFullReport []interface{}
-		if err := json.Unmarshal(reportData, &imageFullReport); err != nil {
-			return nil, fmt.Errorf("error unmarshalling image %s full report: %w", image.Image, err)
-		}
 		if imageFullReport != nil {
 			full[image.Image] = imageFullReport
 		}
 	}
 	var summary *hub.SecurityReportSummary
 	if len(full) > 0 {
+		applyVulnerabilityAllowlist(full, snapshot.VulnerabilityAllowlist, time.Now())
 		summary = generateSummary(full)
 	} else {
 		full = nil
 	}
 
 	return &hub.SnapshotSecurityReport{
-		PackageID: snapshot.PackageID,
-		Version:   snapshot.Version,
-		Full:      full,
-		Summary:   summary,
+		PackageID:              snapshot.PackageID,
+		Version:                snapshot.Version,
+		Full:                   full,
+		Summary:                summary,
+		VulnerabilityAllowlist: snapshot.VulnerabilityAllowlist,
 	}, nil
 }
 
+// applyVulnerabilityAllowlist marks the vulnerabilities in the full report
+// that match any of the allowlist entries provided as suppressed, as long as
+// the entry has not expired at the time provided. Entries are valid until the
+// end of the expiration day.
+func applyVulnerabilityAllowlist(
+	full map[string][]*hub.SecurityReportTarget,
+	allowlist []*hub.VulnerabilityAllowlistEntry,
+	now time.Time,
+) {
+	active := make(map[string]*hub.VulnerabilityAllowlistEntry, len(allowlist))
+	for _, e := range allowlist {
+		expiresAt, err := time.Parse("2006-01-02", e.ExpiresAt)
+		if err != nil || !now.Before(expiresAt.AddDate(0, 0, 1)) {
+			continue
+		}
+		active[e.VulnerabilityID] = e
+	}
+	if len(active) == 0 {
+		return
+	}
+	for _, targets := range full {
+		for _, target := range targets {
+			for _, vulnerability := range target.Vulnerabilities {
+				if e, ok := active[vulnerability.VulnerabilityID]; ok {
+					vulnerability.Suppression = &hub.VulnerabilitySuppression{
+						Justification: e.Justification,
+						ExpiresAt:     e.ExpiresAt,
+					}
+				}
+			}
+		}
+	}
+}
+
 // generateSummary generates a summary of the security report from the full
-// report
-func generateSummary(full map[string][]interface{}) *hub.SecurityReportSummary {
+// report. Suppressed vulnerabilities are counted separately and they are not
+// included in the severities counters.
+func generateSummary(full map[string][]*hub.SecurityReportTarget) *hub.SecurityReportSummary {
 	summary := &hub.SecurityReportSummary{}
 	for _, targets := range full {
-		for _, entry := range targets {
-			var target *Target
-			entryJSON, err := json.Marshal(entry)
-			if err != nil {
-				continue
-			}
-			if err := json.Unmarshal(entryJSON, &target); err != nil {
-				continue
-			}
+		for _, target := range targets {
 			for _, vulnerability := range target.Vulnerabilities {
+				if vulnerability.Suppression != nil {
+					summary.Suppressed++
+					continue
+				}
 				switch vulnerability.Severity {
-				case "CRITICAL":
+				case SeverityCritical:
 					summary.Critical++
-				case "HIGH":
+				case SeverityHigh:
 					summary.High++
-				case "MEDIUM":
+				case SeverityMedium:
 					summary.Medium++
-				case "LOW":
+				case SeverityLow:
 					summary.Low++
-				case "UNKNOWN":
+				case SeverityUnknown:
 					summary.Unknown++
 				}
 			}
@@ -92,12 +139,19 @@ func generateSummary(full map[string][]interface{}) *hub.SecurityReportSummary {
 	return summary
 }
 
-// Target represents a target in a security report.
-type Target struct {
-	Vulnerabilities []*Vulnerability `json:"Vulnerabilities"`
-}
-
-// Vulnerability represents a vulnerability in a security report target.
-type Vulnerability struct {
-	Severity string `json:"Severity"`
+// normalizeSeverity returns the severity provided converted to one of the
+// severities supported. Severities not supported are returned as unknown.
+func normalizeSeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case SeverityCritical:
+		return SeverityCritical
+	case SeverityHigh:
+		return SeverityHigh
+	case SeverityMedium:
+		return SeverityMedium
+	case SeverityLow, "NEGLIGIBLE":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
 }
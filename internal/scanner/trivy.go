@@ -3,21 +3,19 @@ package scanner
 import (
 	"bytes"
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/artifacthub/hub/internal/hub"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/viper"
 )
 
-// ErrImageNotFound represents that the image provided was not found in the
-// repository.
-var ErrImageNotFound = errors.New("image not found")
-
-// TrivyScanner is an implementation of the Scanner interface that uses Trivy.
+// TrivyScanner is an implementation of the Scanner interface that uses the
+// Trivy CLI in client mode.
 type TrivyScanner struct {
 	Ctx context.Context
 	Cfg *viper.Viper
@@ -25,7 +23,7 @@ type TrivyScanner struct {
 }
 
 // Scan implements the Scanner interface.
-func (s *TrivyScanner) Scan(image string) ([]byte, error) {
+func (s *TrivyScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
 	// Setup trivy command
 	cmd := exec.CommandContext(s.Ctx, "trivy", "client", "--quiet", "--remote", s.URL, "-f", "json", image) // #nosec
 	var stdout, stderr bytes.Buffer
@@ -59,5 +57,19 @@ func (s *TrivyScanner) Scan(image string) ([]byte, error) {
 		}
 		return nil, fmt.Errorf("error running trivy on image %s: %w: %s", image, err, stderr.String())
 	}
-	return stdout.Bytes(), nil
+	return parseTrivyReport(stdout.Bytes())
+}
+
+// parseTrivyReport parses the json report produced by the Trivy CLI.
+func parseTrivyReport(data []byte) ([]*hub.SecurityReportTarget, error) {
+	var targets []*hub.SecurityReportTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("error unmarshalling trivy report: %w", err)
+	}
+	for _, target := range targets {
+		for _, v := range target.Vulnerabilities {
+			v.Severity = normalizeSeverity(v.Severity)
+		}
+	}
+	return targets, nil
 }
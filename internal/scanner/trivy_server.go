@@ -0,0 +1,591 @@
+package scanner
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/spf13/viper"
+)
+
+const (
+	trivyMissingBlobsPath = "/twirp/trivy.cache.v1.Cache/MissingBlobs"
+	trivyPutArtifactPath  = "/twirp/trivy.cache.v1.Cache/PutArtifact"
+	trivyPutBlobPath      = "/twirp/trivy.cache.v1.Cache/PutBlob"
+	trivyScanPath         = "/twirp/trivy.scanner.v1.Scanner/Scan"
+	trivyTokenHeader      = "Trivy-Token"
+
+	// trivySchemaVersion represents the version of the artifacts and blobs
+	// schema used by the Trivy server. Blobs stored using a different version
+	// are considered missing by the server.
+	trivySchemaVersion = 1
+
+	// trivyMaxAnalyzedFileSize represents the maximum size of the files read
+	// from the image layers to analyze them.
+	trivyMaxAnalyzedFileSize = 50 * 1024 * 1024
+)
+
+// Files read from the image layers to detect the OS and the OS packages
+// installed. Paths are relative to the root of the image filesystem.
+const (
+	alpineReleaseFile  = "etc/alpine-release"
+	debianVersionFile  = "etc/debian_version"
+	lsbReleaseFile     = "etc/lsb-release"
+	apkInstalledFile   = "lib/apk/db/installed"
+	dpkgStatusFile     = "var/lib/dpkg/status"
+	dpkgStatusDir      = "var/lib/dpkg/status.d/"
+	whiteoutPrefix     = ".wh."
+	whiteoutOpaqueFile = ".wh..wh..opq"
+)
+
+// TrivyServerScanner is an implementation of the Scanner interface that uses
+// the Twirp API of a Trivy server directly, without relying on the Trivy CLI.
+//
+// The Trivy server scans images using the analysis results of their layers
+// stored in its cache, which are identified by the image config digest and
+// the layers diff ids. Like the Trivy client does, the layers missing from
+// the cache are analyzed by the scanner and the results are pushed to the
+// server before requesting the scan. The layers analysis detects the OS and
+// the OS packages installed in Alpine, Debian and Ubuntu based images.
+type TrivyServerScanner struct {
+	Ctx context.Context
+	Cfg *viper.Viper
+	URL string
+	Hc  *http.Client
+}
+
+// trivyArtifactInfo represents the image information stored in the Trivy
+// server cache.
+type trivyArtifactInfo struct {
+	SchemaVersion int    `json:"schema_version"`
+	Architecture  string `json:"architecture"`
+	Created       string `json:"created"`
+	DockerVersion string `json:"docker_version"`
+	OS            string `json:"os"`
+}
+
+// trivyBlobInfo represents the analysis results of an image layer stored in
+// the Trivy server cache.
+type trivyBlobInfo struct {
+	SchemaVersion int                 `json:"schema_version"`
+	OS            *trivyOS            `json:"os,omitempty"`
+	PackageInfos  []*trivyPackageInfo `json:"package_infos,omitempty"`
+	OpaqueDirs    []string            `json:"opaque_dirs,omitempty"`
+	WhiteoutFiles []string            `json:"whiteout_files,omitempty"`
+	Digest        string              `json:"digest"`
+	DiffID        string              `json:"diff_id"`
+}
+
+// trivyOS represents the OS detected in an image layer.
+type trivyOS struct {
+	Family string `json:"family"`
+	Name   string `json:"name"`
+}
+
+// trivyPackageInfo represents the packages found in a packages database file
+// of an image layer.
+type trivyPackageInfo struct {
+	FilePath string          `json:"file_path"`
+	Packages []*trivyPackage `json:"packages"`
+}
+
+// trivyPackage represents an OS package installed in an image.
+type trivyPackage struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Release    string `json:"release,omitempty"`
+	Epoch      int    `json:"epoch,omitempty"`
+	Arch       string `json:"arch,omitempty"`
+	SrcName    string `json:"src_name,omitempty"`
+	SrcVersion string `json:"src_version,omitempty"`
+	SrcRelease string `json:"src_release,omitempty"`
+	SrcEpoch   int    `json:"src_epoch,omitempty"`
+	License    string `json:"license,omitempty"`
+}
+
+// Scan implements the Scanner interface.
+func (s *TrivyServerScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
+	// Get image and its artifact and blobs ids
+	img, err := s.getImage(image)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("error getting image %s: %w", image, err)
+	}
+	artifactID, blobIDs, err := getImageIDs(img)
+	if err != nil {
+		return nil, fmt.Errorf("error getting image %s ids: %w", image, err)
+	}
+
+	// Analyze the parts of the image the server doesn't have yet
+	if err := s.putMissingBlobs(img, artifactID, blobIDs); err != nil {
+		return nil, fmt.Errorf("error analyzing image %s: %w", image, err)
+	}
+
+	// Scan image
+	var scanResp struct {
+		Results []*struct {
+			Target          string `json:"target"`
+			Type            string `json:"type"`
+			Vulnerabilities []*struct {
+				VulnerabilityID  string   `json:"vulnerability_id"`
+				PkgName          string   `json:"pkg_name"`
+				InstalledVersion string   `json:"installed_version"`
+				FixedVersion     string   `json:"fixed_version"`
+				Title            string   `json:"title"`
+				Description      string   `json:"description"`
+				Severity         string   `json:"severity"`
+				SeveritySource   string   `json:"severity_source"`
+				References       []string `json:"references"`
+				CVSS             map[string]*struct {
+					V2Vector string  `json:"v2_vector"`
+					V3Vector string  `json:"v3_vector"`
+					V2Score  float64 `json:"v2_score"`
+					V3Score  float64 `json:"v3_score"`
+				} `json:"cvss"`
+				LastModifiedDate string `json:"last_modified_date"`
+			} `json:"vulnerabilities"`
+		} `json:"results"`
+	}
+	scanReq := map[string]interface{}{
+		"target":      image,
+		"artifact_id": artifactID,
+		"blob_ids":    blobIDs,
+		"options": map[string]interface{}{
+			"vuln_type": []string{"os", "library"},
+		},
+	}
+	if err := s.call(trivyScanPath, scanReq, &scanResp); err != nil {
+		return nil, fmt.Errorf("error scanning image %s: %w", image, err)
+	}
+
+	// Normalize scan results
+	targets := make([]*hub.SecurityReportTarget, 0, len(scanResp.Results))
+	for _, r := range scanResp.Results {
+		target := &hub.SecurityReportTarget{
+			Target: r.Target,
+			Type:   r.Type,
+		}
+		for _, rv := range r.Vulnerabilities {
+			v := &hub.Vulnerability{
+				VulnerabilityID:  rv.VulnerabilityID,
+				PkgName:          rv.PkgName,
+				InstalledVersion: rv.InstalledVersion,
+				FixedVersion:     rv.FixedVersion,
+				Severity:         normalizeSeverity(rv.Severity),
+				SeveritySource:   rv.SeveritySource,
+				Title:            rv.Title,
+				Description:      rv.Description,
+				References:       rv.References,
+				LastModifiedDate: rv.LastModifiedDate,
+			}
+			for source, c := range rv.CVSS {
+				if v.CVSS == nil {
+					v.CVSS = make(map[string]*hub.CVSS)
+				}
+				v.CVSS[source] = &hub.CVSS{
+					V2Vector: c.V2Vector,
+					V3Vector: c.V3Vector,
+					V2Score:  c.V2Score,
+					V3Score:  c.V3Score,
+				}
+			}
+			target.Vulnerabilities = append(target.Vulnerabilities, v)
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// getImage returns the image provided from its registry.
+func (s *TrivyServerScanner) getImage(image string) (v1.Image, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	options := []remote.Option{remote.WithContext(s.Ctx)}
+	if strings.HasSuffix(ref.Context().Registry.Name(), "docker.io") {
+		username := s.Cfg.GetString("scanner.dockerUsername")
+		password := s.Cfg.GetString("scanner.dockerPassword")
+		if username != "" || password != "" {
+			options = append(options, remote.WithAuth(&authn.Basic{
+				Username: username,
+				Password: password,
+			}))
+		}
+	}
+	return remote.Image(ref, options...)
+}
+
+// putMissingBlobs pushes to the Trivy server cache the image information and
+// the analysis results of the image layers it doesn't have yet.
+func (s *TrivyServerScanner) putMissingBlobs(img v1.Image, artifactID string, blobIDs []string) error {
+	var missingBlobsResp struct {
+		MissingArtifact bool     `json:"missing_artifact"`
+		MissingBlobIDs  []string `json:"missing_blob_ids"`
+	}
+	missingBlobsReq := map[string]interface{}{
+		"artifact_id": artifactID,
+		"blob_ids":    blobIDs,
+	}
+	if err := s.call(trivyMissingBlobsPath, missingBlobsReq, &missingBlobsResp); err != nil {
+		return fmt.Errorf("error checking missing blobs: %w", err)
+	}
+
+	// Layers
+	for _, blobID := range missingBlobsResp.MissingBlobIDs {
+		diffID, err := v1.NewHash(blobID)
+		if err != nil {
+			return fmt.Errorf("invalid blob id %s: %w", blobID, err)
+		}
+		layer, err := img.LayerByDiffID(diffID)
+		if err != nil {
+			return fmt.Errorf("error getting layer %s: %w", blobID, err)
+		}
+		blobInfo, err := analyzeLayer(layer)
+		if err != nil {
+			return fmt.Errorf("error analyzing layer %s: %w", blobID, err)
+		}
+		putBlobReq := map[string]interface{}{
+			"diff_id":   blobID,
+			"blob_info": blobInfo,
+		}
+		if err := s.call(trivyPutBlobPath, putBlobReq, nil); err != nil {
+			return fmt.Errorf("error putting blob %s: %w", blobID, err)
+		}
+	}
+
+	// Image information. It's pushed once all the layers are available, as
+	// the server considers the image analyzed when the artifact is found.
+	if missingBlobsResp.MissingArtifact {
+		configFile, err := img.ConfigFile()
+		if err != nil {
+			return fmt.Errorf("error getting config file: %w", err)
+		}
+		putArtifactReq := map[string]interface{}{
+			"artifact_id": artifactID,
+			"artifact_info": &trivyArtifactInfo{
+				SchemaVersion: trivySchemaVersion,
+				Architecture:  configFile.Architecture,
+				Created:       configFile.Created.UTC().Format(time.RFC3339Nano),
+				DockerVersion: configFile.DockerVersion,
+				OS:            configFile.OS,
+			},
+		}
+		if err := s.call(trivyPutArtifactPath, putArtifactReq, nil); err != nil {
+			return fmt.Errorf("error putting artifact: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// call calls the Trivy server Twirp API method available at the path provided
+// using the json protocol. The output is ignored when it is nil.
+func (s *TrivyServerScanner) call(path string, input, output interface{}) error {
+	ctx, cancel := context.WithTimeout(s.Ctx, 5*time.Minute)
+	defer cancel()
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	u := strings.TrimSuffix(s.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, "POST", u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := s.Cfg.GetString("scanner.trivyToken"); token != "" {
+		req.Header.Set(trivyTokenHeader, token)
+	}
+	resp, err := s.Hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var twirpErr struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&twirpErr)
+		return fmt.Errorf("unexpected status code received: %d (%s: %s)", resp.StatusCode, twirpErr.Code, twirpErr.Msg)
+	}
+	if output == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(output)
+}
+
+// getImageIDs returns the ids used by the Trivy server to identify the image
+// provided (config digest) and its layers (diff ids).
+func getImageIDs(img v1.Image) (string, []string, error) {
+	configName, err := img.ConfigName()
+	if err != nil {
+		return "", nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return "", nil, err
+	}
+	blobIDs := make([]string, 0, len(configFile.RootFS.DiffIDs))
+	for _, diffID := range configFile.RootFS.DiffIDs {
+		blobIDs = append(blobIDs, diffID.String())
+	}
+	return configName.String(), blobIDs, nil
+}
+
+// analyzeLayer analyzes the content of the image layer provided, returning the
+// OS and OS packages found as well as the files and directories it removes
+// from the previous layers. Merging the results of all the image layers is
+// done by the Trivy server.
+func analyzeLayer(layer v1.Layer) (*trivyBlobInfo, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return nil, err
+	}
+	diffID, err := layer.DiffID()
+	if err != nil {
+		return nil, err
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	blobInfo := &trivyBlobInfo{
+		SchemaVersion: trivySchemaVersion,
+		Digest:        digest.String(),
+		DiffID:        diffID.String(),
+	}
+	osFiles := make(map[string][]byte)
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		filePath := strings.TrimLeft(path.Clean(hdr.Name), "/")
+		dir, base := path.Split(filePath)
+
+		// Whiteouts
+		if base == whiteoutOpaqueFile {
+			blobInfo.OpaqueDirs = append(blobInfo.OpaqueDirs, dir)
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			blobInfo.WhiteoutFiles = append(
+				blobInfo.WhiteoutFiles,
+				path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)),
+			)
+			continue
+		}
+
+		// OS and OS packages files
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		var parsePackages func([]byte) []*trivyPackage
+		switch {
+		case filePath == alpineReleaseFile, filePath == debianVersionFile, filePath == lsbReleaseFile:
+		case filePath == apkInstalledFile:
+			parsePackages = parseApkInstalled
+		case filePath == dpkgStatusFile, strings.HasPrefix(filePath, dpkgStatusDir):
+			parsePackages = parseDpkgStatus
+		default:
+			continue
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(tr, trivyMaxAnalyzedFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
+		}
+		if parsePackages == nil {
+			osFiles[filePath] = data
+			continue
+		}
+		if pkgs := parsePackages(data); len(pkgs) > 0 {
+			blobInfo.PackageInfos = append(blobInfo.PackageInfos, &trivyPackageInfo{
+				FilePath: filePath,
+				Packages: pkgs,
+			})
+		}
+	}
+	blobInfo.OS = detectOS(osFiles)
+
+	return blobInfo, nil
+}
+
+// detectOS detects the OS from the content of the OS release files provided.
+func detectOS(files map[string][]byte) *trivyOS {
+	if data, ok := files[alpineReleaseFile]; ok {
+		return &trivyOS{Family: "alpine", Name: strings.TrimSpace(string(data))}
+	}
+	if data, ok := files[lsbReleaseFile]; ok {
+		var id, release string
+		for _, line := range strings.Split(string(data), "\n") {
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			switch parts[0] {
+			case "DISTRIB_ID":
+				id = strings.Trim(parts[1], `" `)
+			case "DISTRIB_RELEASE":
+				release = strings.Trim(parts[1], `" `)
+			}
+		}
+		if id == "Ubuntu" && release != "" {
+			return &trivyOS{Family: "ubuntu", Name: release}
+		}
+	}
+	if data, ok := files[debianVersionFile]; ok {
+		return &trivyOS{Family: "debian", Name: strings.TrimSpace(string(data))}
+	}
+	return nil
+}
+
+// parseApkInstalled parses the packages installed from the content of an apk
+// installed database file.
+func parseApkInstalled(data []byte) []*trivyPackage {
+	var pkgs []*trivyPackage
+	pkg := &trivyPackage{}
+	flush := func() {
+		if pkg.Name != "" && pkg.Version != "" {
+			if pkg.SrcName == "" {
+				pkg.SrcName = pkg.Name
+			}
+			pkg.SrcVersion = pkg.Version
+			pkgs = append(pkgs, pkg)
+		}
+		pkg = &trivyPackage{}
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			pkg.Name = value
+		case 'V':
+			pkg.Version = value
+		case 'A':
+			pkg.Arch = value
+		case 'o':
+			pkg.SrcName = value
+		case 'L':
+			pkg.License = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// parseDpkgStatus parses the packages installed from the content of a dpkg
+// status file.
+func parseDpkgStatus(data []byte) []*trivyPackage {
+	var pkgs []*trivyPackage
+	var name, version, status, arch, srcName, srcVersion string
+	flush := func() {
+		installed := status == "" || strings.HasSuffix(status, " installed")
+		if name != "" && version != "" && installed {
+			pkg := &trivyPackage{
+				Name:    name,
+				Arch:    arch,
+				SrcName: name,
+			}
+			pkg.Epoch, pkg.Version, pkg.Release = splitDebianVersion(version)
+			if srcName != "" {
+				pkg.SrcName = srcName
+			}
+			if srcVersion == "" {
+				srcVersion = version
+			}
+			pkg.SrcEpoch, pkg.SrcVersion, pkg.SrcRelease = splitDebianVersion(srcVersion)
+			pkgs = append(pkgs, pkg)
+		}
+		name, version, status, arch, srcName, srcVersion = "", "", "", "", "", ""
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		switch parts[0] {
+		case "Package":
+			name = value
+		case "Version":
+			version = value
+		case "Status":
+			status = value
+		case "Architecture":
+			arch = value
+		case "Source":
+			// i.e. "Source: glibc (2.28-10)"
+			fields := strings.Fields(value)
+			if len(fields) > 0 {
+				srcName = fields[0]
+			}
+			if len(fields) > 1 {
+				srcVersion = strings.Trim(fields[1], "()")
+			}
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// splitDebianVersion splits the debian package version provided
+// ([epoch:]upstream_version[-debian_revision]) into its parts.
+func splitDebianVersion(version string) (epoch int, upstream, revision string) {
+	upstream = version
+	if i := strings.Index(upstream, ":"); i > 0 {
+		if e, err := strconv.Atoi(upstream[:i]); err == nil {
+			epoch = e
+			upstream = upstream[i+1:]
+		}
+	}
+	if i := strings.LastIndex(upstream, "-"); i > 0 {
+		revision = upstream[i+1:]
+		upstream = upstream[:i]
+	}
+	return
+}
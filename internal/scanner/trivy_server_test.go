@@ -0,0 +1,449 @@
+package scanner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrivyServerScanner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("image not found", func(t *testing.T) {
+		t.Parallel()
+		host := setupRegistry(t)
+		s := &TrivyServerScanner{Ctx: ctx, Cfg: viper.New(), URL: "http://localhost", Hc: http.DefaultClient}
+		targets, err := s.Scan(host + "/repo/image:not-found")
+		assert.Equal(t, ErrImageNotFound, err)
+		assert.Nil(t, targets)
+	})
+
+	t.Run("twirp api error", func(t *testing.T) {
+		t.Parallel()
+		image, _, _ := pushImage(t, nil)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code": "unauthenticated", "msg": "invalid token"}`))
+		}))
+		t.Cleanup(ts.Close)
+		s := &TrivyServerScanner{Ctx: ctx, Cfg: viper.New(), URL: ts.URL, Hc: ts.Client()}
+		targets, err := s.Scan(image)
+		assert.Contains(t, err.Error(), "unexpected status code received: 401 (unauthenticated: invalid token)")
+		assert.Nil(t, targets)
+	})
+
+	t.Run("error putting blob", func(t *testing.T) {
+		t.Parallel()
+		image, _, blobIDs := pushImage(t, nil)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case trivyMissingBlobsPath:
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"missing_artifact": true,
+					"missing_blob_ids": blobIDs,
+				})
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"code": "internal", "msg": "failed to store blob"}`))
+			}
+		}))
+		t.Cleanup(ts.Close)
+		s := &TrivyServerScanner{Ctx: ctx, Cfg: viper.New(), URL: ts.URL, Hc: ts.Client()}
+		targets, err := s.Scan(image)
+		assert.Contains(t, err.Error(), "error putting blob "+blobIDs[0])
+		assert.Nil(t, targets)
+	})
+
+	t.Run("image already analyzed scanned successfully", func(t *testing.T) {
+		t.Parallel()
+		image, artifactID, blobIDs := pushImage(t, nil)
+		cfg := viper.New()
+		cfg.Set("scanner.trivyToken", "token")
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "token", r.Header.Get(trivyTokenHeader))
+			var req map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, artifactID, req["artifact_id"])
+			assert.Len(t, req["blob_ids"], len(blobIDs))
+			switch r.URL.Path {
+			case trivyMissingBlobsPath:
+				_, _ = w.Write([]byte(`{}`))
+			case trivyScanPath:
+				assert.Equal(t, image, req["target"])
+				_, _ = w.Write(sampleTrivyServerResponse)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(ts.Close)
+		s := &TrivyServerScanner{Ctx: ctx, Cfg: cfg, URL: ts.URL, Hc: ts.Client()}
+		targets, err := s.Scan(image)
+		require.NoError(t, err)
+		assert.Equal(t, sampleTrivyServerTargets, targets)
+	})
+
+	t.Run("image analyzed and scanned successfully", func(t *testing.T) {
+		t.Parallel()
+		image, artifactID, blobIDs := pushImage(t, [][]*testFile{
+			{
+				{name: "etc/alpine-release", content: "3.12.0\n"},
+				{name: "lib/apk/db/installed", content: sampleApkInstalled},
+				{name: "bin/sh", content: "binary"},
+			},
+			{
+				{name: "etc/.wh.motd"},
+				{name: "var/cache/.wh..wh..opq"},
+			},
+		})
+		var mu sync.Mutex
+		var calls []string
+		var blobs []*trivyBlobInfo
+		var artifact *trivyArtifactInfo
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, r.URL.Path)
+			switch r.URL.Path {
+			case trivyMissingBlobsPath:
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"missing_artifact": true,
+					"missing_blob_ids": blobIDs,
+				})
+			case trivyPutBlobPath:
+				var req struct {
+					DiffID   string         `json:"diff_id"`
+					BlobInfo *trivyBlobInfo `json:"blob_info"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				assert.Equal(t, req.DiffID, req.BlobInfo.DiffID)
+				blobs = append(blobs, req.BlobInfo)
+				_, _ = w.Write([]byte(`{}`))
+			case trivyPutArtifactPath:
+				var req struct {
+					ArtifactID   string             `json:"artifact_id"`
+					ArtifactInfo *trivyArtifactInfo `json:"artifact_info"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				assert.Equal(t, artifactID, req.ArtifactID)
+				artifact = req.ArtifactInfo
+				_, _ = w.Write([]byte(`{}`))
+			case trivyScanPath:
+				_, _ = w.Write(sampleTrivyServerResponse)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(ts.Close)
+		s := &TrivyServerScanner{Ctx: ctx, Cfg: viper.New(), URL: ts.URL, Hc: ts.Client()}
+		targets, err := s.Scan(image)
+		require.NoError(t, err)
+		assert.Equal(t, sampleTrivyServerTargets, targets)
+
+		// Check data pushed to the trivy server cache
+		assert.Equal(t, []string{
+			trivyMissingBlobsPath,
+			trivyPutBlobPath,
+			trivyPutBlobPath,
+			trivyPutArtifactPath,
+			trivyScanPath,
+		}, calls)
+		assert.Equal(t, &trivyArtifactInfo{
+			SchemaVersion: trivySchemaVersion,
+			Architecture:  "amd64",
+			Created:       "0001-01-01T00:00:00Z",
+			OS:            "linux",
+		}, artifact)
+		require.Len(t, blobs, 2)
+		assert.Equal(t, blobIDs[0], blobs[0].DiffID)
+		assert.Equal(t, &trivyOS{Family: "alpine", Name: "3.12.0"}, blobs[0].OS)
+		assert.Equal(t, []*trivyPackageInfo{
+			{
+				FilePath: "lib/apk/db/installed",
+				Packages: []*trivyPackage{
+					{
+						Name:       "musl",
+						Version:    "1.1.24-r8",
+						Arch:       "x86_64",
+						SrcName:    "musl",
+						SrcVersion: "1.1.24-r8",
+						License:    "MIT",
+					},
+					{
+						Name:       "libcrypto1.1",
+						Version:    "1.1.1g-r0",
+						Arch:       "x86_64",
+						SrcName:    "openssl",
+						SrcVersion: "1.1.1g-r0",
+						License:    "OpenSSL",
+					},
+				},
+			},
+		}, blobs[0].PackageInfos)
+		assert.Equal(t, blobIDs[1], blobs[1].DiffID)
+		assert.Nil(t, blobs[1].OS)
+		assert.Equal(t, []string{"etc/motd"}, blobs[1].WhiteoutFiles)
+		assert.Equal(t, []string{"var/cache/"}, blobs[1].OpaqueDirs)
+	})
+}
+
+func TestDetectOS(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		files      map[string][]byte
+		expectedOS *trivyOS
+	}{
+		{
+			"no os release files",
+			map[string][]byte{},
+			nil,
+		},
+		{
+			"debian",
+			map[string][]byte{
+				debianVersionFile: []byte("10.7\n"),
+			},
+			&trivyOS{Family: "debian", Name: "10.7"},
+		},
+		{
+			"ubuntu takes precedence over debian",
+			map[string][]byte{
+				debianVersionFile: []byte("bullseye/sid\n"),
+				lsbReleaseFile:    []byte("DISTRIB_ID=Ubuntu\nDISTRIB_RELEASE=20.04\nDISTRIB_CODENAME=focal\n"),
+			},
+			&trivyOS{Family: "ubuntu", Name: "20.04"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedOS, detectOS(tc.files))
+		})
+	}
+}
+
+func TestParseDpkgStatus(t *testing.T) {
+	pkgs := parseDpkgStatus([]byte(sampleDpkgStatus))
+	assert.Equal(t, []*trivyPackage{
+		{
+			Name:       "libc6",
+			Version:    "2.28",
+			Release:    "10",
+			Arch:       "amd64",
+			SrcName:    "glibc",
+			SrcVersion: "2.28",
+			SrcRelease: "10",
+		},
+		{
+			Name:       "tzdata",
+			Version:    "2020d",
+			Release:    "0+deb10u1",
+			Arch:       "all",
+			SrcName:    "tzdata",
+			SrcVersion: "2020d",
+			SrcRelease: "0+deb10u1",
+		},
+		{
+			Name:       "libgcrypt20",
+			Epoch:      1,
+			Version:    "1.8.4",
+			Release:    "5",
+			Arch:       "amd64",
+			SrcName:    "libgcrypt20",
+			SrcEpoch:   1,
+			SrcVersion: "1.8.4",
+			SrcRelease: "5",
+		},
+	}, pkgs)
+}
+
+func setupRegistry(t *testing.T) string {
+	t.Helper()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	u, _ := url.Parse(s.URL)
+	return u.Host
+}
+
+// testFile represents a file included in the layers of a test image.
+type testFile struct {
+	name    string
+	content string
+}
+
+// pushImage pushes an image with the layers provided to a test registry,
+// returning the image reference as well as its artifact and blobs ids. When
+// no layers are provided, a random image is used.
+func pushImage(t *testing.T, layers [][]*testFile) (string, string, []string) {
+	t.Helper()
+	host := setupRegistry(t)
+	image := host + "/repo/image:1.0.0"
+	var img v1.Image
+	var err error
+	if layers == nil {
+		img, err = random.Image(64, 2)
+		require.NoError(t, err)
+	} else {
+		img, err = mutate.ConfigFile(empty.Image, &v1.ConfigFile{
+			Architecture: "amd64",
+			OS:           "linux",
+		})
+		require.NoError(t, err)
+		for _, files := range layers {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, f := range files {
+				require.NoError(t, tw.WriteHeader(&tar.Header{
+					Name:     f.name,
+					Mode:     0644,
+					Size:     int64(len(f.content)),
+					Typeflag: tar.TypeReg,
+				}))
+				_, err := tw.Write([]byte(f.content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, tw.Close())
+			layer, err := tarball.LayerFromReader(&buf)
+			require.NoError(t, err)
+			img, err = mutate.AppendLayers(img, layer)
+			require.NoError(t, err)
+		}
+	}
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+	artifactID, blobIDs, err := getImageIDs(img)
+	require.NoError(t, err)
+	return image, artifactID, blobIDs
+}
+
+var sampleApkInstalled = `C:Q1g5lSBs6Bd0IlsZtANhbdSpUSGFo=
+P:musl
+V:1.1.24-r8
+A:x86_64
+S:377218
+I:622592
+T:the musl c library (libc) implementation
+U:https://musl.libc.org/
+L:MIT
+o:musl
+
+C:Q1h8Ajc0EaS03KuWyJxjo6XcnlVBA=
+P:libcrypto1.1
+V:1.1.1g-r0
+A:x86_64
+L:OpenSSL
+o:openssl
+`
+
+var sampleDpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc
+Version: 2.28-10
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: tzdata
+Status: install ok installed
+Architecture: all
+Version: 2020d-0+deb10u1
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0-1
+
+Package: libgcrypt20
+Status: install ok installed
+Architecture: amd64
+Version: 1:1.8.4-5
+Source: libgcrypt20 (1:1.8.4-5)
+`
+
+var sampleTrivyServerTargets = []*hub.SecurityReportTarget{
+	{
+		Target: "image (alpine 3.12.0)",
+		Type:   "alpine",
+		Vulnerabilities: []*hub.Vulnerability{
+			{
+				VulnerabilityID:  "CVE-2020-28928",
+				PkgName:          "musl",
+				InstalledVersion: "1.1.24-r8",
+				FixedVersion:     "1.1.24-r10",
+				Severity:         SeverityMedium,
+				SeveritySource:   "nvd",
+				Title:            "In musl libc through 1.2.1, wcsnrtombs mishandles particular combinations",
+				References:       []string{"http://www.openwall.com/lists/oss-security/2020/11/20/4"},
+				CVSS: map[string]*hub.CVSS{
+					"nvd": {
+						V2Vector: "AV:L/AC:L/Au:N/C:N/I:N/A:P",
+						V3Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:N/I:N/A:H",
+						V2Score:  2.1,
+						V3Score:  5.5,
+					},
+				},
+				LastModifiedDate: "2020-12-08T10:15:00Z",
+			},
+		},
+	},
+}
+
+var sampleTrivyServerResponse = []byte(`
+{
+  "os": {
+    "family": "alpine",
+    "name": "3.12.0"
+  },
+  "results": [
+    {
+      "target": "image (alpine 3.12.0)",
+      "type": "alpine",
+      "vulnerabilities": [
+        {
+          "vulnerability_id": "CVE-2020-28928",
+          "pkg_name": "musl",
+          "installed_version": "1.1.24-r8",
+          "fixed_version": "1.1.24-r10",
+          "title": "In musl libc through 1.2.1, wcsnrtombs mishandles particular combinations",
+          "severity": "MEDIUM",
+          "severity_source": "nvd",
+          "references": ["http://www.openwall.com/lists/oss-security/2020/11/20/4"],
+          "cvss": {
+            "nvd": {
+              "v2_vector": "AV:L/AC:L/Au:N/C:N/I:N/A:P",
+              "v3_vector": "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:N/I:N/A:H",
+              "v2_score": 2.1,
+              "v3_score": 5.5
+            }
+          },
+          "last_modified_date": "2020-12-08T10:15:00Z"
+        }
+      ]
+    }
+  ]
+}
+`)
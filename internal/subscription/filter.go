@@ -0,0 +1,98 @@
+package subscription
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// updateLevelsRank represents the rank of each of the supported update levels.
+// Higher ranks represent more significant updates.
+var updateLevelsRank = map[hub.UpdateLevel]int{
+	hub.PatchUpdate: 1,
+	hub.MinorUpdate: 2,
+	hub.MajorUpdate: 3,
+}
+
+// ValidateFilter checks if the subscription filter provided is valid.
+func ValidateFilter(f *hub.SubscriptionFilter) error {
+	if f == nil {
+		return nil
+	}
+	if f.VersionConstraint != "" {
+		if _, err := semver.NewConstraint(f.VersionConstraint); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid version constraint")
+		}
+	}
+	if f.UpdateLevel != "" {
+		if _, ok := updateLevelsRank[f.UpdateLevel]; !ok {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid update level")
+		}
+	}
+	return nil
+}
+
+// MatchesFilter checks if the event provided matches the subscription filter.
+// Filters only apply to new release events, so events of any other kind always
+// match. When some of the information needed to evaluate a condition is not
+// available (i.e. the version is not a valid semver), the condition is
+// considered to be satisfied to avoid missing notifications.
+func MatchesFilter(f *hub.SubscriptionFilter, e *hub.Event) bool {
+	if f == nil || e.EventKind != hub.NewRelease {
+		return true
+	}
+
+	// Security updates
+	if f.SecurityUpdatesOnly {
+		if containsSecurityUpdates, _ := e.Data["contains_security_updates"].(bool); !containsSecurityUpdates {
+			return false
+		}
+	}
+
+	// Prereleases
+	v, err := semver.NewVersion(e.PackageVersion)
+	if f.ExcludePrereleases {
+		if prerelease, _ := e.Data["prerelease"].(bool); prerelease {
+			return false
+		}
+		if err == nil && v.Prerelease() != "" {
+			return false
+		}
+	}
+	if err != nil {
+		return true
+	}
+
+	// Version constraint
+	if f.VersionConstraint != "" {
+		c, err := semver.NewConstraint(f.VersionConstraint)
+		if err == nil && !c.Check(v) {
+			return false
+		}
+	}
+
+	// Update level
+	if f.UpdateLevel != "" {
+		previousVersion, _ := e.Data["previous_version"].(string)
+		pv, err := semver.NewVersion(previousVersion)
+		if err == nil && updateLevelsRank[getUpdateLevel(pv, v)] < updateLevelsRank[f.UpdateLevel] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getUpdateLevel returns the level of the update from the previous version to
+// the new one.
+func getUpdateLevel(previous, new *semver.Version) hub.UpdateLevel {
+	switch {
+	case new.Major() != previous.Major():
+		return hub.MajorUpdate
+	case new.Minor() != previous.Minor():
+		return hub.MinorUpdate
+	default:
+		return hub.PatchUpdate
+	}
+}
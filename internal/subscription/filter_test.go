@@ -0,0 +1,172 @@
+package subscription
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFilter(t *testing.T) {
+	testCases := []struct {
+		f      *hub.SubscriptionFilter
+		errMsg string
+	}{
+		{
+			nil,
+			"",
+		},
+		{
+			&hub.SubscriptionFilter{
+				VersionConstraint:   ">=2.0.0 <3",
+				UpdateLevel:         hub.MinorUpdate,
+				ExcludePrereleases:  true,
+				SecurityUpdatesOnly: true,
+			},
+			"",
+		},
+		{
+			&hub.SubscriptionFilter{
+				VersionConstraint: "invalid",
+			},
+			"invalid version constraint",
+		},
+		{
+			&hub.SubscriptionFilter{
+				UpdateLevel: "invalid",
+			},
+			"invalid update level",
+		},
+	}
+	for _, tc := range testCases {
+		err := ValidateFilter(tc.f)
+		if tc.errMsg == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+			assert.Contains(t, err.Error(), tc.errMsg)
+		}
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	newRelease := func(version string, data map[string]interface{}) *hub.Event {
+		return &hub.Event{
+			EventKind:      hub.NewRelease,
+			PackageVersion: version,
+			Data:           data,
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		f        *hub.SubscriptionFilter
+		e        *hub.Event
+		expected bool
+	}{
+		{
+			"no filter",
+			nil,
+			newRelease("1.0.0", nil),
+			true,
+		},
+		{
+			"filters do not apply to other event kinds",
+			&hub.SubscriptionFilter{SecurityUpdatesOnly: true},
+			&hub.Event{EventKind: hub.SecurityAlert, PackageVersion: "1.0.0"},
+			true,
+		},
+		{
+			"security updates only, release without security updates",
+			&hub.SubscriptionFilter{SecurityUpdatesOnly: true},
+			newRelease("1.0.1", map[string]interface{}{"contains_security_updates": false}),
+			false,
+		},
+		{
+			"security updates only, release with security updates",
+			&hub.SubscriptionFilter{SecurityUpdatesOnly: true},
+			newRelease("1.0.1", map[string]interface{}{"contains_security_updates": true}),
+			true,
+		},
+		{
+			"exclude prereleases, prerelease flag set",
+			&hub.SubscriptionFilter{ExcludePrereleases: true},
+			newRelease("1.0.1", map[string]interface{}{"prerelease": true}),
+			false,
+		},
+		{
+			"exclude prereleases, semver prerelease",
+			&hub.SubscriptionFilter{ExcludePrereleases: true},
+			newRelease("2.0.0-rc.1", nil),
+			false,
+		},
+		{
+			"exclude prereleases, regular release",
+			&hub.SubscriptionFilter{ExcludePrereleases: true},
+			newRelease("2.0.0", map[string]interface{}{"prerelease": false}),
+			true,
+		},
+		{
+			"version constraint not satisfied",
+			&hub.SubscriptionFilter{VersionConstraint: ">=2.0.0 <3"},
+			newRelease("3.0.0", nil),
+			false,
+		},
+		{
+			"version constraint satisfied",
+			&hub.SubscriptionFilter{VersionConstraint: ">=2.0.0 <3"},
+			newRelease("2.1.0", nil),
+			true,
+		},
+		{
+			"version not valid semver",
+			&hub.SubscriptionFilter{VersionConstraint: ">=2.0.0 <3"},
+			newRelease("latest", nil),
+			true,
+		},
+		{
+			"minor update level, patch update",
+			&hub.SubscriptionFilter{UpdateLevel: hub.MinorUpdate},
+			newRelease("1.2.4", map[string]interface{}{"previous_version": "1.2.3"}),
+			false,
+		},
+		{
+			"minor update level, minor update",
+			&hub.SubscriptionFilter{UpdateLevel: hub.MinorUpdate},
+			newRelease("1.3.0", map[string]interface{}{"previous_version": "1.2.3"}),
+			true,
+		},
+		{
+			"minor update level, major update",
+			&hub.SubscriptionFilter{UpdateLevel: hub.MinorUpdate},
+			newRelease("2.0.0", map[string]interface{}{"previous_version": "1.2.3"}),
+			true,
+		},
+		{
+			"major update level, minor update",
+			&hub.SubscriptionFilter{UpdateLevel: hub.MajorUpdate},
+			newRelease("1.3.0", map[string]interface{}{"previous_version": "1.2.3"}),
+			false,
+		},
+		{
+			"patch update level, patch update",
+			&hub.SubscriptionFilter{UpdateLevel: hub.PatchUpdate},
+			newRelease("1.2.4", map[string]interface{}{"previous_version": "1.2.3"}),
+			true,
+		},
+		{
+			"major update level, previous version not available",
+			&hub.SubscriptionFilter{UpdateLevel: hub.MajorUpdate},
+			newRelease("1.3.0", nil),
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, MatchesFilter(tc.f, tc.e))
+		})
+	}
+}
@@ -11,15 +11,19 @@ import (
 
 const (
 	// Database queries
-	addOptOutDBQ               = `select add_opt_out($1::jsonb)`
-	addSubscriptionDBQ         = `select add_subscription($1::jsonb)`
-	deleteOptOutDBQ            = `select delete_opt_out($1::uuid, $2::uuid)`
-	deleteSubscriptionDBQ      = `select delete_subscription($1::jsonb)`
-	getPkgSubscriptorsDBQ      = `select get_package_subscriptors($1::uuid, $2::integer)`
-	getRepoSubscriptorsDBQ     = `select get_repository_subscriptors($1::uuid, $2::integer)`
-	getUserOptOutEntriesDBQ    = `select get_user_opt_out_entries($1::uuid)`
-	getUserPkgSubscriptionsDBQ = `select get_user_package_subscriptions($1::uuid, $2::uuid)`
-	getUserSubscriptionsDBQ    = `select get_user_subscriptions($1::uuid)`
+	addOptOutDBQ                = `select add_opt_out($1::jsonb)`
+	addSubscriptionDBQ          = `select add_subscription($1::jsonb)`
+	deleteOptOutDBQ             = `select delete_opt_out($1::uuid, $2::uuid)`
+	deleteSubscriptionDBQ       = `select delete_subscription($1::jsonb)`
+	getPkgSubscriptorsDBQ       = `select get_package_subscriptors($1::uuid, $2::integer)`
+	getRepoEventSubscriptorsDBQ = `select get_repository_event_subscriptors($1::uuid, $2::integer)`
+	getRepoSubscriptorsDBQ      = `select get_repository_subscriptors($1::uuid, $2::integer)`
+	getUserOptOutEntriesDBQ     = `select get_user_opt_out_entries($1::uuid)`
+	getUserOrgSubscriptionsDBQ  = `select get_user_organizations_subscriptions($1::uuid)`
+	getUserPkgSubscriptionsDBQ  = `select get_user_package_subscriptions($1::uuid, $2::uuid)`
+	getUserRepoSubscriptionsDBQ = `select get_user_repositories_subscriptions($1::uuid)`
+	getUserSubscriptionsDBQ     = `select get_user_subscriptions($1::uuid)`
+	unsubscribeDBQ              = `select unsubscribe($1::jsonb)`
 )
 
 // Manager provides an API to manage subscriptions.
@@ -117,17 +121,44 @@ func (m *Manager) GetOptOutListJSON(ctx context.Context) ([]byte, error) {
 	return dataJSON, nil
 }
 
-// GetSubscriptors returns the users subscribed to receive notifications for
-// certain kind of events.
-func (m *Manager) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.User, error) {
+// GetOrganizationsByUserJSON returns the organizations subscriptions of the
+// user doing the request as a json array of objects.
+func (m *Manager) GetOrganizationsByUserJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserOrgSubscriptionsDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetRepositoriesByUserJSON returns the repositories subscriptions of the
+// user doing the request as a json array of objects.
+func (m *Manager) GetRepositoriesByUserJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserRepoSubscriptionsDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetSubscriptors returns the subscriptions of the users subscribed to receive
+// notifications for certain kind of events. For package events, this includes
+// the users subscribed to the repository or organization the package belongs
+// to. Each subscription includes the filter that must be applied to the event
+// (if any) before notifying the user.
+func (m *Manager) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.Subscription, error) {
 	var dataJSON []byte
 	var err error
 	switch e.EventKind {
-	case hub.NewRelease:
+	case hub.NewRelease, hub.SecurityAlert, hub.PackageDeprecated:
 		err = m.db.QueryRow(ctx, getPkgSubscriptorsDBQ, e.PackageID, e.EventKind).Scan(&dataJSON)
 	case hub.RepositoryTrackingErrors:
 		err = m.db.QueryRow(ctx, getRepoSubscriptorsDBQ, e.RepositoryID, e.EventKind).Scan(&dataJSON)
-	case hub.RepositoryOwnershipClaim:
+	case hub.RepositoryVerifiedPublisherChanged:
+		err = m.db.QueryRow(ctx, getRepoEventSubscriptorsDBQ, e.RepositoryID, e.EventKind).Scan(&dataJSON)
+	case hub.RepositoryOwnershipClaim, hub.PackageVersionRemoved, hub.RepositoryTransferred:
 		dataJSON, _ = json.Marshal(e.Data["subscriptors"])
 	default:
 		return nil, nil
@@ -135,22 +166,83 @@ func (m *Manager) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.Use
 	if err != nil {
 		return nil, err
 	}
-	var subscriptors []*hub.User
+	var subscriptors []*hub.Subscription
 	if err := json.Unmarshal(dataJSON, &subscriptors); err != nil {
 		return nil, err
 	}
 	return subscriptors, nil
 }
 
+// Unsubscribe stops the notifications described in the unsubscribe request
+// provided from being delivered to the user. The user is taken from the
+// request, as unsubscribe requests do not require users to be logged in.
+func (m *Manager) Unsubscribe(ctx context.Context, r *hub.UnsubscribeRequest) error {
+	if _, err := uuid.FromString(r.UserID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid user id")
+	}
+	switch r.EventKind {
+	case hub.NewRelease, hub.SecurityAlert, hub.PackageDeprecated:
+		if _, err := uuid.FromString(r.PackageID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	case hub.PackageVersionRemoved:
+		// The package may have been deleted when its last version was removed,
+		// in which case the request refers to the package's repository
+		if r.PackageID != "" {
+			if _, err := uuid.FromString(r.PackageID); err != nil {
+				return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+			}
+		} else if _, err := uuid.FromString(r.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	case hub.RepositoryTrackingErrors, hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+		if _, err := uuid.FromString(r.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	default:
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+	}
+	rJSON, _ := json.Marshal(r)
+	_, err := m.db.Exec(ctx, unsubscribeDBQ, rJSON)
+	return err
+}
+
 // validateSubscription checks if the subscription provided is valid to be used
 // as input for some database functions calls.
 func validateSubscription(s *hub.Subscription) error {
-	if _, err := uuid.FromString(s.PackageID); err != nil {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	var targets int
+	for _, target := range []string{s.PackageID, s.RepositoryID, s.OrganizationName} {
+		if target != "" {
+			targets++
+		}
 	}
-	if s.EventKind != hub.NewRelease {
+	if targets != 1 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "a package, repository or organization must be provided")
+	}
+	switch {
+	case s.PackageID != "":
+		if _, err := uuid.FromString(s.PackageID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	case s.RepositoryID != "":
+		if _, err := uuid.FromString(s.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	switch s.EventKind {
+	case hub.NewRelease, hub.SecurityAlert, hub.PackageVersionRemoved, hub.PackageDeprecated:
+	case hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+		// Repository events subscriptions are only supported at the repository
+		// and organization levels
+		if s.PackageID != "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+		}
+	default:
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
+	if err := ValidateFilter(s.Filter); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -160,7 +252,15 @@ func validateOptOut(o *hub.OptOut) error {
 	if _, err := uuid.FromString(o.RepositoryID); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
 	}
-	if o.EventKind != hub.RepositoryTrackingErrors {
+	switch o.EventKind {
+	case hub.NewRelease,
+		hub.SecurityAlert,
+		hub.RepositoryTrackingErrors,
+		hub.PackageVersionRemoved,
+		hub.PackageDeprecated,
+		hub.RepositoryTransferred,
+		hub.RepositoryVerifiedPublisherChanged:
+	default:
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
 	return nil
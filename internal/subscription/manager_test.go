@@ -40,11 +40,55 @@ func TestAdd(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"a package, repository or organization must be provided",
+				&hub.Subscription{},
+			},
+			{
+				"a package, repository or organization must be provided",
+				&hub.Subscription{
+					PackageID:    packageID,
+					RepositoryID: repositoryID,
+				},
+			},
+			{
+				"invalid repository id",
+				&hub.Subscription{
+					RepositoryID: "invalid",
+				},
+			},
+			{
+				"invalid event kind",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.EventKind(9),
+				},
+			},
 			{
 				"invalid event kind",
 				&hub.Subscription{
 					PackageID: packageID,
-					EventKind: hub.EventKind(5),
+					EventKind: hub.RepositoryTransferred,
+				},
+			},
+			{
+				"invalid version constraint",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.NewRelease,
+					Filter: &hub.SubscriptionFilter{
+						VersionConstraint: "invalid",
+					},
+				},
+			},
+			{
+				"invalid update level",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.NewRelease,
+					Filter: &hub.SubscriptionFilter{
+						UpdateLevel: "invalid",
+					},
 				},
 			},
 		}
@@ -89,6 +133,51 @@ func TestAdd(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("database query succeeded (security alert subscription)", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addSubscriptionDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		s := &hub.Subscription{
+			PackageID: packageID,
+			EventKind: hub.SecurityAlert,
+		}
+		err := m.Add(ctx, s)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded (repository subscription)", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addSubscriptionDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		s := &hub.Subscription{
+			RepositoryID: repositoryID,
+			EventKind:    hub.NewRelease,
+		}
+		err := m.Add(ctx, s)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded (organization subscription)", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addSubscriptionDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		s := &hub.Subscription{
+			OrganizationName: "org1",
+			EventKind:        hub.SecurityAlert,
+		}
+		err := m.Add(ctx, s)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestAddOptOut(t *testing.T) {
@@ -163,6 +252,21 @@ func TestAddOptOut(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("database query succeeded (new release opt-out)", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addOptOutDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		o := &hub.OptOut{
+			RepositoryID: repositoryID,
+			EventKind:    hub.NewRelease,
+		}
+		err := m.AddOptOut(ctx, o)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -187,11 +291,55 @@ func TestDelete(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"a package, repository or organization must be provided",
+				&hub.Subscription{},
+			},
+			{
+				"a package, repository or organization must be provided",
+				&hub.Subscription{
+					PackageID:    packageID,
+					RepositoryID: repositoryID,
+				},
+			},
+			{
+				"invalid repository id",
+				&hub.Subscription{
+					RepositoryID: "invalid",
+				},
+			},
 			{
 				"invalid event kind",
 				&hub.Subscription{
 					PackageID: packageID,
-					EventKind: hub.EventKind(5),
+					EventKind: hub.EventKind(9),
+				},
+			},
+			{
+				"invalid event kind",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.RepositoryTransferred,
+				},
+			},
+			{
+				"invalid version constraint",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.NewRelease,
+					Filter: &hub.SubscriptionFilter{
+						VersionConstraint: "invalid",
+					},
+				},
+			},
+			{
+				"invalid update level",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.NewRelease,
+					Filter: &hub.SubscriptionFilter{
+						UpdateLevel: "invalid",
+					},
 				},
 			},
 		}
@@ -410,16 +558,105 @@ func TestGetOptOutListJSON(t *testing.T) {
 	})
 }
 
+func TestGetOrganizationsByUserJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, userID)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetOrganizationsByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserOrgSubscriptionsDBQ, userID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOrganizationsByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserOrgSubscriptionsDBQ, userID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOrganizationsByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetRepositoriesByUserJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, userID)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetRepositoriesByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserRepoSubscriptionsDBQ, userID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRepositoriesByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserRepoSubscriptionsDBQ, userID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRepositoriesByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetSubscriptors(t *testing.T) {
 	ctx := context.Background()
 	pkgNewReleaseEvent := &hub.Event{
 		PackageID: packageID,
 		EventKind: hub.NewRelease,
 	}
+	pkgSecurityAlertEvent := &hub.Event{
+		PackageID: packageID,
+		EventKind: hub.SecurityAlert,
+	}
 	repoTrackingErrorsEvent := &hub.Event{
 		RepositoryID: repositoryID,
 		EventKind:    hub.RepositoryTrackingErrors,
 	}
+	repoVerifiedPublisherChangedEvent := &hub.Event{
+		RepositoryID: repositoryID,
+		EventKind:    hub.RepositoryVerifiedPublisherChanged,
+	}
+	pkgVersionRemovedEvent := &hub.Event{
+		RepositoryID: repositoryID,
+		EventKind:    hub.PackageVersionRemoved,
+		Data: map[string]interface{}{
+			"subscriptors": []map[string]string{
+				{"user_id": "00000000-0000-0000-0000-000000000001"},
+			},
+		},
+	}
 	repoOwnershipClaimEvent := &hub.Event{
 		RepositoryID: repositoryID,
 		EventKind:    hub.RepositoryOwnershipClaim,
@@ -445,12 +682,16 @@ func TestGetSubscriptors(t *testing.T) {
 
 	t.Run("database query succeeded (pkg new release event)", func(t *testing.T) {
 		t.Parallel()
-		expectedSubscriptors := []*hub.User{
+		expectedSubscriptors := []*hub.Subscription{
 			{
 				UserID: "00000000-0000-0000-0000-000000000001",
 			},
 			{
 				UserID: "00000000-0000-0000-0000-000000000002",
+				Filter: &hub.SubscriptionFilter{
+					UpdateLevel:        hub.MinorUpdate,
+					ExcludePrereleases: true,
+				},
 			},
 		}
 
@@ -462,7 +703,11 @@ func TestGetSubscriptors(t *testing.T) {
 				"user_id": "00000000-0000-0000-0000-000000000001"
 			},
 			{
-				"user_id": "00000000-0000-0000-0000-000000000002"
+				"user_id": "00000000-0000-0000-0000-000000000002",
+				"filter": {
+					"update_level": "minor",
+					"exclude_prereleases": true
+				}
 			}
 		]
 		`), nil)
@@ -474,9 +719,34 @@ func TestGetSubscriptors(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("database query succeeded (pkg security alert event)", func(t *testing.T) {
+		t.Parallel()
+		expectedSubscriptors := []*hub.Subscription{
+			{
+				UserID: "00000000-0000-0000-0000-000000000001",
+			},
+		}
+
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgSubscriptorsDBQ, packageID, pkgSecurityAlertEvent.EventKind).
+			Return([]byte(`
+		[
+			{
+				"user_id": "00000000-0000-0000-0000-000000000001"
+			}
+		]
+		`), nil)
+		m := NewManager(db)
+
+		subscriptors, err := m.GetSubscriptors(context.Background(), pkgSecurityAlertEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSubscriptors, subscriptors)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("database query succeeded (repo tracking errors event)", func(t *testing.T) {
 		t.Parallel()
-		expectedSubscriptors := []*hub.User{
+		expectedSubscriptors := []*hub.Subscription{
 			{
 				UserID: "00000000-0000-0000-0000-000000000001",
 			},
@@ -496,7 +766,7 @@ func TestGetSubscriptors(t *testing.T) {
 
 	t.Run("database query succeeded (repo ownership claim event)", func(t *testing.T) {
 		t.Parallel()
-		expectedSubscriptors := []*hub.User{
+		expectedSubscriptors := []*hub.Subscription{
 			{
 				UserID: "00000000-0000-0000-0000-000000000001",
 			},
@@ -524,4 +794,114 @@ func TestGetSubscriptors(t *testing.T) {
 		assert.Equal(t, expectedSubscriptors, subscriptors)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("database query succeeded (repo verified publisher changed event)", func(t *testing.T) {
+		t.Parallel()
+		expectedSubscriptors := []*hub.Subscription{
+			{
+				UserID: "00000000-0000-0000-0000-000000000001",
+			},
+		}
+
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoEventSubscriptorsDBQ, repositoryID, repoVerifiedPublisherChangedEvent.EventKind).
+			Return([]byte(`
+		[
+			{
+				"user_id": "00000000-0000-0000-0000-000000000001"
+			}
+		]
+		`), nil)
+		m := NewManager(db)
+
+		subscriptors, err := m.GetSubscriptors(context.Background(), repoVerifiedPublisherChangedEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSubscriptors, subscriptors)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("subscriptors included in event (pkg version removed event)", func(t *testing.T) {
+		t.Parallel()
+		expectedSubscriptors := []*hub.Subscription{
+			{
+				UserID: "00000000-0000-0000-0000-000000000001",
+			},
+		}
+
+		m := NewManager(nil)
+
+		subscriptors, err := m.GetSubscriptors(context.Background(), pkgVersionRemovedEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSubscriptors, subscriptors)
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			r      *hub.UnsubscribeRequest
+		}{
+			{
+				"invalid user id",
+				&hub.UnsubscribeRequest{UserID: "invalid"},
+			},
+			{
+				"invalid package id",
+				&hub.UnsubscribeRequest{UserID: userID, EventKind: hub.NewRelease, PackageID: "invalid"},
+			},
+			{
+				"invalid repository id",
+				&hub.UnsubscribeRequest{UserID: userID, EventKind: hub.RepositoryTrackingErrors},
+			},
+			{
+				"invalid repository id",
+				&hub.UnsubscribeRequest{UserID: userID, EventKind: hub.PackageVersionRemoved},
+			},
+			{
+				"invalid event kind",
+				&hub.UnsubscribeRequest{UserID: userID, EventKind: hub.RepositoryOwnershipClaim},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				err := m.Unsubscribe(ctx, tc.r)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	r := &hub.UnsubscribeRequest{
+		UserID:    userID,
+		EventKind: hub.NewRelease,
+		PackageID: packageID,
+	}
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, unsubscribeDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(db)
+
+		err := m.Unsubscribe(ctx, r)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, unsubscribeDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		err := m.Unsubscribe(ctx, r)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
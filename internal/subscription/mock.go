@@ -57,9 +57,29 @@ func (m *ManagerMock) GetOptOutListJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetOrganizationsByUserJSON implements the SubscriptionManager interface.
+func (m *ManagerMock) GetOrganizationsByUserJSON(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetRepositoriesByUserJSON implements the SubscriptionManager interface.
+func (m *ManagerMock) GetRepositoriesByUserJSON(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetSubscriptors implements the SubscriptionManager interface.
-func (m *ManagerMock) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.User, error) {
+func (m *ManagerMock) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.Subscription, error) {
 	args := m.Called(ctx, e)
-	data, _ := args.Get(0).([]*hub.User)
+	data, _ := args.Get(0).([]*hub.Subscription)
 	return data, args.Error(1)
 }
+
+// Unsubscribe implements the SubscriptionManager interface.
+func (m *ManagerMock) Unsubscribe(ctx context.Context, r *hub.UnsubscribeRequest) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
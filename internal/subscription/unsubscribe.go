@@ -0,0 +1,85 @@
+package subscription
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// UnsubscribeTokenLifetime represents how long unsubscribe tokens are valid
+// since they are issued.
+const UnsubscribeTokenLifetime = 90 * 24 * time.Hour
+
+// unsubscribeTokenPayload represents the payload of an unsubscribe token.
+type unsubscribeTokenPayload struct {
+	Requests  []*hub.UnsubscribeRequest `json:"requests"`
+	ExpiresAt int64                     `json:"exp"`
+}
+
+// NewUnsubscribeToken returns a token that encodes the unsubscribe requests
+// provided, signed using the key provided, which is valid until the given
+// time. The token has the form payload.signature, where the payload is the
+// requests and the expiration time json encoded and both parts are encoded
+// using base64 url encoding.
+func NewUnsubscribeToken(key []byte, rs []*hub.UnsubscribeRequest, expiresAt time.Time) string {
+	payload, _ := json.Marshal(&unsubscribeTokenPayload{
+		Requests:  rs,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	return fmt.Sprintf("%s.%s",
+		base64.RawURLEncoding.EncodeToString(payload),
+		base64.RawURLEncoding.EncodeToString(signUnsubscribePayload(key, payload)),
+	)
+}
+
+// ParseUnsubscribeToken verifies the signature and expiration time of the
+// unsubscribe token provided and returns the unsubscribe requests encoded in
+// it.
+func ParseUnsubscribeToken(key []byte, token string) ([]*hub.UnsubscribeRequest, error) {
+	errInvalidToken := fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid unsubscribe token")
+	if len(key) == 0 {
+		return nil, errInvalidToken
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, errInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	if !hmac.Equal(signature, signUnsubscribePayload(key, payload)) {
+		return nil, errInvalidToken
+	}
+	var p *unsubscribeTokenPayload
+	if err := json.Unmarshal(payload, &p); err != nil || p == nil || len(p.Requests) == 0 {
+		return nil, errInvalidToken
+	}
+	for _, r := range p.Requests {
+		if r == nil {
+			return nil, errInvalidToken
+		}
+	}
+	if time.Now().Unix() > p.ExpiresAt {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "unsubscribe token expired")
+	}
+	return p.Requests, nil
+}
+
+// signUnsubscribePayload returns the HMAC-SHA256 signature of the payload
+// provided using the given key.
+func signUnsubscribePayload(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,72 @@
+package subscription
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	key := []byte("key")
+	rs := []*hub.UnsubscribeRequest{
+		{
+			UserID:    "00000000-0000-0000-0000-000000000001",
+			EventKind: hub.NewRelease,
+			PackageID: "00000000-0000-0000-0000-000000000001",
+		},
+		{
+			UserID:       "00000000-0000-0000-0000-000000000001",
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+		},
+	}
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("valid token", func(t *testing.T) {
+		t.Parallel()
+		token := NewUnsubscribeToken(key, rs, expiresAt)
+		rs2, err := ParseUnsubscribeToken(key, token)
+		require.NoError(t, err)
+		assert.Equal(t, rs, rs2)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		token := NewUnsubscribeToken(key, rs, expiresAt)
+		payload := strings.Split(token, ".")[0]
+		otherPayload := strings.Split(NewUnsubscribeToken(key, []*hub.UnsubscribeRequest{
+			{
+				UserID:    "00000000-0000-0000-0000-000000000002",
+				EventKind: hub.NewRelease,
+				PackageID: "00000000-0000-0000-0000-000000000001",
+			},
+		}, expiresAt), ".")[0]
+		testCases := []struct {
+			desc  string
+			key   []byte
+			token string
+		}{
+			{"key not provided", nil, token},
+			{"signed with a different key", []byte("other"), token},
+			{"missing signature", key, payload},
+			{"invalid payload encoding", key, "!." + strings.Split(token, ".")[1]},
+			{"tampered payload", key, otherPayload + "." + strings.Split(token, ".")[1]},
+			{"empty token", key, ""},
+			{"no requests", key, NewUnsubscribeToken(key, nil, expiresAt)},
+			{"expired token", key, NewUnsubscribeToken(key, rs, time.Now().Add(-time.Minute))},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.desc, func(t *testing.T) {
+				t.Parallel()
+				rs, err := ParseUnsubscribeToken(tc.key, tc.token)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Nil(t, rs)
+			})
+		}
+	})
+}
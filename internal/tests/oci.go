@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ociManifestMediaType represents the media type of an OCI image manifest.
+const ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+// OCIBlob represents a blob (config or layer) that is part of an OCI artifact.
+type OCIBlob struct {
+	MediaType   string
+	Data        []byte
+	Annotations map[string]string
+}
+
+// ociDescriptor represents the descriptor of a blob in an OCI manifest.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int               `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PushOCIArtifact pushes an artifact made of the config and layers provided to
+// the registry listening on the host given (usually a test registry created
+// using the go-containerregistry registry package). It returns the digest of
+// the artifact manifest.
+func PushOCIArtifact(host, repository, tag string, config *OCIBlob, layers ...*OCIBlob) (string, error) {
+	pushBlob := func(b *OCIBlob) (*ociDescriptor, error) {
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(b.Data))
+		u := fmt.Sprintf("http://%s/v2/%s/blobs/uploads/?digest=%s", host, repository, digest)
+		resp, err := http.Post(u, "application/octet-stream", bytes.NewReader(b.Data))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			return nil, fmt.Errorf("unexpected status code pushing blob: %d", resp.StatusCode)
+		}
+		return &ociDescriptor{
+			MediaType:   b.MediaType,
+			Digest:      digest,
+			Size:        len(b.Data),
+			Annotations: b.Annotations,
+		}, nil
+	}
+
+	// Push config and layers blobs
+	manifest := struct {
+		SchemaVersion int              `json:"schemaVersion"`
+		MediaType     string           `json:"mediaType"`
+		Config        *ociDescriptor   `json:"config"`
+		Layers        []*ociDescriptor `json:"layers"`
+	}{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Layers:        []*ociDescriptor{},
+	}
+	var err error
+	manifest.Config, err = pushBlob(config)
+	if err != nil {
+		return "", err
+	}
+	for _, layer := range layers {
+		desc, err := pushBlob(layer)
+		if err != nil {
+			return "", err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	// Push manifest
+	manifestJSON, _ := json.Marshal(manifest)
+	u := fmt.Sprintf("http://%s/v2/%s/manifests/%s", host, repository, tag)
+	req, _ := http.NewRequest("PUT", u, bytes.NewReader(manifestJSON))
+	req.Header.Set("Content-Type", ociManifestMediaType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("unexpected status code pushing manifest: %d", resp.StatusCode)
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(manifestJSON)), nil
+}
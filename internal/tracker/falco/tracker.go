@@ -108,15 +108,21 @@ func (t *Tracker) Track() error {
 			t.warn(fmt.Errorf("invalid package %s kind (%s)", md.Name, md.Kind))
 			return nil
 		}
+		digest, err := tracker.GetDigest(pkgPath)
+		if err != nil {
+			t.warn(fmt.Errorf("error calculating package %s version %s digest: %w", md.Name, md.Version, err))
+			return nil
+		}
 		key := fmt.Sprintf("%s@%s", md.Name, md.Version)
 		packagesAvailable[key] = struct{}{}
-		if _, ok := packagesRegistered[key]; ok && !bypassDigestCheck {
+		registeredDigest, ok := packagesRegistered[key]
+		if ok && registeredDigest == digest && !bypassDigestCheck {
 			return nil
 		}
 
 		// Register package
 		t.logger.Debug().Str("name", md.Name).Str("v", md.Version).Msg("registering package")
-		err = t.registerPackage(md, strings.TrimPrefix(pkgPath, basePath))
+		err = t.registerPackage(md, strings.TrimPrefix(pkgPath, basePath), digest)
 		if err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", md.Name, md.Version, err))
 		}
@@ -150,13 +156,16 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, md); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, md); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
 
 	return nil
 }
 
 // registerPackage registers a package version using the package metadata
 // provided.
-func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath string) error {
+func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath, digest string) error {
 	// Register logo image if needed
 	var logoURL, logoImageID string
 	if md.Icon != "" {
@@ -198,6 +207,7 @@ func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath string) error {
 		Description: md.ShortDescription,
 		Keywords:    md.Keywords,
 		Version:     md.Version,
+		Digest:      digest,
 		Readme:      md.Description,
 		Provider:    md.Vendor,
 		Data: map[string]interface{}{
@@ -30,6 +30,8 @@ func TestTracker(t *testing.T) {
 		URL:               "https://github.com/org1/repo1/path/to/packages",
 		VerifiedPublisher: false,
 	}
+	digest1, _ := tracker.GetDigest("testdata/path4/test.yaml")
+	digest2, _ := tracker.GetDigest("testdata/path5/test.yaml")
 
 	t.Run("error cloning repository", func(t *testing.T) {
 		t.Parallel()
@@ -132,9 +134,30 @@ func TestTracker(t *testing.T) {
 		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path4", nil)
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: r.RepositoryID}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test@0.1.0": "",
+			"test@0.1.0": digest1,
+		}, nil)
+		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("package version registered again because its content has changed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		tw := newTrackerWrapper(r)
+		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path4", nil)
+		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: r.RepositoryID}, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"test@0.1.0": "outdated-digest",
 		}, nil)
 		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
+		tw.pm.On("Register", tw.ctx, mock.MatchedBy(func(p *hub.Package) bool {
+			return p.Name == "test" && p.Version == "0.1.0" && p.Digest == digest1
+		})).Return(nil)
 
 		// Run tracker and check expectations
 		err := tw.t.Track()
@@ -157,6 +180,7 @@ func TestTracker(t *testing.T) {
 			Keywords:    []string{"kw1", "kw2"},
 			Readme:      "Description",
 			Version:     "0.1.0",
+			Digest:      digest1,
 			Provider:    "Sample provider",
 			Repository:  r,
 			Links: []*hub.Link{
@@ -189,7 +213,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"test@0.1.0": "",
-			"test@0.2.0": "",
+			"test@0.2.0": digest2,
 		}, nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
 			Name:       "test",
@@ -213,7 +237,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"test@0.1.0": "",
-			"test@0.2.0": "",
+			"test@0.2.0": digest2,
 		}, nil)
 
 		// Run tracker and check expectations
@@ -231,7 +255,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"test@0.1.0": "",
-			"test@0.2.0": "",
+			"test@0.2.0": digest2,
 		}, nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
 			Name:       "test",
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
@@ -21,9 +22,10 @@ import (
 // Tracker is in charge of tracking the packages available in repository,
 // registering and unregistering them as needed.
 type Tracker struct {
-	svc    *tracker.Services
-	r      *hub.Repository
-	logger zerolog.Logger
+	svc        *tracker.Services
+	r          *hub.Repository
+	cosignKeys []string
+	logger     zerolog.Logger
 }
 
 // NewTracker creates a new Tracker instance.
@@ -43,6 +45,9 @@ func NewTracker(
 	if t.svc.Rc == nil {
 		t.svc.Rc = &repo.Cloner{}
 	}
+	if t.svc.Sv == nil {
+		t.svc.Sv = &oci.SignatureVerifier{}
+	}
 	return t
 }
 
@@ -61,14 +66,24 @@ func (t *Tracker) Track() error {
 	var rmd *hub.RepositoryMetadata
 	rmd, _ = t.svc.Rm.GetMetadata(filepath.Join(basePath, hub.RepositoryMetadataFile))
 
+	// Load keys used to verify containers images signatures
+	var errs []error
+	t.cosignKeys, errs = tracker.GetCosignKeys(rmd)
+	for _, err := range errs {
+		t.warn(fmt.Errorf("error loading signing keys: %w", err))
+	}
+
 	// Load packages already registered from this repository
 	packagesRegistered, err := t.svc.Rm.GetPackagesDigest(t.svc.Ctx, t.r.RepositoryID)
 	if err != nil {
 		return fmt.Errorf("error getting registered packages: %w", err)
 	}
 
-	// Register available packages when needed
-	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	// Register available packages when needed. Packages must be registered
+	// again when the signing keys change, so that they are verified using the
+	// new ones.
+	keysDigest := tracker.GetSigningKeysDigest(rmd)
+	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck") || keysDigest != t.r.SigningKeysDigest
 	packagesAvailable := make(map[string]struct{})
 	err = filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -95,10 +110,21 @@ func (t *Tracker) Track() error {
 			return nil
 		}
 
-		// Check if this package version is already registered
+		// Calculate package version digest when not provided in the metadata
+		if pvmd.Digest == "" {
+			pvmd.Digest, err = getPackageDigest(pkgPath, pvmd)
+			if err != nil {
+				t.warn(fmt.Errorf("error calculating package %s version %s digest: %w", pvmd.Name, pvmd.Version, err))
+				return nil
+			}
+		}
+
+		// Check if this package version is already registered and has not
+		// been updated
 		key := fmt.Sprintf("%s@%s", pvmd.Name, pvmd.Version)
 		packagesAvailable[key] = struct{}{}
-		if _, ok := packagesRegistered[key]; ok && !bypassDigestCheck {
+		digest, ok := packagesRegistered[key]
+		if ok && digest == pvmd.Digest && !bypassDigestCheck {
 			return nil
 		}
 
@@ -165,6 +191,12 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
+	if err := tracker.SetSigningKeysDigest(t.svc.Ctx, t.svc.Rm, t.r, keysDigest); err != nil {
+		t.warn(err)
+	}
 
 	return nil
 }
@@ -197,6 +229,11 @@ func (t *Tracker) registerPackage(pkgPath string, md *hub.PackageMetadata, logoI
 	}
 	p.Data = data
 
+	// Verify containers images signatures
+	for _, err := range tracker.VerifyContainersImagesSignatures(t.svc.Ctx, t.svc.Sv, t.cosignKeys, p.ContainersImages) {
+		t.warn(fmt.Errorf("package %s version %s: %w", md.Name, md.Version, err))
+	}
+
 	// Register package
 	return t.svc.Pm.Register(t.svc.Ctx, p)
 }
@@ -218,6 +255,20 @@ func (t *Tracker) warn(err error) {
 	t.logger.Warn().Err(err).Send()
 }
 
+// getPackageDigest returns the digest of the package version located in the
+// path provided. The logo file is also taken into account when it's located
+// outside of the package version directory.
+func getPackageDigest(pkgPath string, md *hub.PackageMetadata) (string, error) {
+	paths := []string{pkgPath}
+	if md.LogoPath != "" {
+		logoPath := filepath.Join(pkgPath, md.LogoPath)
+		if !strings.HasPrefix(logoPath, pkgPath+string(filepath.Separator)) {
+			paths = append(paths, logoPath)
+		}
+	}
+	return tracker.GetDigest(paths...)
+}
+
 // prepareFalcoData reads and formats Falco specific data available in the path
 // provided, returning the resulting data structure.
 func prepareFalcoData(pkgPath string, ignorer ignore.IgnoreParser) (map[string]interface{}, error) {
@@ -2,6 +2,11 @@ package generic
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -9,6 +14,7 @@ import (
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tests"
@@ -17,6 +23,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -188,6 +195,88 @@ func TestTracker(t *testing.T) {
 		tw.assertExpectations(t)
 	})
 
+	t.Run("(opa) no need to register package version because its content has not changed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		digest, _ := tracker.GetDigest("testdata/path8")
+		tw := newTrackerWrapper(rOPA)
+		tw.rc.On("CloneRepository", tw.ctx, rOPA).Return(".", "testdata/path8", nil)
+		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: rOPA.RepositoryID}, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, rOPA.RepositoryID).Return(map[string]string{
+			"package-name@1.0.0": digest,
+		}, nil)
+		tw.rm.On("SetVerifiedPublisher", tw.ctx, rOPA.RepositoryID, true).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("(opa) package version registered again because its content has changed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		digest, _ := tracker.GetDigest("testdata/path8")
+		tw := newTrackerWrapper(rOPA)
+		tw.rc.On("CloneRepository", tw.ctx, rOPA).Return(".", "testdata/path8", nil)
+		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: rOPA.RepositoryID}, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, rOPA.RepositoryID).Return(map[string]string{
+			"package-name@1.0.0": "outdated-digest",
+		}, nil)
+		tw.rm.On("SetVerifiedPublisher", tw.ctx, rOPA.RepositoryID, true).Return(nil)
+		tw.is.On("SaveImage", tw.ctx, imageData).Return("logoImageID", nil)
+		tw.pm.On("Register", tw.ctx, mock.MatchedBy(func(p *hub.Package) bool {
+			return p.Name == "package-name" && p.Version == "1.0.0" && p.Digest == digest
+		})).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t)
+	})
+
+	t.Run("(opa) package version registered again because the signing keys have changed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+		keys := []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+		md := &hub.RepositoryMetadata{
+			RepositoryID: rOPA.RepositoryID,
+			SigningKeys: &hub.RepositorySigningKeys{
+				Cosign: keys,
+			},
+		}
+		cv := &hub.CosignVerification{Verified: true, Digest: "sha256:1"}
+		digest, _ := tracker.GetDigest("testdata/path8")
+		tw := newTrackerWrapper(rOPA)
+		tw.rc.On("CloneRepository", tw.ctx, rOPA).Return(".", "testdata/path8", nil)
+		tw.rm.On("GetMetadata", mock.Anything).Return(md, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, rOPA.RepositoryID).Return(map[string]string{
+			"package-name@1.0.0": digest,
+		}, nil)
+		tw.rm.On("SetVerifiedPublisher", tw.ctx, rOPA.RepositoryID, true).Return(nil)
+		tw.rm.On("UpdateSigningKeysDigest", tw.ctx, rOPA.RepositoryID, tracker.GetSigningKeysDigest(md)).
+			Return(nil)
+		tw.is.On("SaveImage", tw.ctx, imageData).Return("logoImageID", nil)
+		tw.sv.On("Verify", tw.ctx, "registry/test/test:latest", keys, "", "").Return(cv, nil)
+		tw.pm.On("Register", tw.ctx, mock.MatchedBy(func(p *hub.Package) bool {
+			return p.Name == "package-name" &&
+				p.Version == "1.0.0" &&
+				p.ContainersImages[0].CosignVerification == cv
+		})).Return(nil)
+
+		// Run tracker and check expectations
+		err = tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t)
+	})
+
 	t.Run("(opa) no need to register package version because it is ignored", func(t *testing.T) {
 		t.Parallel()
 
@@ -347,7 +436,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"package-name@1.0.0": "",
-			"package-name@2.0.0": "",
+			"package-name@2.0.0": "0123456789",
 		}, nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
 			Name:       "package-name",
@@ -371,7 +460,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"package-name@1.0.0": "",
-			"package-name@2.0.0": "",
+			"package-name@2.0.0": "0123456789",
 		}, nil)
 
 		// Run tracker and check expectations
@@ -389,7 +478,7 @@ func TestTracker(t *testing.T) {
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"package-name@1.0.0": "",
-			"package-name@2.0.0": "",
+			"package-name@2.0.0": "0123456789",
 		}, nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
 			Name:       "package-name",
@@ -418,7 +507,7 @@ func TestTracker(t *testing.T) {
 		}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
 			"package-name@1.0.0": "",
-			"package-name@2.0.0": "",
+			"package-name@2.0.0": "0123456789",
 		}, nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
 			Name:       "package-name",
@@ -451,6 +540,7 @@ type trackerWrapper struct {
 	rm  *repo.ManagerMock
 	pm  *pkg.ManagerMock
 	is  *img.StoreMock
+	sv  *oci.SignatureVerifierMock
 	ec  *tracker.ErrorsCollectorMock
 	t   tracker.Tracker
 }
@@ -462,6 +552,7 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 	rm := &repo.ManagerMock{}
 	pm := &pkg.ManagerMock{}
 	is := &img.StoreMock{}
+	sv := &oci.SignatureVerifierMock{}
 	ec := &tracker.ErrorsCollectorMock{}
 	svc := &tracker.Services{
 		Ctx: ctx,
@@ -469,6 +560,7 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 		Rm:  rm,
 		Pm:  pm,
 		Is:  is,
+		Sv:  sv,
 		Ec:  ec,
 	}
 
@@ -481,6 +573,7 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 		rm:  rm,
 		pm:  pm,
 		is:  is,
+		sv:  sv,
 		ec:  ec,
 		t:   t,
 	}
@@ -491,5 +584,6 @@ func (tw *trackerWrapper) assertExpectations(t *testing.T) {
 	tw.rm.AssertExpectations(t)
 	tw.pm.AssertExpectations(t)
 	tw.is.AssertExpectations(t)
+	tw.sv.AssertExpectations(t)
 	tw.ec.AssertExpectations(t)
 }
@@ -0,0 +1,135 @@
+package helm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/helmchart"
+	"github.com/artifacthub/hub/internal/hub"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// renderTimeout represents the maximum amount of time the chart templates
+// rendering can take when detecting the containers images used.
+const renderTimeout = 10 * time.Second
+
+// podSpec represents the part of a Kubernetes pod spec that contains the
+// containers definitions.
+type podSpec struct {
+	InitContainers []*container `yaml:"initContainers"`
+	Containers     []*container `yaml:"containers"`
+}
+
+// container represents a container definition in a Kubernetes pod spec.
+type container struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+}
+
+// podTemplate represents a Kubernetes pod template.
+type podTemplate struct {
+	Spec podSpec `yaml:"spec"`
+}
+
+// manifest represents the parts of a Kubernetes manifest needed to extract
+// the containers images used by the workload resources.
+type manifest struct {
+	Kind string `yaml:"kind"`
+	Spec struct {
+		podSpec     `yaml:",inline"`
+		Template    podTemplate `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template podTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// getContainersImagesFromTemplates renders the templates of the chart provided
+// using its default values and returns the containers images used by the
+// workload resources found in the resulting manifests. Images returned are
+// flagged as auto-detected. The chart archive data is required to render the
+// templates in a sandboxed process.
+func getContainersImagesFromTemplates(
+	ctx context.Context,
+	chrt *chart.Chart,
+	chartData []byte,
+) ([]*hub.ContainerImage, error) {
+	if len(chrt.Templates) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+	manifests, err := helmchart.RenderTemplates(ctx, chartData, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return extractContainersImages(manifests), nil
+}
+
+// extractContainersImages returns the containers images used by the pods,
+// deployments, statefulsets, daemonsets, jobs and cronjobs defined in the
+// rendered templates provided. Images are returned only once, in the order
+// they are found after sorting the templates by name.
+func extractContainersImages(manifests map[string]string) []*hub.ContainerImage {
+	templates := make([]string, 0, len(manifests))
+	for name := range manifests {
+		templates = append(templates, name)
+	}
+	sort.Strings(templates)
+
+	var images []*hub.ContainerImage
+	seen := make(map[string]struct{})
+	for _, name := range templates {
+		if strings.HasSuffix(name, "NOTES.txt") {
+			continue
+		}
+		docs := releaseutil.SplitManifests(manifests[name])
+		keys := make([]string, 0, len(docs))
+		for key := range docs {
+			keys = append(keys, key)
+		}
+		sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+		for _, key := range keys {
+			var m *manifest
+			if err := yaml.Unmarshal([]byte(docs[key]), &m); err != nil || m == nil {
+				continue
+			}
+			var spec podSpec
+			switch m.Kind {
+			case "Pod":
+				spec = m.Spec.podSpec
+			case "Deployment", "StatefulSet", "DaemonSet", "Job":
+				spec = m.Spec.Template.Spec
+			case "CronJob":
+				spec = m.Spec.JobTemplate.Spec.Template.Spec
+			default:
+				continue
+			}
+			for _, c := range append(spec.InitContainers, spec.Containers...) {
+				if c == nil {
+					continue
+				}
+				image := strings.TrimSpace(c.Image)
+				if image == "" {
+					continue
+				}
+				if _, ok := seen[image]; ok {
+					continue
+				}
+				seen[image] = struct{}{}
+				images = append(images, &hub.ContainerImage{
+					Name:         c.Name,
+					Image:        image,
+					AutoDetected: true,
+				})
+			}
+		}
+	}
+	return images
+}
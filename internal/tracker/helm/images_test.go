@@ -0,0 +1,198 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+func TestGetContainersImagesFromTemplates(t *testing.T) {
+	t.Run("chart without templates", func(t *testing.T) {
+		t.Parallel()
+		chrt, chartData := newTestChart(t, nil)
+		images, err := getContainersImagesFromTemplates(context.Background(), chrt, chartData)
+		assert.NoError(t, err)
+		assert.Nil(t, images)
+	})
+
+	t.Run("error rendering templates", func(t *testing.T) {
+		t.Parallel()
+		chrt, chartData := newTestChart(t, map[string]string{
+			"templates/deployment.yaml": "{{ .Values.image.repository | fail }}",
+		})
+		images, err := getContainersImagesFromTemplates(context.Background(), chrt, chartData)
+		assert.Error(t, err)
+		assert.Nil(t, images)
+	})
+
+	t.Run("images detected using default values", func(t *testing.T) {
+		t.Parallel()
+		chrt, chartData := newTestChart(t, map[string]string{
+			"templates/_helpers.tpl": `{{- define "pkg1.image" -}}{{ .Values.image.repository }}:{{ .Values.image.tag }}{{- end -}}`,
+			"templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: {{ include "pkg1.image" . }}
+`,
+			"templates/NOTES.txt": "image: {{ .Values.image.repository }}",
+		})
+		images, err := getContainersImagesFromTemplates(context.Background(), chrt, chartData)
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.ContainerImage{
+			{Name: "app", Image: "repo/pkg1:1.0.0", AutoDetected: true},
+		}, images)
+	})
+}
+
+func TestExtractContainersImages(t *testing.T) {
+	testCases := []struct {
+		manifests      map[string]string
+		expectedImages []*hub.ContainerImage
+	}{
+		{
+			map[string]string{},
+			nil,
+		},
+		{
+			map[string]string{
+				"templates/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  containers:
+    - name: app
+      image: repo/app:1.0.0
+`,
+				"templates/invalid.yaml": "{",
+			},
+			nil,
+		},
+		{
+			map[string]string{
+				"templates/pod.yaml": `
+apiVersion: v1
+kind: Pod
+spec:
+  initContainers:
+    - name: init
+      image: repo/init:1.0.0
+  containers:
+    - name: app
+      image: repo/app:1.0.0
+`,
+				"templates/workloads.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: repo/app:1.0.0
+---
+apiVersion: apps/v1
+kind: StatefulSet
+spec:
+  template:
+    spec:
+      containers:
+        - name: db
+          image: repo/db:1.0.0
+---
+apiVersion: apps/v1
+kind: DaemonSet
+spec:
+  template:
+    spec:
+      containers:
+        - name: agent
+          image: repo/agent:1.0.0
+---
+apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      containers:
+        - name: job
+          image: repo/job:1.0.0
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cronjob
+              image: repo/cronjob:1.0.0
+            - name: empty
+              image: ""
+`,
+			},
+			[]*hub.ContainerImage{
+				{Name: "init", Image: "repo/init:1.0.0", AutoDetected: true},
+				{Name: "app", Image: "repo/app:1.0.0", AutoDetected: true},
+				{Name: "db", Image: "repo/db:1.0.0", AutoDetected: true},
+				{Name: "agent", Image: "repo/agent:1.0.0", AutoDetected: true},
+				{Name: "job", Image: "repo/job:1.0.0", AutoDetected: true},
+				{Name: "cronjob", Image: "repo/cronjob:1.0.0", AutoDetected: true},
+			},
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			images := extractContainersImages(tc.manifests)
+			assert.Equal(t, tc.expectedImages, images)
+		})
+	}
+}
+
+func newTestChart(t *testing.T, templates map[string]string) (*chart.Chart, []byte) {
+	t.Helper()
+	files := map[string]string{
+		"pkg1/Chart.yaml":  "apiVersion: v2\nname: pkg1\nversion: 1.0.0\n",
+		"pkg1/values.yaml": "image:\n  repository: repo/pkg1\n  tag: 1.0.0\n",
+	}
+	for name, content := range templates {
+		files["pkg1/"+name] = content
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	chrt, err := loader.LoadArchive(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	return chrt, buf.Bytes()
+}
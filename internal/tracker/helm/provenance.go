@@ -0,0 +1,118 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// errInvalidProvenanceFile indicates that the provenance file provided is
+	// not a valid PGP clear signed message.
+	errInvalidProvenanceFile = errors.New("invalid provenance file")
+
+	// provenanceSeparator represents the separator between the chart metadata
+	// and the files checksums sections in the provenance file message.
+	provenanceSeparator = []byte("\n...\n")
+)
+
+// provenanceFiles represents the files checksums section of the provenance
+// file message.
+type provenanceFiles struct {
+	Files map[string]string `yaml:"files"`
+}
+
+// parsePGPKeys parses the ASCII armored PGP public keys provided, returning a
+// keyring containing all the valid ones. The errors found reading the keys are
+// returned, but they do not stop the rest of the keys from being loaded.
+func parsePGPKeys(keys []string) (openpgp.EntityList, []error) {
+	var keyring openpgp.EntityList
+	var errs []error
+	for i, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading pgp key %d: %w", i, err))
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+	return keyring, errs
+}
+
+// verifyProvenance verifies the provenance file provided using the keyring
+// given. The provenance file is expected to be signed by one of the keys in
+// the keyring and it must contain the sha256 digest of the chart archive.
+func verifyProvenance(
+	keyring openpgp.EntityList,
+	chartFileName string,
+	chartData,
+	provData []byte,
+) (*hub.ProvenanceVerification, error) {
+	// Check signature
+	block, _ := clearsign.Decode(provData)
+	if block == nil {
+		return nil, errInvalidProvenanceFile
+	}
+	signer, err := openpgp.CheckDetachedSignature(
+		keyring,
+		bytes.NewReader(block.Bytes),
+		block.ArmoredSignature.Body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error checking signature: %w", err)
+	}
+
+	// Check chart archive digest
+	parts := bytes.SplitN(block.Plaintext, provenanceSeparator, 2)
+	if len(parts) != 2 {
+		return nil, errInvalidProvenanceFile
+	}
+	var pf *provenanceFiles
+	if err := yaml.Unmarshal(parts[1], &pf); err != nil || pf == nil {
+		return nil, errInvalidProvenanceFile
+	}
+	expectedDigest, ok := pf.Files[chartFileName]
+	if !ok {
+		return nil, fmt.Errorf("provenance file does not contain a digest for %s", chartFileName)
+	}
+	sum := sha256.Sum256(chartData)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if expectedDigest != digest {
+		return nil, fmt.Errorf("digest mismatch for %s: %s != %s", chartFileName, expectedDigest, digest)
+	}
+
+	return &hub.ProvenanceVerification{
+		Verified:       true,
+		KeyFingerprint: strings.ToUpper(hex.EncodeToString(signer.PrimaryKey.Fingerprint[:])),
+		Signer:         getPrimaryIdentity(signer),
+	}, nil
+}
+
+// getPrimaryIdentity returns the name of the primary identity of the entity
+// provided. When none of the identities is flagged as primary, the first one
+// in alphabetical order is returned.
+func getPrimaryIdentity(e *openpgp.Entity) string {
+	names := make([]string, 0, len(e.Identities))
+	for name, identity := range e.Identities {
+		if identity.SelfSignature != nil &&
+			identity.SelfSignature.IsPrimaryId != nil &&
+			*identity.SelfSignature.IsPrimaryId {
+			return name
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
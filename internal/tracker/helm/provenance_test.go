@@ -0,0 +1,135 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+func TestParsePGPKeys(t *testing.T) {
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+		keyring, errs := parsePGPKeys([]string{"invalid"})
+		assert.Len(t, errs, 1)
+		assert.Nil(t, keyring)
+	})
+
+	t.Run("invalid key is skipped and valid ones are loaded", func(t *testing.T) {
+		t.Parallel()
+		e1 := newTestPGPEntity(t, "signer1")
+		e2 := newTestPGPEntity(t, "signer2")
+		keyring, errs := parsePGPKeys([]string{armorPublicKey(t, e1), "invalid", armorPublicKey(t, e2)})
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "pgp key 1")
+		require.Len(t, keyring, 2)
+		assert.Equal(t, e1.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+		assert.Equal(t, e2.PrimaryKey.Fingerprint, keyring[1].PrimaryKey.Fingerprint)
+	})
+
+	t.Run("valid keys", func(t *testing.T) {
+		t.Parallel()
+		e1 := newTestPGPEntity(t, "signer1")
+		e2 := newTestPGPEntity(t, "signer2")
+		keyring, errs := parsePGPKeys([]string{armorPublicKey(t, e1), armorPublicKey(t, e2)})
+		assert.Empty(t, errs)
+		require.Len(t, keyring, 2)
+		assert.Equal(t, e1.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+		assert.Equal(t, e2.PrimaryKey.Fingerprint, keyring[1].PrimaryKey.Fingerprint)
+	})
+}
+
+func TestVerifyProvenance(t *testing.T) {
+	chartData, err := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+	require.NoError(t, err)
+	signer := newTestPGPEntity(t, "signer1")
+	other := newTestPGPEntity(t, "signer2")
+	keyring := openpgp.EntityList{signer}
+
+	t.Run("invalid provenance file", func(t *testing.T) {
+		t.Parallel()
+		v, err := verifyProvenance(keyring, "pkg1-1.0.0.tgz", chartData, []byte("invalid"))
+		assert.Equal(t, errInvalidProvenanceFile, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("provenance file signed with an unknown key", func(t *testing.T) {
+		t.Parallel()
+		provData := newTestProvenanceFile(t, other, "pkg1-1.0.0.tgz", chartData)
+		v, err := verifyProvenance(keyring, "pkg1-1.0.0.tgz", chartData, provData)
+		assert.Error(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("provenance file without chart digest", func(t *testing.T) {
+		t.Parallel()
+		provData := newTestProvenanceFile(t, signer, "pkg1-2.0.0.tgz", chartData)
+		v, err := verifyProvenance(keyring, "pkg1-1.0.0.tgz", chartData, provData)
+		assert.Error(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("chart digest mismatch", func(t *testing.T) {
+		t.Parallel()
+		provData := newTestProvenanceFile(t, signer, "pkg1-1.0.0.tgz", []byte("other"))
+		v, err := verifyProvenance(keyring, "pkg1-1.0.0.tgz", chartData, provData)
+		assert.Error(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("provenance file verified", func(t *testing.T) {
+		t.Parallel()
+		provData := newTestProvenanceFile(t, signer, "pkg1-1.0.0.tgz", chartData)
+		v, err := verifyProvenance(keyring, "pkg1-1.0.0.tgz", chartData, provData)
+		assert.NoError(t, err)
+		assert.Equal(t, &hub.ProvenanceVerification{
+			Verified:       true,
+			KeyFingerprint: strings.ToUpper(hex.EncodeToString(signer.PrimaryKey.Fingerprint[:])),
+			Signer:         "signer1 <signer1@email.com>",
+		}, v)
+	})
+}
+
+func newTestPGPEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	e, err := openpgp.NewEntity(name, "", name+"@email.com", nil)
+	require.NoError(t, err)
+	return e
+}
+
+func armorPublicKey(t *testing.T, e *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func newTestProvenanceFile(t *testing.T, e *openpgp.Entity, chartFileName string, chartData []byte) []byte {
+	t.Helper()
+	sum := sha256.Sum256(chartData)
+	msg := fmt.Sprintf(
+		"name: pkg1\nversion: 1.0.0\n\n...\nfiles:\n  %s: sha256:%s\n",
+		chartFileName,
+		hex.EncodeToString(sum[:]),
+	)
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, e.PrivateKey, nil)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(msg))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -1,24 +1,23 @@
 package helm
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"net/url"
 	"path"
-	"sort"
 	"strings"
 	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
-	"github.com/google/go-containerregistry/pkg/authn"
-	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/openpgp"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
@@ -59,7 +58,13 @@ func NewTracker(
 		t.svc.Il = &repo.HelmIndexLoader{}
 	}
 	if t.svc.Tg == nil {
-		t.svc.Tg = &OCITagsGetter{}
+		t.svc.Tg = &oci.TagsGetter{}
+	}
+	if t.svc.Op == nil {
+		t.svc.Op = &oci.Puller{}
+	}
+	if t.svc.Sv == nil {
+		t.svc.Sv = &oci.SignatureVerifier{}
 	}
 	return t
 }
@@ -82,34 +87,70 @@ func WithIndexLoader(il hub.HelmIndexLoader) func(t tracker.Tracker) {
 
 // WithOCITagsGetter allows providing a specific OCI tags getter for a Tracker
 // instance.
-func WithOCITagsGetter(tg tracker.OCITagsGetter) func(t tracker.Tracker) {
+func WithOCITagsGetter(tg hub.OCITagsGetter) func(t tracker.Tracker) {
 	return func(t tracker.Tracker) {
 		t.(*Tracker).svc.Tg = tg
 	}
 }
 
+// WithOCIPuller allows providing a specific OCI puller for a Tracker instance.
+func WithOCIPuller(op hub.OCIPuller) func(t tracker.Tracker) {
+	return func(t tracker.Tracker) {
+		t.(*Tracker).svc.Op = op
+	}
+}
+
 // Track registers or unregisters the Helm packages available in the repository
 // provided as needed. It is in charge of generating jobs to register or
 // unregister Helm packages and dispatching them among the available workers.
 func (t *Tracker) Track() error {
+	// Get repository metadata
+	var rmd *hub.RepositoryMetadata
+	u, _ := url.Parse(t.r.URL)
+	if repo.SchemeIsHTTP(u) {
+		u.Path = path.Join(u.Path, hub.RepositoryMetadataFile)
+		rmd, _ = t.svc.Rm.GetMetadata(u.String())
+	}
+
+	// Load keys used to verify charts provenance files and cosign signatures
+	var pgpKeyring openpgp.EntityList
+	if rmd != nil && rmd.SigningKeys != nil {
+		var errs []error
+		pgpKeyring, errs = parsePGPKeys(rmd.SigningKeys.PGP)
+		for _, err := range errs {
+			t.warn(fmt.Errorf("error loading signing keys: %w", err))
+		}
+	}
+	cosignKeys, errs := tracker.GetCosignKeys(rmd)
+	for _, err := range errs {
+		t.warn(fmt.Errorf("error loading signing keys: %w", err))
+	}
+
+	// Packages must be registered again when the signing keys change, so that
+	// they are verified using the new ones. The new signing keys digest is
+	// stored once all jobs have been processed.
+	keysDigest := tracker.GetSigningKeysDigest(rmd)
+	keysChanged := keysDigest != t.r.SigningKeysDigest
+	var jobsQueued bool
+	defer func() {
+		if !jobsQueued {
+			return
+		}
+		if err := tracker.SetSigningKeysDigest(t.svc.Ctx, t.svc.Rm, t.r, keysDigest); err != nil {
+			t.warn(err)
+		}
+	}()
+
 	// Launch workers
 	var workersWg sync.WaitGroup
 	defer workersWg.Wait()
 	defer close(t.queue)
 	for i := 0; i < t.numWorkers; i++ {
-		w := NewWorker(t.svc, t.r)
+		w := NewWorker(t.svc, t.r, pgpKeyring, cosignKeys)
 		workersWg.Add(1)
 		go w.Run(&workersWg, t.queue)
 	}
 
-	// Get repository metadata
-	var rmd *hub.RepositoryMetadata
-	u, _ := url.Parse(t.r.URL)
-	if repo.SchemeIsHTTP(u) {
-		u.Path = path.Join(u.Path, hub.RepositoryMetadataFile)
-		rmd, _ = t.svc.Rm.GetMetadata(u.String())
-	}
-
 	// Load packages already registered from this repository
 	packagesRegistered, err := t.svc.Rm.GetPackagesDigest(t.svc.Ctx, t.r.RepositoryID)
 	if err != nil {
@@ -117,9 +158,9 @@ func (t *Tracker) Track() error {
 	}
 
 	// Generate jobs to register available packages when needed
-	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck") || keysChanged
 	packagesAvailable := make(map[string]struct{})
-	charts, err := t.getCharts()
+	charts, err := t.getCharts(packagesRegistered, bypassDigestCheck)
 	if err != nil {
 		return err
 	}
@@ -157,7 +198,8 @@ func (t *Tracker) Track() error {
 			}
 
 			// Register package if it isn't already registered, if its digest
-			// has changed or if the tracker is bypassing the digest check
+			// has changed or if the tracker is bypassing the digest check (the
+			// repository signing keys may have changed)
 			digest, ok := packagesRegistered[key]
 			if !ok || chartVersion.Digest != digest || bypassDigestCheck {
 				t.queue <- &Job{
@@ -204,12 +246,21 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
 
+	jobsQueued = true
 	return nil
 }
 
-// getCharts returns the charts available in the repository.
-func (t *Tracker) getCharts() (map[string][]*helmrepo.ChartVersion, error) {
+// getCharts returns the charts available in the repository. The packages
+// registered and the bypass digest check flag are used to avoid pulling the
+// content of the OCI charts versions that have not changed.
+func (t *Tracker) getCharts(
+	packagesRegistered map[string]string,
+	bypassDigestCheck bool,
+) (map[string][]*helmrepo.ChartVersion, error) {
 	charts := make(map[string][]*helmrepo.ChartVersion)
 
 	u, _ := url.Parse(t.r.URL)
@@ -235,16 +286,23 @@ func (t *Tracker) getCharts() (map[string][]*helmrepo.ChartVersion, error) {
 			return nil, fmt.Errorf("error getting package's available versions: %w", err)
 		}
 
-		// Prepare chart versions using the list of versions available
+		// Prepare chart versions using the manifest digest of each of the
+		// versions available, reading the metadata from the chart content
+		// layer only when the version is new or has changed
 		name := path.Base(t.r.URL)
 		for _, version := range versions {
-			charts[name] = append(charts[name], &helmrepo.ChartVersion{
-				Metadata: &chart.Metadata{
-					Name:    name,
-					Version: version,
-				},
-				URLs: []string{t.r.URL + ":" + version},
-			})
+			chartVersion, err := t.getOCIChartVersion(name, version, packagesRegistered, bypassDigestCheck)
+			if err != nil {
+				t.warn(fmt.Errorf("error getting chart version %s:%s: %w", name, version, err))
+				chartVersion = &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    name,
+						Version: version,
+					},
+					URLs: []string{t.r.URL + ":" + version},
+				}
+			}
+			charts[name] = append(charts[name], chartVersion)
 		}
 	default:
 		return nil, repo.ErrSchemeNotSupported
@@ -253,6 +311,58 @@ func (t *Tracker) getCharts() (map[string][]*helmrepo.ChartVersion, error) {
 	return charts, nil
 }
 
+// getOCIChartVersion builds a chart version for the version provided using
+// the digest of its manifest. The chart content layer is only pulled to read
+// the chart metadata when the version is not registered yet, when its digest
+// has changed or when the digest check is bypassed.
+func (t *Tracker) getOCIChartVersion(
+	name,
+	version string,
+	packagesRegistered map[string]string,
+	bypassDigestCheck bool,
+) (*helmrepo.ChartVersion, error) {
+	ref := t.r.URL + ":" + version
+	digest, err := t.svc.Op.Digest(t.svc.Ctx, ref, t.r.AuthUser, t.r.AuthPass)
+	if err != nil {
+		return nil, fmt.Errorf("error getting chart manifest digest: %w", err)
+	}
+
+	// Tags cannot contain a plus sign, so it's replaced by an underscore
+	chartVersion := strings.ReplaceAll(version, "_", "+")
+	if sv, err := semver.NewVersion(chartVersion); err == nil && !bypassDigestCheck {
+		key := fmt.Sprintf("%s@%s", name, sv.String())
+		if registeredDigest, ok := packagesRegistered[key]; ok && registeredDigest == digest {
+			return &helmrepo.ChartVersion{
+				Metadata: &chart.Metadata{
+					Name:    name,
+					Version: chartVersion,
+				},
+				URLs:   []string{ref},
+				Digest: digest,
+			}, nil
+		}
+	}
+
+	// Pull chart content layer to read the chart metadata
+	_, data, err := t.svc.Op.PullLayer(t.svc.Ctx, ref, helmChartContentLayerMediaType, t.r.AuthUser, t.r.AuthPass)
+	if err != nil {
+		return nil, fmt.Errorf("error pulling chart content layer: %w", err)
+	}
+	chrt, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error loading chart: %w", err)
+	}
+	tagVersion := strings.ReplaceAll(chrt.Metadata.Version, "+", "_")
+	if chrt.Metadata.Name != name || tagVersion != version {
+		return nil, fmt.Errorf("name and version in tag (%s:%s) do not match chart content", name, version)
+	}
+	return &helmrepo.ChartVersion{
+		Metadata: chrt.Metadata,
+		URLs:     []string{ref},
+		Digest:   digest,
+	}, nil
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (t *Tracker) warn(err error) {
@@ -279,35 +389,3 @@ type Job struct {
 	ChartVersion *helmrepo.ChartVersion
 	StoreLogo    bool
 }
-
-// OCITagsGetter provides a mechanism to get all the tags available for a given
-// repository in a OCI registry.
-type OCITagsGetter struct{}
-
-// Tags returns a list with the tags available for the provided repository.
-func (tg *OCITagsGetter) Tags(ctx context.Context, r *hub.Repository) ([]string, error) {
-	u := strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix)
-	ociRepo, err := name.NewRepository(u)
-	if err != nil {
-		return nil, err
-	}
-	var options []remote.Option
-	if r.AuthUser != "" || r.AuthPass != "" {
-		options = []remote.Option{
-			remote.WithAuth(&authn.Basic{
-				Username: r.AuthUser,
-				Password: r.AuthPass,
-			}),
-		}
-	}
-	tags, err := remote.ListWithContext(ctx, ociRepo, options...)
-	if err != nil {
-		return nil, err
-	}
-	sort.Slice(tags, func(i, j int) bool {
-		vi, _ := semver.NewVersion(tags[i])
-		vj, _ := semver.NewVersion(tags[j])
-		return vj.LessThan(vi)
-	})
-	return tags, nil
-}
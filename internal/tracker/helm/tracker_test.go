@@ -1,23 +1,31 @@
 package helm
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/helmchart"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/tracker"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
 func TestMain(m *testing.M) {
+	helmchart.RunRendererIfRequested()
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	os.Exit(m.Run())
 }
@@ -444,25 +452,87 @@ func TestTracker(t *testing.T) {
 		}
 	})
 
+	t.Run("tracker completed successfully (signing keys changed)", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			RepositoryID:      "00000000-0000-0000-0000-000000000001",
+			URL:               "http://localhost",
+			SigningKeysDigest: "old-digest",
+		}
+		md := &hub.RepositoryMetadata{
+			RepositoryID: r.RepositoryID,
+			SigningKeys: &hub.RepositorySigningKeys{
+				PGP: []string{armorPublicKey(t, newTestPGPEntity(t, "signer1"))},
+			},
+		}
+		pkg1V1 := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "pkg1",
+				Version: "1.0.0",
+			},
+			Digest: "pkg1-1.0.0",
+		}
+
+		// Setup tracker and expectations
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetMetadata", r.URL+"/"+hub.RepositoryMetadataFile).Return(md, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"pkg1@1.0.0": "pkg1-1.0.0",
+		}, nil)
+		tw.il.On("LoadIndex", r).Return(&helmrepo.IndexFile{
+			Entries: map[string]helmrepo.ChartVersions{
+				"pkg1": []*helmrepo.ChartVersion{pkg1V1},
+			},
+		}, "", nil)
+		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
+		tw.rm.On("UpdateSigningKeysDigest", tw.ctx, r.RepositoryID, tracker.GetSigningKeysDigest(md)).
+			Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t, []*Job{
+			{
+				Kind:         Register,
+				ChartVersion: pkg1V1,
+				StoreLogo:    true,
+			},
+		})
+	})
+
 	t.Run("tracker completed successfully (oci scheme)", func(t *testing.T) {
 		repo1ID := "00000000-0000-0000-0000-000000000001"
 		repo1 := &hub.Repository{
 			RepositoryID: repo1ID,
 			URL:          "oci://localhost/repo1/pkg1",
 		}
+		pkg1V1Data, _ := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+		pkg1V1Chart, _ := loader.LoadArchive(bytes.NewReader(pkg1V1Data))
 		pkg1V1 := &helmrepo.ChartVersion{
-			Metadata: &chart.Metadata{
-				Name:    "pkg1",
-				Version: "1.0.0",
-			},
-			URLs: []string{"oci://localhost/repo1/pkg1:1.0.0"},
+			Metadata: pkg1V1Chart.Metadata,
+			URLs:     []string{"oci://localhost/repo1/pkg1:1.0.0"},
+			Digest:   "pkg1-1.0.0",
 		}
+		pkg1V2Data, _ := ioutil.ReadFile("testdata/pkg1-2.0.0.tgz")
+		pkg1V2Chart, _ := loader.LoadArchive(bytes.NewReader(pkg1V2Data))
 		pkg1V2 := &helmrepo.ChartVersion{
-			Metadata: &chart.Metadata{
-				Name:    "pkg1",
-				Version: "2.0.0",
+			Metadata: pkg1V2Chart.Metadata,
+			URLs:     []string{"oci://localhost/repo1/pkg1:2.0.0"},
+			Digest:   "pkg1-2.0.0",
+		}
+		digests := map[string]string{
+			"1.0.0": "pkg1-1.0.0",
+			"2.0.0": "pkg1-2.0.0",
+		}
+		layers := map[string]*ociLayer{
+			"1.0.0": {
+				desc: v1.Descriptor{Digest: v1.Hash{Algorithm: "sha256", Hex: "pkg1-1.0.0-layer"}},
+				data: pkg1V1Data,
+			},
+			"2.0.0": {
+				desc: v1.Descriptor{Digest: v1.Hash{Algorithm: "sha256", Hex: "pkg1-2.0.0-layer"}},
+				data: pkg1V2Data,
 			},
-			URLs: []string{"oci://localhost/repo1/pkg1:2.0.0"},
 		}
 
 		testCases := []struct {
@@ -509,7 +579,7 @@ func TestTracker(t *testing.T) {
 				[]string{"1.0.0"},
 				map[string]map[string]string{
 					repo1ID: {
-						"pkg1@1.0.0": "",
+						"pkg1@1.0.0": "pkg1-1.0.0",
 					},
 				},
 				nil,
@@ -520,8 +590,8 @@ func TestTracker(t *testing.T) {
 				[]string{"1.0.0", "2.0.0"},
 				map[string]map[string]string{
 					repo1ID: {
-						"pkg1@1.0.0": "",
-						"pkg1@2.0.0": "",
+						"pkg1@1.0.0": "pkg1-1.0.0",
+						"pkg1@2.0.0": "pkg1-2.0.0",
 					},
 				},
 				nil,
@@ -529,11 +599,29 @@ func TestTracker(t *testing.T) {
 			{
 				5,
 				repo1,
+				[]string{"1.0.0", "2.0.0"},
+				map[string]map[string]string{
+					repo1ID: {
+						"pkg1@1.0.0": "pkg1-1.0.0",
+						"pkg1@2.0.0": "pkg1-2.0.0-updated",
+					},
+				},
+				[]*Job{
+					{
+						Kind:         Register,
+						ChartVersion: pkg1V2,
+						StoreLogo:    false,
+					},
+				},
+			},
+			{
+				6,
+				repo1,
 				[]string{"2.0.0"},
 				map[string]map[string]string{
 					repo1ID: {
-						"pkg1@1.0.0": "",
-						"pkg1@2.0.0": "",
+						"pkg1@1.0.0": "pkg1-1.0.0",
+						"pkg1@2.0.0": "pkg1-2.0.0",
 					},
 				},
 				[]*Job{
@@ -549,13 +637,13 @@ func TestTracker(t *testing.T) {
 				},
 			},
 			{
-				6,
+				7,
 				repo1,
 				[]string{},
 				map[string]map[string]string{
 					repo1ID: {
-						"pkg1@1.0.0": "",
-						"pkg1@2.0.0": "",
+						"pkg1@1.0.0": "pkg1-1.0.0",
+						"pkg1@2.0.0": "pkg1-2.0.0",
 					},
 				},
 				nil,
@@ -571,6 +659,17 @@ func TestTracker(t *testing.T) {
 				tw.rm.On("GetPackagesDigest", tw.ctx, tc.r.RepositoryID).
 					Return(tc.packagesDigest[tc.r.RepositoryID], nil)
 				tw.tg.On("Tags", tw.ctx, tc.r).Return(tc.tags, nil)
+				for _, tag := range tc.tags {
+					ref := tc.r.URL + ":" + tag
+					tw.op.On("Digest", tw.ctx, ref, "", "").Return(digests[tag], nil)
+
+					// The chart content layer is only pulled for new or
+					// updated versions
+					if tc.packagesDigest[tc.r.RepositoryID]["pkg1@"+tag] != digests[tag] {
+						tw.op.On("PullLayer", tw.ctx, ref, helmChartContentLayerMediaType, "", "").
+							Return(layers[tag].desc, layers[tag].data, nil)
+					}
+				}
 
 				// Run tracker and check expectations
 				err := tw.t.Track()
@@ -579,6 +678,81 @@ func TestTracker(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("tracker completed with warnings (oci scheme: error pulling layer)", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		r := &hub.Repository{
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+			URL:          "oci://localhost/repo1/pkg1",
+		}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"pkg1@1.0.0": "pkg1-1.0.0",
+		}, nil)
+		tw.tg.On("Tags", tw.ctx, r).Return([]string{"1.0.0"}, nil)
+		tw.op.On("Digest", tw.ctx, r.URL+":1.0.0", "", "").Return("pkg1-1.0.0-updated", nil)
+		tw.op.On("PullLayer", tw.ctx, r.URL+":1.0.0", helmChartContentLayerMediaType, "", "").
+			Return(nil, nil, tests.ErrFake)
+		tw.ec.On("Append", r.RepositoryID, mock.Anything).Return()
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t, []*Job{
+			{
+				Kind: Register,
+				ChartVersion: &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "pkg1",
+						Version: "1.0.0",
+					},
+					URLs: []string{"oci://localhost/repo1/pkg1:1.0.0"},
+				},
+				StoreLogo: true,
+			},
+		})
+	})
+
+	t.Run("tracker completed with warnings (oci scheme: error getting digest)", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		r := &hub.Repository{
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+			URL:          "oci://localhost/repo1/pkg1",
+		}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"pkg1@1.0.0": "pkg1-1.0.0",
+		}, nil)
+		tw.tg.On("Tags", tw.ctx, r).Return([]string{"1.0.0"}, nil)
+		tw.op.On("Digest", tw.ctx, r.URL+":1.0.0", "", "").Return("", tests.ErrFake)
+		tw.ec.On("Append", r.RepositoryID, mock.Anything).Return()
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t, []*Job{
+			{
+				Kind: Register,
+				ChartVersion: &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "pkg1",
+						Version: "1.0.0",
+					},
+					URLs: []string{"oci://localhost/repo1/pkg1:1.0.0"},
+				},
+				StoreLogo: true,
+			},
+		})
+	})
+}
+
+type ociLayer struct {
+	desc v1.Descriptor
+	data []byte
 }
 
 type trackerWrapper struct {
@@ -586,7 +760,8 @@ type trackerWrapper struct {
 	cfg          *viper.Viper
 	rm           *repo.ManagerMock
 	il           *repo.HelmIndexLoaderMock
-	tg           *OCITagsGetterMock
+	tg           *oci.TagsGetterMock
+	op           *oci.PullerMock
 	ec           *tracker.ErrorsCollectorMock
 	t            tracker.Tracker
 	queuedJobs   *[]*Job
@@ -598,7 +773,8 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 	ctx := context.Background()
 	cfg := viper.New()
 	il := &repo.HelmIndexLoaderMock{}
-	tg := &OCITagsGetterMock{}
+	tg := &oci.TagsGetterMock{}
+	op := &oci.PullerMock{}
 	rm := &repo.ManagerMock{}
 	ec := &tracker.ErrorsCollectorMock{}
 	svc := &tracker.Services{
@@ -607,9 +783,17 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 		Rm:  rm,
 		Il:  il,
 		Tg:  tg,
+		Op:  op,
 		Ec:  ec,
 	}
-	t := NewTracker(svc, r, WithNumWorkers(-1), WithIndexLoader(il), WithOCITagsGetter(tg))
+	t := NewTracker(
+		svc,
+		r,
+		WithNumWorkers(-1),
+		WithIndexLoader(il),
+		WithOCITagsGetter(tg),
+		WithOCIPuller(op),
+	)
 
 	// Consume queued jobs from tracker queue and store them
 	jobsConsumed := make(chan struct{})
@@ -627,6 +811,7 @@ func newTrackerWrapper(r *hub.Repository) *trackerWrapper {
 		rm:           rm,
 		il:           il,
 		tg:           tg,
+		op:           op,
 		ec:           ec,
 		t:            t,
 		queuedJobs:   &queuedJobs,
@@ -639,6 +824,7 @@ func (tw *trackerWrapper) assertExpectations(t *testing.T, expectedJobs []*Job)
 
 	tw.il.AssertExpectations(t)
 	tw.tg.AssertExpectations(t)
+	tw.op.AssertExpectations(t)
 	tw.rm.AssertExpectations(t)
 	tw.ec.AssertExpectations(t)
 
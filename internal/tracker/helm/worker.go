@@ -1,11 +1,9 @@
 package helm
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"image"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -15,20 +13,17 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/artifacthub/hub/internal/helmchart"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/license"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
-	"github.com/containerd/containerd/remotes/docker"
-	"github.com/deislabs/oras/pkg/content"
-	ctxo "github.com/deislabs/oras/pkg/context"
-	"github.com/deislabs/oras/pkg/oras"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/vincent-petithory/dataurl"
+	"golang.org/x/crypto/openpgp"
 	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chart/loader"
 )
 
 const (
@@ -43,28 +38,36 @@ const (
 	operatorCapabilitiesAnnotation = "artifacthub.io/operatorCapabilities"
 	prereleaseAnnotation           = "artifacthub.io/prerelease"
 	securityUpdatesAnnotation      = "artifacthub.io/containsSecurityUpdates"
+	vulnAllowlistAnnotation        = "artifacthub.io/vulnerabilityAllowlist"
 
-	helmChartConfigMediaType       = "application/vnd.cncf.helm.config.v1+json"
-	helmChartContentLayerMediaType = "application/tar+gzip"
+	helmChartContentLayerMediaType = helmchart.ContentLayerMediaType
 )
 
 // Worker is in charge of handling Helm packages register and unregister jobs
 // generated by the tracker.
 type Worker struct {
-	svc    *tracker.Services
-	r      *hub.Repository
-	logger zerolog.Logger
+	svc        *tracker.Services
+	r          *hub.Repository
+	pgpKeyring openpgp.EntityList
+	cosignKeys []string
+	logger     zerolog.Logger
 }
 
-// NewWorker creates a new worker instance.
+// NewWorker creates a new worker instance. The PGP keyring provided, if any,
+// will be used to verify the charts provenance files. The cosign keys will be
+// used to verify the signatures of OCI charts and containers images.
 func NewWorker(
 	svc *tracker.Services,
 	r *hub.Repository,
+	pgpKeyring openpgp.EntityList,
+	cosignKeys []string,
 ) *Worker {
 	return &Worker{
-		svc:    svc,
-		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		svc:        svc,
+		r:          r,
+		pgpKeyring: pgpKeyring,
+		cosignKeys: cosignKeys,
+		logger:     log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 	}
 }
 
@@ -123,7 +126,7 @@ func (w *Worker) handleRegisterJob(j *Job) {
 	}
 
 	// Load chart from remote archive
-	chart, err := w.loadChart(chartURL)
+	chart, chartData, err := w.loadChart(chartURL)
 	if err != nil {
 		w.warn(md, fmt.Errorf("error loading chart (%s): %w", chartURL.String(), err))
 		return
@@ -178,12 +181,29 @@ func (w *Worker) handleRegisterJob(j *Job) {
 		p.License = license.Detect(licenseFile.Data)
 	}
 	if repo.SchemeIsHTTP(chartURL) {
-		hasProvenanceFile, err := w.chartVersionHasProvenanceFile(chartURL.String())
+		provData, err := w.getProvenanceFile(chartURL.String())
 		if err == nil {
-			p.Signed = hasProvenanceFile
+			p.Signed = provData != nil
 		} else {
 			w.warn(md, fmt.Errorf("error checking provenance file: %w", err))
 		}
+		if provData != nil && len(w.pgpKeyring) > 0 {
+			v, err := verifyProvenance(w.pgpKeyring, path.Base(chartURL.Path), chartData, provData)
+			if err != nil {
+				w.warn(md, fmt.Errorf("error verifying provenance file: %w", err))
+				v = &hub.ProvenanceVerification{Verified: false}
+			}
+			p.ProvenanceVerification = v
+		}
+	}
+	if chartURL.Scheme == "oci" && len(w.cosignKeys) > 0 {
+		v, err := w.svc.Sv.Verify(w.svc.Ctx, chartURL.String(), w.cosignKeys, w.r.AuthUser, w.r.AuthPass)
+		if err != nil {
+			w.warn(md, fmt.Errorf("error verifying chart signature: %w", err))
+		} else if v != nil {
+			p.Signed = true
+			p.CosignVerification = v
+		}
 	}
 	var maintainers []*hub.Maintainer
 	for _, entry := range md.Maintainers {
@@ -212,11 +232,15 @@ func (w *Worker) handleRegisterJob(j *Job) {
 	}
 	dependencies := make([]map[string]string, 0, len(md.Dependencies))
 	for _, dependency := range md.Dependencies {
-		dependencies = append(dependencies, map[string]string{
+		d := map[string]string{
 			"name":       dependency.Name,
 			"version":    dependency.Version,
 			"repository": dependency.Repository,
-		})
+		}
+		if dependency.Alias != "" {
+			d["alias"] = dependency.Alias
+		}
+		dependencies = append(dependencies, d)
 	}
 	if len(dependencies) > 0 {
 		p.Data = map[string]interface{}{
@@ -229,6 +253,21 @@ func (w *Worker) handleRegisterJob(j *Job) {
 		w.warn(md, fmt.Errorf("error enriching package: %w", err))
 	}
 
+	// Detect containers images from templates when not provided explicitly
+	if len(p.ContainersImages) == 0 {
+		images, err := getContainersImagesFromTemplates(w.svc.Ctx, chart, chartData)
+		if err != nil {
+			w.warn(md, fmt.Errorf("error detecting containers images: %w", err))
+		} else {
+			p.ContainersImages = images
+		}
+	}
+
+	// Verify containers images signatures
+	for _, err := range tracker.VerifyContainersImagesSignatures(w.svc.Ctx, w.svc.Sv, w.cosignKeys, p.ContainersImages) {
+		w.warn(md, err)
+	}
+
 	// Register package
 	w.logger.Debug().Str("name", md.Name).Str("v", md.Version).Msg("registering package")
 	if err := w.svc.Pm.Register(w.svc.Ctx, p); err != nil {
@@ -255,98 +294,42 @@ func (w *Worker) handleUnregisterJob(j *Job) {
 }
 
 // loadChart loads a chart from a remote archive located at the url provided.
-func (w *Worker) loadChart(u *url.URL) (*chart.Chart, error) {
-	var r io.Reader
-
-	switch u.Scheme {
-	case "http", "https":
-		// Get chart content
-		req, _ := http.NewRequest("GET", u.String(), nil)
-		if u.Host == "github.com" || u.Host == "raw.githubusercontent.com" {
-			// Authenticate and rate limit requests to Github
-			githubToken := w.svc.Cfg.GetString("tracker.githubToken")
-			if githubToken != "" {
-				req.Header.Set("Authorization", fmt.Sprintf("token %s", githubToken))
-			}
-			_ = w.svc.GithubRL.Wait(w.svc.Ctx)
-		}
-		if w.r.AuthUser != "" || w.r.AuthPass != "" {
-			req.SetBasicAuth(w.r.AuthUser, w.r.AuthPass)
-		}
-		resp, err := w.svc.Hc.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
-		}
-		r = resp.Body
-	case "oci":
-		// Pull reference layers from OCI registry
-		ref := strings.TrimPrefix(u.String(), hub.RepositoryOCIPrefix)
-		resolverOptions := docker.ResolverOptions{}
-		if w.r.AuthUser != "" || w.r.AuthPass != "" {
-			resolverOptions.Authorizer = docker.NewDockerAuthorizer(
-				docker.WithAuthCreds(func(string) (string, string, error) {
-					return w.r.AuthUser, w.r.AuthPass, nil
-				}),
-			)
-		}
-		store := content.NewMemoryStore()
-		_, layers, err := oras.Pull(
-			ctxo.WithLoggerDiscarded(w.svc.Ctx),
-			docker.NewResolver(resolverOptions),
-			ref,
-			store,
-			oras.WithPullEmptyNameAllowed(),
-			oras.WithAllowedMediaTypes([]string{helmChartConfigMediaType, helmChartContentLayerMediaType}),
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// Create reader for Helm chart content layer, if available
-		for _, layer := range layers {
-			if layer.MediaType == helmChartContentLayerMediaType {
-				_, b, ok := store.Get(layer)
-				if ok {
-					r = bytes.NewReader(b)
-					break
+// The archive data is returned as well.
+func (w *Worker) loadChart(u *url.URL) (*chart.Chart, []byte, error) {
+	return helmchart.Load(w.svc.Ctx, w.svc.Hc, w.svc.Op, &helmchart.LoadInput{
+		URL:      u,
+		Username: w.r.AuthUser,
+		Password: w.r.AuthPass,
+		PrepareRequest: func(req *http.Request) {
+			if u.Host == "github.com" || u.Host == "raw.githubusercontent.com" {
+				// Authenticate and rate limit requests to Github
+				githubToken := w.svc.Cfg.GetString("tracker.githubToken")
+				if githubToken != "" {
+					req.Header.Set("Authorization", fmt.Sprintf("token %s", githubToken))
 				}
+				_ = w.svc.GithubRL.Wait(w.svc.Ctx)
 			}
-		}
-		if r == nil {
-			return nil, errors.New("content layer not found")
-		}
-	default:
-		return nil, repo.ErrSchemeNotSupported
-	}
-
-	// Load chart from reader previously setup
-	chart, err := loader.LoadArchive(r)
-	if err != nil {
-		return nil, err
-	}
-	return chart, nil
+		},
+	})
 }
 
-// chartVersionHasProvenanceFile checks if a chart version has a provenance
-// file checking if a .prov file exists for the chart version url provided.
-func (w *Worker) chartVersionHasProvenanceFile(u string) (bool, error) {
+// getProvenanceFile returns the provenance file of the chart version located
+// at the url provided. When the chart version does not have a provenance file
+// no data is returned.
+func (w *Worker) getProvenanceFile(u string) ([]byte, error) {
 	req, _ := http.NewRequest("GET", u+".prov", nil)
 	if w.r.AuthUser != "" || w.r.AuthPass != "" {
 		req.SetBasicAuth(w.r.AuthUser, w.r.AuthPass)
 	}
 	resp, err := w.svc.Hc.Do(req)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusOK {
-		return true, nil
+		return ioutil.ReadAll(resp.Body)
 	}
-	return false, nil
+	return nil, nil
 }
 
 // getImage gets the image located at the url provided. If it's a data url the
@@ -511,5 +494,17 @@ func enrichPackageFromAnnotations(p *hub.Package, annotations map[string]string)
 		p.ContainsSecurityUpdates = containsSecurityUpdates
 	}
 
+	// Vulnerability allowlist
+	if v, ok := annotations[vulnAllowlistAnnotation]; ok {
+		var allowlist []*hub.VulnerabilityAllowlistEntry
+		if err := yaml.Unmarshal([]byte(v), &allowlist); err != nil {
+			return fmt.Errorf("invalid vulnerability allowlist value: %s", v)
+		}
+		if err := tracker.ValidateVulnerabilityAllowlist(allowlist); err != nil {
+			return err
+		}
+		p.VulnerabilityAllowlist = allowlist
+	}
+
 	return nil
 }
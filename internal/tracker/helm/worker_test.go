@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"net/http"
@@ -13,11 +14,13 @@ import (
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/tracker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/openpgp"
 	"golang.org/x/time/rate"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/repo"
@@ -373,6 +376,117 @@ func TestWorker(t *testing.T) {
 			ww.w.Run(ww.wg, ww.queue)
 			ww.assertExpectations(t)
 		})
+
+		t.Run("package with provenance file verified registered successfully", func(t *testing.T) {
+			t.Parallel()
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			signer := newTestPGPEntity(t, "signer1")
+			ww.w.pgpKeyring = openpgp.EntityList{signer}
+			job := &Job{
+				Kind:         Register,
+				ChartVersion: pkg2V1,
+			}
+			ww.queue <- job
+			close(ww.queue)
+			chartData, _ := ioutil.ReadFile("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			reqChart, _ := http.NewRequest("GET", job.ChartVersion.URLs[0], nil)
+			ww.hc.On("Do", reqChart).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			provData := newTestProvenanceFile(t, signer, path.Base(job.ChartVersion.URLs[0]), chartData)
+			reqProv, _ := http.NewRequest("GET", job.ChartVersion.URLs[0]+".prov", nil)
+			ww.hc.On("Do", reqProv).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(provData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Signed &&
+					p.ProvenanceVerification != nil &&
+					p.ProvenanceVerification.Verified &&
+					p.ProvenanceVerification.Signer == "signer1 <signer1@email.com>"
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("package with containers images signatures verified registered successfully", func(t *testing.T) {
+			t.Parallel()
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.cosignKeys = []string{"key"}
+			job := &Job{
+				Kind:         Register,
+				ChartVersion: pkg1V1,
+			}
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			reqChart, _ := http.NewRequest("GET", job.ChartVersion.URLs[0], nil)
+			ww.hc.On("Do", reqChart).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			reqProv, _ := http.NewRequest("GET", job.ChartVersion.URLs[0]+".prov", nil)
+			ww.hc.On("Do", reqProv).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			v := &hub.CosignVerification{Verified: true, Digest: "sha256:1"}
+			ww.sv.On("Verify", mock.Anything, "repo/img1:1.0.0", []string{"key"}, "", "").Return(v, nil)
+			ww.sv.On("Verify", mock.Anything, "repo/img2:2.0.0", []string{"key"}, "", "").Return(nil, tests.ErrFake)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return len(p.ContainersImages) == 2 &&
+					p.ContainersImages[0].CosignVerification == v &&
+					p.ContainersImages[1].CosignVerification == nil
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("package with provenance file not verified registered successfully", func(t *testing.T) {
+			t.Parallel()
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.pgpKeyring = openpgp.EntityList{newTestPGPEntity(t, "signer1")}
+			job := &Job{
+				Kind:         Register,
+				ChartVersion: pkg2V1,
+			}
+			ww.queue <- job
+			close(ww.queue)
+			chartData, _ := ioutil.ReadFile("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			reqChart, _ := http.NewRequest("GET", job.ChartVersion.URLs[0], nil)
+			ww.hc.On("Do", reqChart).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			provData := newTestProvenanceFile(t, newTestPGPEntity(t, "signer2"), path.Base(job.ChartVersion.URLs[0]), chartData)
+			reqProv, _ := http.NewRequest("GET", job.ChartVersion.URLs[0]+".prov", nil)
+			ww.hc.On("Do", reqProv).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(provData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Signed &&
+					p.ProvenanceVerification != nil &&
+					!p.ProvenanceVerification.Verified
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
 	})
 
 	t.Run("handle unregister job", func(t *testing.T) {
@@ -821,6 +935,46 @@ func TestEnrichPackageFromAnnotations(t *testing.T) {
 			},
 			"",
 		},
+		// Vulnerability allowlist
+		{
+			&hub.Package{},
+			map[string]string{
+				vulnAllowlistAnnotation: `
+- vulnerabilityID: CVE-2020-0001
+  justification: Not exploitable
+  expiresAt: "2021-01-01"
+`,
+			},
+			&hub.Package{
+				VulnerabilityAllowlist: []*hub.VulnerabilityAllowlistEntry{
+					{
+						VulnerabilityID: "CVE-2020-0001",
+						Justification:   "Not exploitable",
+						ExpiresAt:       "2021-01-01",
+					},
+				},
+			},
+			"",
+		},
+		{
+			&hub.Package{},
+			map[string]string{
+				vulnAllowlistAnnotation: `"{\"`,
+			},
+			&hub.Package{},
+			"invalid vulnerability allowlist value",
+		},
+		{
+			&hub.Package{},
+			map[string]string{
+				vulnAllowlistAnnotation: `
+- vulnerabilityID: CVE-2020-0001
+  justification: Not exploitable
+`,
+			},
+			&hub.Package{},
+			"invalid expiration date",
+		},
 	}
 	for i, tc := range testCases {
 		tc := tc
@@ -844,6 +998,7 @@ type workerWrapper struct {
 	is    *img.StoreMock
 	ec    *tracker.ErrorsCollectorMock
 	hc    *tests.HTTPClientMock
+	sv    *oci.SignatureVerifierMock
 	w     *Worker
 	queue chan *Job
 }
@@ -854,6 +1009,7 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 	is := &img.StoreMock{}
 	ec := &tracker.ErrorsCollectorMock{}
 	hc := &tests.HTTPClientMock{}
+	sv := &oci.SignatureVerifierMock{}
 	r := &hub.Repository{RepositoryID: "repo1"}
 	svc := &tracker.Services{
 		Ctx:      ctx,
@@ -861,9 +1017,10 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 		Is:       is,
 		Ec:       ec,
 		Hc:       hc,
+		Sv:       sv,
 		GithubRL: rate.NewLimiter(rate.Inf, 0),
 	}
-	w := NewWorker(svc, r)
+	w := NewWorker(svc, r, nil, nil)
 	queue := make(chan *Job, 100)
 
 	// Wait group used for Worker.Run()
@@ -876,6 +1033,7 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 		is:    is,
 		ec:    ec,
 		hc:    hc,
+		sv:    sv,
 		w:     w,
 		queue: queue,
 	}
@@ -888,4 +1046,5 @@ func (ww *workerWrapper) assertExpectations(t *testing.T) {
 	ww.is.AssertExpectations(t)
 	ww.ec.AssertExpectations(t)
 	ww.hc.AssertExpectations(t)
+	ww.sv.AssertExpectations(t)
 }
@@ -102,10 +102,19 @@ func (t *Tracker) Track() error {
 			return nil
 		}
 
-		// Check if this package version is already registered
+		// Calculate package version digest
+		digest, err := tracker.GetDigest(pkgPath)
+		if err != nil {
+			t.warn(fmt.Errorf("error calculating package %s version %s digest: %w", pmd.Name, pmd.Version, err))
+			return nil
+		}
+
+		// Check if this package version is already registered and has not
+		// been updated
 		key := fmt.Sprintf("%s@%s", pmd.Name, pmd.Version)
 		packagesAvailable[key] = struct{}{}
-		if _, ok := packagesRegistered[key]; ok && !bypassDigestCheck {
+		registeredDigest, ok := packagesRegistered[key]
+		if ok && registeredDigest == digest && !bypassDigestCheck {
 			return nil
 		}
 
@@ -116,7 +125,7 @@ func (t *Tracker) Track() error {
 
 		// Register package version
 		t.logger.Debug().Str("name", pmd.Name).Str("v", pmd.Version).Msg("registering package")
-		err = t.registerPackage(pkgPath, pmd)
+		err = t.registerPackage(pkgPath, digest, pmd)
 		if err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", pmd.Name, pmd.Version, err))
 		}
@@ -157,17 +166,21 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
 
 	return nil
 }
 
 // registerPackage registers a package version using the package metadata
 // provided.
-func (t *Tracker) registerPackage(pkgPath string, md *plugin.Metadata) error {
+func (t *Tracker) registerPackage(pkgPath, digest string, md *plugin.Metadata) error {
 	// Prepare package from metadata
 	p := &hub.Package{
 		Name:        md.Name,
 		Version:     md.Version,
+		Digest:      digest,
 		Description: md.Description,
 		Keywords: []string{
 			"helm",
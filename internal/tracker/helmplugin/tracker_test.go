@@ -30,6 +30,7 @@ func TestTracker(t *testing.T) {
 		URL:               "https://github.com/org1/repo1",
 		VerifiedPublisher: false,
 	}
+	digest, _ := tracker.GetDigest("testdata/path3")
 
 	t.Run("error cloning repository", func(t *testing.T) {
 		t.Parallel()
@@ -115,7 +116,7 @@ func TestTracker(t *testing.T) {
 		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path3", nil)
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test-plugin@0.1.0": "",
+			"test-plugin@0.1.0": digest,
 		}, nil)
 
 		// Run tracker and check expectations
@@ -124,6 +125,26 @@ func TestTracker(t *testing.T) {
 		tw.assertExpectations(t)
 	})
 
+	t.Run("package version registered again because its content has changed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup tracker and expectations
+		tw := newTrackerWrapper(r)
+		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path3", nil)
+		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{}, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"test-plugin@0.1.0": "outdated-digest",
+		}, nil)
+		tw.pm.On("Register", tw.ctx, mock.MatchedBy(func(p *hub.Package) bool {
+			return p.Name == "test-plugin" && p.Version == "0.1.0" && p.Digest == digest
+		})).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track()
+		assert.NoError(t, err)
+		tw.assertExpectations(t)
+	})
+
 	t.Run("package version not registered because it is ignored", func(t *testing.T) {
 		t.Parallel()
 
@@ -158,6 +179,7 @@ func TestTracker(t *testing.T) {
 			Keywords:    []string{"helm", "helm-plugin"},
 			Description: "This is a sample test plugin",
 			Version:     "0.1.0",
+			Digest:      digest,
 			Readme:      "This is the readme file of the plugin\n",
 			Repository:  r,
 			License:     "Apache-2.0",
@@ -183,7 +205,7 @@ func TestTracker(t *testing.T) {
 		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path3", nil)
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: r.RepositoryID}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test-plugin@0.1.0": "",
+			"test-plugin@0.1.0": digest,
 			"test-plugin@0.2.0": "",
 		}, nil)
 		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
@@ -208,7 +230,7 @@ func TestTracker(t *testing.T) {
 		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path1", nil)
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: r.RepositoryID}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test-plugin@0.1.0": "",
+			"test-plugin@0.1.0": digest,
 		}, nil)
 		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
 
@@ -226,7 +248,7 @@ func TestTracker(t *testing.T) {
 		tw.rc.On("CloneRepository", tw.ctx, r).Return(".", "testdata/path3", nil)
 		tw.rm.On("GetMetadata", mock.Anything).Return(&hub.RepositoryMetadata{RepositoryID: r.RepositoryID}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test-plugin@0.1.0": "",
+			"test-plugin@0.1.0": digest,
 			"test-plugin@0.2.0": "",
 		}, nil)
 		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
@@ -257,7 +279,7 @@ func TestTracker(t *testing.T) {
 			},
 		}, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
-			"test-plugin@0.1.0": "",
+			"test-plugin@0.1.0": digest,
 		}, nil)
 		tw.rm.On("SetVerifiedPublisher", tw.ctx, r.RepositoryID, true).Return(nil)
 		tw.pm.On("Unregister", tw.ctx, &hub.Package{
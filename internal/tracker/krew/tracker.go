@@ -117,10 +117,19 @@ func (t *Tracker) Track() error {
 		}
 		version := sv.String()
 
-		// Check if this package version is already registered
+		// Calculate package version digest
+		digest, err := tracker.GetDigest(filepath.Join(pluginsPath, file.Name()))
+		if err != nil {
+			t.warn(fmt.Errorf("error calculating package %s version %s digest: %w", name, version, err))
+			continue
+		}
+
+		// Check if this package version is already registered and has not
+		// been updated
 		key := fmt.Sprintf("%s@%s", name, version)
 		packagesAvailable[key] = struct{}{}
-		if _, ok := packagesRegistered[key]; ok && !bypassDigestCheck {
+		registeredDigest, ok := packagesRegistered[key]
+		if ok && registeredDigest == digest && !bypassDigestCheck {
 			continue
 		}
 
@@ -131,7 +140,7 @@ func (t *Tracker) Track() error {
 
 		// Register package version
 		t.logger.Debug().Str("name", name).Str("v", version).Msg("registering package")
-		err = t.registerPackage(name, version, manifest)
+		err = t.registerPackage(name, version, digest, manifest)
 		if err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", name, version, err))
 		}
@@ -167,17 +176,21 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, rmd); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
 
 	return nil
 }
 
 // registerPackage registers a package version using the package manifest
 // provided.
-func (t *Tracker) registerPackage(name, version string, manifest *index.Plugin) error {
+func (t *Tracker) registerPackage(name, version, digest string, manifest *index.Plugin) error {
 	// Prepare package to be registered
 	p := &hub.Package{
 		Name:        name,
 		Version:     version,
+		Digest:      digest,
 		Description: manifest.Spec.ShortDescription,
 		HomeURL:     manifest.Spec.Homepage,
 		Readme:      manifest.Spec.Description,
@@ -15,6 +15,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
 	"github.com/ghodss/yaml"
@@ -43,9 +44,10 @@ var (
 // Tracker is in charge of tracking the packages available in a OLM operators
 // repository, registering and unregistering them as needed.
 type Tracker struct {
-	svc    *tracker.Services
-	r      *hub.Repository
-	logger zerolog.Logger
+	svc        *tracker.Services
+	r          *hub.Repository
+	cosignKeys []string
+	logger     zerolog.Logger
 }
 
 // NewTracker creates a new Tracker instance.
@@ -68,6 +70,9 @@ func NewTracker(
 	if t.svc.Re == nil {
 		t.svc.Re = &repo.OLMRepositoryExporter{}
 	}
+	if t.svc.Sv == nil {
+		t.svc.Sv = &oci.SignatureVerifier{}
+	}
 	return t
 }
 
@@ -98,14 +103,24 @@ func (t *Tracker) Track() error {
 	var md *hub.RepositoryMetadata
 	md, _ = t.svc.Rm.GetMetadata(filepath.Join(basePath, hub.RepositoryMetadataFile))
 
+	// Load keys used to verify containers images signatures
+	var errs []error
+	t.cosignKeys, errs = tracker.GetCosignKeys(md)
+	for _, err := range errs {
+		t.warn(fmt.Errorf("error loading signing keys: %w", err))
+	}
+
 	// Load packages already registered from this repository
 	packagesRegistered, err := t.svc.Rm.GetPackagesDigest(t.svc.Ctx, t.r.RepositoryID)
 	if err != nil {
 		return fmt.Errorf("error getting registered packages: %w", err)
 	}
 
-	// Register available packages when needed
-	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	// Register available packages when needed. Packages must be registered
+	// again when the signing keys change, so that they are verified using the
+	// new ones.
+	keysDigest := tracker.GetSigningKeysDigest(md)
+	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck") || keysDigest != t.r.SigningKeysDigest
 	packagesAvailable := make(map[string]struct{})
 	err = filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -229,6 +244,12 @@ func (t *Tracker) Track() error {
 	if err := tracker.SetVerifiedPublisherFlag(t.svc.Ctx, t.svc.Rm, t.r, md); err != nil {
 		t.warn(fmt.Errorf("error setting verified publisher flag: %w", err))
 	}
+	if err := tracker.SetVulnerabilityAllowlist(t.svc.Ctx, t.svc.Rm, t.r, md); err != nil {
+		t.warn(fmt.Errorf("error setting vulnerability allowlist: %w", err))
+	}
+	if err := tracker.SetSigningKeysDigest(t.svc.Ctx, t.svc.Rm, t.r, keysDigest); err != nil {
+		t.warn(err)
+	}
 
 	return nil
 }
@@ -418,6 +439,11 @@ func (t *Tracker) registerPackage(
 		"isGlobalOperator": isGlobalOperator,
 	}
 
+	// Verify containers images signatures
+	for _, err := range tracker.VerifyContainersImagesSignatures(t.svc.Ctx, t.svc.Sv, t.cosignKeys, p.ContainersImages) {
+		t.warn(fmt.Errorf("package %s version %s: %w", name, p.Version, err))
+	}
+
 	// Register package
 	return t.svc.Pm.Register(t.svc.Ctx, p)
 }
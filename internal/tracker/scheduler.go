@@ -0,0 +1,175 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultTrackingInterval represents the tracking interval used for the
+	// repositories that don't have one set when none has been configured.
+	defaultTrackingInterval = 30 * time.Minute
+
+	// pollInterval represents how often the scheduler checks if there are
+	// repositories that should be tracked.
+	pollInterval = 30 * time.Second
+)
+
+// NewTrackerFn represents a function that creates a new Tracker instance for
+// the repository provided.
+type NewTrackerFn func(svc *Services, r *hub.Repository) Tracker
+
+// Scheduler is in charge of tracking repositories continuously when the
+// tracker runs in daemon mode. Each repository is tracked on its own interval
+// (or the default one when it doesn't have one set), and repositories with a
+// pending tracking request are tracked as soon as possible. Only the
+// repositories matching the repositories names or kinds configured, if any,
+// are tracked.
+type Scheduler struct {
+	svc        *Services
+	newTracker NewTrackerFn
+	limiter    chan struct{}
+	wg         sync.WaitGroup
+
+	mu         sync.Mutex
+	inProgress map[string]struct{} // K: repository id
+}
+
+// NewScheduler creates a new Scheduler instance.
+func NewScheduler(svc *Services, newTracker NewTrackerFn) *Scheduler {
+	svc.Cfg.SetDefault("tracker.concurrency", 1)
+	svc.Cfg.SetDefault("tracker.trackingInterval", defaultTrackingInterval)
+	return &Scheduler{
+		svc:        svc,
+		newTracker: newTracker,
+		limiter:    make(chan struct{}, svc.Cfg.GetInt("tracker.concurrency")),
+		inProgress: make(map[string]struct{}),
+	}
+}
+
+// Run is the main loop of the scheduler. It checks periodically if there are
+// repositories that should be tracked until it's asked to stop via the context
+// provided. Before returning, it waits for the repositories being tracked at
+// that moment to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		s.schedule(ctx)
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		}
+	}
+}
+
+// schedule launches the tracking of the repositories that should be tracked
+// now, skipping the ones that are already being tracked.
+func (s *Scheduler) schedule(ctx context.Context) {
+	interval := s.svc.Cfg.GetDuration("tracker.trackingInterval")
+	repos, err := s.svc.Rm.GetToTrack(ctx, interval)
+	if err != nil {
+		log.Error().Err(err).Msg("error getting repositories to track")
+		return
+	}
+	repos, err = filterRepositories(s.svc.Cfg, repos)
+	if err != nil {
+		log.Error().Err(err).Msg("error filtering repositories to track")
+		return
+	}
+	for _, r := range repos {
+		s.mu.Lock()
+		if _, ok := s.inProgress[r.RepositoryID]; ok {
+			s.mu.Unlock()
+			continue
+		}
+		s.inProgress[r.RepositoryID] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func(r *hub.Repository) {
+			defer func() {
+				s.mu.Lock()
+				delete(s.inProgress, r.RepositoryID)
+				s.mu.Unlock()
+				s.wg.Done()
+			}()
+			select {
+			case s.limiter <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-s.limiter }()
+			s.track(ctx, r)
+		}(r)
+	}
+}
+
+// track tracks the repository provided. The errors found while tracking it
+// are collected and flushed once the tracking is done.
+func (s *Scheduler) track(ctx context.Context, r *hub.Repository) {
+	if err := s.svc.Rm.StartTracking(ctx, r.RepositoryID); err != nil {
+		log.Error().Err(err).Str("repo", r.Name).Msg("error starting repository tracking")
+		return
+	}
+	ec := NewDBErrorsCollector(s.svc.Rm, []*hub.Repository{r})
+	svc := *s.svc
+	svc.Ec = ec
+	t := s.newTracker(&svc, r)
+	if err := TrackRepository(ctx, svc.Cfg, svc.Rm, t, r); err != nil {
+		ec.Append(r.RepositoryID, err)
+		log.Error().Err(err).Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Send()
+	}
+	ec.Flush()
+}
+
+// filterRepositories returns the repositories provided that match the
+// repositories names or kinds set in the configuration, following the same
+// rules used to select the repositories to track when the tracker does not
+// run in daemon mode (see GetRepositories).
+func filterRepositories(cfg *viper.Viper, repos []*hub.Repository) ([]*hub.Repository, error) {
+	reposNames := cfg.GetStringSlice("tracker.repositoriesNames")
+	reposKinds := cfg.GetStringSlice("tracker.repositoriesKinds")
+
+	var match func(r *hub.Repository) bool
+	switch {
+	case len(reposNames) > 0:
+		names := make(map[string]struct{}, len(reposNames))
+		for _, name := range reposNames {
+			names[name] = struct{}{}
+		}
+		match = func(r *hub.Repository) bool {
+			_, ok := names[r.Name]
+			return ok
+		}
+	case len(reposKinds) > 0:
+		kinds := make(map[hub.RepositoryKind]struct{}, len(reposKinds))
+		for _, kindName := range reposKinds {
+			kind, err := hub.GetKindFromName(kindName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid repository kind found in config: %s", kindName)
+			}
+			kinds[kind] = struct{}{}
+		}
+		match = func(r *hub.Repository) bool {
+			_, ok := kinds[r.Kind]
+			return ok
+		}
+	default:
+		return repos, nil
+	}
+
+	var reposFiltered []*hub.Repository
+	for _, r := range repos {
+		if match(r) {
+			reposFiltered = append(reposFiltered, r)
+		}
+	}
+	return reposFiltered, nil
+}
@@ -0,0 +1,231 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	r := &hub.Repository{
+		RepositoryID: "00000000-0000-0000-0000-000000000001",
+		Name:         "repo1",
+		Digest:       "digest",
+	}
+
+	t.Run("error getting repositories to track", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return(nil, tests.ErrFake)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error starting repository tracking", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+		sw.rm.On("StartTracking", ctx, r.RepositoryID).Return(tests.ErrFake)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository tracked successfully", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.trackingInterval", "1h")
+		sw.rm.On("GetToTrack", ctx, 1*time.Hour).Return([]*hub.Repository{r}, nil)
+		sw.rm.On("StartTracking", ctx, r.RepositoryID).Return(nil)
+		sw.rm.On("GetRemoteDigest", ctx, r).Return("updated-digest", nil)
+		sw.tm.On("Track").Return(nil)
+		sw.rm.On("UpdateDigest", ctx, r.RepositoryID, "updated-digest").Return(nil)
+		sw.rm.On("SetLastTrackingResults", mock.Anything, r.RepositoryID, "").Return(nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+		assert.Empty(t, sw.s.inProgress)
+	})
+
+	t.Run("repository tracking failed, errors are flushed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+		sw.rm.On("StartTracking", ctx, r.RepositoryID).Return(nil)
+		sw.rm.On("GetRemoteDigest", ctx, r).Return("", tests.ErrFake)
+		sw.rm.On("SetLastTrackingResults", mock.Anything, r.RepositoryID,
+			"error getting repository remote digest: fake error for tests").Return(nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("invalid repository kind in config", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.repositoriesKinds", []string{"invalid"})
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repositories not matching the names configured are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.repositoriesNames", []string{"repo2"})
+		sw.cfg.Set("tracker.repositoriesKinds", []string{"helm"})
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repositories not matching the kinds configured are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.repositoriesKinds", []string{"olm", "falco"})
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository matching the name configured is tracked", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.repositoriesNames", []string{"repo1"})
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+		sw.rm.On("StartTracking", ctx, r.RepositoryID).Return(tests.ErrFake)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository matching the kind configured is tracked", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.cfg.Set("tracker.repositoriesKinds", []string{"helm"})
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+		sw.rm.On("StartTracking", ctx, r.RepositoryID).Return(tests.ErrFake)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository already being tracked is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		sw := newSchedulerWrapper()
+		sw.s.inProgress[r.RepositoryID] = struct{}{}
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return([]*hub.Repository{r}, nil)
+
+		// Run scheduler and check expectations
+		sw.s.schedule(ctx)
+		sw.s.wg.Wait()
+		sw.assertExpectations(t)
+	})
+
+	t.Run("scheduler stops when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup scheduler and expectations
+		ctx, cancel := context.WithCancel(context.Background())
+		sw := newSchedulerWrapper()
+		sw.rm.On("GetToTrack", ctx, defaultTrackingInterval).Return(nil, nil).Run(func(args mock.Arguments) {
+			cancel()
+		})
+
+		// Run scheduler and check expectations
+		done := make(chan struct{})
+		go func() {
+			sw.s.Run(ctx)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+		sw.assertExpectations(t)
+	})
+}
+
+type schedulerWrapper struct {
+	cfg *viper.Viper
+	rm  *repo.ManagerMock
+	tm  *Mock
+	s   *Scheduler
+}
+
+func newSchedulerWrapper() *schedulerWrapper {
+	cfg := viper.New()
+	rm := &repo.ManagerMock{}
+	tm := &Mock{}
+	svc := &Services{
+		Ctx: context.Background(),
+		Cfg: cfg,
+		Rm:  rm,
+	}
+	s := NewScheduler(svc, func(svc *Services, r *hub.Repository) Tracker {
+		return tm
+	})
+
+	return &schedulerWrapper{
+		cfg: cfg,
+		rm:  rm,
+		tm:  tm,
+		s:   s,
+	}
+}
+
+func (sw *schedulerWrapper) assertExpectations(t *testing.T) {
+	sw.rm.AssertExpectations(t)
+	sw.tm.AssertExpectations(t)
+}
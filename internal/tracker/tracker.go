@@ -2,12 +2,21 @@ package tracker
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
 )
@@ -23,12 +32,6 @@ type Tracker interface {
 	Track() error
 }
 
-// OCITagsGetter is the interface that wraps the Tags method, used to get all
-// the tags available for a given repository in a OCI registry.
-type OCITagsGetter interface {
-	Tags(ctx context.Context, r *hub.Repository) ([]string, error)
-}
-
 // New represents a function that creates new repository trackers. Each tracker
 // is in charge of processing a given repository, and based on the concurrency
 // configured, the tracker cmd may run multiple Tracker instances concurrently.
@@ -43,7 +46,9 @@ type Services struct {
 	Rm       hub.RepositoryManager
 	Pm       hub.PackageManager
 	Il       hub.HelmIndexLoader
-	Tg       OCITagsGetter
+	Tg       hub.OCITagsGetter
+	Op       hub.OCIPuller
+	Sv       hub.OCISignatureVerifier
 	Re       hub.OLMRepositoryExporter
 	Is       img.Store
 	Ec       ErrorsCollector
@@ -166,6 +171,171 @@ func SetVerifiedPublisherFlag(
 	return nil
 }
 
+// SetVulnerabilityAllowlist sets the vulnerability allowlist of the repository
+// provided when needed, using the entries defined in the repository metadata.
+func SetVulnerabilityAllowlist(
+	ctx context.Context,
+	rm hub.RepositoryManager,
+	r *hub.Repository,
+	md *hub.RepositoryMetadata,
+) error {
+	var allowlist []*hub.VulnerabilityAllowlistEntry
+	if md != nil {
+		allowlist = md.VulnerabilityAllowlist
+	}
+	if len(allowlist) == 0 && len(r.VulnerabilityAllowlist) == 0 {
+		return nil
+	}
+	if reflect.DeepEqual(allowlist, r.VulnerabilityAllowlist) {
+		return nil
+	}
+	if err := ValidateVulnerabilityAllowlist(allowlist); err != nil {
+		return err
+	}
+	if err := rm.SetVulnerabilityAllowlist(ctx, r.RepositoryID, allowlist); err != nil {
+		return fmt.Errorf("error setting vulnerability allowlist: %w", err)
+	}
+	return nil
+}
+
+// GetCosignKeys returns the valid cosign public keys defined in the
+// repository metadata provided. The errors found validating the keys are
+// returned, but they do not stop the rest of the keys from being loaded.
+func GetCosignKeys(md *hub.RepositoryMetadata) ([]string, []error) {
+	if md == nil || md.SigningKeys == nil || len(md.SigningKeys.Cosign) == 0 {
+		return nil, nil
+	}
+	var keys []string
+	var errs []error
+	for i, key := range md.SigningKeys.Cosign {
+		if err := oci.ValidateCosignKey(key); err != nil {
+			errs = append(errs, fmt.Errorf("error reading cosign key %d: %w", i, err))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, errs
+}
+
+// GetSigningKeysDigest returns a digest of the signing keys defined in the
+// repository metadata provided. An empty string is returned when no keys have
+// been defined.
+func GetSigningKeysDigest(md *hub.RepositoryMetadata) string {
+	if md == nil || md.SigningKeys == nil {
+		return ""
+	}
+	if len(md.SigningKeys.PGP) == 0 && len(md.SigningKeys.Cosign) == 0 {
+		return ""
+	}
+	hash := sha256.New()
+	for _, key := range md.SigningKeys.PGP {
+		fmt.Fprintf(hash, "pgp\x00%d\x00%s", len(key), key)
+	}
+	for _, key := range md.SigningKeys.Cosign {
+		fmt.Fprintf(hash, "cosign\x00%d\x00%s", len(key), key)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// SetSigningKeysDigest updates the signing keys digest of the repository
+// provided when needed.
+func SetSigningKeysDigest(
+	ctx context.Context,
+	rm hub.RepositoryManager,
+	r *hub.Repository,
+	digest string,
+) error {
+	if r.SigningKeysDigest == digest {
+		return nil
+	}
+	if err := rm.UpdateSigningKeysDigest(ctx, r.RepositoryID, digest); err != nil {
+		return fmt.Errorf("error updating signing keys digest: %w", err)
+	}
+	return nil
+}
+
+// VerifyContainersImagesSignatures verifies the cosign signatures of the
+// containers images provided using the keys given, storing the result of the
+// verification in each image. The errors found verifying the images are
+// returned, but they do not stop the rest of the images from being verified.
+func VerifyContainersImagesSignatures(
+	ctx context.Context,
+	sv hub.OCISignatureVerifier,
+	keys []string,
+	images []*hub.ContainerImage,
+) []error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, image := range images {
+		v, err := sv.Verify(ctx, image.Image, keys, "", "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error verifying image %s signature: %w", image.Image, err))
+			continue
+		}
+		image.CosignVerification = v
+	}
+	return errs
+}
+
+// ValidateVulnerabilityAllowlist checks if the vulnerability allowlist entries
+// provided are valid. All entries must include the vulnerability id, a
+// justification and the expiration date (YYYY-MM-DD).
+func ValidateVulnerabilityAllowlist(allowlist []*hub.VulnerabilityAllowlistEntry) error {
+	for _, e := range allowlist {
+		if e == nil || e.VulnerabilityID == "" {
+			return errors.New("invalid vulnerability allowlist entry: vulnerability id not provided")
+		}
+		if e.Justification == "" {
+			return fmt.Errorf("invalid vulnerability allowlist entry (%s): justification not provided", e.VulnerabilityID)
+		}
+		if _, err := time.Parse("2006-01-02", e.ExpiresAt); err != nil {
+			return fmt.Errorf("invalid vulnerability allowlist entry (%s): invalid expiration date", e.VulnerabilityID)
+		}
+	}
+	return nil
+}
+
+// GetDigest returns a digest of the content of the paths provided, which can
+// be files or directories (processed recursively). Both the relative path and
+// the content of each file are taken into account, so renaming, adding or
+// removing a file will produce a different digest. Git directories are skipped.
+func GetDigest(paths ...string) (string, error) {
+	hash := sha256.New()
+	for _, basePath := range paths {
+		err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if info.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			relPath, err := filepath.Rel(basePath, path)
+			if err != nil {
+				return err
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(relPath), len(data))
+			_, _ = hash.Write(data)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // ShouldIgnorePackage checks if the package provided should be ignored.
 func ShouldIgnorePackage(md *hub.RepositoryMetadata, name, version string) bool {
 	if md == nil {
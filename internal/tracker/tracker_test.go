@@ -2,15 +2,26 @@ package tracker
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetRepositories(t *testing.T) {
@@ -378,6 +389,378 @@ func TestSetVerifiedPublisherFlag(t *testing.T) {
 	})
 }
 
+func TestSetVulnerabilityAllowlist(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup some services required by tests
+	repo1ID := "00000000-0000-0000-0000-000000000001"
+	allowlist := []*hub.VulnerabilityAllowlistEntry{
+		{
+			VulnerabilityID: "CVE-2020-0001",
+			Justification:   "Not exploitable",
+			ExpiresAt:       "2021-01-01",
+		},
+	}
+
+	t.Run("vulnerability allowlist set successfully", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup expectations
+		r := &hub.Repository{
+			RepositoryID: repo1ID,
+		}
+		md := &hub.RepositoryMetadata{
+			VulnerabilityAllowlist: allowlist,
+		}
+		rm := &repo.ManagerMock{}
+		rm.On("SetVulnerabilityAllowlist", ctx, r.RepositoryID, allowlist).Return(nil)
+
+		// Run test and check expectations
+		err := SetVulnerabilityAllowlist(ctx, rm, r, md)
+		assert.Nil(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("vulnerability allowlist not set as it did not change", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup expectations
+		r := &hub.Repository{
+			RepositoryID:           repo1ID,
+			VulnerabilityAllowlist: allowlist,
+		}
+		md := &hub.RepositoryMetadata{
+			VulnerabilityAllowlist: allowlist,
+		}
+		rm := &repo.ManagerMock{}
+
+		// Run test and check expectations
+		err := SetVulnerabilityAllowlist(ctx, rm, r, md)
+		assert.Nil(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("vulnerability allowlist cleared: md file did not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup expectations
+		r := &hub.Repository{
+			RepositoryID:           repo1ID,
+			VulnerabilityAllowlist: allowlist,
+		}
+		rm := &repo.ManagerMock{}
+		rm.On("SetVulnerabilityAllowlist", ctx, r.RepositoryID, []*hub.VulnerabilityAllowlistEntry(nil)).Return(nil)
+
+		// Run test and check expectations
+		err := SetVulnerabilityAllowlist(ctx, rm, r, nil)
+		assert.Nil(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("invalid vulnerability allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup expectations
+		r := &hub.Repository{
+			RepositoryID: repo1ID,
+		}
+		md := &hub.RepositoryMetadata{
+			VulnerabilityAllowlist: []*hub.VulnerabilityAllowlistEntry{
+				{
+					VulnerabilityID: "CVE-2020-0001",
+				},
+			},
+		}
+		rm := &repo.ManagerMock{}
+
+		// Run test and check expectations
+		err := SetVulnerabilityAllowlist(ctx, rm, r, md)
+		assert.Error(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("set vulnerability allowlist failed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup expectations
+		r := &hub.Repository{
+			RepositoryID: repo1ID,
+		}
+		md := &hub.RepositoryMetadata{
+			VulnerabilityAllowlist: allowlist,
+		}
+		rm := &repo.ManagerMock{}
+		rm.On("SetVulnerabilityAllowlist", ctx, r.RepositoryID, allowlist).Return(tests.ErrFake)
+
+		// Run test and check expectations
+		err := SetVulnerabilityAllowlist(ctx, rm, r, md)
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		rm.AssertExpectations(t)
+	})
+}
+
+func TestGetCosignKeys(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	t.Run("no metadata provided", func(t *testing.T) {
+		t.Parallel()
+		keys, errs := GetCosignKeys(nil)
+		assert.Empty(t, errs)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("no signing keys provided", func(t *testing.T) {
+		t.Parallel()
+		keys, errs := GetCosignKeys(&hub.RepositoryMetadata{})
+		assert.Empty(t, errs)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("invalid keys are skipped", func(t *testing.T) {
+		t.Parallel()
+		keys, errs := GetCosignKeys(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{
+				Cosign: []string{"invalid", keyPEM, "invalid"},
+			},
+		})
+		require.Len(t, errs, 2)
+		assert.True(t, errors.Is(errs[0], oci.ErrInvalidCosignKey))
+		assert.Contains(t, errs[0].Error(), "cosign key 0")
+		assert.True(t, errors.Is(errs[1], oci.ErrInvalidCosignKey))
+		assert.Contains(t, errs[1].Error(), "cosign key 2")
+		assert.Equal(t, []string{keyPEM}, keys)
+	})
+
+	t.Run("valid keys provided", func(t *testing.T) {
+		t.Parallel()
+		keys, errs := GetCosignKeys(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{
+				Cosign: []string{keyPEM},
+			},
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{keyPEM}, keys)
+	})
+}
+
+func TestGetSigningKeysDigest(t *testing.T) {
+	t.Run("no signing keys provided", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, GetSigningKeysDigest(nil))
+		assert.Empty(t, GetSigningKeysDigest(&hub.RepositoryMetadata{}))
+		assert.Empty(t, GetSigningKeysDigest(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{},
+		}))
+	})
+
+	t.Run("digest changes when the keys change", func(t *testing.T) {
+		t.Parallel()
+		digest1 := GetSigningKeysDigest(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{PGP: []string{"key1"}},
+		})
+		digest2 := GetSigningKeysDigest(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{PGP: []string{"key1", "key2"}},
+		})
+		digest3 := GetSigningKeysDigest(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{Cosign: []string{"key1"}},
+		})
+		assert.NotEmpty(t, digest1)
+		assert.NotEqual(t, digest1, digest2)
+		assert.NotEqual(t, digest1, digest3)
+		assert.Equal(t, digest1, GetSigningKeysDigest(&hub.RepositoryMetadata{
+			SigningKeys: &hub.RepositorySigningKeys{PGP: []string{"key1"}},
+		}))
+	})
+}
+
+func TestSetSigningKeysDigest(t *testing.T) {
+	ctx := context.Background()
+	repo1ID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("digest has not changed", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{RepositoryID: repo1ID, SigningKeysDigest: "digest"}
+		rm := &repo.ManagerMock{}
+
+		err := SetSigningKeysDigest(ctx, rm, r, "digest")
+		assert.NoError(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("error updating digest", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{RepositoryID: repo1ID}
+		rm := &repo.ManagerMock{}
+		rm.On("UpdateSigningKeysDigest", ctx, repo1ID, "digest").Return(tests.ErrFakeDB)
+
+		err := SetSigningKeysDigest(ctx, rm, r, "digest")
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("digest updated successfully", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{RepositoryID: repo1ID, SigningKeysDigest: "digest"}
+		rm := &repo.ManagerMock{}
+		rm.On("UpdateSigningKeysDigest", ctx, repo1ID, "").Return(nil)
+
+		err := SetSigningKeysDigest(ctx, rm, r, "")
+		assert.NoError(t, err)
+		rm.AssertExpectations(t)
+	})
+}
+
+func TestVerifyContainersImagesSignatures(t *testing.T) {
+	ctx := context.Background()
+	keys := []string{"key"}
+
+	t.Run("no keys provided", func(t *testing.T) {
+		t.Parallel()
+		sv := &oci.SignatureVerifierMock{}
+		images := []*hub.ContainerImage{{Image: "repo/img1:1.0.0"}}
+
+		errs := VerifyContainersImagesSignatures(ctx, sv, nil, images)
+		assert.Nil(t, errs)
+		assert.Nil(t, images[0].CosignVerification)
+		sv.AssertExpectations(t)
+	})
+
+	t.Run("images signatures verified", func(t *testing.T) {
+		t.Parallel()
+		v1 := &hub.CosignVerification{Verified: true, Digest: "sha256:1"}
+		sv := &oci.SignatureVerifierMock{}
+		sv.On("Verify", ctx, "repo/img1:1.0.0", keys, "", "").Return(v1, nil)
+		sv.On("Verify", ctx, "repo/img2:1.0.0", keys, "", "").Return(nil, nil)
+		sv.On("Verify", ctx, "repo/img3:1.0.0", keys, "", "").Return(nil, tests.ErrFake)
+		images := []*hub.ContainerImage{
+			{Image: "repo/img1:1.0.0"},
+			{Image: "repo/img2:1.0.0"},
+			{Image: "repo/img3:1.0.0"},
+		}
+
+		errs := VerifyContainersImagesSignatures(ctx, sv, keys, images)
+		require.Len(t, errs, 1)
+		assert.True(t, errors.Is(errs[0], tests.ErrFake))
+		assert.Equal(t, v1, images[0].CosignVerification)
+		assert.Nil(t, images[1].CosignVerification)
+		assert.Nil(t, images[2].CosignVerification)
+		sv.AssertExpectations(t)
+	})
+}
+
+func TestValidateVulnerabilityAllowlist(t *testing.T) {
+	testCases := []struct {
+		entry         *hub.VulnerabilityAllowlistEntry
+		expectedError string
+	}{
+		{
+			&hub.VulnerabilityAllowlistEntry{
+				VulnerabilityID: "CVE-2020-0001",
+				Justification:   "Not exploitable",
+				ExpiresAt:       "2021-01-01",
+			},
+			"",
+		},
+		{
+			&hub.VulnerabilityAllowlistEntry{
+				Justification: "Not exploitable",
+				ExpiresAt:     "2021-01-01",
+			},
+			"vulnerability id not provided",
+		},
+		{
+			&hub.VulnerabilityAllowlistEntry{
+				VulnerabilityID: "CVE-2020-0001",
+				ExpiresAt:       "2021-01-01",
+			},
+			"justification not provided",
+		},
+		{
+			&hub.VulnerabilityAllowlistEntry{
+				VulnerabilityID: "CVE-2020-0001",
+				Justification:   "Not exploitable",
+				ExpiresAt:       "01/01/2021",
+			},
+			"invalid expiration date",
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			err := ValidateVulnerabilityAllowlist([]*hub.VulnerabilityAllowlistEntry{tc.entry})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetDigest(t *testing.T) {
+	t.Run("path does not exist", func(t *testing.T) {
+		t.Parallel()
+		_, err := GetDigest("testdata/not-found")
+		assert.Error(t, err)
+	})
+
+	t.Run("digest changes when a file is modified, added or renamed", func(t *testing.T) {
+		t.Parallel()
+		dir, err := ioutil.TempDir("", "tracker-digest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file1 := filepath.Join(dir, "file1")
+		assert.NoError(t, ioutil.WriteFile(file1, []byte("content1"), 0644))
+
+		d1, err := GetDigest(dir)
+		assert.NoError(t, err)
+		d1Again, err := GetDigest(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, d1, d1Again)
+
+		assert.NoError(t, ioutil.WriteFile(file1, []byte("content1-updated"), 0644))
+		d2, err := GetDigest(dir)
+		assert.NoError(t, err)
+		assert.NotEqual(t, d1, d2)
+
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "file2"), []byte("content2"), 0644))
+		d3, err := GetDigest(dir)
+		assert.NoError(t, err)
+		assert.NotEqual(t, d2, d3)
+
+		assert.NoError(t, os.Rename(file1, filepath.Join(dir, "file3")))
+		d4, err := GetDigest(dir)
+		assert.NoError(t, err)
+		assert.NotEqual(t, d3, d4)
+
+		d5, err := GetDigest(dir, filepath.Join(dir, "file3"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, d4, d5)
+	})
+
+	t.Run("git directories are ignored", func(t *testing.T) {
+		t.Parallel()
+		dir, err := ioutil.TempDir("", "tracker-digest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file1"), []byte("content1"), 0644))
+		d1, err := GetDigest(dir)
+		assert.NoError(t, err)
+
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644))
+		d2, err := GetDigest(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, d1, d2)
+	})
+}
+
 func TestShouldIgnorePackage(t *testing.T) {
 	testCases := []struct {
 		md             *hub.RepositoryMetadata
@@ -365,6 +365,9 @@ func (m *Manager) UpdateProfile(ctx context.Context, user *hub.User) error {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid profile image id")
 		}
 	}
+	if user.EmailDeliveryPreference != nil && !hub.IsValidEmailDeliveryPreference(*user.EmailDeliveryPreference) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid email delivery preference")
+	}
 
 	// Update user profile in database
 	userJSON, _ := json.Marshal(user)
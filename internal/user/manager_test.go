@@ -788,6 +788,7 @@ func TestUpdateProfile(t *testing.T) {
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
+		invalidPreference := hub.EmailDeliveryPreference(9)
 		testCases := []struct {
 			errMsg string
 			user   *hub.User
@@ -800,6 +801,10 @@ func TestUpdateProfile(t *testing.T) {
 				"invalid profile image id",
 				&hub.User{Alias: "user1", Email: "email", ProfileImageID: "invalid"},
 			},
+			{
+				"invalid email delivery preference",
+				&hub.User{Alias: "user1", EmailDeliveryPreference: &invalidPreference},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -19,6 +19,10 @@ var (
 	// ErrDBInsufficientPrivilege indicates that the user does not have the
 	// required privilege to perform the operation.
 	ErrDBInsufficientPrivilege = errors.New("ERROR: insufficient_privilege (SQLSTATE 42501)")
+
+	// ErrDBNoDataFound indicates that the data the operation was meant to
+	// process was not found.
+	ErrDBNoDataFound = errors.New("ERROR: no_data_found (SQLSTATE P0002)")
 )
 
 // SetupDB creates a database connection pool using the configuration provided.
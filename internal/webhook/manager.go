@@ -4,23 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"net/url"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/satori/uuid"
 )
 
 const (
 	// Database queries
-	addWebhookDBQ                 = `select add_webhook($1::uuid, $2::text, $3::jsonb)`
-	deleteWebhookDBQ              = `select delete_webhook($1::uuid, $2::uuid)`
-	getWebhooksSubscribedToPkgDBQ = `select get_webhooks_subscribed_to_package($1::int, $2::uuid)`
-	getOrgWebhooksDBQ             = `select get_org_webhooks($1::uuid, $2::text)`
-	getUserWebhooksDBQ            = `select get_user_webhooks($1::uuid)`
-	getWebhookDBQ                 = `select get_webhook($1::uuid, $2::uuid)`
-	updateWebhookDBQ              = `select update_webhook($1::uuid, $2::jsonb)`
+	addWebhookDBQ                  = `select add_webhook($1::uuid, $2::text, $3::jsonb)`
+	deleteWebhookDBQ               = `select delete_webhook($1::uuid, $2::uuid)`
+	getWebhooksByIDsDBQ            = `select get_webhooks_by_ids($1::int, $2::uuid[])`
+	getWebhooksSubscribedToPkgDBQ  = `select get_webhooks_subscribed_to_package($1::int, $2::uuid)`
+	getWebhooksSubscribedToRepoDBQ = `select get_webhooks_subscribed_to_repository($1::int, $2::uuid)`
+	getOrgWebhooksDBQ              = `select get_org_webhooks($1::uuid, $2::text)`
+	getUserWebhooksDBQ             = `select get_user_webhooks($1::uuid)`
+	getWebhookDBQ                  = `select get_webhook($1::uuid, $2::uuid)`
+	getWebhookDeliveriesDBQ        = `select get_webhook_deliveries($1::uuid, $2::uuid)`
+	redeliverWebhookNotifDBQ       = `select redeliver_webhook_notification($1::uuid, $2::uuid, $3::uuid)`
+	updateWebhookDBQ               = `select update_webhook($1::uuid, $2::jsonb)`
 )
 
 // Manager provides an API to manage webhooks.
@@ -50,20 +55,39 @@ func (m *Manager) Add(ctx context.Context, orgName string, wh *hub.Webhook) erro
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 	}
-	if _, err := template.New("").Parse(wh.Template); err != nil {
+	if err := notification.ValidateWebhookTemplate(wh); err != nil {
 		return fmt.Errorf("%w: %s %s", hub.ErrInvalidInput, "invalid template", err)
 	}
+	if !hub.IsValidWebhookChannel(wh.Channel) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid channel")
+	}
+	if err := subscription.ValidateFilter(wh.Filter); err != nil {
+		return err
+	}
+	if wh.SignPayloads && wh.Secret == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "secret required to sign payloads")
+	}
 	if len(wh.EventKinds) == 0 {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no event kinds provided")
 	}
-	if len(wh.Packages) == 0 {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages provided")
+	if len(wh.Packages) == 0 && len(wh.Repositories) == 0 && len(wh.Organizations) == 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages, repositories or organizations provided")
 	}
 	for _, p := range wh.Packages {
 		if _, err := uuid.FromString(p.PackageID); err != nil {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 		}
 	}
+	for _, r := range wh.Repositories {
+		if _, err := uuid.FromString(r.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	for _, o := range wh.Organizations {
+		if o.Name == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid organization name")
+		}
+	}
 
 	// Add webhook to the database
 	whJSON, _ := json.Marshal(wh)
@@ -91,6 +115,27 @@ func (m *Manager) Delete(ctx context.Context, webhookID string) error {
 	return err
 }
 
+// GetDeliveriesJSON returns the latest delivery attempts of the provided
+// webhook as a json array.
+func (m *Manager) GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+
+	// Get webhook deliveries from database
+	dataJSON, err := util.DBQueryJSON(ctx, m.db, getWebhookDeliveriesDBQ, userID, webhookID)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
 // GetJSON returns the requested webhook as a json object.
 func (m *Manager) GetJSON(ctx context.Context, webhookID string) ([]byte, error) {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -139,11 +184,40 @@ func (m *Manager) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub.Web
 	var dataJSON []byte
 	var err error
 	switch e.EventKind {
-	case hub.NewRelease:
+	case hub.NewRelease, hub.SecurityAlert, hub.PackageVersionRemoved, hub.PackageDeprecated:
+		// Package version removed events include the ids of the webhooks that
+		// were subscribed to the package when the version was removed, as the
+		// package and its webhooks links may have been deleted already
+		if e.EventKind == hub.PackageVersionRemoved {
+			if v, ok := e.Data["webhooks"]; ok {
+				var webhooksIDs []string
+				webhooksIDs, err = getWebhooksIDs(v)
+				if err != nil {
+					return nil, err
+				}
+				dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksByIDsDBQ, e.EventKind, webhooksIDs)
+				break
+			}
+		}
+
+		// Package version removed events are not linked to the package when
+		// its last version was removed, as the package has been deleted
+		if e.EventKind == hub.PackageVersionRemoved && e.PackageID == "" {
+			if _, err := uuid.FromString(e.RepositoryID); err != nil {
+				return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+			}
+			dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksSubscribedToRepoDBQ, e.EventKind, e.RepositoryID)
+			break
+		}
 		if _, err := uuid.FromString(e.PackageID); err != nil {
 			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 		}
 		dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksSubscribedToPkgDBQ, e.EventKind, e.PackageID)
+	case hub.RepositoryTrackingErrors, hub.RepositoryTransferred, hub.RepositoryVerifiedPublisherChanged:
+		if _, err := uuid.FromString(e.RepositoryID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+		dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksSubscribedToRepoDBQ, e.EventKind, e.RepositoryID)
 	default:
 		return nil, nil
 	}
@@ -157,6 +231,53 @@ func (m *Manager) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub.Web
 	return webhooks, err
 }
 
+// getWebhooksIDs returns the webhooks ids stored in the event data value
+// provided, validating them.
+func getWebhooksIDs(v interface{}) ([]string, error) {
+	if v == nil {
+		return []string{}, nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhooks ids")
+	}
+	webhooksIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		webhookID, _ := item.(string)
+		if _, err := uuid.FromString(webhookID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+		}
+		webhooksIDs = append(webhooksIDs, webhookID)
+	}
+	return webhooksIDs, nil
+}
+
+// Redeliver queues again for delivery the notification corresponding to the
+// webhook delivery provided. The notification delivery attempts are reset.
+func (m *Manager) Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+	if _, err := uuid.FromString(webhookDeliveryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook delivery id")
+	}
+
+	// Queue notification again in database
+	_, err := m.db.Exec(ctx, redeliverWebhookNotifDBQ, userID, webhookID, webhookDeliveryID)
+	if err != nil {
+		switch err.Error() {
+		case util.ErrDBInsufficientPrivilege.Error():
+			return hub.ErrInsufficientPrivilege
+		case util.ErrDBNoDataFound.Error():
+			return hub.ErrNotFound
+		}
+	}
+	return err
+}
+
 // Update updates the provided webhook in the database.
 func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -175,20 +296,39 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 	}
-	if _, err := template.New("").Parse(wh.Template); err != nil {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid template")
+	if err := notification.ValidateWebhookTemplate(wh); err != nil {
+		return fmt.Errorf("%w: %s %s", hub.ErrInvalidInput, "invalid template", err)
+	}
+	if !hub.IsValidWebhookChannel(wh.Channel) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid channel")
+	}
+	if err := subscription.ValidateFilter(wh.Filter); err != nil {
+		return err
+	}
+	if wh.SignPayloads && wh.Secret == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "secret required to sign payloads")
 	}
 	if len(wh.EventKinds) == 0 {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no event kinds provided")
 	}
-	if len(wh.Packages) == 0 {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages provided")
+	if len(wh.Packages) == 0 && len(wh.Repositories) == 0 && len(wh.Organizations) == 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages, repositories or organizations provided")
 	}
 	for _, p := range wh.Packages {
 		if _, err := uuid.FromString(p.PackageID); err != nil {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 		}
 	}
+	for _, r := range wh.Repositories {
+		if _, err := uuid.FromString(r.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	for _, o := range wh.Organizations {
+		if o.Name == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid organization name")
+		}
+	}
 
 	// Update webhook in database
 	whJSON, _ := json.Marshal(wh)
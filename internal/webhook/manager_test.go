@@ -73,6 +73,53 @@ func TestAdd(t *testing.T) {
 					Template: "{{ .",
 				},
 			},
+			{
+				"invalid template",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					Template:   "{{ .Package.name }}",
+					EventKinds: []hub.EventKind{hub.NewRelease, hub.RepositoryTrackingErrors},
+				},
+			},
+			{
+				"invalid template",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					Template:   "{{ .Package.name }}",
+					EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+				},
+			},
+			{
+				"invalid channel",
+				"org1",
+				&hub.Webhook{
+					Name:    "webhook",
+					URL:     "http://webhook1.url",
+					Channel: hub.WebhookChannel(100),
+				},
+			},
+			{
+				"invalid version constraint",
+				"org1",
+				&hub.Webhook{
+					Name:   "webhook",
+					URL:    "http://webhook1.url",
+					Filter: &hub.SubscriptionFilter{VersionConstraint: "invalid"},
+				},
+			},
+			{
+				"secret required to sign payloads",
+				"org1",
+				&hub.Webhook{
+					Name:         "webhook",
+					URL:          "http://webhook1.url",
+					SignPayloads: true,
+				},
+			},
 			{
 				"no event kinds provided",
 				"org1",
@@ -82,7 +129,7 @@ func TestAdd(t *testing.T) {
 				},
 			},
 			{
-				"no packages provided",
+				"no packages, repositories or organizations provided",
 				"org1",
 				&hub.Webhook{
 					Name:       "webhook",
@@ -102,6 +149,30 @@ func TestAdd(t *testing.T) {
 					},
 				},
 			},
+			{
+				"invalid repository id",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Repositories: []*hub.Repository{
+						{RepositoryID: "invalid"},
+					},
+				},
+			},
+			{
+				"invalid organization name",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Organizations: []*hub.Organization{
+						{Name: ""},
+					},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -216,6 +287,67 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestGetDeliveriesJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetDeliveriesJSON(context.Background(), validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		_, err := m.GetDeliveriesJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getWebhookDeliveriesDBQ, "userID", validUUID).Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetDeliveriesJSON(ctx, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("webhook deliveries data returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookDeliveriesDBQ, "userID", validUUID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDeliveriesJSON(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetJSON(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
@@ -375,6 +507,31 @@ func TestGetSubscribedTo(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.Event{
+					EventKind:    hub.RepositoryTransferred,
+					RepositoryID: "invalid",
+				},
+			},
+			{
+				"invalid webhooks ids",
+				&hub.Event{
+					EventKind: hub.PackageVersionRemoved,
+					Data: map[string]interface{}{
+						"webhooks": "invalid",
+					},
+				},
+			},
+			{
+				"invalid webhook id",
+				&hub.Event{
+					EventKind: hub.PackageVersionRemoved,
+					Data: map[string]interface{}{
+						"webhooks": []interface{}{"invalid"},
+					},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -429,6 +586,204 @@ func TestGetSubscribedTo(t *testing.T) {
 		assert.Equal(t, "http://webhook2.url", w[1].URL)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("webhooks subscribed to security alerts returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksSubscribedToPkgDBQ, hub.SecurityAlert, validUUID).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url"
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind: hub.SecurityAlert,
+			PackageID: validUUID,
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("webhooks subscribed to repository events returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksSubscribedToRepoDBQ, hub.RepositoryVerifiedPublisherChanged, validUUID).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url"
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind:    hub.RepositoryVerifiedPublisherChanged,
+			RepositoryID: validUUID,
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("webhooks subscribed to repository tracking errors returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksSubscribedToRepoDBQ, hub.RepositoryTrackingErrors, validUUID).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url"
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: validUUID,
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("webhooks subscribed to removed package version resolved from event data", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksByIDsDBQ, hub.PackageVersionRemoved, []string{validUUID}).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url"
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind:    hub.PackageVersionRemoved,
+			RepositoryID: validUUID,
+			Data: map[string]interface{}{
+				"webhooks": []interface{}{validUUID},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("webhooks subscribed to removed package version returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksSubscribedToRepoDBQ, hub.PackageVersionRemoved, validUUID).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url"
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind:    hub.PackageVersionRemoved,
+			RepositoryID: validUUID,
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Redeliver(context.Background(), validUUID, validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg            string
+			webhookID         string
+			webhookDeliveryID string
+		}{
+			{
+				"invalid webhook id",
+				"",
+				validUUID,
+			},
+			{
+				"invalid webhook delivery id",
+				validUUID,
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				err := m.Redeliver(ctx, tc.webhookID, tc.webhookDeliveryID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+			{
+				util.ErrDBNoDataFound,
+				hub.ErrNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, redeliverWebhookNotifDBQ, "userID", validUUID, validUUID).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Redeliver(ctx, validUUID, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("redelivery requested successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, redeliverWebhookNotifDBQ, "userID", validUUID, validUUID).Return(nil)
+		m := NewManager(db)
+
+		err := m.Redeliver(ctx, validUUID, validUUID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -495,6 +850,43 @@ func TestUpdate(t *testing.T) {
 					Template:  "{{ .",
 				},
 			},
+			{
+				"invalid template",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					Template:   "{{ .Repository.name }}",
+					EventKinds: []hub.EventKind{hub.PackageDeprecated},
+				},
+			},
+			{
+				"invalid channel",
+				&hub.Webhook{
+					WebhookID: validUUID,
+					Name:      "webhook",
+					URL:       "http://webhook1.url",
+					Channel:   hub.WebhookChannel(100),
+				},
+			},
+			{
+				"invalid update level",
+				&hub.Webhook{
+					WebhookID: validUUID,
+					Name:      "webhook",
+					URL:       "http://webhook1.url",
+					Filter:    &hub.SubscriptionFilter{UpdateLevel: "invalid"},
+				},
+			},
+			{
+				"secret required to sign payloads",
+				&hub.Webhook{
+					WebhookID:    validUUID,
+					Name:         "webhook",
+					URL:          "http://webhook1.url",
+					SignPayloads: true,
+				},
+			},
 			{
 				"no event kinds provided",
 				&hub.Webhook{
@@ -504,7 +896,7 @@ func TestUpdate(t *testing.T) {
 				},
 			},
 			{
-				"no packages provided",
+				"no packages, repositories or organizations provided",
 				&hub.Webhook{
 					WebhookID:  validUUID,
 					Name:       "webhook",
@@ -524,6 +916,30 @@ func TestUpdate(t *testing.T) {
 					},
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Repositories: []*hub.Repository{
+						{RepositoryID: "invalid"},
+					},
+				},
+			},
+			{
+				"invalid organization name",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Organizations: []*hub.Organization{
+						{Name: ""},
+					},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -24,6 +24,13 @@ func (m *ManagerMock) Delete(ctx context.Context, webhookID string) error {
 	return args.Error(0)
 }
 
+// GetDeliveriesJSON implements the WebhookManager interface.
+func (m *ManagerMock) GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error) {
+	args := m.Called(ctx, webhookID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetOwnedByOrgJSON implements the WebhookManager interface.
 func (m *ManagerMock) GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
 	args := m.Called(ctx, orgName)
@@ -52,6 +59,12 @@ func (m *ManagerMock) GetJSON(ctx context.Context, webhookID string) ([]byte, er
 	return data, args.Error(1)
 }
 
+// Redeliver implements the WebhookManager interface.
+func (m *ManagerMock) Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error {
+	args := m.Called(ctx, webhookID, webhookDeliveryID)
+	return args.Error(0)
+}
+
 // Update implements the WebhookManager interface.
 func (m *ManagerMock) Update(ctx context.Context, wh *hub.Webhook) error {
 	args := m.Called(ctx, wh)
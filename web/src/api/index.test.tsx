@@ -20,6 +20,7 @@ import {
   Stats,
   Subscription,
   TestWebhook,
+  UpdateLevel,
   User,
   UserFullName,
   UserLogin,
@@ -1205,6 +1206,8 @@ describe('index API', () => {
           JSON.stringify({
             ...formattedWebhook,
             packages: formattedPackages,
+            repositories: [],
+            organizations: [],
           })
         );
         expect(response).toBe('');
@@ -1236,6 +1239,8 @@ describe('index API', () => {
           JSON.stringify({
             ...formattedWebhook,
             packages: formattedPackages,
+            repositories: [],
+            organizations: [],
           })
         );
         expect(response).toBe('');
@@ -1303,6 +1308,8 @@ describe('index API', () => {
           JSON.stringify({
             ...formattedWebhook,
             packages: formattedPackages,
+            repositories: [],
+            organizations: [],
           })
         );
         expect(response).toBe('');
@@ -1334,10 +1341,33 @@ describe('index API', () => {
           JSON.stringify({
             ...formattedWebhook,
             packages: formattedPackages,
+            repositories: [],
+            organizations: [],
           })
         );
         expect(response).toBe('');
       });
+      it('formats the filter provided', async () => {
+        const webhook: Webhook = {
+          ...(getData('27') as Webhook),
+          filter: { versionConstraint: '>=2.0.0', updateLevel: UpdateLevel.Minor, excludePrereleases: true },
+        };
+        fetchMock.mockResponse('', {
+          headers: {
+            'content-type': 'text/plain; charset=utf-8',
+          },
+          status: 204,
+        });
+
+        await methods.API.updateWebhook(webhook);
+
+        expect(fetchMock.mock.calls.length).toEqual(1);
+        expect(JSON.parse(fetchMock.mock.calls[0][1]!.body as string).filter).toEqual({
+          version_constraint: '>=2.0.0',
+          update_level: 'minor',
+          exclude_prereleases: true,
+        });
+      });
     });
 
     describe('triggerWebhookTest', () => {
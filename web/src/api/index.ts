@@ -9,6 +9,7 @@ import {
   APIKeyCode,
   AuthorizerAction,
   ChangeLog,
+  ChartTemplate,
   CheckAvailabilityProps,
   Error,
   ErrorKind,
@@ -18,6 +19,8 @@ import {
   Organization,
   OrganizationPolicy,
   Package,
+  PackageDependency,
+  PackageDependent,
   PackageStars,
   Profile,
   RegoPlaygroundPolicy,
@@ -34,6 +37,7 @@ import {
   UserFullName,
   UserLogin,
   Webhook,
+  WebhookPreview,
 } from '../types';
 import { TS_QUERY } from '../utils/data';
 import getHubBaseURL from '../utils/getHubBaseURL';
@@ -147,6 +151,18 @@ export const getUrlContext = (fromOrgName?: string): string => {
   return context;
 };
 
+const formatWebhookScopes = (webhook: Webhook) => ({
+  packages: webhook.packages.map((packageItem: Package) => ({
+    package_id: packageItem.packageId,
+  })),
+  repositories: (webhook.repositories || []).map((repo: Repository) => ({
+    repository_id: repo.repositoryId,
+  })),
+  organizations: (webhook.organizations || []).map((org: Organization) => ({
+    name: org.name,
+  })),
+});
+
 const API_BASE_URL = `${getHubBaseURL()}/api/v1`;
 
 export const API = {
@@ -209,6 +225,9 @@ export const API = {
     if (!isUndefined(query.official) && query.official) {
       q.set('official', 'true');
     }
+    if (!isUndefined(query.verifiedSignature) && query.verifiedSignature) {
+      q.set('verified_signature', 'true');
+    }
     return apiFetch(`${API_BASE_URL}/packages/search?${q.toString()}`);
   },
 
@@ -488,10 +507,16 @@ export const API = {
   },
 
   addWebhook: (webhook: Webhook, fromOrgName?: string): Promise<null | string> => {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
-    const formattedPackages = webhook.packages.map((packageItem: Package) => ({
-      package_id: packageItem.packageId,
-    }));
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      secondarySecret: 'secondary_secret',
+      signPayloads: 'sign_payloads',
+      'filter.versionConstraint': 'filter.version_constraint',
+      'filter.updateLevel': 'filter.update_level',
+      'filter.excludePrereleases': 'filter.exclude_prereleases',
+      'filter.securityUpdatesOnly': 'filter.security_updates_only',
+    });
     return apiFetch(`${API_BASE_URL}/webhooks${getUrlContext(fromOrgName)}`, {
       method: 'POST',
       headers: {
@@ -499,7 +524,7 @@ export const API = {
       },
       body: JSON.stringify({
         ...formattedWebhook,
-        packages: formattedPackages,
+        ...formatWebhookScopes(webhook),
       }),
     });
   },
@@ -511,21 +536,32 @@ export const API = {
   },
 
   updateWebhook: (webhook: Webhook, fromOrgName?: string): Promise<null | string> => {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
-    const formattedPackages = webhook.packages.map((packageItem: Package) => ({
-      package_id: packageItem.packageId,
-    }));
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      secondarySecret: 'secondary_secret',
+      signPayloads: 'sign_payloads',
+      'filter.versionConstraint': 'filter.version_constraint',
+      'filter.updateLevel': 'filter.update_level',
+      'filter.excludePrereleases': 'filter.exclude_prereleases',
+      'filter.securityUpdatesOnly': 'filter.security_updates_only',
+    });
     return apiFetch(`${API_BASE_URL}/webhooks${getUrlContext(fromOrgName)}/${webhook.webhookId}`, {
       method: 'PUT',
       headers: {
         'Content-Type': 'application/json',
       },
-      body: JSON.stringify({ ...formattedWebhook, packages: formattedPackages }),
+      body: JSON.stringify({ ...formattedWebhook, ...formatWebhookScopes(webhook) }),
     });
   },
 
   triggerWebhookTest: (webhook: TestWebhook): Promise<string | null> => {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      secondarySecret: 'secondary_secret',
+      signPayloads: 'sign_payloads',
+    });
 
     return apiFetch(`${API_BASE_URL}/webhooks/test`, {
       method: 'POST',
@@ -536,6 +572,23 @@ export const API = {
     });
   },
 
+  previewWebhook: (webhook: TestWebhook, packageId: string, eventKind: EventKind): Promise<WebhookPreview> => {
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      secondarySecret: 'secondary_secret',
+      signPayloads: 'sign_payloads',
+    });
+
+    return apiFetch(`${API_BASE_URL}/webhooks/preview`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ webhook: formattedWebhook, package_id: packageId, event_kind: eventKind }),
+    });
+  },
+
   getAPIKeys: (): Promise<APIKey[]> => {
     return apiFetch(`${API_BASE_URL}/api-keys`);
   },
@@ -631,10 +684,28 @@ export const API = {
     return apiFetch(`${API_BASE_URL}/packages/${packageId}/${version}/valuesSchema`, undefined, true);
   },
 
+  renderChartTemplates: (packageId: string, version: string, values: string): Promise<ChartTemplate[]> => {
+    return apiFetch(`${API_BASE_URL}/packages/${packageId}/${version}/template`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/x-yaml',
+      },
+      body: values,
+    });
+  },
+
   getChangelog: (packageId: string): Promise<ChangeLog[]> => {
     return apiFetch(`${API_BASE_URL}/packages/${packageId}/changelog`);
   },
 
+  getPackageDependencies: (packageId: string, version: string): Promise<PackageDependency[]> => {
+    return apiFetch(`${API_BASE_URL}/packages/${packageId}/${version}/dependencies`);
+  },
+
+  getPackageDependents: (packageId: string): Promise<PackageDependent[]> => {
+    return apiFetch(`${API_BASE_URL}/packages/${packageId}/dependents`);
+  },
+
   // External API call
   triggerTestInRegoPlayground: (data: RegoPlaygroundPolicy): Promise<RegoPlaygroundResult> => {
     return apiFetch('https://play.openpolicyagent.org/v1/share', {
@@ -5,7 +5,7 @@ import { mocked } from 'ts-jest/utils';
 
 import { API } from '../../../../api';
 import { AppCtx } from '../../../../context/AppCtx';
-import { ErrorKind, SearchResults, Webhook } from '../../../../types';
+import { ErrorKind, SearchResults, UpdateLevel, Webhook, WebhookChannel } from '../../../../types';
 import WebhookForm from './Form';
 jest.mock('../../../../api');
 
@@ -127,7 +127,7 @@ describe('WebhookForm', () => {
       expect(getByText('Packages')).toBeInTheDocument();
       expect(
         getByText(
-          "When the events selected happen for any of the packages you've chosen, a notification will be triggered and the configured url will be called. At least one package must be selected."
+          "When the events selected happen for any of the packages you've chosen, or for any package in the repositories and organizations selected below, a notification will be triggered and the configured url will be called. At least one package, repository or organization must be selected."
         )
       ).toBeInTheDocument();
       expect(getByText('Package')).toBeInTheDocument();
@@ -219,7 +219,7 @@ describe('WebhookForm', () => {
       expect(getByText('Packages')).toBeInTheDocument();
       expect(
         getByText(
-          "When the events selected happen for any of the packages you've chosen, a notification will be triggered and the configured url will be called. At least one package must be selected."
+          "When the events selected happen for any of the packages you've chosen, or for any package in the repositories and organizations selected below, a notification will be triggered and the configured url will be called. At least one package, repository or organization must be selected."
         )
       ).toBeInTheDocument();
       expect(queryByText('Package')).toBeNull();
@@ -325,7 +325,7 @@ describe('WebhookForm', () => {
       fireEvent.click(btn);
 
       expect(getAllByText('This field is required')).toHaveLength(4);
-      expect(getByText('At least one package has to be selected')).toBeInTheDocument();
+      expect(getByText('At least one package, repository or organization has to be selected')).toBeInTheDocument();
     });
 
     it('calls updateWebhook', async () => {
@@ -351,7 +351,11 @@ describe('WebhookForm', () => {
         expect(API.updateWebhook).toHaveBeenCalledWith(
           {
             ...mockWebhook,
+            channel: WebhookChannel.Generic,
+            signPayloads: false,
             name: 'test',
+            repositories: [],
+            organizations: [],
           },
           undefined
         );
@@ -384,7 +388,11 @@ describe('WebhookForm', () => {
         expect(API.updateWebhook).toHaveBeenCalledWith(
           {
             ...mockWebhook,
+            channel: WebhookChannel.Generic,
+            signPayloads: false,
             name: 'test',
+            repositories: [],
+            organizations: [],
           },
           'test'
         );
@@ -436,8 +444,12 @@ describe('WebhookForm', () => {
             active: true,
             description: '',
             secret: '',
+            channel: WebhookChannel.Generic,
             eventKinds: [0],
+            signPayloads: false,
             packages: [mockSearch.data.packages![0]],
+            repositories: [],
+            organizations: [],
           },
           undefined
         );
@@ -447,6 +459,63 @@ describe('WebhookForm', () => {
       expect(mockOnClose).toHaveBeenCalledTimes(1);
     });
 
+    it('calls addWebhook with payloads signing enabled', async () => {
+      mocked(API).addWebhook.mockResolvedValue(null);
+      const mockSearch = getMockSearch('1');
+      mocked(API).searchPackages.mockResolvedValue(mockSearch);
+
+      const { getByTestId, getAllByTestId, queryByTestId } = render(
+        <AppCtx.Provider value={{ ctx: mockUserCtx, dispatch: jest.fn() }}>
+          <Router>
+            <WebhookForm {...defaultProps} />
+          </Router>
+        </AppCtx.Provider>
+      );
+
+      expect(queryByTestId('secondarySecretInput')).toBeNull();
+
+      fireEvent.change(getByTestId('nameInput'), { target: { value: 'test' } });
+      fireEvent.change(getByTestId('urlInput'), { target: { value: 'http://url.com' } });
+      fireEvent.change(getByTestId('secretInput'), { target: { value: 'secret1' } });
+      fireEvent.click(getByTestId('signPayloadsCheckbox'));
+      fireEvent.change(getByTestId('secondarySecretInput'), { target: { value: 'secret2' } });
+
+      const input = getByTestId('searchPackagesInput');
+      fireEvent.change(input, { target: { value: 'testing' } });
+      fireEvent.keyDown(input, { key: 'Enter', code: 13, charCode: 13 });
+
+      await waitFor(() => {
+        expect(API.searchPackages).toHaveBeenCalledTimes(1);
+      });
+
+      const packages = getAllByTestId('packageItem');
+      fireEvent.click(packages[0]);
+
+      const btn = getByTestId('sendWebhookBtn');
+      fireEvent.click(btn);
+
+      await waitFor(() => {
+        expect(API.addWebhook).toHaveBeenCalledTimes(1);
+        expect(API.addWebhook).toHaveBeenCalledWith(
+          {
+            name: 'test',
+            url: 'http://url.com',
+            active: true,
+            description: '',
+            secret: 'secret1',
+            secondarySecret: 'secret2',
+            channel: WebhookChannel.Generic,
+            eventKinds: [0],
+            signPayloads: true,
+            packages: [mockSearch.data.packages![0]],
+            repositories: [],
+            organizations: [],
+          },
+          undefined
+        );
+      });
+    });
+
     describe('when fails', () => {
       it('UnauthorizedError', async () => {
         mocked(API).updateWebhook.mockRejectedValue({
@@ -473,7 +542,11 @@ describe('WebhookForm', () => {
           expect(API.updateWebhook).toHaveBeenCalledWith(
             {
               ...mockWebhook,
+              channel: WebhookChannel.Generic,
+              signPayloads: false,
               name: 'test',
+              repositories: [],
+              organizations: [],
             },
             undefined
           );
@@ -509,7 +582,11 @@ describe('WebhookForm', () => {
           expect(API.updateWebhook).toHaveBeenCalledWith(
             {
               ...mockWebhook,
+              channel: WebhookChannel.Generic,
+              signPayloads: false,
               name: 'test',
+              repositories: [],
+              organizations: [],
             },
             undefined
           );
@@ -545,7 +622,11 @@ describe('WebhookForm', () => {
           expect(API.updateWebhook).toHaveBeenCalledWith(
             {
               ...mockWebhook,
+              channel: WebhookChannel.Generic,
+              signPayloads: false,
               name: 'test',
+              repositories: [],
+              organizations: [],
             },
             undefined
           );
@@ -584,7 +665,140 @@ describe('WebhookForm', () => {
         expect(API.updateWebhook).toHaveBeenCalledWith(
           {
             ...mockWebhook,
+            channel: WebhookChannel.Generic,
+            signPayloads: false,
             packages: newPackagesList,
+            repositories: [],
+            organizations: [],
+          },
+          undefined
+        );
+      });
+    });
+
+    it('keeps the webhook channel and allows changing it', async () => {
+      mocked(API).updateWebhook.mockResolvedValue(null);
+      const mockWebhook = getMockWebhook('3');
+
+      const { getByTestId } = render(
+        <AppCtx.Provider value={{ ctx: mockUserCtx, dispatch: jest.fn() }}>
+          <Router>
+            <WebhookForm
+              {...defaultProps}
+              webhook={{ ...mockWebhook, contentType: null, template: null, channel: WebhookChannel.Slack }}
+            />
+          </Router>
+        </AppCtx.Provider>
+      );
+
+      const select = getByTestId('channelSelect');
+      expect(select).toHaveValue(WebhookChannel.Slack.toString());
+      fireEvent.change(select, { target: { value: WebhookChannel.Discord.toString() } });
+
+      const btn = getByTestId('sendWebhookBtn');
+      fireEvent.click(btn);
+
+      await waitFor(() => {
+        expect(API.updateWebhook).toHaveBeenCalledTimes(1);
+        expect(API.updateWebhook).toHaveBeenCalledWith(
+          {
+            ...mockWebhook,
+            channel: WebhookChannel.Discord,
+            signPayloads: false,
+            repositories: [],
+            organizations: [],
+          },
+          undefined
+        );
+      });
+    });
+
+    it('keeps the webhook filter and allows changing it', async () => {
+      mocked(API).updateWebhook.mockResolvedValue(null);
+      const mockWebhook = getMockWebhook('3');
+
+      const { getByTestId } = render(
+        <AppCtx.Provider value={{ ctx: mockUserCtx, dispatch: jest.fn() }}>
+          <Router>
+            <WebhookForm
+              {...defaultProps}
+              webhook={{
+                ...mockWebhook,
+                contentType: null,
+                template: null,
+                filter: { versionConstraint: '>=2.0.0', excludePrereleases: true },
+              }}
+            />
+          </Router>
+        </AppCtx.Provider>
+      );
+
+      expect(getByTestId('versionConstraintInput')).toHaveValue('>=2.0.0');
+      expect(getByTestId('excludePrereleasesCheckbox')).toBeChecked();
+      expect(getByTestId('securityUpdatesOnlyCheckbox')).not.toBeChecked();
+
+      fireEvent.change(getByTestId('updateLevelSelect'), { target: { value: UpdateLevel.Minor } });
+      fireEvent.click(getByTestId('excludePrereleasesCheckbox'));
+
+      const btn = getByTestId('sendWebhookBtn');
+      fireEvent.click(btn);
+
+      await waitFor(() => {
+        expect(API.updateWebhook).toHaveBeenCalledTimes(1);
+        expect(API.updateWebhook).toHaveBeenCalledWith(
+          {
+            ...mockWebhook,
+            channel: WebhookChannel.Generic,
+            filter: { versionConstraint: '>=2.0.0', updateLevel: UpdateLevel.Minor },
+            signPayloads: false,
+            repositories: [],
+            organizations: [],
+          },
+          undefined
+        );
+      });
+    });
+
+    it('calls updateWebhook for webhooks scoped only to repositories and organizations', async () => {
+      mocked(API).updateWebhook.mockResolvedValue(null);
+      const mockWebhook = getMockWebhook('3');
+      const repository = mockWebhook.packages[0].repository;
+      const organization = { name: 'helm', displayName: 'Helm' };
+
+      const { getByTestId, getAllByTestId } = render(
+        <AppCtx.Provider value={{ ctx: mockUserCtx, dispatch: jest.fn() }}>
+          <Router>
+            <WebhookForm
+              {...defaultProps}
+              webhook={{
+                ...mockWebhook,
+                contentType: null,
+                template: null,
+                packages: [],
+                repositories: [repository],
+                organizations: [organization],
+              }}
+            />
+          </Router>
+        </AppCtx.Provider>
+      );
+
+      expect(getAllByTestId('repositoryTableCell')).toHaveLength(1);
+      expect(getAllByTestId('organizationTableCell')).toHaveLength(1);
+
+      const btn = getByTestId('sendWebhookBtn');
+      fireEvent.click(btn);
+
+      await waitFor(() => {
+        expect(API.updateWebhook).toHaveBeenCalledTimes(1);
+        expect(API.updateWebhook).toHaveBeenCalledWith(
+          {
+            ...mockWebhook,
+            channel: WebhookChannel.Generic,
+            signPayloads: false,
+            packages: [],
+            repositories: [repository],
+            organizations: [organization],
           },
           undefined
         );
@@ -615,6 +829,7 @@ describe('WebhookForm', () => {
         expect(API.triggerWebhookTest).toHaveBeenCalledWith({
           url: mockWebhook.url,
           eventKinds: mockWebhook.eventKinds,
+          channel: WebhookChannel.Generic,
         });
       });
 
@@ -647,6 +862,7 @@ describe('WebhookForm', () => {
         expect(API.triggerWebhookTest).toHaveBeenCalledWith({
           url: 'http://url.com',
           eventKinds: [0],
+          channel: WebhookChannel.Generic,
         });
       });
 
@@ -697,6 +913,7 @@ describe('WebhookForm', () => {
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            channel: WebhookChannel.Generic,
           });
         });
 
@@ -727,6 +944,7 @@ describe('WebhookForm', () => {
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            channel: WebhookChannel.Generic,
           });
         });
 
@@ -759,6 +977,7 @@ describe('WebhookForm', () => {
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            channel: WebhookChannel.Generic,
           });
         });
 
@@ -9,13 +9,28 @@ import { RiTestTubeFill } from 'react-icons/ri';
 
 import { API } from '../../../../api';
 import { AppCtx } from '../../../../context/AppCtx';
-import { ErrorKind, EventKind, Package, PayloadKind, RefInputField, TestWebhook, Webhook } from '../../../../types';
+import {
+  ErrorKind,
+  EventKind,
+  Organization,
+  Package,
+  PayloadKind,
+  RefInputField,
+  Repository,
+  SubscriptionFilter,
+  TestWebhook,
+  UpdateLevel,
+  Webhook,
+  WebhookChannel,
+} from '../../../../types';
 import compoundErrorMessage from '../../../../utils/compoundErrorMessage';
 import {
   PACKAGE_SUBSCRIPTIONS_LIST,
   PAYLOAD_KINDS_LIST,
   PayloadKindsItem,
   SubscriptionItem,
+  WEBHOOK_CHANNELS_LIST,
+  WebhookChannelItem,
 } from '../../../../utils/data';
 import Alert from '../../../common/Alert';
 import AutoresizeTextarea from '../../../common/AutoresizeTextarea';
@@ -25,6 +40,7 @@ import Image from '../../../common/Image';
 import InputField from '../../../common/InputField';
 import RepositoryIcon from '../../../common/RepositoryIcon';
 import SearchPackages from '../../../common/SearchPackages';
+import SearchTypeaheadRepository from '../../../common/SearchTypeaheadRepository';
 import styles from './Form.module.css';
 
 interface Props {
@@ -75,10 +91,39 @@ const WebhookForm = (props: Props) => {
   const [selectedPackages, setSelectedPackages] = useState<Package[]>(
     !isUndefined(props.webhook) ? props.webhook.packages : []
   );
+  const [selectedRepositories, setSelectedRepositories] = useState<Repository[]>(
+    !isUndefined(props.webhook) && props.webhook.repositories ? props.webhook.repositories : []
+  );
+  const [selectedOrganizations, setSelectedOrganizations] = useState<Organization[]>(
+    !isUndefined(props.webhook) && props.webhook.organizations ? props.webhook.organizations : []
+  );
+  const [repositories, setRepositories] = useState<Repository[] | undefined>(undefined);
+  const [organizations, setOrganizations] = useState<Organization[] | undefined>(undefined);
+  const [isLoadingScopes, setIsLoadingScopes] = useState<boolean>(false);
   const [eventKinds, setEventKinds] = useState<EventKind[]>(
     !isUndefined(props.webhook) ? props.webhook.eventKinds : [EventKind.NewPackageRelease]
   );
+  const [channel, setChannel] = useState<WebhookChannel>(
+    !isUndefined(props.webhook) && props.webhook.channel ? props.webhook.channel : WebhookChannel.Generic
+  );
+  const [versionConstraint, setVersionConstraint] = useState<string>(
+    !isUndefined(props.webhook) && props.webhook.filter && props.webhook.filter.versionConstraint
+      ? props.webhook.filter.versionConstraint
+      : ''
+  );
+  const [updateLevel, setUpdateLevel] = useState<UpdateLevel | ''>(
+    !isUndefined(props.webhook) && props.webhook.filter && props.webhook.filter.updateLevel
+      ? props.webhook.filter.updateLevel
+      : ''
+  );
+  const [excludePrereleases, setExcludePrereleases] = useState<boolean>(
+    !isUndefined(props.webhook) && !!props.webhook.filter && !!props.webhook.filter.excludePrereleases
+  );
+  const [securityUpdatesOnly, setSecurityUpdatesOnly] = useState<boolean>(
+    !isUndefined(props.webhook) && !!props.webhook.filter && !!props.webhook.filter.securityUpdatesOnly
+  );
   const [isActive, setIsActive] = useState<boolean>(!isUndefined(props.webhook) ? props.webhook.active : true);
+  const [signPayloads, setSignPayloads] = useState<boolean>(!isUndefined(props.webhook) && !!props.webhook.signPayloads);
   const [contentType, setContentType] = useState<string>(
     !isUndefined(props.webhook) && props.webhook.contentType ? props.webhook.contentType : ''
   );
@@ -138,6 +183,23 @@ const WebhookForm = (props: Props) => {
     }
   }
 
+  async function getScopes() {
+    try {
+      setIsLoadingScopes(true);
+      const [repos, orgs] = await Promise.all([API.getAllRepositories(), API.getUserOrganizations()]);
+      setRepositories(repos || []);
+      setOrganizations(orgs || []);
+      setIsLoadingScopes(false);
+    } catch (err) {
+      setIsLoadingScopes(false);
+      setRepositories([]);
+      setOrganizations([]);
+      if (err.kind === ErrorKind.Unauthorized) {
+        props.onAuthError();
+      }
+    }
+  }
+
   async function triggerWebhookTest(webhook: TestWebhook) {
     try {
       setIsSendingTest(true);
@@ -159,7 +221,18 @@ const WebhookForm = (props: Props) => {
   const triggerTest = () => {
     if (!isNull(currentTestWebhook)) {
       cleanApiError();
-      triggerWebhookTest(currentTestWebhook);
+      let webhook: TestWebhook = { ...currentTestWebhook, channel: channel };
+      // Test requests are signed the same way notifications are
+      if (signPayloads) {
+        const formData = new FormData(form.current!);
+        webhook = {
+          ...webhook,
+          secret: formData.get('secret') as string,
+          secondarySecret: formData.get('secondarySecret') as string,
+          signPayloads: true,
+        };
+      }
+      triggerWebhookTest(webhook);
     }
   };
 
@@ -176,7 +249,7 @@ const WebhookForm = (props: Props) => {
   const validateForm = (form: HTMLFormElement): FormValidation => {
     let webhook: Webhook | null = null;
     const formData = new FormData(form);
-    const isValid = form.checkValidity() && selectedPackages.length > 0;
+    const isValid = form.checkValidity() && hasScope();
 
     if (isValid) {
       webhook = {
@@ -184,11 +257,30 @@ const WebhookForm = (props: Props) => {
         url: formData.get('url') as string,
         secret: formData.get('secret') as string,
         description: formData.get('description') as string,
+        channel: channel,
         eventKinds: eventKinds,
         active: isActive,
+        signPayloads: signPayloads,
         packages: selectedPackages,
+        repositories: selectedRepositories,
+        organizations: selectedOrganizations,
       };
 
+      const filter = getFilter();
+      if (!isUndefined(filter)) {
+        webhook = {
+          ...webhook,
+          filter: filter,
+        };
+      }
+
+      if (signPayloads) {
+        webhook = {
+          ...webhook,
+          secondarySecret: formData.get('secondarySecret') as string,
+        };
+      }
+
       if (payloadKind === PayloadKind.custom) {
         webhook = {
           ...webhook,
@@ -223,6 +315,58 @@ const WebhookForm = (props: Props) => {
     return selectedPackages.map((item: Package) => item.packageId);
   };
 
+  const addRepository = (repo: Repository) => {
+    setSelectedRepositories([...selectedRepositories, repo]);
+  };
+
+  const deleteRepository = (repositoryId: string) => {
+    setSelectedRepositories(selectedRepositories.filter((item: Repository) => item.repositoryId !== repositoryId));
+  };
+
+  const getRepositoriesIds = (): string[] => {
+    return selectedRepositories.map((item: Repository) => item.repositoryId!);
+  };
+
+  const addOrganization = (e: React.ChangeEvent<HTMLSelectElement>) => {
+    const org = (organizations || []).find((item: Organization) => item.name === e.target.value);
+    if (!isUndefined(org)) {
+      setSelectedOrganizations([...selectedOrganizations, org]);
+    }
+  };
+
+  const deleteOrganization = (name: string) => {
+    setSelectedOrganizations(selectedOrganizations.filter((item: Organization) => item.name !== name));
+  };
+
+  const getAvailableOrganizations = (): Organization[] => {
+    const selectedNames = selectedOrganizations.map((item: Organization) => item.name);
+    return (organizations || []).filter((item: Organization) => !selectedNames.includes(item.name));
+  };
+
+  // Only the filter conditions set are included, no filter means all the
+  // events are notified
+  const getFilter = (): SubscriptionFilter | undefined => {
+    const filter: SubscriptionFilter = {};
+    if (versionConstraint.trim() !== '') {
+      filter.versionConstraint = versionConstraint.trim();
+    }
+    if (updateLevel !== '') {
+      filter.updateLevel = updateLevel;
+    }
+    if (excludePrereleases) {
+      filter.excludePrereleases = true;
+    }
+    if (securityUpdatesOnly) {
+      filter.securityUpdatesOnly = true;
+    }
+    return Object.keys(filter).length > 0 ? filter : undefined;
+  };
+
+  // Webhooks must be scoped to some packages, repositories or organizations
+  const hasScope = (): boolean => {
+    return selectedPackages.length > 0 || selectedRepositories.length > 0 || selectedOrganizations.length > 0;
+  };
+
   const updateEventKindList = (eventKind: EventKind) => {
     let updatedEventKinds: EventKind[] = [...eventKinds];
     if (eventKinds.includes(eventKind)) {
@@ -271,6 +415,7 @@ const WebhookForm = (props: Props) => {
 
   useEffect(() => {
     checkTestAvailability();
+    getScopes();
   }, []); /* eslint-disable-line react-hooks/exhaustive-deps */
 
   return (
@@ -352,6 +497,37 @@ const WebhookForm = (props: Props) => {
             </div>
           </div>
 
+          <div className="mb-4">
+            <label className={`font-weight-bold ${styles.label}`} htmlFor="channel">
+              Channel
+            </label>
+            <div>
+              <small className="form-text text-muted mb-2 mt-0">
+                Notifications sent to Slack, Microsoft Teams or Discord are formatted for the selected platform, unless
+                a custom payload is used.
+              </small>
+            </div>
+            <div className="form-row">
+              <div className="col-md-8">
+                <select
+                  data-testid="channelSelect"
+                  id="channel"
+                  name="channel"
+                  className="custom-select"
+                  aria-label="channel-select"
+                  value={channel}
+                  onChange={(e: React.ChangeEvent<HTMLSelectElement>) => setChannel(parseInt(e.target.value))}
+                >
+                  {WEBHOOK_CHANNELS_LIST.map((item: WebhookChannelItem) => (
+                    <option key={`channel_${item.name}`} value={item.channel}>
+                      {item.title}
+                    </option>
+                  ))}
+                </select>
+              </div>
+            </div>
+          </div>
+
           <div>
             <label className={`font-weight-bold ${styles.label}`} htmlFor="secret">
               Secret
@@ -360,16 +536,82 @@ const WebhookForm = (props: Props) => {
               <small className="form-text text-muted mb-2 mt-0">
                 If you provide a secret, we'll send it to you in the{' '}
                 <span className="font-weight-bold">X-ArtifactHub-Secret</span> header on each request. This will allow
-                you to validate that the request comes from ArtifactHub.
+                you to validate that the request comes from ArtifactHub. When payloads signing is enabled, the secret is
+                not sent. It is used to sign the payload instead, and the signature is sent in the{' '}
+                <span className="font-weight-bold">X-ArtifactHub-Signature</span> header. For more information about
+                how to validate the requests please see the{' '}
+                <ExternalLink
+                  href="https://github.com/artifacthub/hub/blob/master/docs/webhooks.md"
+                  className="font-weight-bold text-dark"
+                >
+                  webhooks documentation
+                </ExternalLink>
+                .
               </small>
             </div>
             <div className="form-row">
               <div className="col-md-8">
-                <InputField type="text" name="secret" value={!isUndefined(props.webhook) ? props.webhook.secret : ''} />
+                <InputField
+                  type="text"
+                  name="secret"
+                  value={!isUndefined(props.webhook) ? props.webhook.secret : ''}
+                  invalidText={{
+                    default: 'A secret is required to sign the payloads',
+                  }}
+                  required={signPayloads}
+                />
               </div>
             </div>
           </div>
 
+          <div className="mb-4">
+            <div className="custom-control custom-switch pl-0">
+              <input
+                data-testid="signPayloadsCheckbox"
+                id="signPayloads"
+                type="checkbox"
+                className={`custom-control-input ${styles.checkbox}`}
+                value="true"
+                onChange={() => setSignPayloads(!signPayloads)}
+                checked={signPayloads}
+              />
+              <label
+                htmlFor="signPayloads"
+                className={`custom-control-label font-weight-bold ${styles.label} ${styles.customControlRightLabel}`}
+              >
+                Sign payloads
+              </label>
+            </div>
+
+            <small className="form-text text-muted mt-2">
+              When enabled, requests include an HMAC-SHA256 signature of the payload built using the secret, instead
+              of the secret itself.
+            </small>
+          </div>
+
+          {signPayloads && (
+            <div>
+              <label className={`font-weight-bold ${styles.label}`} htmlFor="secondarySecret">
+                Secondary secret
+              </label>
+              <div>
+                <small className="form-text text-muted mb-2 mt-0">
+                  While a secondary secret is set, payloads are signed using both secrets. This allows you to rotate the
+                  secret without missing any notification.
+                </small>
+              </div>
+              <div className="form-row">
+                <div className="col-md-8">
+                  <InputField
+                    type="text"
+                    name="secondarySecret"
+                    value={!isUndefined(props.webhook) ? props.webhook.secondarySecret : ''}
+                  />
+                </div>
+              </div>
+            </div>
+          )}
+
           <div className="mb-3">
             <div className="custom-control custom-switch pl-0">
               <input
@@ -419,14 +661,87 @@ const WebhookForm = (props: Props) => {
             })}
           </div>
 
+          <div className="mb-4">
+            <label className={`font-weight-bold ${styles.label}`} htmlFor="versionConstraint">
+              Filter
+            </label>
+            <div>
+              <small className="form-text text-muted mb-3 mt-0">
+                New releases are only notified when they match all the conditions set below. The version constraint
+                must be a valid semver constraint (i.e. <span className="font-weight-bold">&gt;=2.0.0 &lt;3</span>).
+              </small>
+            </div>
+            <div className="form-row">
+              <div className="col-md-4">
+                <InputField
+                  type="text"
+                  label="Version constraint"
+                  name="versionConstraint"
+                  value={versionConstraint}
+                  onChange={(e: React.ChangeEvent<HTMLInputElement>) => setVersionConstraint(e.target.value)}
+                />
+              </div>
+              <div className="col-md-4">
+                <div className="form-group mb-4">
+                  <label className={`font-weight-bold ${styles.label}`} htmlFor="updateLevel">
+                    Minimum update level
+                  </label>
+                  <select
+                    data-testid="updateLevelSelect"
+                    id="updateLevel"
+                    name="updateLevel"
+                    className="custom-select"
+                    aria-label="update-level-select"
+                    value={updateLevel}
+                    onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+                      setUpdateLevel(e.target.value as UpdateLevel | '')
+                    }
+                  >
+                    <option value="">Any</option>
+                    <option value={UpdateLevel.Patch}>Patch</option>
+                    <option value={UpdateLevel.Minor}>Minor</option>
+                    <option value={UpdateLevel.Major}>Major</option>
+                  </select>
+                </div>
+              </div>
+            </div>
+            <div className="custom-control custom-checkbox mb-2">
+              <input
+                data-testid="excludePrereleasesCheckbox"
+                id="excludePrereleases"
+                type="checkbox"
+                className="custom-control-input"
+                onChange={() => setExcludePrereleases(!excludePrereleases)}
+                checked={excludePrereleases}
+              />
+              <label className="custom-control-label" htmlFor="excludePrereleases">
+                Exclude prereleases
+              </label>
+            </div>
+            <div className="custom-control custom-checkbox mb-2">
+              <input
+                data-testid="securityUpdatesOnlyCheckbox"
+                id="securityUpdatesOnly"
+                type="checkbox"
+                className="custom-control-input"
+                onChange={() => setSecurityUpdatesOnly(!securityUpdatesOnly)}
+                checked={securityUpdatesOnly}
+              />
+              <label className="custom-control-label" htmlFor="securityUpdatesOnly">
+                Only releases containing security updates
+              </label>
+            </div>
+          </div>
+
           <div className="mb-4">
             <label className={`font-weight-bold ${styles.label}`} htmlFor="packages">
-              Packages<small className="ml-1 font-italic">(Required)</small>
+              Packages
             </label>
             <div>
               <small className="form-text text-muted mb-4 mt-0">
-                When the events selected happen for any of the packages you've chosen, a notification will be triggered
-                and the configured url will be called. At least one package must be selected.
+                When the events selected happen for any of the packages you've chosen, or for any package in the
+                repositories and organizations selected below, a notification will be triggered and the configured url
+                will be called. At least one package, repository or organization must be selected.
               </small>
             </div>
             <div className="mb-3 row">
@@ -435,8 +750,10 @@ const WebhookForm = (props: Props) => {
               </div>
             </div>
 
-            {isValidated && selectedPackages.length === 0 && (
-              <div className="invalid-feedback mt-0 d-block">At least one package has to be selected</div>
+            {isValidated && !hasScope() && (
+              <div className="invalid-feedback mt-0 d-block">
+                At least one package, repository or organization has to be selected
+              </div>
             )}
 
             {selectedPackages.length > 0 && (
@@ -513,6 +830,135 @@ const WebhookForm = (props: Props) => {
             )}
           </div>
 
+          <div className="mb-4">
+            <label className={`font-weight-bold ${styles.label}`} htmlFor="repositories">
+              Repositories
+            </label>
+            <div className="mb-3 row">
+              <div className="col-12 col-xxl-8">
+                <SearchTypeaheadRepository
+                  repositories={repositories || []}
+                  disabledList={getRepositoriesIds()}
+                  isLoading={isLoadingScopes}
+                  onSelect={addRepository}
+                  placeholder="There aren't any repositories available at the moment."
+                />
+              </div>
+            </div>
+
+            {selectedRepositories.length > 0 && (
+              <div className="row">
+                <div className="col-12 col-xxl-8">
+                  <table className={`table table-hover table-sm ${styles.table}`}>
+                    <thead>
+                      <tr className={`table-primary ${styles.tableTitle}`}>
+                        <th scope="col" className={`align-middle d-none d-sm-table-cell ${styles.fitCell}`}></th>
+                        <th scope="col" className="align-middle w-50">
+                          Repository
+                        </th>
+                        <th scope="col" className="align-middle w-50">
+                          Publisher
+                        </th>
+                        <th scope="col" className={`align-middle ${styles.fitCell}`}></th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {selectedRepositories.map((item: Repository) => (
+                        <tr key={`repo_${item.repositoryId}`} data-testid="repositoryTableCell">
+                          <td className="align-middle text-center d-none d-sm-table-cell">
+                            <RepositoryIcon kind={item.kind} className={`${styles.icon} mx-2`} />
+                          </td>
+                          <td className="align-middle text-dark">{item.displayName || item.name}</td>
+                          <td className="align-middle text-dark">
+                            {item.userAlias || item.organizationDisplayName || item.organizationName}
+                          </td>
+                          <td className="align-middle">
+                            <button
+                              data-testid="deleteRepositoryButton"
+                              className={`close text-danger mx-2 ${styles.closeBtn}`}
+                              type="button"
+                              onClick={(event: React.MouseEvent<HTMLButtonElement, MouseEvent>) => {
+                                event.preventDefault();
+                                event.stopPropagation();
+                                deleteRepository(item.repositoryId!);
+                              }}
+                            >
+                              <span aria-hidden="true">&times;</span>
+                            </button>
+                          </td>
+                        </tr>
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              </div>
+            )}
+          </div>
+
+          <div className="mb-4">
+            <label className={`font-weight-bold ${styles.label}`} htmlFor="organizations">
+              Organizations
+            </label>
+            <div className="mb-3 row">
+              <div className="col-12 col-md-8 col-xxl-6">
+                <select
+                  data-testid="selectOrganization"
+                  id="organizations"
+                  className="custom-select"
+                  aria-label="org-select"
+                  value=""
+                  onChange={addOrganization}
+                  disabled={isLoadingScopes}
+                >
+                  <option value="">Select organization</option>
+                  {getAvailableOrganizations().map((org: Organization) => (
+                    <option key={`opt_${org.name}`} value={org.name}>
+                      {org.displayName || org.name}
+                    </option>
+                  ))}
+                </select>
+              </div>
+            </div>
+
+            {selectedOrganizations.length > 0 && (
+              <div className="row">
+                <div className="col-12 col-xxl-8">
+                  <table className={`table table-hover table-sm ${styles.table}`}>
+                    <thead>
+                      <tr className={`table-primary ${styles.tableTitle}`}>
+                        <th scope="col" className="align-middle w-100">
+                          Organization
+                        </th>
+                        <th scope="col" className={`align-middle ${styles.fitCell}`}></th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {selectedOrganizations.map((item: Organization) => (
+                        <tr key={`org_${item.name}`} data-testid="organizationTableCell">
+                          <td className="align-middle text-dark">{item.displayName || item.name}</td>
+                          <td className="align-middle">
+                            <button
+                              data-testid="deleteOrganizationButton"
+                              className={`close text-danger mx-2 ${styles.closeBtn}`}
+                              type="button"
+                              onClick={(event: React.MouseEvent<HTMLButtonElement, MouseEvent>) => {
+                                event.preventDefault();
+                                event.stopPropagation();
+                                deleteOrganization(item.name);
+                              }}
+                            >
+                              <span aria-hidden="true">&times;</span>
+                            </button>
+                          </td>
+                        </tr>
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              </div>
+            )}
+          </div>
+
           <div className="h4 pb-2 mt-4 mt-md-5 mb-4 border-bottom">Payload</div>
 
           <div className="d-flex flex-row mb-3">
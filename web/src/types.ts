@@ -66,6 +66,8 @@ export interface Package {
   deprecated: boolean | null;
   isOperator?: boolean | null;
   signed: boolean | null;
+  provenanceVerification?: ProvenanceVerification | null;
+  cosignVerification?: CosignVerification | null;
   links?: PackageLink[];
   stars?: number | null;
   eventKinds?: EventKind[];
@@ -90,6 +92,14 @@ export interface Package {
 export interface ContainerImage {
   image: string;
   name?: string;
+  autoDetected?: boolean;
+  cosignVerification?: CosignVerification | null;
+}
+
+export interface CosignVerification {
+  verified: boolean;
+  digest?: string;
+  keyFingerprint?: string;
 }
 
 export interface Version {
@@ -117,10 +127,40 @@ export interface CustomResourcesDefinition {
   example?: CustomResourcesDefinitionExample;
 }
 
+export interface ProvenanceVerification {
+  verified: boolean;
+  keyFingerprint?: string;
+  signer?: string;
+}
+
 export interface Dependency {
   name: string;
   version: string;
   repository?: string;
+  alias?: string;
+}
+
+export interface PackageDependency {
+  name: string;
+  version?: string;
+  repositoryUrl?: string;
+  alias?: string;
+  package?: Package;
+}
+
+export interface ChartTemplate {
+  name: string;
+  manifests: string[];
+}
+
+export interface PackageDependent {
+  package: Package;
+  dependency: {
+    name: string;
+    version?: string;
+    repositoryUrl?: string;
+    alias?: string;
+  };
 }
 
 export interface PackageData {
@@ -151,6 +191,7 @@ export interface SearchFiltersURL {
   operators?: boolean | null;
   verifiedPublisher?: boolean | null;
   official?: boolean | null;
+  verifiedSignature?: boolean | null;
 }
 
 export interface SearchQuery {
@@ -163,6 +204,7 @@ export interface SearchQuery {
   operators?: boolean | null;
   verifiedPublisher?: boolean | null;
   official?: boolean | null;
+  verifiedSignature?: boolean | null;
   limit: number;
   offset: number;
   total?: number;
@@ -214,8 +256,15 @@ export interface UserFullName {
   profileImageId?: null | string;
 }
 
+export enum EmailDeliveryPreference {
+  Immediate = 0,
+  DailyDigest,
+  WeeklyDigest,
+}
+
 export interface Profile extends UserFullName {
   email: string;
+  emailDeliveryPreference?: EmailDeliveryPreference;
 }
 
 export interface User extends UserLogin {
@@ -318,10 +367,29 @@ export enum EventKind {
   NewPackageRelease = 0,
   SecurityAlert,
   RepositoryTrackingErrors,
+  RepositoryOwnershipClaim,
+  PackageVersionRemoved,
+  PackageDeprecated,
+  RepositoryTransferred,
+  RepositoryVerifiedPublisherChanged,
+}
+
+export enum UpdateLevel {
+  Major = 'major',
+  Minor = 'minor',
+  Patch = 'patch',
+}
+
+export interface SubscriptionFilter {
+  versionConstraint?: string;
+  updateLevel?: UpdateLevel;
+  excludePrereleases?: boolean;
+  securityUpdatesOnly?: boolean;
 }
 
 export interface Subscription {
   eventKind: EventKind;
+  filter?: SubscriptionFilter;
 }
 
 export interface TestWebhook {
@@ -329,15 +397,26 @@ export interface TestWebhook {
   contentType?: string | null;
   template?: string | null;
   eventKinds: EventKind[];
+  secret?: string;
+  secondarySecret?: string;
+  signPayloads?: boolean;
+  channel?: WebhookChannel;
+}
+
+export interface WebhookPreview {
+  contentType: string;
+  payload: string;
 }
 
 export interface Webhook extends TestWebhook {
   webhookId?: string;
   name: string;
   description?: string;
-  secret?: string;
+  filter?: SubscriptionFilter;
   active: boolean;
   packages: Package[];
+  repositories?: Repository[];
+  organizations?: Organization[];
   lastNotifications?: null | WebhookNotification[];
 }
 
@@ -349,6 +428,13 @@ export interface WebhookNotification {
   error?: null | string;
 }
 
+export enum WebhookChannel {
+  Generic = 0,
+  Slack,
+  MicrosoftTeams,
+  Discord,
+}
+
 export enum PayloadKind {
   default = 0,
   custom,
@@ -415,6 +501,7 @@ export enum AuthorizerAction {
   DeleteOrganizationMember = 'deleteOrganizationMember',
   DeleteOrganizationRepository = 'deleteOrganizationRepository',
   GetAuthorizationPolicy = 'getAuthorizationPolicy',
+  TrackOrganizationRepository = 'trackOrganizationRepository',
   TransferOrganizationRepository = 'transferOrganizationRepository',
   UpdateAuthorizationPolicy = 'updateAuthorizationPolicy',
   UpdateOrganization = 'updateOrganization',
@@ -18,6 +18,7 @@ import {
   SeverityRatingList,
   TsQuery,
   VulnerabilitySeverity,
+  WebhookChannel,
 } from '../types';
 
 export interface SubscriptionItem {
@@ -35,6 +36,12 @@ export interface PayloadKindsItem {
   title: string;
 }
 
+export interface WebhookChannelItem {
+  channel: WebhookChannel;
+  name: string;
+  title: string;
+}
+
 export const PACKAGE_SUBSCRIPTIONS_LIST: SubscriptionItem[] = [
   {
     kind: EventKind.NewPackageRelease,
@@ -146,6 +153,29 @@ export const PAYLOAD_KINDS_LIST: PayloadKindsItem[] = [
   },
 ];
 
+export const WEBHOOK_CHANNELS_LIST: WebhookChannelItem[] = [
+  {
+    channel: WebhookChannel.Generic,
+    name: 'generic',
+    title: 'Generic',
+  },
+  {
+    channel: WebhookChannel.Slack,
+    name: 'slack',
+    title: 'Slack',
+  },
+  {
+    channel: WebhookChannel.MicrosoftTeams,
+    name: 'microsoftTeams',
+    title: 'Microsoft Teams',
+  },
+  {
+    channel: WebhookChannel.Discord,
+    name: 'discord',
+    title: 'Discord',
+  },
+];
+
 export const CONTROL_PANEL_SECTIONS: NavSection = {
   user: [
     {